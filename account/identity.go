@@ -289,6 +289,17 @@ func IdentityFilterByUsername(username string) func(db *gorm.DB) *gorm.DB {
 	}
 }
 
+// IdentityFilterByUsernames is a gorm filter by any of the given 'username's
+func IdentityFilterByUsernames(usernames []string) func(db *gorm.DB) *gorm.DB {
+	lowered := make([]string, len(usernames))
+	for i, username := range usernames {
+		lowered[i] = strings.ToLower(username)
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("lower(username) IN (?)", lowered)
+	}
+}
+
 // IdentityFilterByProfileURL is a gorm filter by 'profile_url'
 func IdentityFilterByProfileURL(profileURL string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
@@ -0,0 +1,79 @@
+package account
+
+import (
+	"context"
+	"database/sql/driver"
+	"sort"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/rendering"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Mentions maps the usernames mentioned in a text to the IDs of the
+// identities of the mentioned users
+type Mentions map[string]uuid.UUID
+
+// Value implements the driver.Valuer interface
+func (m Mentions) Value() (driver.Value, error) {
+	if m == nil {
+		return toBytes(Mentions{})
+	}
+	return toBytes(map[string]uuid.UUID(m))
+}
+
+// Scan implements the sql.Scanner interface
+func (m *Mentions) Scan(src interface{}) error {
+	return fromBytes(src, m)
+}
+
+// IdentityIDs returns the IDs of the mentioned identities, sorted
+func (m Mentions) IdentityIDs() []uuid.UUID {
+	return m.Added(nil)
+}
+
+// Added returns the IDs of the identities that are mentioned but that are not
+// part of the given previous mentions, sorted
+func (m Mentions) Added(previous Mentions) []uuid.UUID {
+	known := map[uuid.UUID]bool{}
+	for _, id := range previous {
+		known[id] = true
+	}
+	added := []uuid.UUID{}
+	for _, id := range m {
+		if !known[id] {
+			known[id] = true
+			added = append(added, id)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i].String() < added[j].String() })
+	return added
+}
+
+// ResolveMentions looks up the identities of the users mentioned in the given
+// content. Mentions of unknown usernames are ignored.
+func ResolveMentions(ctx context.Context, identities IdentityRepository, content, markup string) (Mentions, error) {
+	mentions := Mentions{}
+	usernames := rendering.ParseMentions(content, markup)
+	if len(usernames) == 0 {
+		return mentions, nil
+	}
+	found, err := identities.Query(IdentityFilterByUsernames(usernames))
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to look up the mentioned users %s", strings.Join(usernames, ", "))
+	}
+	byUsername := map[string]uuid.UUID{}
+	for _, identity := range found {
+		if _, ok := byUsername[strings.ToLower(identity.Username)]; !ok {
+			byUsername[strings.ToLower(identity.Username)] = identity.ID
+		}
+	}
+	for _, username := range usernames {
+		if id, ok := byUsername[strings.ToLower(username)]; ok {
+			mentions[username] = id
+		}
+	}
+	return mentions, nil
+}
@@ -0,0 +1,70 @@
+package account_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type mentionBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo account.IdentityRepository
+}
+
+func TestRunMentionBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &mentionBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *mentionBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = account.NewIdentityRepository(s.DB)
+}
+
+func (s *mentionBlackBoxTest) TestResolveMentions() {
+	// given
+	identity := &account.Identity{
+		ID:           uuid.NewV4(),
+		Username:     "mentioned-" + uuid.NewV4().String(),
+		ProviderType: account.KeycloakIDP}
+	require.NoError(s.T(), s.repo.Create(s.Ctx, identity))
+	unknown := "unknown-" + uuid.NewV4().String()
+	// when
+	mentions, err := account.ResolveMentions(s.Ctx, s.repo, "@"+identity.Username+" and @"+unknown, rendering.SystemMarkupMarkdown)
+	// then
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), account.Mentions{identity.Username: identity.ID}, mentions)
+
+	s.T().Run("exact usernames only", func(t *testing.T) {
+		// given a user whose username starts with the mentioned username
+		similar := &account.Identity{
+			ID:           uuid.NewV4(),
+			Username:     unknown + "-similar",
+			ProviderType: account.KeycloakIDP}
+		require.NoError(t, s.repo.Create(s.Ctx, similar))
+		// when
+		mentions, err := account.ResolveMentions(s.Ctx, s.repo, "@"+unknown+" and @"+strings.ToUpper(identity.Username), rendering.SystemMarkupMarkdown)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, account.Mentions{strings.ToUpper(identity.Username): identity.ID}, mentions)
+	})
+}
+
+func TestMentionsAdded(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	a, b := uuid.NewV4(), uuid.NewV4()
+	previous := account.Mentions{"a": a}
+	current := account.Mentions{"a": a, "b": b}
+	assert.Equal(t, []uuid.UUID{b}, current.Added(previous))
+	assert.Empty(t, previous.Added(current))
+	assert.Len(t, current.IdentityIDs(), 2)
+}
@@ -3,13 +3,17 @@ package application
 import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 )
@@ -18,6 +22,7 @@ import (
 type Application interface {
 	WorkItems() workitem.WorkItemRepository
 	WorkItemTypes() workitem.WorkItemTypeRepository
+	WorkItemRevisions() workitem.RevisionRepository
 	Trackers() remoteworkitem.TrackerRepository
 	TrackerQueries() remoteworkitem.TrackerQueryRepository
 	SearchItems() SearchRepository
@@ -25,7 +30,9 @@ type Application interface {
 	WorkItemLinkCategories() link.WorkItemLinkCategoryRepository
 	WorkItemLinkTypes() link.WorkItemLinkTypeRepository
 	WorkItemLinks() link.WorkItemLinkRepository
+	WorkItemLinkRevisions() link.RevisionRepository
 	Comments() comment.Repository
+	CommentRevisions() comment.RevisionRepository
 	Spaces() space.Repository
 	Iterations() iteration.Repository
 	Users() account.UserRepository
@@ -33,6 +40,14 @@ type Application interface {
 	Codebases() codebase.Repository
 	Labels() label.Repository
 	Queries() query.Repository
+	NotificationOutbox() outbox.Repository
+	Webhooks() webhook.Repository
+	WebhookDeliveries() webhook.DeliveryRepository
+	SpaceTemplates() spacetemplate.Repository
+	WorkItemTypeGroups() workitem.WorkItemTypeGroupRepository
+	Attachments() attachment.Repository
+	WorkItemWatchers() workitem.WatcherRepository
+	WorkItemWorklogs() workitem.WorklogRepository
 }
 
 // A Transaction abstracts a database transaction. The repositories created for the transaction object make changes inside the the transaction
@@ -0,0 +1,55 @@
+package attachment
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The types of the entities that files can be attached to
+const (
+	ParentTypeWorkItem = "workitems"
+	ParentTypeComment  = "comments"
+)
+
+// APIStringTypeAttachments is the JSON-API type of attachments
+const APIStringTypeAttachments = "attachments"
+
+// Attachment describes a file that was attached to a work item or a comment.
+// The content of the file is kept in a BlobStore under the BlobKey.
+type Attachment struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	ParentType string
+	ParentID   uuid.UUID `sql:"type:uuid"`
+	SpaceID    uuid.UUID `sql:"type:uuid"`
+	FileName   string
+	MIMEType   string `gorm:"column:mime_type"`
+	Size       int64
+	Creator    uuid.UUID `sql:"type:uuid"`
+	BlobKey    string
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Attachment) TableName() string {
+	return "attachments"
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Attachment) GetETagData() []interface{} {
+	return []interface{}{m.ID, strconv.FormatInt(m.UpdatedAt.Unix(), 10)}
+}
+
+// GetLastModified returns the last modification time
+func (m Attachment) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// BlobKeyFor returns the key under which the content of the attachment with
+// the given ID is stored
+func BlobKeyFor(parentType string, parentID, id uuid.UUID) string {
+	return parentType + "/" + parentID.String() + "/" + id.String()
+}
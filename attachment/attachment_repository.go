@@ -0,0 +1,137 @@
+package attachment
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository describes interactions with the metadata of attachments
+type Repository interface {
+	Create(ctx context.Context, attachment *Attachment) error
+	Load(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	List(ctx context.Context, parentType string, parentID uuid.UUID) ([]Attachment, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListOrphans(ctx context.Context, deletedBefore time.Time, limit int) ([]Attachment, error)
+	Purge(ctx context.Context, id uuid.UUID) error
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormAttachmentRepository{db: db}
+}
+
+// GormAttachmentRepository is the implementation of the storage interface for
+// attachments.
+type GormAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new record.
+// returns BadParameterError or InternalError
+func (m *GormAttachmentRepository) Create(ctx context.Context, attachment *Attachment) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "create"}, time.Now())
+	if attachment.ParentType != ParentTypeWorkItem && attachment.ParentType != ParentTypeComment {
+		return errors.NewBadParameterError("parent_type", attachment.ParentType).Expected(ParentTypeWorkItem + "|" + ParentTypeComment)
+	}
+	if attachment.FileName == "" {
+		return errors.NewBadParameterError("file_name", attachment.FileName).Expected("a non-empty file name")
+	}
+	if attachment.ID == uuid.Nil {
+		attachment.ID = uuid.NewV4()
+	}
+	if attachment.BlobKey == "" {
+		attachment.BlobKey = BlobKeyFor(attachment.ParentType, attachment.ParentID, attachment.ID)
+	}
+	if err := m.db.Create(attachment).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create attachment"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"attachment_id": attachment.ID,
+		"parent_type":   attachment.ParentType,
+		"parent_id":     attachment.ParentID,
+	}, "attachment created")
+	return nil
+}
+
+// Load returns the attachment with the given ID
+// returns NotFoundError or InternalError
+func (m *GormAttachmentRepository) Load(ctx context.Context, id uuid.UUID) (*Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "load"}, time.Now())
+	a := Attachment{}
+	tx := m.db.Where("id = ?", id).First(&a)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("attachment", id.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &a, nil
+}
+
+// List returns the attachments of the given work item or comment in the order
+// in which they were uploaded
+// returns InternalError
+func (m *GormAttachmentRepository) List(ctx context.Context, parentType string, parentID uuid.UUID) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "list"}, time.Now())
+	var result []Attachment
+	if err := m.db.Where("parent_type = ? AND parent_id = ?", parentType, parentID).Order("created_at").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return result, nil
+}
+
+// Delete deletes the metadata of the attachment with the given ID. The
+// content must be removed from the blob store by the caller.
+// returns NotFoundError or InternalError
+func (m *GormAttachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "delete"}, time.Now())
+	tx := m.db.Where("id = ?", id).Delete(&Attachment{})
+	if err := tx.Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("attachment", id.String())
+	}
+	return nil
+}
+
+// ListOrphans returns at most limit attachments whose work item or comment
+// was deleted before the given time. The attachments of a comment are also
+// orphaned when the work item of the comment was deleted.
+// returns InternalError
+func (m *GormAttachmentRepository) ListOrphans(ctx context.Context, deletedBefore time.Time, limit int) ([]Attachment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "listorphans"}, time.Now())
+	var result []Attachment
+	err := m.db.Where(`(parent_type = ? AND NOT EXISTS (
+			SELECT 1 FROM work_items w WHERE w.id = attachments.parent_id AND (w.deleted_at IS NULL OR w.deleted_at >= ?)))
+		OR (parent_type = ? AND NOT EXISTS (
+			SELECT 1 FROM comments c JOIN work_items w ON w.id = c.parent_id
+			WHERE c.id = attachments.parent_id
+			AND (c.deleted_at IS NULL OR c.deleted_at >= ?)
+			AND (w.deleted_at IS NULL OR w.deleted_at >= ?)))`,
+		ParentTypeWorkItem, deletedBefore, ParentTypeComment, deletedBefore, deletedBefore).
+		Order("created_at").Limit(limit).Find(&result).Error
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return result, nil
+}
+
+// Purge removes the metadata of the attachment with the given ID for good.
+// The content must be removed from the blob store by the caller.
+// returns InternalError
+func (m *GormAttachmentRepository) Purge(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "attachment", "purge"}, time.Now())
+	if err := m.db.Unscoped().Where("id = ?", id).Delete(&Attachment{}).Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package attachment_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestAttachmentRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunAttachmentRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestAttachmentRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestAttachmentRepository) newAttachment(fxt *tf.TestFixture, name string) attachment.Attachment {
+	return attachment.Attachment{
+		ParentType: attachment.ParentTypeWorkItem,
+		ParentID:   fxt.WorkItems[0].ID,
+		SpaceID:    fxt.WorkItems[0].SpaceID,
+		FileName:   name,
+		MIMEType:   "text/plain",
+		Size:       42,
+		Creator:    fxt.Identities[0].ID,
+	}
+}
+
+func (s *TestAttachmentRepository) TestCreate() {
+	repo := attachment.NewRepository(s.DB)
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		att := s.newAttachment(fxt, "build.log")
+		// when
+		err := repo.Create(context.Background(), &att)
+		// then
+		require.NoError(t, err)
+		require.NotEqual(t, uuid.Nil, att.ID)
+		assert.Equal(t, attachment.BlobKeyFor(attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID, att.ID), att.BlobKey)
+		loaded, err := repo.Load(context.Background(), att.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "build.log", loaded.FileName)
+		assert.Equal(t, "text/plain", loaded.MIMEType)
+		assert.Equal(t, int64(42), loaded.Size)
+	})
+
+	s.T().Run("invalid parent type", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		att := s.newAttachment(fxt, "build.log")
+		att.ParentType = "spaces"
+		err := repo.Create(context.Background(), &att)
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("empty file name", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		att := s.newAttachment(fxt, "")
+		err := repo.Create(context.Background(), &att)
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestAttachmentRepository) TestListAndDelete() {
+	// given
+	repo := attachment.NewRepository(s.DB)
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2))
+	first := s.newAttachment(fxt, "first.png")
+	require.NoError(s.T(), repo.Create(context.Background(), &first))
+	second := s.newAttachment(fxt, "second.png")
+	require.NoError(s.T(), repo.Create(context.Background(), &second))
+
+	s.T().Run("list", func(t *testing.T) {
+		list, err := repo.List(context.Background(), attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, first.ID, list[0].ID)
+		assert.Equal(t, second.ID, list[1].ID)
+		// other work items and comments have no attachments
+		list, err = repo.List(context.Background(), attachment.ParentTypeWorkItem, fxt.WorkItems[1].ID)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+		list, err = repo.List(context.Background(), attachment.ParentTypeComment, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+
+	s.T().Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(context.Background(), first.ID))
+		_, err := repo.Load(context.Background(), first.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		list, err := repo.List(context.Background(), attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, second.ID, list[0].ID)
+		// deleting twice fails
+		err = repo.Delete(context.Background(), first.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+type collectorConfig struct {
+	retention time.Duration
+}
+
+func (c collectorConfig) GetAttachmentOrphanRetention() time.Duration {
+	return c.retention
+}
+
+func (s *TestAttachmentRepository) TestCollectOrphans() {
+	dir, err := ioutil.TempDir("", "attachments")
+	require.NoError(s.T(), err)
+	defer os.RemoveAll(dir)
+	store, err := attachment.NewLocalBlobStore(dir)
+	require.NoError(s.T(), err)
+	repo := attachment.NewRepository(s.DB)
+	// create stores an attachment of the given parent together with its content
+	create := func(t *testing.T, fxt *tf.TestFixture, parentType string, parentID uuid.UUID) attachment.Attachment {
+		att := s.newAttachment(fxt, "build.log")
+		att.ParentType = parentType
+		att.ParentID = parentID
+		require.NoError(t, repo.Create(s.Ctx, &att))
+		require.NoError(t, store.Put(s.Ctx, att.BlobKey, bytes.NewReader([]byte("x")), 1, att.MIMEType))
+		return att
+	}
+
+	s.T().Run("attachments of deleted work items and comments", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.Comments[idx].ParentID = fxt.WorkItems[1].ID
+			return nil
+		}))
+		deletedWorkItem := create(t, fxt, attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID)
+		liveWorkItem := create(t, fxt, attachment.ParentTypeWorkItem, fxt.WorkItems[1].ID)
+		deletedComment := create(t, fxt, attachment.ParentTypeComment, fxt.Comments[0].ID)
+		liveComment := create(t, fxt, attachment.ParentTypeComment, fxt.Comments[1].ID)
+		require.NoError(t, workitem.NewWorkItemRepository(s.DB).Delete(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID))
+		require.NoError(t, comment.NewRepository(s.DB).Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID))
+		// when
+		collected, err := attachment.NewCollector(s.DB, store, collectorConfig{}).CollectOrphans(s.Ctx)
+		// then
+		require.NoError(t, err)
+		assert.True(t, collected >= 2)
+		for _, att := range []attachment.Attachment{deletedWorkItem, deletedComment} {
+			_, err = repo.Load(s.Ctx, att.ID)
+			assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+			_, err = store.Get(s.Ctx, att.BlobKey)
+			assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		}
+		for _, att := range []attachment.Attachment{liveWorkItem, liveComment} {
+			_, err = repo.Load(s.Ctx, att.ID)
+			assert.NoError(t, err)
+			content, err := store.Get(s.Ctx, att.BlobKey)
+			require.NoError(t, err)
+			content.Close()
+		}
+	})
+
+	s.T().Run("attachments are kept during the retention", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		att := create(t, fxt, attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID)
+		require.NoError(t, workitem.NewWorkItemRepository(s.DB).Delete(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID))
+		// when
+		_, err := attachment.NewCollector(s.DB, store, collectorConfig{retention: time.Hour}).CollectOrphans(s.Ctx)
+		// then
+		require.NoError(t, err)
+		_, err = repo.Load(s.Ctx, att.ID)
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,147 @@
+package attachment
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// The kinds of blob stores that can be configured
+const (
+	BlobStoreLocal = "local"
+	BlobStoreS3    = "s3"
+)
+
+// BlobStore keeps the content of the attachments
+type BlobStore interface {
+	// Put stores the content read from the given reader under the given key.
+	// The size is the number of bytes that will be read.
+	Put(ctx context.Context, key string, content io.Reader, size int64, mimeType string) error
+	// Get returns the content stored under the given key. The caller must
+	// close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the content stored under the given key. Deleting a key
+	// that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// BlobStoreConfiguration the configuration of the blob store
+type BlobStoreConfiguration interface {
+	GetAttachmentStore() string
+	GetAttachmentLocalDir() string
+	GetAttachmentS3Endpoint() string
+	GetAttachmentS3Bucket() string
+	GetAttachmentS3Region() string
+	GetAttachmentS3AccessKey() string
+	GetAttachmentS3SecretKey() string
+}
+
+// NewBlobStore creates the blob store selected in the given configuration
+func NewBlobStore(config BlobStoreConfiguration) (BlobStore, error) {
+	switch config.GetAttachmentStore() {
+	case BlobStoreLocal:
+		return NewLocalBlobStore(config.GetAttachmentLocalDir())
+	case BlobStoreS3:
+		return NewS3BlobStore(config.GetAttachmentS3Endpoint(), config.GetAttachmentS3Bucket(), config.GetAttachmentS3Region(), config.GetAttachmentS3AccessKey(), config.GetAttachmentS3SecretKey())
+	default:
+		return nil, errs.Errorf("unknown attachment store: '%s' (expected '%s' or '%s')", config.GetAttachmentStore(), BlobStoreLocal, BlobStoreS3)
+	}
+}
+
+// LocalBlobStore implements BlobStore with files below a directory of the
+// local filesystem
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates a blob store that keeps the content in the given
+// directory, the directory is created if it does not exist yet
+func NewLocalBlobStore(dir string) (*LocalBlobStore, error) {
+	if dir == "" {
+		return nil, errs.New("the directory of the local attachment store is not set")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, errs.Wrapf(err, "failed to create the directory of the local attachment store: %s", dir)
+	}
+	return &LocalBlobStore{dir: dir}, nil
+}
+
+// path returns the path of the file for the given key and makes sure that it
+// is located below the directory of the store
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return "", errors.NewBadParameterError("key", key).Expected("a relative blob key")
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errors.NewBadParameterError("key", key).Expected("a relative blob key")
+		}
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put implements BlobStore
+func (s *LocalBlobStore) Put(ctx context.Context, key string, content io.Reader, size int64, mimeType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create the directory of blob %s", key))
+	}
+	// write to a temporary file first so that a failed upload never leaves a
+	// truncated blob behind
+	tmp, err := ioutil.TempFile(filepath.Dir(p), ".upload-")
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create blob %s", key))
+	}
+	defer os.Remove(tmp.Name())
+	written, err := io.Copy(tmp, content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to write blob %s", key))
+	}
+	if written != size {
+		return errors.NewInternalError(ctx, errs.Errorf("blob %s has %d bytes instead of %d", key, written, size))
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to store blob %s", key))
+	}
+	return nil
+}
+
+// Get implements BlobStore
+func (s *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFoundError("blob", key)
+	}
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to read blob %s", key))
+	}
+	return f, nil
+}
+
+// Delete implements BlobStore
+func (s *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to delete blob %s", key))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package attachment_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlobStore runs the same checks against all implementations of the
+// BlobStore interface
+func testBlobStore(t *testing.T, store attachment.BlobStore) {
+	ctx := context.Background()
+	key := "workitems/8b4b4b9e-9e2f-4a66-9d22-4f6b1a3a5c9f/0d6c5a1e-2bd2-4f5e-9b5a-52c5d6b1c0a7"
+	content := []byte("stack trace of the crash")
+
+	t.Run("put and get", func(t *testing.T) {
+		require.NoError(t, store.Put(ctx, key, bytes.NewReader(content), int64(len(content)), "text/plain"))
+		r, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		defer r.Close()
+		actual, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, content, actual)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, store.Delete(ctx, key))
+		_, err := store.Get(ctx, key)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		// deleting a missing blob is fine
+		require.NoError(t, store.Delete(ctx, key))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := store.Get(ctx, "workitems/unknown")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	dir, err := ioutil.TempDir("", "attachments")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	store, err := attachment.NewLocalBlobStore(dir)
+	require.NoError(t, err)
+
+	testBlobStore(t, store)
+
+	t.Run("keys must stay in the directory", func(t *testing.T) {
+		for _, key := range []string{"", "/etc/passwd", "../secret", "workitems/../../secret"} {
+			err := store.Put(context.Background(), key, bytes.NewReader([]byte("x")), 1, "text/plain")
+			require.IsType(t, errors.BadParameterError{}, errs.Cause(err), "key: %s", key)
+		}
+	})
+
+	t.Run("size mismatch", func(t *testing.T) {
+		err := store.Put(context.Background(), "workitems/short", bytes.NewReader([]byte("x")), 2, "text/plain")
+		require.Error(t, err)
+		_, err = store.Get(context.Background(), "workitems/short")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+// fakeS3 is a minimal stand-in for an S3-compatible object storage that keeps
+// the objects in memory
+type fakeS3 struct {
+	sync.Mutex
+	objects map[string][]byte
+}
+
+func (s *fakeS3) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	s.Lock()
+	defer s.Unlock()
+	auth := req.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/eu-west-1/s3/aws4_request") || req.Header.Get("X-Amz-Date") == "" {
+		rw.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if !strings.HasPrefix(req.URL.Path, "/attachments/") {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch req.Method {
+	case http.MethodPut:
+		body, _ := ioutil.ReadAll(req.Body)
+		s.objects[req.URL.Path] = body
+	case http.MethodGet:
+		body, ok := s.objects[req.URL.Path]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Write(body)
+	case http.MethodDelete:
+		delete(s.objects, req.URL.Path)
+		rw.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3BlobStore(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	s3 := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(s3)
+	defer server.Close()
+
+	t.Run("signed requests", func(t *testing.T) {
+		store, err := attachment.NewS3BlobStore(server.URL, "attachments", "eu-west-1", "access", "secret")
+		require.NoError(t, err)
+		testBlobStore(t, store)
+	})
+
+	t.Run("rejected requests", func(t *testing.T) {
+		store, err := attachment.NewS3BlobStore(server.URL, "attachments", "us-east-1", "other", "secret")
+		require.NoError(t, err)
+		err = store.Put(context.Background(), "workitems/foo", bytes.NewReader([]byte("x")), 1, "text/plain")
+		require.IsType(t, errors.InternalError{}, errs.Cause(err))
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		_, err := attachment.NewS3BlobStore("not a url", "attachments", "", "access", "secret")
+		require.Error(t, err)
+		_, err = attachment.NewS3BlobStore(server.URL, "", "", "access", "secret")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,121 @@
+package attachment
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+)
+
+// CollectorConfiguration the configuration of the orphaned attachments
+// collector
+type CollectorConfiguration interface {
+	GetAttachmentOrphanRetention() time.Duration
+}
+
+// collectInterval is the interval in which the orphaned attachments are
+// collected
+const collectInterval = time.Hour
+
+// collectBatchSize is the number of orphaned attachments removed per batch
+const collectBatchSize = 100
+
+// Collector periodically removes the attachments of deleted work items and
+// comments together with their content in the blob store. Attachments are
+// only removed once their work item or comment has been deleted for longer
+// than the configured retention, so that restoring it brings them back.
+type Collector struct {
+	db     *gorm.DB
+	store  BlobStore
+	config CollectorConfiguration
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewCollector creates a collector that removes orphaned attachments from the
+// given blob store
+func NewCollector(db *gorm.DB, store BlobStore, config CollectorConfiguration) *Collector {
+	return &Collector{
+		db:     db,
+		store:  store,
+		config: config,
+	}
+}
+
+// Start runs the collector in the background until Stop is called
+func (c *Collector) Start() {
+	c.stop = make(chan struct{})
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(collectInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-ticker.C:
+				if _, err := c.CollectOrphans(context.Background()); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "failed to collect orphaned attachments")
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the background collection and waits for a running batch to
+// complete
+func (c *Collector) Stop() {
+	if c.stop == nil {
+		return
+	}
+	close(c.stop)
+	c.wg.Wait()
+	c.stop = nil
+}
+
+// CollectOrphans removes the orphaned attachments and returns how many were
+// removed. The content is deleted from the blob store before the metadata, an
+// attachment whose content cannot be deleted is retried with the next run.
+func (c *Collector) CollectOrphans(ctx context.Context) (int, error) {
+	deletedBefore := time.Now().Add(-c.config.GetAttachmentOrphanRetention())
+	var orphans []Attachment
+	err := models.Transactional(c.db, func(tx *gorm.DB) error {
+		var err error
+		orphans, err = NewRepository(tx).ListOrphans(ctx, deletedBefore, collectBatchSize)
+		return err
+	})
+	if err != nil {
+		return 0, errs.Wrap(err, "failed to list orphaned attachments")
+	}
+	collected := 0
+	for _, att := range orphans {
+		if err := c.store.Delete(ctx, att.BlobKey); err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":           err,
+				"attachment_id": att.ID,
+				"blob_key":      att.BlobKey,
+			}, "unable to delete the content of an orphaned attachment")
+			continue
+		}
+		err := models.Transactional(c.db, func(tx *gorm.DB) error {
+			return NewRepository(tx).Purge(ctx, att.ID)
+		})
+		if err != nil {
+			return collected, errs.Wrapf(err, "failed to purge orphaned attachment %s", att.ID)
+		}
+		collected++
+	}
+	if collected > 0 {
+		log.Info(ctx, map[string]interface{}{
+			"collected": collected,
+		}, "removed orphaned attachments")
+	}
+	return collected, nil
+}
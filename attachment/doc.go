@@ -0,0 +1,3 @@
+// Package attachment contains the metadata of the files attached to work
+// items and comments and the blob stores that hold their content.
+package attachment
@@ -0,0 +1,196 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// unsignedPayload is used as the payload hash so that uploads can be streamed
+// without reading them twice
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3BlobStore implements BlobStore with the objects of a bucket of an
+// S3-compatible object storage (e.g. AWS S3, Ceph or Minio). The bucket is
+// addressed path-style and the requests are signed with AWS Signature
+// Version 4.
+type S3BlobStore struct {
+	endpoint  *url.URL
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	client    *http.Client
+	// now is replaced in tests
+	now func() time.Time
+}
+
+// NewS3BlobStore creates a blob store that keeps the content in the given
+// bucket of the object storage at the given endpoint (e.g.
+// https://s3.amazonaws.com)
+func NewS3BlobStore(endpoint, bucket, region, accessKey, secretKey string) (*S3BlobStore, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, errs.Errorf("invalid endpoint of the S3 attachment store: '%s'", endpoint)
+	}
+	if bucket == "" {
+		return nil, errs.New("the bucket of the S3 attachment store is not set")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3BlobStore{
+		endpoint:  u,
+		bucket:    bucket,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: 5 * time.Minute},
+		now:       time.Now,
+	}, nil
+}
+
+// objectURL returns the path-style URL of the object with the given key
+func (s *S3BlobStore) objectURL(key string) string {
+	return strings.TrimSuffix(s.endpoint.String(), "/") + "/" + s3Escape(s.bucket) + "/" + s3Escape(key)
+}
+
+// Put implements BlobStore
+func (s *S3BlobStore) Put(ctx context.Context, key string, content io.Reader, size int64, mimeType string) error {
+	req, err := http.NewRequest(http.MethodPut, s.objectURL(key), content)
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create upload request of blob %s", key))
+	}
+	req.ContentLength = size
+	if mimeType != "" {
+		req.Header.Set("Content-Type", mimeType)
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to upload blob %s", key))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.NewInternalError(ctx, s3Error(resp, "failed to upload blob "+key))
+	}
+	return nil
+}
+
+// Get implements BlobStore
+func (s *S3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := http.NewRequest(http.MethodGet, s.objectURL(key), nil)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create download request of blob %s", key))
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to download blob %s", key))
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, errors.NewNotFoundError("blob", key)
+	default:
+		defer resp.Body.Close()
+		return nil, errors.NewInternalError(ctx, s3Error(resp, "failed to download blob "+key))
+	}
+}
+
+// Delete implements BlobStore
+func (s *S3BlobStore) Delete(ctx context.Context, key string) error {
+	req, err := http.NewRequest(http.MethodDelete, s.objectURL(key), nil)
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to create delete request of blob %s", key))
+	}
+	resp, err := s.do(ctx, req)
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to delete blob %s", key))
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return errors.NewInternalError(ctx, s3Error(resp, "failed to delete blob "+key))
+	}
+}
+
+// do signs and sends the given request
+func (s *S3BlobStore) do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	s.sign(req, s.now().UTC())
+	return s.client.Do(req.WithContext(ctx))
+}
+
+// sign adds the AWS Signature Version 4 headers to the given request, see
+// https://docs.aws.amazon.com/general/latest/gr/signature-version-4.html
+func (s *S3BlobStore) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + unsignedPayload + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// s3Escape escapes all characters of the given path except the unreserved
+// ones and the slashes the way the signature expects it
+func s3Escape(path string) string {
+	var b bytes.Buffer
+	for _, c := range []byte(path) {
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3Error creates an error from the status and the body of the given response
+func s3Error(resp *http.Response, msg string) error {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	return errs.Errorf("%s: %s %s", msg, resp.Status, strings.TrimSpace(string(body)))
+}
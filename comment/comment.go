@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	uuid "github.com/satori/go.uuid"
 )
@@ -16,6 +17,21 @@ type Comment struct {
 	Creator  uuid.UUID `sql:"type:uuid"` // Belongs To Identity
 	Body     string
 	Markup   string
+	// ParentCommentID references the top-level comment of the thread this
+	// comment replies to, it is nil for top-level comments
+	ParentCommentID *uuid.UUID `sql:"type:uuid"`
+	// ResolvedAt and ResolvedBy are set when the thread started by this
+	// top-level comment was marked as resolved
+	ResolvedAt *time.Time
+	ResolvedBy *uuid.UUID `sql:"type:uuid"`
+	// Mentions are the users mentioned in the body, they are resolved by the
+	// repository whenever the comment is stored
+	Mentions account.Mentions `sql:"type:jsonb"`
+}
+
+// IsReply returns true if the comment is a reply in a thread
+func (m Comment) IsReply() bool {
+	return m.ParentCommentID != nil
 }
 
 // TableName overrides the table name settings in Gorm to force a specific table name
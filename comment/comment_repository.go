@@ -6,6 +6,7 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/closeable"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/application/repository"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
@@ -23,9 +24,13 @@ type Repository interface {
 	Create(ctx context.Context, comment *Comment, creator uuid.UUID) error
 	Save(ctx context.Context, comment *Comment, modifier uuid.UUID) error
 	Delete(ctx context.Context, commentID uuid.UUID, suppressor uuid.UUID) error
+	Restore(ctx context.Context, commentID uuid.UUID, modifier uuid.UUID) (*Comment, error)
 	List(ctx context.Context, parent uuid.UUID, start *int, limit *int) ([]Comment, uint64, error)
 	Load(ctx context.Context, id uuid.UUID) (*Comment, error)
 	Count(ctx context.Context, parentID uuid.UUID) (int, error)
+	ListReplies(ctx context.Context, threadIDs []uuid.UUID) ([]Comment, error)
+	Resolve(ctx context.Context, commentID uuid.UUID, resolverID uuid.UUID) (*Comment, error)
+	Unresolve(ctx context.Context, commentID uuid.UUID, modifierID uuid.UUID) (*Comment, error)
 }
 
 // NewRepository creates a new storage type.
@@ -47,6 +52,19 @@ func (m *GormCommentRepository) Create(ctx context.Context, comment *Comment, cr
 	if comment.Markup == "" {
 		comment.Markup = rendering.SystemMarkupDefault
 	}
+	if comment.ParentCommentID != nil {
+		threadID, err := m.threadOf(ctx, *comment.ParentCommentID, comment.ParentID)
+		if err != nil {
+			return errs.WithStack(err)
+		}
+		comment.ParentCommentID = &threadID
+	}
+	if err := m.resolveMentions(ctx, comment); err != nil {
+		return errs.WithStack(err)
+	}
+	// only threads can be resolved
+	comment.ResolvedAt = nil
+	comment.ResolvedBy = nil
 	if err := m.db.Create(comment).Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
 			"comment_id": comment.ID,
@@ -88,6 +106,9 @@ func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modi
 	if comment.Markup == "" {
 		comment.Markup = rendering.SystemMarkupDefault
 	}
+	if err := m.resolveMentions(ctx, comment); err != nil {
+		return errs.WithStack(err)
+	}
 	tx = tx.Save(comment)
 	if err := tx.Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -108,6 +129,16 @@ func (m *GormCommentRepository) Save(ctx context.Context, comment *Comment, modi
 	return nil
 }
 
+// resolveMentions sets the users mentioned in the body of the given comment
+func (m *GormCommentRepository) resolveMentions(ctx context.Context, comment *Comment) error {
+	mentions, err := account.ResolveMentions(ctx, account.NewIdentityRepository(m.db), comment.Body, comment.Markup)
+	if err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	comment.Mentions = mentions
+	return nil
+}
+
 // Delete a single comment
 func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID, suppressorID uuid.UUID) error {
 	if commentID == uuid.Nil {
@@ -115,7 +146,7 @@ func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID,
 	}
 	// fetch the id and parent id of the comment to delete, to store them in the new revision.
 	c := Comment{}
-	tx := m.db.Select("id, parent_id").Where("id = ?", commentID).Find(&c)
+	tx := m.db.Select("id, parent_id, parent_comment_id").Where("id = ?", commentID).Find(&c)
 	if tx.RowsAffected != 1 {
 		return errors.NewNotFoundError("comment", commentID.String())
 	}
@@ -127,14 +158,78 @@ func (m *GormCommentRepository) Delete(ctx context.Context, commentID uuid.UUID,
 	if err := m.revisionRepository.Create(ctx, suppressorID, RevisionTypeDelete, c); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	if c.IsReply() {
+		return nil
+	}
+	// the replies of a thread are deleted together with the thread
+	var replies []Comment
+	if err := m.db.Select("id, parent_id, parent_comment_id").Where("parent_comment_id = ?", commentID).Find(&replies).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to find the replies of comment %s", commentID))
+	}
+	for _, reply := range replies {
+		if err := m.db.Delete(reply).Error; err != nil {
+			return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to delete reply %s", reply.ID))
+		}
+		if err := m.revisionRepository.Create(ctx, suppressorID, RevisionTypeDelete, reply); err != nil {
+			return errs.Wrapf(err, "error while deleting reply")
+		}
+	}
 	return nil
 }
 
+// Restore undeletes the soft-deleted comment with the given id
+func (m *GormCommentRepository) Restore(ctx context.Context, commentID uuid.UUID, modifierID uuid.UUID) (*Comment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "restore"}, time.Now())
+	c := Comment{}
+	tx := m.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", commentID).First(&c)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("deleted comment", commentID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	deletedAt := *c.DeletedAt
+	if err := m.db.Unscoped().Model(&c).Update("deleted_at", nil).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"comment_id": commentID,
+			"err":        err,
+		}, "unable to restore the comment")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	c.DeletedAt = nil
+	// save a revision of the restored comment, which holds the body again
+	if err := m.revisionRepository.Create(ctx, modifierID, RevisionTypeUpdate, c); err != nil {
+		return nil, errs.Wrapf(err, "error while restoring comment")
+	}
+	if !c.IsReply() {
+		// restore the replies that were deleted together with the thread, the
+		// replies deleted before stay deleted
+		var replies []Comment
+		if err := m.db.Unscoped().Where("parent_comment_id = ? AND deleted_at >= ?", commentID, deletedAt).Find(&replies).Error; err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to find the deleted replies of comment %s", commentID))
+		}
+		for _, reply := range replies {
+			if err := m.db.Unscoped().Model(&reply).Update("deleted_at", nil).Error; err != nil {
+				return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to restore reply %s", reply.ID))
+			}
+			reply.DeletedAt = nil
+			if err := m.revisionRepository.Create(ctx, modifierID, RevisionTypeUpdate, reply); err != nil {
+				return nil, errs.Wrapf(err, "error while restoring reply")
+			}
+		}
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"comment_id": commentID,
+	}, "Comment restored!")
+	return &c, nil
+}
+
 // List all comments related to a single item
 func (m *GormCommentRepository) List(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]Comment, uint64, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
 
-	db := m.db.Model(&Comment{}).Where("parent_id = ?", parentID)
+	// replies are listed with ListReplies
+	db := m.db.Model(&Comment{}).Where("parent_id = ? AND parent_comment_id IS NULL", parentID)
 	orgDB := db
 	if start != nil {
 		if *start < 0 {
@@ -199,16 +294,104 @@ func (m *GormCommentRepository) List(ctx context.Context, parentID uuid.UUID, st
 	return result, count, nil
 }
 
-// Count all comments related to a single item
+// Count all comment threads related to a single item, like List the replies
+// are not counted
 func (m *GormCommentRepository) Count(ctx context.Context, parentID uuid.UUID) (int, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "query"}, time.Now())
 	var count int
 
-	m.db.Model(&Comment{}).Where("parent_id = ?", parentID).Count(&count)
+	m.db.Model(&Comment{}).Where("parent_id = ? AND parent_comment_id IS NULL", parentID).Count(&count)
 
 	return count, nil
 }
 
+// threadOf returns the ID of the thread a reply to the given comment belongs
+// to. Replies to replies are added to the thread of the replied comment, so
+// that threads are never nested.
+// returns BadParameterError or InternalError
+func (m *GormCommentRepository) threadOf(ctx context.Context, repliedCommentID uuid.UUID, parentID uuid.UUID) (uuid.UUID, error) {
+	replied := Comment{}
+	tx := m.db.Select("id, parent_id, parent_comment_id").Where("id = ?", repliedCommentID).First(&replied)
+	if tx.RecordNotFound() {
+		return uuid.Nil, errors.NewBadParameterError("parent_comment_id", repliedCommentID).Expected("an existing comment")
+	}
+	if err := tx.Error; err != nil {
+		return uuid.Nil, errors.NewInternalError(ctx, err)
+	}
+	if replied.ParentID != parentID {
+		return uuid.Nil, errors.NewBadParameterError("parent_comment_id", repliedCommentID).Expected("a comment of the same parent")
+	}
+	if replied.ParentCommentID != nil {
+		return *replied.ParentCommentID, nil
+	}
+	return replied.ID, nil
+}
+
+// ListReplies returns the replies of the given threads, oldest first
+func (m *GormCommentRepository) ListReplies(ctx context.Context, threadIDs []uuid.UUID) ([]Comment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "replies"}, time.Now())
+	result := []Comment{}
+	if len(threadIDs) == 0 {
+		return result, nil
+	}
+	if err := m.db.Where("parent_comment_id IN (?)", threadIDs).Order("created_at asc").Find(&result).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return result, nil
+}
+
+// Resolve marks the thread started by the given top-level comment as
+// resolved by the given identity. Resolving a resolved thread has no effect.
+// returns NotFoundError, BadParameterError or InternalError
+func (m *GormCommentRepository) Resolve(ctx context.Context, commentID uuid.UUID, resolverID uuid.UUID) (*Comment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "resolve"}, time.Now())
+	return m.setResolved(ctx, commentID, resolverID, true)
+}
+
+// Unresolve marks the thread started by the given top-level comment as
+// unresolved. Unresolving an unresolved thread has no effect.
+// returns NotFoundError, BadParameterError or InternalError
+func (m *GormCommentRepository) Unresolve(ctx context.Context, commentID uuid.UUID, modifierID uuid.UUID) (*Comment, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "comment", "unresolve"}, time.Now())
+	return m.setResolved(ctx, commentID, modifierID, false)
+}
+
+func (m *GormCommentRepository) setResolved(ctx context.Context, commentID uuid.UUID, modifierID uuid.UUID, resolved bool) (*Comment, error) {
+	c, err := m.Load(ctx, commentID)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if c.IsReply() {
+		return nil, errors.NewBadParameterError("comment", commentID).Expected("a top-level comment that starts a thread")
+	}
+	if (c.ResolvedAt != nil) == resolved {
+		return c, nil
+	}
+	revisionType := RevisionTypeUnresolve
+	updates := map[string]interface{}{"resolved_at": nil, "resolved_by": nil}
+	if resolved {
+		revisionType = RevisionTypeResolve
+		now := time.Now()
+		c.ResolvedAt = &now
+		c.ResolvedBy = &modifierID
+		updates = map[string]interface{}{"resolved_at": now, "resolved_by": modifierID}
+	} else {
+		c.ResolvedAt = nil
+		c.ResolvedBy = nil
+	}
+	if err := m.db.Model(c).Updates(updates).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if err := m.revisionRepository.Create(ctx, modifierID, revisionType, *c); err != nil {
+		return nil, errs.Wrapf(err, "error while resolving comment")
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"comment_id": commentID,
+		"resolved":   resolved,
+	}, "Comment thread resolution changed!")
+	return c, nil
+}
+
 // Load a single comment regardless of parent
 func (m *GormCommentRepository) Load(ctx context.Context, id uuid.UUID) (*Comment, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "comment", "get"}, time.Now())
@@ -7,9 +7,11 @@ import (
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -132,6 +134,38 @@ func (s *TestCommentRepository) TestDeleteComment() {
 		require.NoError(t, err)
 	})
 
+	s.T().Run("Delete thread with replies", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		s.createComment(reply, fxt.Identities[0].ID)
+		// when
+		err := s.repo.Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		_, err = s.repo.Load(s.Ctx, reply.ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		revisions, err := comment.NewRevisionRepository(s.DB).List(s.Ctx, reply.ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 2)
+		assert.Equal(t, comment.RevisionTypeDelete, revisions[1].Type)
+	})
+
+	s.T().Run("Delete reply keeps the thread", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		s.createComment(reply, fxt.Identities[0].ID)
+		// when
+		err := s.repo.Delete(s.Ctx, reply.ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		_, err = s.repo.Load(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+	})
+
 	s.T().Run("Delete missing", func(t *testing.T) {
 		numComments := 4
 		// given
@@ -147,6 +181,54 @@ func (s *TestCommentRepository) TestDeleteComment() {
 	})
 }
 
+func (s *TestCommentRepository) TestRestoreComment() {
+	s.T().Run("Restore deleted", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		c := fxt.Comments[0]
+		require.NoError(t, s.repo.Delete(s.Ctx, c.ID, c.Creator))
+		// when
+		restored, err := s.repo.Restore(s.Ctx, c.ID, c.Creator)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, c.Body, restored.Body)
+		loaded, err := s.repo.Load(s.Ctx, c.ID)
+		require.NoError(t, err)
+		assert.Equal(t, c.Body, loaded.Body)
+	})
+
+	s.T().Run("Restore thread with replies", func(t *testing.T) {
+		// given a thread with a reply that was deleted before the thread and
+		// a reply that was deleted together with the thread
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		deletedBefore := newComment(fxt.WorkItems[0].ID, "deleted before", "")
+		deletedBefore.ParentCommentID = &fxt.Comments[0].ID
+		deletedWith := newComment(fxt.WorkItems[0].ID, "deleted with the thread", "")
+		deletedWith.ParentCommentID = &fxt.Comments[0].ID
+		s.createComments([]*comment.Comment{deletedBefore, deletedWith}, fxt.Identities[0].ID)
+		require.NoError(t, s.repo.Delete(s.Ctx, deletedBefore.ID, fxt.Identities[0].ID))
+		require.NoError(t, s.repo.Delete(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID))
+		// when
+		_, err := s.repo.Restore(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		replies, err := s.repo.ListReplies(s.Ctx, []uuid.UUID{fxt.Comments[0].ID})
+		require.NoError(t, err)
+		require.Len(t, replies, 1)
+		assert.Equal(t, deletedWith.ID, replies[0].ID)
+	})
+
+	s.T().Run("Restore not deleted", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		c := fxt.Comments[0]
+		// when
+		_, err := s.repo.Restore(s.Ctx, c.ID, c.Creator)
+		// then
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
+
 func (s *TestCommentRepository) TestCountComments() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2), tf.Comments(2, func(fxt *tf.TestFixture, idx int) error {
@@ -158,6 +240,10 @@ func (s *TestCommentRepository) TestCountComments() {
 		}
 		return nil
 	}))
+	// replies are not counted
+	reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+	reply.ParentCommentID = &fxt.Comments[0].ID
+	s.createComment(reply, fxt.Identities[0].ID)
 	// when
 	count, err := s.repo.Count(s.Ctx, fxt.WorkItems[0].ID)
 	// then
@@ -228,3 +314,150 @@ func (s *TestCommentRepository) TestExistsComment() {
 		require.IsType(t, errors.NotFoundError{}, err)
 	})
 }
+
+func (s *TestCommentRepository) TestReplies() {
+	s.T().Run("reply to a top-level comment", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		// when
+		err := s.repo.Create(s.Ctx, reply, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, reply.ParentCommentID)
+		assert.Equal(t, fxt.Comments[0].ID, *reply.ParentCommentID)
+		assert.True(t, reply.IsReply())
+	})
+
+	s.T().Run("reply to a reply belongs to the thread", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		s.createComment(reply, fxt.Identities[0].ID)
+		answer := newComment(fxt.WorkItems[0].ID, "answer", "")
+		answer.ParentCommentID = &reply.ID
+		// when
+		err := s.repo.Create(s.Ctx, answer, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, answer.ParentCommentID)
+		assert.Equal(t, fxt.Comments[0].ID, *answer.ParentCommentID)
+	})
+
+	s.T().Run("reply to a comment of another work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Comments(1))
+		reply := newComment(fxt.WorkItems[1].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		// when
+		err := s.repo.Create(s.Ctx, reply, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("reply to an unknown comment", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = ptr.UUID(uuid.NewV4())
+		// when
+		err := s.repo.Create(s.Ctx, reply, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("list threads and their replies", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(2))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[1].ID
+		s.createComment(reply, fxt.Identities[0].ID)
+		// when
+		threads, count, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID, nil, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, uint64(2), count)
+		require.Len(t, threads, 2)
+		for _, c := range threads {
+			assert.False(t, c.IsReply())
+		}
+		// when
+		replies, err := s.repo.ListReplies(s.Ctx, []uuid.UUID{threads[0].ID, threads[1].ID})
+		// then
+		require.NoError(t, err)
+		require.Len(t, replies, 1)
+		assert.Equal(t, reply.ID, replies[0].ID)
+	})
+}
+
+func (s *TestCommentRepository) TestResolve() {
+	s.T().Run("resolve and unresolve a thread", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Comments(1))
+		resolver := fxt.Identities[1].ID
+		// when
+		resolved, err := s.repo.Resolve(s.Ctx, fxt.Comments[0].ID, resolver)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, resolved.ResolvedAt)
+		require.NotNil(t, resolved.ResolvedBy)
+		assert.Equal(t, resolver, *resolved.ResolvedBy)
+		loaded, err := s.repo.Load(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.ResolvedBy)
+		assert.Equal(t, resolver, *loaded.ResolvedBy)
+		// when
+		unresolved, err := s.repo.Unresolve(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, unresolved.ResolvedAt)
+		assert.Nil(t, unresolved.ResolvedBy)
+		revisions, err := comment.NewRevisionRepository(s.DB).List(s.Ctx, fxt.Comments[0].ID)
+		require.NoError(t, err)
+		require.True(t, len(revisions) >= 2)
+		last := revisions[len(revisions)-1]
+		assert.Equal(t, comment.RevisionTypeUnresolve, last.Type)
+		assert.Equal(t, fxt.Identities[0].ID, last.ModifierIdentity)
+		previous := revisions[len(revisions)-2]
+		assert.Equal(t, comment.RevisionTypeResolve, previous.Type)
+		assert.Equal(t, resolver, previous.ModifierIdentity)
+	})
+
+	s.T().Run("resolve a resolved thread", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Comments(1))
+		first, err := s.repo.Resolve(s.Ctx, fxt.Comments[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		second, err := s.repo.Resolve(s.Ctx, fxt.Comments[0].ID, fxt.Identities[1].ID)
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, second.ResolvedBy)
+		assert.Equal(t, *first.ResolvedBy, *second.ResolvedBy)
+	})
+
+	s.T().Run("resolve a reply", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Comments(1))
+		reply := newComment(fxt.WorkItems[0].ID, "reply", "")
+		reply.ParentCommentID = &fxt.Comments[0].ID
+		s.createComment(reply, fxt.Identities[0].ID)
+		// when
+		_, err := s.repo.Resolve(s.Ctx, reply.ID, fxt.Identities[0].ID)
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+
+	s.T().Run("resolve an unknown comment", func(t *testing.T) {
+		// when
+		_, err := s.repo.Resolve(s.Ctx, uuid.NewV4(), uuid.NewV4())
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
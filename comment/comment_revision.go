@@ -18,6 +18,10 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a comment update
 	RevisionTypeUpdate // 4
+	// RevisionTypeResolve the thread of a comment was marked as resolved
+	RevisionTypeResolve // 5
+	// RevisionTypeUnresolve the thread of a comment was marked as unresolved
+	RevisionTypeUnresolve // 6
 )
 
 // Revision represents a version of a comment
@@ -27,12 +31,16 @@ type Revision struct {
 	Time time.Time `gorm:"column:revision_time"`
 	// the type of modification
 	Type RevisionType `gorm:"column:revision_type"`
-	// the identity of author of the comment modification
+	// the identity of author of the comment modification (for the resolve and
+	// unresolve revisions the identity that resolved or unresolved the thread)
 	ModifierIdentity uuid.UUID `sql:"type:uuid" gorm:"column:modifier_id"`
 	// the id of the comment that changed
 	CommentID uuid.UUID `gorm:"column:comment_id"`
 	// the id of the parent of the comment that changed
 	CommentParentID uuid.UUID `gorm:"column:comment_parent_id"`
+	// the id of the top-level comment of the thread when the comment that
+	// changed is a reply
+	CommentParentCommentID *uuid.UUID `gorm:"column:comment_parent_comment_id"`
 	// the body of the comment (nil when comment was deleted)
 	CommentBody *string `gorm:"column:comment_body"`
 	// the markup used to input the comment body (nil when comment was deleted)
@@ -18,6 +18,8 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, comment Comment) error
 	// List retrieves all revisions for a given comment
 	List(ctx context.Context, workitemID uuid.UUID) ([]Revision, error)
+	// ListForParent retrieves all revisions of the comments of a given parent (e.g. a work item)
+	ListForParent(ctx context.Context, parentID uuid.UUID) ([]Revision, error)
 }
 
 // NewRevisionRepository creates a GormCommentRevisionRepository
@@ -39,13 +41,14 @@ func (r *GormCommentRevisionRepository) Create(ctx context.Context, modifierID u
 	}, "Storing a revision after operation on comment.")
 	tx := r.db
 	revision := &Revision{
-		ModifierIdentity: modifierID,
-		Time:             time.Now(),
-		Type:             revisionType,
-		CommentID:        c.ID,
-		CommentParentID:  c.ParentID,
-		CommentBody:      &c.Body,
-		CommentMarkup:    &c.Markup,
+		ModifierIdentity:       modifierID,
+		Time:                   time.Now(),
+		Type:                   revisionType,
+		CommentID:              c.ID,
+		CommentParentID:        c.ParentID,
+		CommentBody:            &c.Body,
+		CommentMarkup:          &c.Markup,
+		CommentParentCommentID: c.ParentCommentID,
 	}
 	if revision.Type == RevisionTypeDelete {
 		revision.CommentBody = nil
@@ -68,3 +71,13 @@ func (r *GormCommentRevisionRepository) List(ctx context.Context, commentID uuid
 	}
 	return revisions, nil
 }
+
+// ListForParent retrieves all revisions of the comments of a given parent (e.g. a work item)
+func (r *GormCommentRevisionRepository) ListForParent(ctx context.Context, parentID uuid.UUID) ([]Revision, error) {
+	log.Debug(nil, map[string]interface{}{}, "List all revisions for comments of parent with ID=%v", parentID.String())
+	var revisions []Revision
+	if err := r.db.Where("comment_parent_id = ?", parentID.String()).Order("revision_time asc").Find(&revisions).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to retrieve comment revisions"))
+	}
+	return revisions, nil
+}
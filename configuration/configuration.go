@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -106,6 +107,46 @@ const (
 	varNotificationServiceURL   = "notification.serviceurl"
 	varTogglesServiceURL        = "toggles.serviceurl"
 	varDeploymentsHTTPTimeout   = "deployments.http.timeout"
+
+	varNotificationDispatchInterval    = "notification.dispatch.interval"
+	varNotificationDispatchBatchSize   = "notification.dispatch.batchsize"
+	varNotificationMaxDeliveryAttempts = "notification.delivery.maxattempts"
+	varNotificationRetryBackoff        = "notification.delivery.backoff"
+	varNotificationMaxRetryBackoff     = "notification.delivery.maxbackoff"
+	varNotificationDeliveryTimeout     = "notification.delivery.timeout"
+	varNotificationServiceToken        = "notification.servicetoken"
+	varNotificationOutboxRetention     = "notification.outbox.retention"
+
+	varReminderInterval      = "reminder.interval"
+	varReminderLeadTimes     = "reminder.leadtimes"
+	varReminderOverdueMaxAge = "reminder.overdue.maxage"
+	varReminderBatchSize     = "reminder.batchsize"
+
+	varWebhookDispatchInterval      = "webhook.dispatch.interval"
+	varWebhookDispatchBatchSize     = "webhook.dispatch.batchsize"
+	varWebhookMaxDeliveryAttempts   = "webhook.delivery.maxattempts"
+	varWebhookRetryBackoff          = "webhook.delivery.backoff"
+	varWebhookMaxRetryBackoff       = "webhook.delivery.maxbackoff"
+	varWebhookHTTPTimeout           = "webhook.http.timeout"
+	varWebhookAllowPrivateAddresses = "webhook.allowprivateaddresses"
+
+	varSpaceEventsPollInterval = "space.events.pollinterval"
+	varSpaceEventsBatchSize    = "space.events.batchsize"
+	varSpaceEventsHeartbeat    = "space.events.heartbeat"
+
+	varWorkItemBulkUpdateLimit = "workitem.bulkupdate.limit"
+	varWorkItemScheduleLimit   = "workitem.schedule.limit"
+
+	varAttachmentMaxSize          = "attachment.maxsize"
+	varAttachmentAllowedMIMETypes = "attachment.mimetypes"
+	varAttachmentStore            = "attachment.store"
+	varAttachmentLocalDir         = "attachment.local.dir"
+	varAttachmentS3Endpoint       = "attachment.s3.endpoint"
+	varAttachmentS3Bucket         = "attachment.s3.bucket"
+	varAttachmentS3Region         = "attachment.s3.region"
+	varAttachmentS3AccessKey      = "attachment.s3.accesskey"
+	varAttachmentS3SecretKey      = "attachment.s3.secretkey"
+	varAttachmentOrphanRetention  = "attachment.orphan.retention"
 )
 
 // Registry encapsulates the Viper configuration registry which stores the
@@ -243,6 +284,57 @@ func (c *Registry) setConfigDefaults() {
 	c.v.SetDefault(varCheStarterURL, defaultCheStarterURL)
 	c.v.SetDefault(varTogglesServiceURL, defaultTogglesServiceURL)
 	c.v.SetDefault(varDeploymentsHTTPTimeout, defaultDeploymentsHTTPTimeout)
+
+	// Notification outbox
+	c.v.SetDefault(varNotificationDispatchInterval, time.Duration(5*time.Second))
+	c.v.SetDefault(varNotificationDispatchBatchSize, 50)
+	c.v.SetDefault(varNotificationMaxDeliveryAttempts, 10)
+	c.v.SetDefault(varNotificationRetryBackoff, time.Duration(10*time.Second))
+	c.v.SetDefault(varNotificationMaxRetryBackoff, time.Duration(1*time.Hour))
+	c.v.SetDefault(varNotificationDeliveryTimeout, time.Duration(30*time.Second))
+	c.v.SetDefault(varNotificationOutboxRetention, time.Duration(7*24*time.Hour))
+
+	// Due date reminders
+	c.v.SetDefault(varReminderInterval, time.Duration(1*time.Minute))
+	c.v.SetDefault(varReminderLeadTimes, []string{"24h"})
+	c.v.SetDefault(varReminderOverdueMaxAge, time.Duration(24*time.Hour))
+	c.v.SetDefault(varReminderBatchSize, 100)
+
+	// Webhooks
+	c.v.SetDefault(varWebhookDispatchInterval, time.Duration(5*time.Second))
+	c.v.SetDefault(varWebhookDispatchBatchSize, 50)
+	c.v.SetDefault(varWebhookMaxDeliveryAttempts, 8)
+	c.v.SetDefault(varWebhookRetryBackoff, time.Duration(30*time.Second))
+	c.v.SetDefault(varWebhookMaxRetryBackoff, time.Duration(6*time.Hour))
+	c.v.SetDefault(varWebhookHTTPTimeout, time.Duration(10*time.Second))
+
+	// Space activity stream
+	c.v.SetDefault(varSpaceEventsPollInterval, time.Duration(1*time.Second))
+	c.v.SetDefault(varSpaceEventsBatchSize, 100)
+	c.v.SetDefault(varSpaceEventsHeartbeat, time.Duration(15*time.Second))
+
+	// Bulk update of work items
+	c.v.SetDefault(varWorkItemBulkUpdateLimit, 500)
+
+	// Schedule of work items
+	c.v.SetDefault(varWorkItemScheduleLimit, 500)
+
+	// Attachments
+	c.v.SetDefault(varAttachmentMaxSize, int64(10*1024*1024))
+	c.v.SetDefault(varAttachmentAllowedMIMETypes, []string{
+		"image/*",
+		"text/plain",
+		"text/csv",
+		"application/pdf",
+		"application/json",
+		"application/xml",
+		"application/zip",
+		"application/gzip",
+		"application/x-gzip",
+	})
+	c.v.SetDefault(varAttachmentStore, "local")
+	c.v.SetDefault(varAttachmentS3Region, "us-east-1")
+	c.v.SetDefault(varAttachmentOrphanRetention, time.Duration(24*time.Hour))
 }
 
 // GetPostgresHost returns the postgres host as set via default, config file, or environment variable
@@ -829,6 +921,163 @@ func (c *Registry) GetNotificationServiceURL() string {
 	return c.v.GetString(varNotificationServiceURL)
 }
 
+// GetNotificationDispatchInterval returns the interval in which the notification
+// outbox is checked for messages that are due for delivery
+func (c *Registry) GetNotificationDispatchInterval() time.Duration {
+	return c.v.GetDuration(varNotificationDispatchInterval)
+}
+
+// GetNotificationDispatchBatchSize returns the maximum number of outbox messages
+// delivered in one go
+func (c *Registry) GetNotificationDispatchBatchSize() int {
+	return c.v.GetInt(varNotificationDispatchBatchSize)
+}
+
+// GetNotificationMaxDeliveryAttempts returns the number of failed delivery
+// attempts after which a notification is dead lettered
+func (c *Registry) GetNotificationMaxDeliveryAttempts() int {
+	return c.v.GetInt(varNotificationMaxDeliveryAttempts)
+}
+
+// GetNotificationRetryBackoff returns the delay before the first retry of a
+// failed notification delivery; the delay doubles with each further attempt
+func (c *Registry) GetNotificationRetryBackoff() time.Duration {
+	return c.v.GetDuration(varNotificationRetryBackoff)
+}
+
+// GetNotificationMaxRetryBackoff returns the upper bound of the delay between
+// two delivery attempts of a notification
+func (c *Registry) GetNotificationMaxRetryBackoff() time.Duration {
+	return c.v.GetDuration(varNotificationMaxRetryBackoff)
+}
+
+// GetNotificationDeliveryTimeout returns the time after which a request to the
+// Notification service is given up
+func (c *Registry) GetNotificationDeliveryTimeout() time.Duration {
+	return c.v.GetDuration(varNotificationDeliveryTimeout)
+}
+
+// GetNotificationServiceToken returns the token of the service account used to
+// authenticate with the Notification service
+func (c *Registry) GetNotificationServiceToken() string {
+	return c.v.GetString(varNotificationServiceToken)
+}
+
+// GetNotificationOutboxRetention returns the time for which delivered and
+// dead lettered notifications are kept in the outbox, which is also the time
+// for which the event stream of a space can be resumed
+func (c *Registry) GetNotificationOutboxRetention() time.Duration {
+	return c.v.GetDuration(varNotificationOutboxRetention)
+}
+
+// GetReminderInterval returns the interval in which the due dates of the work
+// items are checked for reminders to send
+func (c *Registry) GetReminderInterval() time.Duration {
+	return c.v.GetDuration(varReminderInterval)
+}
+
+// GetReminderLeadTimes returns how long before the due date of a work item
+// the "due soon" reminders are sent, one reminder per lead time. Invalid
+// durations are skipped.
+func (c *Registry) GetReminderLeadTimes() []time.Duration {
+	var leadTimes []time.Duration
+	for _, s := range c.v.GetStringSlice(varReminderLeadTimes) {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			log.WithFields(map[string]interface{}{
+				"lead_time": s,
+			}).Warnln("Ignoring invalid reminder lead time")
+			continue
+		}
+		leadTimes = append(leadTimes, d)
+	}
+	return leadTimes
+}
+
+// GetReminderOverdueMaxAge returns for how long after the due date of a work
+// item the "overdue" reminder is sent. Work items that are overdue for longer,
+// e.g. when the reminders are enabled for the first time, get no reminder.
+func (c *Registry) GetReminderOverdueMaxAge() time.Duration {
+	return c.v.GetDuration(varReminderOverdueMaxAge)
+}
+
+// GetReminderBatchSize returns the maximum number of reminders sent in one go
+func (c *Registry) GetReminderBatchSize() int {
+	return c.v.GetInt(varReminderBatchSize)
+}
+
+// GetWebhookDispatchInterval returns the interval in which pending webhook
+// deliveries are checked
+func (c *Registry) GetWebhookDispatchInterval() time.Duration {
+	return c.v.GetDuration(varWebhookDispatchInterval)
+}
+
+// GetWebhookDispatchBatchSize returns the maximum number of webhook deliveries
+// sent in one go
+func (c *Registry) GetWebhookDispatchBatchSize() int {
+	return c.v.GetInt(varWebhookDispatchBatchSize)
+}
+
+// GetWebhookMaxDeliveryAttempts returns the number of failed attempts after
+// which a webhook delivery is given up
+func (c *Registry) GetWebhookMaxDeliveryAttempts() int {
+	return c.v.GetInt(varWebhookMaxDeliveryAttempts)
+}
+
+// GetWebhookRetryBackoff returns the delay before the first retry of a failed
+// webhook delivery; the delay doubles with each further attempt
+func (c *Registry) GetWebhookRetryBackoff() time.Duration {
+	return c.v.GetDuration(varWebhookRetryBackoff)
+}
+
+// GetWebhookMaxRetryBackoff returns the upper bound of the delay between two
+// attempts of a webhook delivery
+func (c *Registry) GetWebhookMaxRetryBackoff() time.Duration {
+	return c.v.GetDuration(varWebhookMaxRetryBackoff)
+}
+
+// GetWebhookHTTPTimeout returns the timeout of the requests sent to webhooks
+func (c *Registry) GetWebhookHTTPTimeout() time.Duration {
+	return c.v.GetDuration(varWebhookHTTPTimeout)
+}
+
+// GetWebhookAllowPrivateAddresses returns true if webhooks may be sent to
+// loopback, link-local and private addresses, which is only meant for
+// development and tests
+func (c *Registry) GetWebhookAllowPrivateAddresses() bool {
+	return c.v.GetBool(varWebhookAllowPrivateAddresses)
+}
+
+// GetSpaceEventsPollInterval returns the interval in which the activity
+// stream of a space checks for new events
+func (c *Registry) GetSpaceEventsPollInterval() time.Duration {
+	return c.v.GetDuration(varSpaceEventsPollInterval)
+}
+
+// GetSpaceEventsBatchSize returns the maximum number of events pushed to the
+// activity stream of a space in one go
+func (c *Registry) GetSpaceEventsBatchSize() int {
+	return c.v.GetInt(varSpaceEventsBatchSize)
+}
+
+// GetSpaceEventsHeartbeat returns the interval in which a comment is sent on
+// an idle activity stream to keep the connection open
+func (c *Registry) GetSpaceEventsHeartbeat() time.Duration {
+	return c.v.GetDuration(varSpaceEventsHeartbeat)
+}
+
+// GetWorkItemBulkUpdateLimit returns the maximum number of work items that
+// can be modified with a single bulk update
+func (c *Registry) GetWorkItemBulkUpdateLimit() int {
+	return c.v.GetInt(varWorkItemBulkUpdateLimit)
+}
+
+// GetWorkItemScheduleLimit returns the maximum number of work items that can
+// be scheduled at once by their IDs or a filter
+func (c *Registry) GetWorkItemScheduleLimit() int {
+	return c.v.GetInt(varWorkItemScheduleLimit)
+}
+
 // GetTogglesServiceURL returns the URL for the Feature Toggles service used enabling/disabling features per user
 func (c *Registry) GetTogglesServiceURL() string {
 	return c.v.GetString(varTogglesServiceURL)
@@ -917,3 +1166,73 @@ OCCAgsB8g8yTB4qntAYyfofEoDiseKrngQT5DSdxd51A/jw7B8WyBK8=
 
 // ActualToken is actual OAuth access token of github
 var defaultActualToken = strings.Split(camouflagedAccessToken, "-AccessToken-")[0] + strings.Split(camouflagedAccessToken, "-AccessToken-")[1]
+
+// GetAttachmentMaxSize returns the maximum size in bytes of a file that is
+// attached to a work item or a comment
+func (c *Registry) GetAttachmentMaxSize() int64 {
+	return c.v.GetInt64(varAttachmentMaxSize)
+}
+
+// GetAttachmentAllowedMIMETypes returns the MIME types of the files that can
+// be attached to work items and comments. A type can end with a wildcard
+// (e.g. "image/*").
+func (c *Registry) GetAttachmentAllowedMIMETypes() []string {
+	return c.v.GetStringSlice(varAttachmentAllowedMIMETypes)
+}
+
+// GetAttachmentStore returns the kind of blob store that holds the content of
+// the attachments, either "local" or "s3"
+func (c *Registry) GetAttachmentStore() string {
+	return c.v.GetString(varAttachmentStore)
+}
+
+// GetAttachmentLocalDir returns the directory in which the local blob store
+// keeps the content of the attachments. There is no default directory, the
+// local blob store cannot be created unless one is set. Only in developer mode
+// the attachments are kept in the temporary directory.
+func (c *Registry) GetAttachmentLocalDir() string {
+	if c.v.IsSet(varAttachmentLocalDir) {
+		return c.v.GetString(varAttachmentLocalDir)
+	}
+	if c.IsPostgresDeveloperModeEnabled() {
+		return filepath.Join(os.TempDir(), "fabric8-wit-attachments")
+	}
+	return ""
+}
+
+// GetAttachmentS3Endpoint returns the URL of the S3-compatible object storage
+// that holds the content of the attachments
+func (c *Registry) GetAttachmentS3Endpoint() string {
+	return c.v.GetString(varAttachmentS3Endpoint)
+}
+
+// GetAttachmentS3Bucket returns the bucket that holds the content of the
+// attachments
+func (c *Registry) GetAttachmentS3Bucket() string {
+	return c.v.GetString(varAttachmentS3Bucket)
+}
+
+// GetAttachmentS3Region returns the region of the bucket that holds the
+// content of the attachments
+func (c *Registry) GetAttachmentS3Region() string {
+	return c.v.GetString(varAttachmentS3Region)
+}
+
+// GetAttachmentS3AccessKey returns the access key used to sign the requests
+// sent to the object storage
+func (c *Registry) GetAttachmentS3AccessKey() string {
+	return c.v.GetString(varAttachmentS3AccessKey)
+}
+
+// GetAttachmentS3SecretKey returns the secret key used to sign the requests
+// sent to the object storage
+func (c *Registry) GetAttachmentS3SecretKey() string {
+	return c.v.GetString(varAttachmentS3SecretKey)
+}
+
+// GetAttachmentOrphanRetention returns the time for which the attachments of
+// deleted work items and comments are kept, so that they come back when the
+// work item or comment is restored in time
+func (c *Registry) GetAttachmentOrphanRetention() time.Duration {
+	return c.v.GetDuration(varAttachmentOrphanRetention)
+}
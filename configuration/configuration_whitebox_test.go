@@ -198,3 +198,17 @@ func TestDeploymentsTimeoutIsAlwaysPositive(t *testing.T) {
 
 	resetConfiguration()
 }
+
+func TestAttachmentLocalDirIsOnlyDefaultedInDevMode(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	defer resetConfiguration()
+
+	config.v.Set(varDeveloperModeEnabled, false)
+	assert.Equal(t, "", config.GetAttachmentLocalDir())
+
+	config.v.Set(varDeveloperModeEnabled, true)
+	assert.NotEqual(t, "", config.GetAttachmentLocalDir())
+
+	config.v.Set(varAttachmentLocalDir, "/var/lib/fabric8-wit/attachments")
+	assert.Equal(t, "/var/lib/fabric8-wit/attachments", config.GetAttachmentLocalDir())
+}
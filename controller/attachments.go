@@ -0,0 +1,477 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// AttachmentsControllerConfiguration the configuration for the controllers of
+// the attachments of work items and comments
+type AttachmentsControllerConfiguration interface {
+	GetAttachmentMaxSize() int64
+	GetAttachmentAllowedMIMETypes() []string
+}
+
+// multipartOverhead is the room left in the request body next to the content
+// of an attachment for the headers and boundaries of the multipart form
+const multipartOverhead = 64 * 1024
+
+// maxAttachmentRequestLength returns the maximum length of the body of an
+// upload request. Larger bodies are cut off while they are read, so that they
+// are neither buffered in memory nor spooled to disk.
+func maxAttachmentRequestLength(config AttachmentsControllerConfiguration) int64 {
+	return config.GetAttachmentMaxSize() + multipartOverhead
+}
+
+// attachments implements the actions that the controllers of the attachments
+// of work items and comments have in common
+type attachments struct {
+	db     application.DB
+	store  attachment.BlobStore
+	config AttachmentsControllerConfiguration
+}
+
+// WorkItemAttachmentsController implements the work_item_attachments resource.
+type WorkItemAttachmentsController struct {
+	*goa.Controller
+	attachments
+}
+
+// NewWorkItemAttachmentsController creates a work_item_attachments controller.
+func NewWorkItemAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config AttachmentsControllerConfiguration) *WorkItemAttachmentsController {
+	ctrl := &WorkItemAttachmentsController{
+		Controller:  service.NewController("WorkItemAttachmentsController"),
+		attachments: attachments{db: db, store: store, config: config},
+	}
+	ctrl.MaxRequestBodyLength = maxAttachmentRequestLength(config)
+	return ctrl
+}
+
+// List runs the list action.
+func (c *WorkItemAttachmentsController) List(ctx *app.ListWorkItemAttachmentsContext) error {
+	res, err := c.list(ctx, ctx.Request, attachment.ParentTypeWorkItem, ctx.WiID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// Upload runs the upload action.
+func (c *WorkItemAttachmentsController) Upload(ctx *app.UploadWorkItemAttachmentsContext) error {
+	att, err := c.upload(ctx, attachment.ParentTypeWorkItem, ctx.WiID, ctx.Payload.File)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.AttachmentSingle{
+		Data: ConvertAttachment(ctx.Request, *att),
+	}
+	ctx.ResponseData.Header().Set("Location", *res.Data.Links.Self)
+	return ctx.Created(res)
+}
+
+// Download runs the download action.
+func (c *WorkItemAttachmentsController) Download(ctx *app.DownloadWorkItemAttachmentsContext) error {
+	if err := c.download(ctx, ctx.ResponseData, attachment.ParentTypeWorkItem, ctx.WiID, ctx.AttachmentID); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return nil
+}
+
+// Delete runs the delete action.
+func (c *WorkItemAttachmentsController) Delete(ctx *app.DeleteWorkItemAttachmentsContext) error {
+	if err := c.delete(ctx, attachment.ParentTypeWorkItem, ctx.WiID, ctx.AttachmentID); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// CommentAttachmentsController implements the comment_attachments resource.
+type CommentAttachmentsController struct {
+	*goa.Controller
+	attachments
+}
+
+// NewCommentAttachmentsController creates a comment_attachments controller.
+func NewCommentAttachmentsController(service *goa.Service, db application.DB, store attachment.BlobStore, config AttachmentsControllerConfiguration) *CommentAttachmentsController {
+	ctrl := &CommentAttachmentsController{
+		Controller:  service.NewController("CommentAttachmentsController"),
+		attachments: attachments{db: db, store: store, config: config},
+	}
+	ctrl.MaxRequestBodyLength = maxAttachmentRequestLength(config)
+	return ctrl
+}
+
+// List runs the list action.
+func (c *CommentAttachmentsController) List(ctx *app.ListCommentAttachmentsContext) error {
+	res, err := c.list(ctx, ctx.Request, attachment.ParentTypeComment, ctx.CommentID)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(res)
+}
+
+// Upload runs the upload action.
+func (c *CommentAttachmentsController) Upload(ctx *app.UploadCommentAttachmentsContext) error {
+	att, err := c.upload(ctx, attachment.ParentTypeComment, ctx.CommentID, ctx.Payload.File)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.AttachmentSingle{
+		Data: ConvertAttachment(ctx.Request, *att),
+	}
+	ctx.ResponseData.Header().Set("Location", *res.Data.Links.Self)
+	return ctx.Created(res)
+}
+
+// Download runs the download action.
+func (c *CommentAttachmentsController) Download(ctx *app.DownloadCommentAttachmentsContext) error {
+	if err := c.download(ctx, ctx.ResponseData, attachment.ParentTypeComment, ctx.CommentID, ctx.AttachmentID); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return nil
+}
+
+// Delete runs the delete action.
+func (c *CommentAttachmentsController) Delete(ctx *app.DeleteCommentAttachmentsContext) error {
+	if err := c.delete(ctx, attachment.ParentTypeComment, ctx.CommentID, ctx.AttachmentID); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// loadAttachmentParentSpace returns the ID of the space of the given work
+// item or comment
+func loadAttachmentParentSpace(ctx context.Context, appl application.Application, parentType string, parentID uuid.UUID) (uuid.UUID, error) {
+	workItemID := parentID
+	if parentType == attachment.ParentTypeComment {
+		cm, err := appl.Comments().Load(ctx, parentID)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		workItemID = cm.ParentID
+	}
+	wi, err := appl.WorkItems().LoadByID(ctx, workItemID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return wi.SpaceID, nil
+}
+
+// loadAttachment loads the attachment with the given ID and makes sure that
+// it belongs to the given work item or comment
+func loadAttachment(ctx context.Context, appl application.Application, parentType string, parentID, id uuid.UUID) (*attachment.Attachment, error) {
+	att, err := appl.Attachments().Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if att.ParentType != parentType || !uuid.Equal(att.ParentID, parentID) {
+		return nil, errors.NewNotFoundError("attachment", id.String())
+	}
+	return att, nil
+}
+
+func (c *attachments) list(ctx context.Context, request *http.Request, parentType string, parentID uuid.UUID) (*app.AttachmentList, error) {
+	var list []attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := loadAttachmentParentSpace(ctx, appl, parentType, parentID); err != nil {
+			return err
+		}
+		var err error
+		list, err = appl.Attachments().List(ctx, parentType, parentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := &app.AttachmentList{
+		Data: make([]*app.Attachment, len(list)),
+	}
+	for i, att := range list {
+		res.Data[i] = ConvertAttachment(request, att)
+	}
+	return res, nil
+}
+
+func (c *attachments) upload(ctx context.Context, parentType string, parentID uuid.UUID, fh *multipart.FileHeader) (*attachment.Attachment, error) {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+	var spaceID uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		spaceID, err = loadAttachmentParentSpace(ctx, appl, parentType, parentID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	authorized, err := authz.Authorize(ctx, spaceID.String())
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+	if !authorized {
+		return nil, errors.NewForbiddenError("user is not a space collaborator")
+	}
+	if fh == nil {
+		return nil, errors.NewBadParameterError("file", nil).Expected("a file")
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return nil, errors.NewBadParameterError("file", fh.Filename).Expected("a readable file")
+	}
+	defer f.Close()
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if size == 0 {
+		return nil, errors.NewBadParameterError("file", fh.Filename).Expected("a non-empty file")
+	}
+	if maxSize := c.config.GetAttachmentMaxSize(); size > maxSize {
+		return nil, errors.NewBadParameterError("file", fmt.Sprintf("%d bytes", size)).Expected(fmt.Sprintf("a file of at most %d bytes", maxSize))
+	}
+	mimeType, err := attachmentMIMEType(f, fh.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if !mimeTypeAllowed(mimeType, c.config.GetAttachmentAllowedMIMETypes()) {
+		return nil, errors.NewBadParameterError("file", mimeType).Expected(strings.Join(c.config.GetAttachmentAllowedMIMETypes(), ", "))
+	}
+	att := attachment.Attachment{
+		ID:         uuid.NewV4(),
+		ParentType: parentType,
+		ParentID:   parentID,
+		SpaceID:    spaceID,
+		FileName:   attachmentFileName(fh.Filename),
+		MIMEType:   mimeType,
+		Size:       size,
+		Creator:    *currentUser,
+	}
+	att.BlobKey = attachment.BlobKeyFor(parentType, parentID, att.ID)
+	if err := c.store.Put(ctx, att.BlobKey, f, size, mimeType); err != nil {
+		return nil, err
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Create(ctx, &att)
+	})
+	if err != nil {
+		// don't keep the content of attachments that don't exist
+		if deleteErr := c.store.Delete(ctx, att.BlobKey); deleteErr != nil {
+			log.Error(ctx, map[string]interface{}{
+				"err":      deleteErr,
+				"blob_key": att.BlobKey,
+			}, "unable to delete the content of a failed upload")
+		}
+		return nil, err
+	}
+	return &att, nil
+}
+
+func (c *attachments) download(ctx context.Context, rw http.ResponseWriter, parentType string, parentID, id uuid.UUID) error {
+	var att *attachment.Attachment
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		att, err = loadAttachment(ctx, appl, parentType, parentID, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	content, err := c.store.Get(ctx, att.BlobKey)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	rw.Header().Set("Content-Type", att.MIMEType)
+	rw.Header().Set("Content-Length", strconv.FormatInt(att.Size, 10))
+	rw.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": att.FileName}))
+	// browsers must not guess another type (e.g. HTML) from the content
+	rw.Header().Set("X-Content-Type-Options", "nosniff")
+	rw.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(rw, content); err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err":           err,
+			"attachment_id": att.ID,
+		}, "unable to send the content of the attachment")
+	}
+	return nil
+}
+
+func (c *attachments) delete(ctx context.Context, parentType string, parentID, id uuid.UUID) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return errors.NewUnauthorizedError(err.Error())
+	}
+	var att *attachment.Attachment
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		att, err = loadAttachment(ctx, appl, parentType, parentID, id)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// User is allowed to delete if user is creator of the attachment OR user
+	// is a space collaborator
+	if !uuid.Equal(*currentUser, att.Creator) {
+		authorized, err := authz.Authorize(ctx, att.SpaceID.String())
+		if err != nil {
+			return errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return errors.NewForbiddenError("user is not a space collaborator")
+		}
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.Attachments().Delete(ctx, att.ID)
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.store.Delete(ctx, att.BlobKey); err != nil {
+		// the attachment is gone for the users, only storage is wasted
+		log.Error(ctx, map[string]interface{}{
+			"err":           err,
+			"attachment_id": att.ID,
+			"blob_key":      att.BlobKey,
+		}, "unable to delete the content of the attachment")
+	}
+	return nil
+}
+
+// attachmentMIMEType returns the MIME type declared for the uploaded file. If
+// none (or only the generic binary type) was declared, the type is detected
+// from the content.
+func attachmentMIMEType(f multipart.File, declared string) (string, error) {
+	if declared != "" {
+		if mediaType, _, err := mime.ParseMediaType(declared); err == nil && mediaType != "application/octet-stream" {
+			return mediaType, nil
+		}
+	}
+	buf := make([]byte, 512)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	if err != nil {
+		return "application/octet-stream", nil
+	}
+	return mediaType, nil
+}
+
+// mimeTypeAllowed returns true if the given MIME type matches one of the
+// allowed types. An allowed type can end with a wildcard (e.g. "image/*").
+func mimeTypeAllowed(mimeType string, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "*/*" || a == mimeType {
+			return true
+		}
+		if strings.HasSuffix(a, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(a, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// attachmentFileName strips the directories some browsers send along with
+// the name of the uploaded file
+func attachmentFileName(name string) string {
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "attachment"
+	}
+	return name
+}
+
+// ConvertAttachment converts from internal to external REST representation
+func ConvertAttachment(request *http.Request, att attachment.Attachment) *app.Attachment {
+	parentHref := app.WorkitemHref(att.ParentID)
+	if att.ParentType == attachment.ParentTypeComment {
+		parentHref = app.CommentsHref(att.ParentID)
+	}
+	selfURL := rest.AbsoluteURL(request, parentHref+"/attachments/"+att.ID.String())
+	return &app.Attachment{
+		Type: attachment.APIStringTypeAttachments,
+		ID:   &att.ID,
+		Attributes: &app.AttachmentAttributes{
+			FileName:  &att.FileName,
+			MimeType:  &att.MIMEType,
+			Size:      ptr.Int(int(att.Size)),
+			CreatedAt: &att.CreatedAt,
+		},
+		Relationships: &app.AttachmentRelations{
+			Creator: &app.RelationGeneric{
+				Data: ConvertUserSimple(request, att.Creator),
+			},
+			Parent: genericRelation(request, att.ParentType, att.ParentID.String(), parentHref),
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+// workItemIncludeAttachments adds the relationship to the files attached to
+// the work item
+func workItemIncludeAttachments(ctx context.Context, db application.DB) WorkItemConvertFunc {
+	return func(request *http.Request, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		related := rest.AbsoluteURL(request, app.WorkitemHref(wi.ID)) + "/attachments"
+		wi2.Relationships.Attachments = &app.RelationGenericList{
+			Data: []*app.GenericData{},
+			Links: &app.GenericLinks{
+				Related: &related,
+			},
+		}
+		err := application.Transactional(db, func(appl application.Application) error {
+			list, err := appl.Attachments().List(ctx, attachment.ParentTypeWorkItem, wi.ID)
+			if err != nil {
+				return err
+			}
+			for _, att := range list {
+				self := related + "/" + att.ID.String()
+				wi2.Relationships.Attachments.Data = append(wi2.Relationships.Attachments.Data, &app.GenericData{
+					Type:  ptr.String(attachment.APIStringTypeAttachments),
+					ID:    ptr.String(att.ID.String()),
+					Links: &app.GenericLinks{Self: &self},
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id": wi.ID,
+				"err":   err,
+			}, "unable to list the attachments of the work item")
+		}
+	}
+}
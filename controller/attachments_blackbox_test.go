@@ -0,0 +1,205 @@
+package controller_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	"github.com/fabric8-services/fabric8-wit/attachment"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type attachmentsConfig struct {
+	maxSize int64
+}
+
+func (c attachmentsConfig) GetAttachmentMaxSize() int64 {
+	return c.maxSize
+}
+
+func (c attachmentsConfig) GetAttachmentAllowedMIMETypes() []string {
+	return []string{"text/plain", "image/*"}
+}
+
+type TestAttachmentsREST struct {
+	gormtestsupport.DBTestSuite
+	dir   string
+	store attachment.BlobStore
+}
+
+func TestRunAttachmentsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestAttachmentsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestAttachmentsREST) SetupTest() {
+	rest.DBTestSuite.SetupTest()
+	var err error
+	rest.dir, err = ioutil.TempDir("", "attachments")
+	require.NoError(rest.T(), err)
+	rest.store, err = attachment.NewLocalBlobStore(rest.dir)
+	require.NoError(rest.T(), err)
+}
+
+func (rest *TestAttachmentsREST) TearDownTest() {
+	os.RemoveAll(rest.dir)
+	rest.DBTestSuite.TearDownTest()
+}
+
+func (rest *TestAttachmentsREST) newController(svc *goa.Service) *WorkItemAttachmentsController {
+	return NewWorkItemAttachmentsController(svc, gormapplication.NewGormDB(rest.DB), rest.store, attachmentsConfig{maxSize: 1024})
+}
+
+// multipartBody returns a multipart/form-data body that holds the given
+// content as the "file" field, and the content type of the body
+func multipartBody(t *testing.T, fileName, mimeType string, content []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%s"`, fileName))
+	h.Set("Content-Type", mimeType)
+	part, err := w.CreatePart(h)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return body, w.FormDataContentType()
+}
+
+// newUploadPayload returns the payload to upload a file with the given content
+func newUploadPayload(t *testing.T, fileName, mimeType string, content []byte) *app.AttachmentUpload {
+	body, contentType := multipartBody(t, fileName, mimeType, content)
+	_, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	form, err := multipart.NewReader(body, params["boundary"]).ReadForm(1 << 20)
+	require.NoError(t, err)
+	require.Len(t, form.File["file"], 1)
+	return &app.AttachmentUpload{File: form.File["file"][0]}
+}
+
+func (rest *TestAttachmentsREST) TestUpload() {
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.Identities(2), tf.WorkItems(1))
+	spaceAuthz := &TestSpaceAuthzService{*fxt.Identities[0], ""}
+
+	rest.T().Run("ok - collaborator", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[0], spaceAuthz)
+		ctrl := rest.newController(svc)
+		// when
+		_, created := test.UploadWorkItemAttachmentsCreated(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, newUploadPayload(t, "build.log", "text/plain", []byte("hello")))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, "build.log", *created.Data.Attributes.FileName)
+		assert.Equal(t, "text/plain", *created.Data.Attributes.MimeType)
+		assert.Equal(t, 5, *created.Data.Attributes.Size)
+		t.Run("download", func(t *testing.T) {
+			rw := test.DownloadWorkItemAttachmentsOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, *created.Data.ID)
+			recorder, ok := rw.(*httptest.ResponseRecorder)
+			require.True(t, ok)
+			assert.Equal(t, "hello", recorder.Body.String())
+			assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+			assert.Equal(t, "nosniff", recorder.Header().Get("X-Content-Type-Options"))
+		})
+		t.Run("list", func(t *testing.T) {
+			_, list := test.ListWorkItemAttachmentsOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+			require.Len(t, list.Data, 1)
+			assert.Equal(t, *created.Data.ID, *list.Data[0].ID)
+		})
+	})
+
+	rest.T().Run("bad request - file too large", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[0], spaceAuthz)
+		// when/then
+		test.UploadWorkItemAttachmentsBadRequest(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, newUploadPayload(t, "big.log", "text/plain", bytes.Repeat([]byte("x"), 1025)))
+	})
+
+	rest.T().Run("bad request - mime type not allowed", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[0], spaceAuthz)
+		// when/then
+		test.UploadWorkItemAttachmentsBadRequest(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, newUploadPayload(t, "page.html", "text/html", []byte("<html></html>")))
+	})
+
+	rest.T().Run("request body too large", func(t *testing.T) {
+		// given a request that is larger than the allowed size of a file
+		// together with the multipart overhead
+		svc := goa.New("Attachments-Service")
+		app.MountWorkItemAttachmentsController(svc, rest.newController(svc))
+		body, contentType := multipartBody(t, "huge.log", "text/plain", bytes.Repeat([]byte("x"), 1<<20))
+		req := httptest.NewRequest(http.MethodPost, "/api/workitems/"+fxt.WorkItems[0].ID.String()+"/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		rw := httptest.NewRecorder()
+		// when
+		svc.Mux.ServeHTTP(rw, req)
+		// then the body is rejected while it is read
+		assert.Contains(t, []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge}, rw.Code)
+		list, err := attachment.NewRepository(rest.DB).List(rest.Ctx, attachment.ParentTypeWorkItem, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		for _, att := range list {
+			assert.NotEqual(t, "huge.log", att.FileName)
+		}
+	})
+
+	rest.T().Run("forbidden - not a collaborator", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[1], spaceAuthz)
+		// when/then
+		test.UploadWorkItemAttachmentsForbidden(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, newUploadPayload(t, "build.log", "text/plain", []byte("hello")))
+	})
+
+	rest.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		svc := goa.New("Attachments-Service")
+		// when/then
+		test.UploadWorkItemAttachmentsUnauthorized(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, newUploadPayload(t, "build.log", "text/plain", []byte("hello")))
+	})
+}
+
+func (rest *TestAttachmentsREST) TestDelete() {
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.Identities(2), tf.WorkItems(1))
+	spaceAuthz := &TestSpaceAuthzService{*fxt.Identities[0], ""}
+	upload := func(t *testing.T) *app.AttachmentSingle {
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[0], spaceAuthz)
+		_, created := test.UploadWorkItemAttachmentsCreated(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, newUploadPayload(t, "build.log", "text/plain", []byte("hello")))
+		return created
+	}
+
+	rest.T().Run("ok - creator", func(t *testing.T) {
+		// given
+		created := upload(t)
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[0], spaceAuthz)
+		ctrl := rest.newController(svc)
+		// when
+		test.DeleteWorkItemAttachmentsOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, *created.Data.ID)
+		// then
+		test.DownloadWorkItemAttachmentsNotFound(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, *created.Data.ID)
+	})
+
+	rest.T().Run("forbidden - not a collaborator", func(t *testing.T) {
+		// given
+		created := upload(t)
+		svc := testsupport.ServiceAsSpaceUser("Attachments-Service", *fxt.Identities[1], spaceAuthz)
+		// when/then
+		test.DeleteWorkItemAttachmentsForbidden(t, svc.Context, svc, rest.newController(svc), fxt.WorkItems[0].ID, *created.Data.ID)
+	})
+}
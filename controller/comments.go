@@ -106,7 +106,6 @@ func (c *CommentsController) Update(ctx *app.UpdateCommentsContext) error {
 	res := &app.CommentSingle{
 		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
 	}
-	c.notification.Send(ctx, notification.NewCommentUpdated(cm.ID.String()))
 	return ctx.OK(res)
 }
 
@@ -132,10 +131,23 @@ func (c *CommentsController) loadComment(ctx context.Context, commentID, identit
 
 func (c *CommentsController) performUpdate(ctx *app.UpdateCommentsContext, cm *comment.Comment, identityID *uuid.UUID) error {
 	return application.Transactional(c.db, func(appl application.Application) error {
+		previousMentions := cm.Mentions
 		cm.Body = *ctx.Payload.Data.Attributes.Body
 		cm.Markup = rendering.NilSafeGetMarkup(ctx.Payload.Data.Attributes.Markup)
 		err := appl.Comments().Save(ctx.Context, cm, *identityID)
-		return err
+		if err != nil {
+			return err
+		}
+		wi, err := appl.WorkItems().LoadByID(ctx, cm.ParentID)
+		if err != nil {
+			return err
+		}
+		err = enqueueWorkItemMessage(ctx, appl, c.notification, wi.ID, notification.NewCommentUpdated(cm.ID.String(), wi.SpaceID))
+		if err != nil {
+			return err
+		}
+		// only the users who were not mentioned before are notified
+		return enqueueMentions(ctx, appl, c.notification, "comments", cm.ID.String(), wi.SpaceID, cm.Mentions.Added(previousMentions))
 	})
 }
 
@@ -168,6 +180,110 @@ func (c *CommentsController) Delete(ctx *app.DeleteCommentsContext) error {
 	return ctx.OK([]byte{})
 }
 
+// Restore does POST comment restore
+func (c *CommentsController) Restore(ctx *app.RestoreCommentsContext) error {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	var cm *comment.Comment
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		cm, err = appl.Comments().Restore(ctx.Context, ctx.CommentID, *identityID)
+		if err != nil {
+			return err
+		}
+		// the parent work item must not be deleted
+		wi, err := appl.WorkItems().LoadByID(ctx, cm.ParentID)
+		if err != nil {
+			return err
+		}
+		// User is allowed to restore if user is creator of the comment OR
+		// user is a space collaborator, otherwise the restoration is rolled
+		// back
+		if *identityID != cm.Creator {
+			authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+			if err != nil {
+				return errors.NewUnauthorizedError(err.Error())
+			}
+			if !authorized {
+				return errors.NewForbiddenError("user is not a space collaborator")
+			}
+		}
+		return enqueueWorkItemMessage(ctx, appl, c.notification, wi.ID, notification.NewCommentUpdated(cm.ID.String(), wi.SpaceID))
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.CommentSingle{
+		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
+	}
+	return ctx.OK(res)
+}
+
+// Resolve runs the resolve action.
+func (c *CommentsController) Resolve(ctx *app.ResolveCommentsContext) error {
+	cm, err := c.setResolved(ctx, ctx.CommentID, true)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.CommentSingle{
+		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
+	})
+}
+
+// Unresolve runs the unresolve action.
+func (c *CommentsController) Unresolve(ctx *app.UnresolveCommentsContext) error {
+	cm, err := c.setResolved(ctx, ctx.CommentID, false)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.CommentSingle{
+		Data: ConvertComment(ctx.Request, *cm, CommentIncludeParentWorkItem(ctx, cm)),
+	})
+}
+
+// setResolved marks the thread started by the given comment as resolved or
+// unresolved. The creator of the comment and the space collaborators are
+// allowed to do so.
+func (c *CommentsController) setResolved(ctx context.Context, commentID uuid.UUID, resolved bool) (*comment.Comment, error) {
+	identityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return nil, errors.NewUnauthorizedError(err.Error())
+	}
+	cm, wi, userIsCreator, err := c.loadComment(ctx, commentID, *identityID)
+	if err != nil {
+		return nil, err
+	}
+	if !userIsCreator {
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return nil, errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return nil, errors.NewForbiddenError("user is not a space collaborator")
+		}
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if resolved {
+			cm, err = appl.Comments().Resolve(ctx, cm.ID, *identityID)
+		} else {
+			cm, err = appl.Comments().Unresolve(ctx, cm.ID, *identityID)
+		}
+		if err != nil {
+			return err
+		}
+		wi, err := appl.WorkItems().LoadByID(ctx, cm.ParentID)
+		if err != nil {
+			return err
+		}
+		return enqueueWorkItemMessage(ctx, appl, c.notification, wi.ID, notification.NewCommentUpdated(cm.ID.String(), wi.SpaceID))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cm, nil
+}
+
 // CommentConvertFunc is a open ended function to add additional links/data/relations to a Comment during
 // conversion from internal to API
 type CommentConvertFunc func(*http.Request, *comment.Comment, *app.Comment)
@@ -211,7 +327,7 @@ func ConvertComment(request *http.Request, comment comment.Comment, additional .
 		ID:   &comment.ID,
 		Attributes: &app.CommentAttributes{
 			Body:         &comment.Body,
-			BodyRendered: ptr.String(rendering.RenderMarkupToHTML(html.EscapeString(comment.Body), comment.Markup)),
+			BodyRendered: ptr.String(rendering.RenderMentions(rendering.RenderMarkupToHTML(html.EscapeString(comment.Body), comment.Markup), mentionLinks(request, comment.Mentions))),
 			Markup:       ptr.String(rendering.NilSafeGetMarkup(&comment.Markup)),
 			CreatedAt:    &comment.CreatedAt,
 			UpdatedAt:    &comment.UpdatedAt,
@@ -241,12 +357,45 @@ func ConvertComment(request *http.Request, comment comment.Comment, additional .
 			Related: &relatedURL,
 		},
 	}
+	if comment.ParentCommentID != nil {
+		c.Relationships.ParentComment = genericRelation(request, "comments", comment.ParentCommentID.String(), app.CommentsHref(*comment.ParentCommentID))
+	} else {
+		c.Attributes.Resolved = ptr.Bool(comment.ResolvedAt != nil)
+		c.Attributes.ResolvedAt = comment.ResolvedAt
+		if comment.ResolvedBy != nil {
+			c.Relationships.ResolvedBy = modifierRelation(request, *comment.ResolvedBy)
+		}
+	}
 	for _, add := range additional {
 		add(request, &comment, c)
 	}
 	return c
 }
 
+// CommentIncludeReplies adds the "replies" relationship to the top-level
+// comments, the given replies can belong to several threads
+func CommentIncludeReplies(replies []comment.Comment) CommentConvertFunc {
+	return func(request *http.Request, cm *comment.Comment, data *app.Comment) {
+		if cm.IsReply() {
+			return
+		}
+		data.Relationships.Replies = &app.RelationGenericList{
+			Data: []*app.GenericData{},
+		}
+		for _, reply := range replies {
+			if reply.ParentCommentID != nil && *reply.ParentCommentID == cm.ID {
+				data.Relationships.Replies.Data = append(data.Relationships.Replies.Data, &app.GenericData{
+					Type: ptr.String("comments"),
+					ID:   ptr.String(reply.ID.String()),
+				})
+			}
+		}
+		data.Relationships.Replies.Meta = map[string]interface{}{
+			"totalCount": len(data.Relationships.Replies.Data),
+		}
+	}
+}
+
 // HrefFunc generic function to greate a relative Href to a resource
 type HrefFunc func(id interface{}) string
 
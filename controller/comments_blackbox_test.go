@@ -20,6 +20,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/gormapplication"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
@@ -563,6 +564,40 @@ func (s *CommentsSuite) TestNotificationSendOnUpdate() {
 	assert.Equal(s.T(), c.Data.ID.String(), s.notification.Messages[0].TargetID)
 }
 
+func (s *CommentsSuite) TestNotificationSendOnMention() {
+	// given
+	mentioned, err := testsupport.CreateTestIdentity(s.DB, "mentioned-"+uuid.NewV4().String(), "test provider")
+	require.NoError(s.T(), err)
+	wiID := s.createWorkItem(s.testIdentity)
+	c := s.createWorkItemComment(s.testIdentity, wiID, "body", &plaintextMarkup)
+	// when
+	updateCommentPayload := newUpdateCommentsPayload("ping @"+mentioned.Username, &markdownMarkup)
+	userSvc, _, _, _, commentsCtrl := s.securedControllers(s.testIdentity)
+	_, result := test.UpdateCommentsOK(s.T(), userSvc.Context, userSvc, commentsCtrl, *c.Data.ID, updateCommentPayload)
+	// then
+	require.NotNil(s.T(), result.Data.Attributes.BodyRendered)
+	assert.Contains(s.T(), *result.Data.Attributes.BodyRendered, `class="mention">@`+mentioned.Username+`</a>`)
+	var mentions []notification.Message
+	for _, msg := range s.notification.Messages {
+		if msg.MessageType == "mention" {
+			mentions = append(mentions, msg)
+		}
+	}
+	require.Len(s.T(), mentions, 1)
+	assert.Equal(s.T(), c.Data.ID.String(), mentions[0].TargetID)
+	assert.Equal(s.T(), mentioned.ID.String(), mentions[0].Details["mentioned_id"])
+	// when the comment is updated again the user is not notified again
+	updateCommentPayload = newUpdateCommentsPayload("ping @"+mentioned.Username+" again", &markdownMarkup)
+	test.UpdateCommentsOK(s.T(), userSvc.Context, userSvc, commentsCtrl, *c.Data.ID, updateCommentPayload)
+	count := 0
+	for _, msg := range s.notification.Messages {
+		if msg.MessageType == "mention" {
+			count++
+		}
+	}
+	assert.Equal(s.T(), 1, count)
+}
+
 func CreateSecuredSpace(t *testing.T, db application.DB, config SpaceConfiguration, owner account.Identity, userIDs string) app.Space {
 	svc := testsupport.ServiceAsSpaceUser("Collaborators-Service", owner, &TestSpaceAuthzService{owner: owner, userIDs: userIDs})
 	spaceCtrl := NewSpaceController(svc, db, config, &DummyResourceManager{})
@@ -12,6 +12,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
@@ -25,13 +26,18 @@ import (
 const (
 	KeyTotalWorkItems  = "total"
 	KeyClosedWorkItems = "closed"
+	// the estimates and the time spent (in seconds) of the work items
+	KeyOriginalEstimate  = "original-estimate"
+	KeyRemainingEstimate = "remaining-estimate"
+	KeyTimeSpent         = "time-spent"
 )
 
 // IterationController implements the iteration resource.
 type IterationController struct {
 	*goa.Controller
-	db     application.DB
-	config IterationControllerConfiguration
+	db           application.DB
+	config       IterationControllerConfiguration
+	notification notification.Channel
 }
 
 // IterationControllerConfiguration configuration for the IterationController
@@ -42,7 +48,16 @@ type IterationControllerConfiguration interface {
 
 // NewIterationController creates a iteration controller.
 func NewIterationController(service *goa.Service, db application.DB, config IterationControllerConfiguration) *IterationController {
-	return &IterationController{Controller: service.NewController("IterationController"), db: db, config: config}
+	return NewNotifyingIterationController(service, db, &notification.DevNullChannel{}, config)
+}
+
+// NewNotifyingIterationController creates a iteration controller with notification broadcast.
+func NewNotifyingIterationController(service *goa.Service, db application.DB, notificationChannel notification.Channel, config IterationControllerConfiguration) *IterationController {
+	n := notificationChannel
+	if n == nil {
+		n = &notification.DevNullChannel{}
+	}
+	return &IterationController{Controller: service.NewController("IterationController"), db: db, config: config, notification: n}
 }
 
 // verifyUser checks if user is a space owner or a collaborator
@@ -237,6 +252,7 @@ func (c *IterationController) Update(ctx *app.UpdateIterationContext) error {
 	var iterations []iteration.Iteration
 	var wiCounts map[string]workitem.WICountsPerIteration
 	err = application.Transactional(c.db, func(appl application.Application) error {
+		oldState := itr.State
 		if ctx.Payload.Data.Attributes.Name != nil {
 			itr.Name = *ctx.Payload.Data.Attributes.Name
 		}
@@ -305,6 +321,12 @@ func (c *IterationController) Update(ctx *app.UpdateIterationContext) error {
 		if err != nil {
 			return err
 		}
+		if itr.State != oldState {
+			err = notification.Enqueue(ctx, c.notification, appl.NotificationOutbox(), notification.NewIterationStateChanged(*itr, oldState))
+			if err != nil {
+				return err
+			}
+		}
 		if ctx.Payload.Data.Relationships != nil && ctx.Payload.Data.Relationships.Parent != nil {
 			// update all child iterations's parent as well
 			for _, x := range oldSubtree {
@@ -558,7 +580,8 @@ func convertToUUID(uuidStrings []string) []uuid.UUID {
 // updateIterationsWithCounts accepts map of 'iterationID to a workitem.WICountsPerIteration instance'.
 // This function returns function of type IterationConvertFunc
 // Inner function is able to access `wiCounts` in closure and it is responsible
-// for adding 'closed' and 'total' count of WI in relationship's meta for every given iteration.
+// for adding 'closed' and 'total' count of WI as well as their estimates and time spent
+// in relationship's meta for every given iteration.
 func updateIterationsWithCounts(wiCounts map[string]workitem.WICountsPerIteration) IterationConvertFunc {
 	return func(request *http.Request, itr *iteration.Iteration, appIteration *app.Iteration) {
 		var counts workitem.WICountsPerIteration
@@ -578,5 +601,8 @@ func updateIterationsWithCounts(wiCounts map[string]workitem.WICountsPerIteratio
 		}
 		appIteration.Relationships.Workitems.Meta[KeyTotalWorkItems] = counts.Total
 		appIteration.Relationships.Workitems.Meta[KeyClosedWorkItems] = counts.Closed
+		appIteration.Relationships.Workitems.Meta[KeyOriginalEstimate] = counts.OriginalEstimate
+		appIteration.Relationships.Workitems.Meta[KeyRemainingEstimate] = counts.RemainingEstimate
+		appIteration.Relationships.Workitems.Meta[KeyTimeSpent] = counts.TimeSpent
 	}
 }
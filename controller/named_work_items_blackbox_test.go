@@ -4,12 +4,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/app/test"
 	. "github.com/fabric8-services/fabric8-wit/controller"
 	"github.com/fabric8-services/fabric8-wit/gormapplication"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/space"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -46,3 +49,53 @@ func (s *TestNamedWorkItemsSuite) TestShowNamedWorkItems() {
 		test.ShowNamedWorkItemsNotFound(t, svc.Context, svc, namedWorkItemsCtrl, username, spaceName, wiNumber)
 	})
 }
+
+func (s *TestNamedWorkItemsSuite) TestShowMovedNamedWorkItems() {
+	// given a parent and its child moved to another space
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Spaces(2),
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].SpaceID = space.SystemSpace
+			return nil
+		}),
+		tf.WorkItems(3, tf.SetWorkItemTitles("parent", "child", "other")),
+		tf.WorkItemLinksCustom(1,
+			tf.BuildLinks(tf.L("parent", "child")),
+			func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				return nil
+			},
+		),
+	)
+	svc := testsupport.ServiceAsSpaceUser("Collaborators-Service", *fxt.Identities[0], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+	db := gormapplication.NewGormDB(s.DB)
+	children := true
+	payload := app.WorkItemMove{
+		Data: &app.WorkItemMoveData{
+			Space:    fxt.Spaces[1].ID,
+			Children: &children,
+		},
+	}
+	_, moved := test.MoveWorkitemOK(s.T(), svc.Context, svc, NewWorkitemController(svc, db, s.Configuration), fxt.WorkItemByTitle("parent").ID, &payload)
+	require.Len(s.T(), moved.Data, 2)
+	assert.Equal(s.T(), fxt.WorkItemByTitle("parent").ID, *moved.Data[0].ID)
+	assert.Equal(s.T(), fxt.WorkItemByTitle("child").ID, *moved.Data[1].ID)
+	namedWorkItemsCtrl := NewNamedWorkItemsController(svc, db)
+
+	s.T().Run("ok", func(t *testing.T) {
+		for _, title := range []string{"parent", "child"} {
+			wi := fxt.WorkItemByTitle(title)
+			// when
+			res := test.ShowNamedWorkItemsTemporaryRedirect(t, svc.Context, svc, namedWorkItemsCtrl, fxt.Identities[0].Username, fxt.Spaces[0].Name, wi.Number)
+			// then
+			assert.True(t, strings.HasSuffix(res.Header().Get("Location"), "/workitems/"+wi.ID.String()))
+		}
+	})
+	s.T().Run("not moved", func(t *testing.T) {
+		wi := fxt.WorkItemByTitle("other")
+		// when
+		res := test.ShowNamedWorkItemsTemporaryRedirect(t, svc.Context, svc, namedWorkItemsCtrl, fxt.Identities[0].Username, fxt.Spaces[0].Name, wi.Number)
+		// then
+		assert.True(t, strings.HasSuffix(res.Header().Get("Location"), "/workitems/"+wi.ID.String()))
+	})
+}
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/goadesign/goa"
+)
+
+// NotificationOutboxController implements the notification_outbox resource.
+type NotificationOutboxController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewNotificationOutboxController creates a notification_outbox controller.
+func NewNotificationOutboxController(service *goa.Service, db application.DB) *NotificationOutboxController {
+	return &NotificationOutboxController{
+		Controller: service.NewController("NotificationOutboxController"),
+		db:         db,
+	}
+}
+
+// Show runs the show action. The dead lettered notifications reveal the
+// activity in all spaces, hence only the service account may see them.
+func (c *NotificationOutboxController) Show(ctx *app.ShowNotificationOutboxContext) error {
+	_, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	isSvcAccount, err := isServiceAccount(ctx, serviceNameAuth)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to determine if account is a service account")
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !isSvcAccount {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("only the service account may show the notification outbox"))
+	}
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var stats *outbox.Statistics
+	var deadLetters []outbox.Entry
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		stats, err = appl.NotificationOutbox().Statistics(ctx)
+		if err != nil {
+			return err
+		}
+		deadLetters, _, err = appl.NotificationOutbox().ListDead(ctx, offset, limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.NotificationOutboxStatus{
+		Pending:       stats.Pending,
+		Delivered:     stats.Delivered,
+		Dead:          stats.Dead,
+		OldestPending: stats.OldestPending,
+		DeadLetters:   make([]*app.NotificationOutboxEntry, len(deadLetters)),
+	}
+	for i, entry := range deadLetters {
+		res.DeadLetters[i] = ConvertNotificationOutboxEntry(entry)
+	}
+	return ctx.OK(res)
+}
+
+// ConvertNotificationOutboxEntry converts between internal and external REST representation
+func ConvertNotificationOutboxEntry(entry outbox.Entry) *app.NotificationOutboxEntry {
+	return &app.NotificationOutboxEntry{
+		MessageID:   entry.MessageID,
+		MessageType: entry.MessageType,
+		TargetID:    entry.TargetID,
+		Attempts:    entry.Attempts,
+		LastError:   entry.LastError,
+		CreatedAt:   entry.CreatedAt,
+		UpdatedAt:   entry.UpdatedAt,
+	}
+}
@@ -0,0 +1,51 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+
+	"github.com/goadesign/goa"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestNotificationOutboxREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunNotificationOutboxREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestNotificationOutboxREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (rest *TestNotificationOutboxREST) TestShow() {
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.Identities(1))
+
+	rest.T().Run("ok - service account", func(t *testing.T) {
+		svc := testsupport.ServiceAsServiceAccountUser("NotificationOutbox-ServiceAccount-Service", *fxt.Identities[0])
+		ctrl := NewNotificationOutboxController(svc, gormapplication.NewGormDB(rest.DB))
+		_, status := test.ShowNotificationOutboxOK(t, svc.Context, svc, ctrl, nil, nil)
+		require.NotNil(t, status)
+		assert.NotNil(t, status.DeadLetters)
+	})
+
+	rest.T().Run("forbidden - not the service account", func(t *testing.T) {
+		svc := testsupport.ServiceAsUser("NotificationOutbox-Service", *fxt.Identities[0])
+		ctrl := NewNotificationOutboxController(svc, gormapplication.NewGormDB(rest.DB))
+		test.ShowNotificationOutboxForbidden(t, svc.Context, svc, ctrl, nil, nil)
+	})
+
+	rest.T().Run("unauthorized - no token", func(t *testing.T) {
+		svc := goa.New("NotificationOutbox-Service")
+		ctrl := NewNotificationOutboxController(svc, gormapplication.NewGormDB(rest.DB))
+		test.ShowNotificationOutboxUnauthorized(t, svc.Context, svc, ctrl, nil, nil)
+	})
+}
@@ -144,6 +144,8 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForListWI, urlRegexString)
 	urlRegexString = fmt.Sprintf("(?P<domain>%s)(?P<path>/work-item/board/detail/)(?P<id>\\d*)", hostString)
 	search.RegisterAsKnownURL(search.HostRegistrationKeyForBoardWI, urlRegexString)
+	urlRegexString = fmt.Sprintf("(?P<domain>%s)(?P<path>/api/workitemkeys/)(?P<key>[A-Za-z][A-Za-z0-9]{1,9}-\\d+)", hostString)
+	search.RegisterAsKnownURL(search.HostRegistrationKeyForWIKey, urlRegexString)
 
 	if ctx.FilterExpression != nil {
 		var result []workitem.WorkItem
@@ -178,12 +180,14 @@ func (c *SearchController) Show(ctx *app.ShowSearchContext) error {
 		}
 		hasChildren := workItemIncludeHasChildren(ctx, c.db, childLinks)
 		includeParent := includeParentWorkItem(ctx, ancestors, childLinks)
+		blockers := workItemIncludeBlockers(ctx, c.db, result...)
+		rollUps := workItemIncludeRollUps(ctx, c.db, result...)
 		response := app.SearchWorkItemList{
 			Links: &app.PagingLinks{},
 			Meta: &app.WorkItemListResponseMeta{
 				TotalCount: count,
 			},
-			Data: ConvertWorkItems(ctx.Request, result, hasChildren, includeParent),
+			Data: ConvertWorkItems(ctx.Request, result, hasChildren, includeParent, blockers, rollUps),
 		}
 		c.enrichWorkItemList(ctx, ancestors, matchingWorkItemIDs, childLinks, &response, hasChildren) // append parentWI and ancestors (if not empty) in response
 		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(result), offset, limit, count, "filter[expression]="+*ctx.FilterExpression)
@@ -63,6 +63,15 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 	if reqSpace.ID != nil {
 		spaceID = *reqSpace.ID
 	}
+	var templateID *uuid.UUID
+	if reqSpace.Relationships != nil && reqSpace.Relationships.SpaceTemplate != nil &&
+		reqSpace.Relationships.SpaceTemplate.Data != nil && reqSpace.Relationships.SpaceTemplate.Data.ID != nil {
+		id, err := uuid.FromString(*reqSpace.Relationships.SpaceTemplate.Data.ID)
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.relationships.space-template.data.id", *reqSpace.Relationships.SpaceTemplate.Data.ID).Expected("a space template ID"))
+		}
+		templateID = &id
+	}
 
 	var rSpace *space.Space
 	err = application.Transactional(c.db, func(appl application.Application) error {
@@ -74,6 +83,7 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 		if reqSpace.Attributes.Description != nil {
 			newSpace.Description = *reqSpace.Attributes.Description
 		}
+		newSpace.KeyPrefix = reqSpace.Attributes.KeyPrefix
 
 		rSpace, err = appl.Spaces().Create(ctx, &newSpace)
 		if err != nil {
@@ -109,6 +119,19 @@ func (c *SpaceController) Create(ctx *app.CreateSpaceContext) error {
 		if err != nil {
 			return errs.Wrapf(err, "failed to create iteration for space: %s", rSpace.Name)
 		}
+
+		// Set up the work item types, type groups and everything else the
+		// selected space template holds
+		if templateID != nil {
+			st, err := appl.SpaceTemplates().Load(ctx, *templateID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to load space template: %s", *templateID)
+			}
+			err = applySpaceTemplate(ctx, appl, *rSpace, newArea, newIteration, st.Template)
+			if err != nil {
+				return errs.Wrapf(err, "failed to apply space template %s to space: %s", st.Name, rSpace.Name)
+			}
+		}
 		return nil
 	})
 	if err != nil {
@@ -281,6 +304,9 @@ func (c *SpaceController) Update(ctx *app.UpdateSpaceContext) error {
 		if ctx.Payload.Data.Attributes.Description != nil {
 			s.Description = *ctx.Payload.Data.Attributes.Description
 		}
+		if ctx.Payload.Data.Attributes.KeyPrefix != nil {
+			s.KeyPrefix = ctx.Payload.Data.Attributes.KeyPrefix
+		}
 
 		s, err = appl.Spaces().Save(ctx.Context, s)
 		return err
@@ -351,6 +377,7 @@ func ConvertSpaceToModel(appSpace app.Space) space.Space {
 		if appSpace.Attributes.Description != nil {
 			modelSpace.Description = *appSpace.Attributes.Description
 		}
+		modelSpace.KeyPrefix = appSpace.Attributes.KeyPrefix
 	}
 	if appSpace.Relationships != nil && appSpace.Relationships.OwnedBy != nil &&
 		appSpace.Relationships.OwnedBy.Data != nil && appSpace.Relationships.OwnedBy.Data.ID != nil {
@@ -413,6 +440,7 @@ func ConvertSpaceFromModel(request *http.Request, sp space.Space, options ...Spa
 		Attributes: &app.SpaceAttributes{
 			Name:        &sp.Name,
 			Description: &sp.Description,
+			KeyPrefix:   sp.KeyPrefix,
 			CreatedAt:   &sp.CreatedAt,
 			UpdatedAt:   &sp.UpdatedAt,
 			Version:     &sp.Version,
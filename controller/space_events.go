@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceEventsControllerConfiguration configuration for the SpaceEventsController
+type SpaceEventsControllerConfiguration interface {
+	GetSpaceEventsPollInterval() time.Duration
+	GetSpaceEventsBatchSize() int
+	GetSpaceEventsHeartbeat() time.Duration
+}
+
+// SpaceEventsController implements the space-events resource.
+type SpaceEventsController struct {
+	*goa.Controller
+	db     application.DB
+	config SpaceEventsControllerConfiguration
+}
+
+// NewSpaceEventsController creates a space-events controller.
+func NewSpaceEventsController(service *goa.Service, db application.DB, config SpaceEventsControllerConfiguration) *SpaceEventsController {
+	return &SpaceEventsController{Controller: service.NewController("SpaceEventsController"), db: db, config: config}
+}
+
+// spaceEvent is the data of a server-sent event of the activity stream
+type spaceEvent struct {
+	ID        int64                  `json:"id"`
+	MessageID uuid.UUID              `json:"message_id"`
+	Type      string                 `json:"type"`
+	TargetID  string                 `json:"target_id"`
+	SpaceID   *uuid.UUID             `json:"space_id"`
+	ActorID   *string                `json:"actor_id,omitempty"`
+	Version   *int                   `json:"version,omitempty"`
+	Changes   []workitem.FieldChange `json:"changes,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+}
+
+// Stream runs the stream action. The response is kept open until the client
+// disconnects and the events of the space are written to it as they appear.
+// Only the owner and the collaborators of the space can stream its events.
+func (c *SpaceEventsController) Stream(ctx *app.StreamSpaceEventsContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	after, err := lastEventID(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		s, err := appl.Spaces().Load(ctx, ctx.SpaceID)
+		if err != nil {
+			return err
+		}
+		if !uuid.Equal(*currentUser, s.OwnerID) {
+			authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+			if err != nil {
+				return errors.NewUnauthorizedError(err.Error())
+			}
+			if !authorized {
+				return errors.NewForbiddenError("user is not a collaborator of the space")
+			}
+		}
+		if after == nil {
+			// without a previous event the stream starts with the next event
+			last, err := notification.LastEventSequence(ctx, appl.NotificationOutbox(), ctx.SpaceID)
+			if err != nil {
+				return err
+			}
+			after = &last
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	w := ctx.ResponseData
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // disable proxy buffering
+	w.WriteHeader(http.StatusOK)
+	flush := func() {
+		if f, ok := w.ResponseWriter.(http.Flusher); ok {
+			f.Flush()
+		}
+	}
+	flush()
+
+	ticker := time.NewTicker(c.config.GetSpaceEventsPollInterval())
+	defer ticker.Stop()
+	lastWrite := time.Now()
+	for {
+		var events []notification.Event
+		err := application.Transactional(c.db, func(appl application.Application) error {
+			return notification.SequenceEvents(ctx, appl.NotificationOutbox())
+		})
+		if err == nil {
+			err = application.Transactional(c.db, func(appl application.Application) error {
+				var err error
+				events, err = notification.ListEvents(ctx, appl.NotificationOutbox(), ctx.SpaceID, *after, c.config.GetSpaceEventsBatchSize())
+				return err
+			})
+		}
+		if err != nil {
+			// the response has already started, all we can do is to end it
+			log.Error(ctx, map[string]interface{}{
+				"space_id": ctx.SpaceID,
+				"err":      err,
+			}, "failed to load the events of the space")
+			return nil
+		}
+		for _, e := range events {
+			if err := writeSpaceEvent(w, e); err != nil {
+				log.Debug(ctx, map[string]interface{}{
+					"space_id": ctx.SpaceID,
+					"err":      err,
+				}, "stopped streaming the events of the space")
+				return nil
+			}
+			*after = e.Sequence
+		}
+		if len(events) == 0 && time.Since(lastWrite) >= c.config.GetSpaceEventsHeartbeat() {
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			lastWrite = time.Now()
+		}
+		if len(events) > 0 {
+			lastWrite = time.Now()
+		}
+		flush()
+		select {
+		case <-ctx.Request.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastEventID returns the ID of the last event seen by the client, taken from
+// the "Last-Event-ID" header sent by reconnecting clients or from the
+// "last_event_id" parameter, or nil if the client hasn't seen any event yet
+func lastEventID(ctx *app.StreamSpaceEventsContext) (*int64, error) {
+	if header := ctx.Request.Header.Get("Last-Event-ID"); header != "" {
+		id, err := strconv.ParseInt(header, 10, 64)
+		if err != nil {
+			return nil, errors.NewBadParameterError("Last-Event-ID", header).Expected("integer")
+		}
+		return &id, nil
+	}
+	if ctx.LastEventID != nil {
+		id := int64(*ctx.LastEventID)
+		return &id, nil
+	}
+	return nil, nil
+}
+
+// writeSpaceEvent writes the event in the server-sent events format
+func writeSpaceEvent(w http.ResponseWriter, e notification.Event) error {
+	data, err := json.Marshal(spaceEvent{
+		ID:        e.Sequence,
+		MessageID: e.MessageID,
+		Type:      e.MessageType,
+		TargetID:  e.TargetID,
+		SpaceID:   e.SpaceID,
+		ActorID:   e.UserID,
+		Version:   e.Version,
+		Changes:   e.Changes,
+		Details:   e.Details,
+		Timestamp: e.Timestamp,
+	})
+	if err != nil {
+		return errs.Wrapf(err, "failed to encode event %d", e.Sequence)
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.Sequence, e.MessageType, data)
+	return err
+}
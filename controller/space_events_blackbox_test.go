@@ -0,0 +1,53 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSpaceEventsREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunSpaceEventsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestSpaceEventsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+// the success case keeps the response open until the client disconnects and
+// is covered by the tests of the notification package
+
+func (rest *TestSpaceEventsREST) TestStreamAuthorization() {
+	fxt := tf.NewTestFixture(rest.T(), rest.DB, tf.Identities(2), tf.Spaces(1))
+	owner := *fxt.Identities[0]
+	authzService := &TestSpaceAuthzService{owner, ""}
+
+	rest.T().Run("unauthorized - no token", func(t *testing.T) {
+		svc := goa.New("SpaceEvents-Service")
+		ctrl := NewSpaceEventsController(svc, gormapplication.NewGormDB(rest.DB), rest.Configuration)
+		test.StreamSpaceEventsUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil)
+	})
+
+	rest.T().Run("forbidden - not a collaborator", func(t *testing.T) {
+		svc := testsupport.ServiceAsSpaceUser("SpaceEvents-Service", *fxt.Identities[1], authzService)
+		ctrl := NewSpaceEventsController(svc, gormapplication.NewGormDB(rest.DB), rest.Configuration)
+		test.StreamSpaceEventsForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, nil)
+	})
+
+	rest.T().Run("not found - unknown space", func(t *testing.T) {
+		svc := testsupport.ServiceAsSpaceUser("SpaceEvents-Service", owner, authzService)
+		ctrl := NewSpaceEventsController(svc, gormapplication.NewGormDB(rest.DB), rest.Configuration)
+		test.StreamSpaceEventsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+}
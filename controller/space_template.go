@@ -1,10 +1,27 @@
 package controller
 
 import (
+	"context"
+	"net/http"
+
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
 	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
 )
 
 var APISpaceTemplates = "spacetemplates"
@@ -22,15 +39,236 @@ func NewSpaceTemplateController(service *goa.Service, db application.DB) *SpaceT
 
 // Show runs the show action.
 func (c *SpaceTemplateController) Show(ctx *app.ShowSpaceTemplateContext) error {
-	// till we have space templates in place, let this controller redirect
-	// user to the typegroups endpoint that returns list of type-groups.
+	var st *spacetemplate.SpaceTemplate
 	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		st, err = appl.SpaceTemplates().Load(ctx, ctx.SpaceTemplateID)
+		if err == nil {
+			return nil
+		}
+		if notFound, _ := errors.IsNotFoundError(err); !notFound {
+			return err
+		}
+		// the type groups of a space are still listed below the
+		// space template endpoint, so redirect the user to the typegroups
+		// endpoint when the ID is the one of a space.
+		st = nil
 		return appl.Spaces().CheckExists(ctx, ctx.SpaceTemplateID)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
+	if st != nil {
+		return ctx.OK(&app.SpaceTemplateSingle{
+			Data: ConvertSpaceTemplate(ctx.Request, *st),
+		})
+	}
 	typeGroupURL := app.SpaceTemplateHref(ctx.SpaceTemplateID) + "/workitemtypegroups/"
 	ctx.ResponseData.Header().Set("Location", typeGroupURL)
 	return ctx.TemporaryRedirect()
 }
+
+// List runs the list action.
+func (c *SpaceTemplateController) List(ctx *app.ListSpaceTemplateContext) error {
+	var templates []spacetemplate.SpaceTemplate
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		templates, err = appl.SpaceTemplates().List(ctx)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.SpaceTemplateList{
+		Data: make([]*app.SpaceTemplate, len(templates)),
+	}
+	for i, st := range templates {
+		res.Data[i] = ConvertSpaceTemplate(ctx.Request, st)
+	}
+	return ctx.OK(res)
+}
+
+// Import runs the import action.
+func (c *SpaceTemplateController) Import(ctx *app.ImportSpaceTemplateContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	// imported templates are available to all users, so only the service
+	// account may import them
+	isSvcAccount, err := isServiceAccount(ctx, serviceNameAuth)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "failed to determine if account is a service account")
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !isSvcAccount {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("only the service account may import space templates"))
+	}
+	template, err := spacetemplate.Parse([]byte(ctx.Payload.Data.Content))
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var st *spacetemplate.SpaceTemplate
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		st, err = appl.SpaceTemplates().Import(ctx, *template)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	log.Info(ctx, map[string]interface{}{
+		"space_template_id": st.ID,
+		"version":           st.Version,
+		"identity_id":       *currentUser,
+	}, "space template imported")
+	res := &app.SpaceTemplateSingle{
+		Data: ConvertSpaceTemplate(ctx.Request, *st),
+	}
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.SpaceTemplateHref(st.ID)))
+	return ctx.Created(res)
+}
+
+// ConvertSpaceTemplate converts from internal to external REST representation
+func ConvertSpaceTemplate(request *http.Request, st spacetemplate.SpaceTemplate) *app.SpaceTemplate {
+	selfURL := rest.AbsoluteURL(request, app.SpaceTemplateHref(st.ID))
+	attrs := &app.SpaceTemplateAttributes{
+		Name:        &st.Name,
+		Description: &st.Description,
+		Version:     &st.Version,
+		BuiltIn:     &st.BuiltIn,
+		Content:     st.Template,
+	}
+	if !st.BuiltIn {
+		attrs.CreatedAt = &st.CreatedAt
+		attrs.UpdatedAt = &st.UpdatedAt
+	}
+	return &app.SpaceTemplate{
+		Type:       APISpaceTemplates,
+		ID:         &st.ID,
+		Attributes: attrs,
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+}
+
+// applySpaceTemplate creates the work item types, type groups, link types,
+// areas, iterations and labels of the given template in the given space. The
+// areas and iterations are created below the given root area and iteration.
+func applySpaceTemplate(ctx context.Context, appl application.Application, s space.Space, rootArea area.Area, rootIteration iteration.Iteration, tmpl spacetemplate.Template) error {
+	// maps the work item type IDs of the template to the IDs of the types
+	// used in the space
+	typeIDs := map[uuid.UUID]uuid.UUID{}
+	for _, t := range tmpl.SortedWorkItemTypes() {
+		if t.Reuse {
+			if err := appl.WorkItemTypes().CheckExists(ctx, t.ID); err != nil {
+				return errs.Wrapf(err, "failed to find the reused work item type %s", t.ID)
+			}
+			typeIDs[t.ID] = t.ID
+			continue
+		}
+		var extendedTypeID *uuid.UUID
+		if t.Extends != nil {
+			id := *t.Extends
+			if mapped, ok := typeIDs[id]; ok {
+				id = mapped
+			}
+			extendedTypeID = &id
+		}
+		var description *string
+		if t.Description != "" {
+			description = &t.Description
+		}
+		id := uuid.NewV4()
+		wit, err := appl.WorkItemTypes().Create(ctx, s.ID, &id, extendedTypeID, t.Name, description, t.Icon, t.Fields)
+		if err != nil {
+			return errs.Wrapf(err, "failed to create work item type %s", t.Name)
+		}
+		if t.Workflow != nil {
+			if _, err = appl.WorkItemTypes().SetWorkflow(ctx, wit.ID, t.Workflow); err != nil {
+				return errs.Wrapf(err, "failed to set the workflow of work item type %s", t.Name)
+			}
+		}
+		if len(t.RollUps) > 0 {
+			if _, err = appl.WorkItemTypes().SetRollUps(ctx, wit.ID, t.RollUps); err != nil {
+				return errs.Wrapf(err, "failed to set the roll-ups of work item type %s", t.Name)
+			}
+		}
+		typeIDs[t.ID] = wit.ID
+	}
+	for i, g := range tmpl.TypeGroups {
+		group := workitem.WorkItemTypeGroup{
+			ID:       uuid.NewV4(),
+			SpaceID:  s.ID,
+			Bucket:   g.Bucket,
+			Name:     g.Name,
+			Icon:     g.Icon,
+			Position: i,
+			TypeList: workitem.TypeList{},
+		}
+		for _, id := range g.Types {
+			group.TypeList = append(group.TypeList, typeIDs[id])
+		}
+		if err := appl.WorkItemTypeGroups().Create(ctx, &group); err != nil {
+			return errs.Wrapf(err, "failed to create work item type group %s", g.Name)
+		}
+	}
+	for _, lt := range tmpl.LinkTypes {
+		linkType := link.WorkItemLinkType{
+			Name:           lt.Name,
+			Topology:       lt.Topology,
+			ForwardName:    lt.ForwardName,
+			ReverseName:    lt.ReverseName,
+			LinkCategoryID: link.SystemWorkItemLinkCategoryUserID,
+			SpaceID:        s.ID,
+		}
+		if lt.Description != "" {
+			description := lt.Description
+			linkType.Description = &description
+		}
+		if lt.CategoryID != nil {
+			linkType.LinkCategoryID = *lt.CategoryID
+		}
+		if _, err := appl.WorkItemLinkTypes().Create(ctx, &linkType); err != nil {
+			return errs.Wrapf(err, "failed to create work item link type %s", lt.Name)
+		}
+	}
+	for _, name := range tmpl.Areas {
+		a := area.Area{
+			ID:      uuid.NewV4(),
+			SpaceID: s.ID,
+			Name:    name,
+		}
+		a.MakeChildOf(rootArea)
+		if err := appl.Areas().Create(ctx, &a); err != nil {
+			return errs.Wrapf(err, "failed to create area %s", name)
+		}
+	}
+	for _, name := range tmpl.Iterations {
+		itr := iteration.Iteration{
+			ID:      uuid.NewV4(),
+			SpaceID: s.ID,
+			Name:    name,
+		}
+		itr.MakeChildOf(rootIteration)
+		if err := appl.Iterations().Create(ctx, &itr); err != nil {
+			return errs.Wrapf(err, "failed to create iteration %s", name)
+		}
+	}
+	for _, l := range tmpl.Labels {
+		lbl := label.Label{
+			SpaceID:         s.ID,
+			Name:            l.Name,
+			TextColor:       l.TextColor,
+			BackgroundColor: l.BackgroundColor,
+			BorderColor:     l.BorderColor,
+		}
+		if err := appl.Labels().Create(ctx, &lbl); err != nil {
+			return errs.Wrapf(err, "failed to create label %s", l.Name)
+		}
+	}
+	return nil
+}
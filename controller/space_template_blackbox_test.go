@@ -0,0 +1,211 @@
+package controller_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type spaceTemplateSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestSuiteSpaceTemplate(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &spaceTemplateSuite{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+// supportTemplate returns a template that creates a new work item type in the
+// space next to the reused system bug type
+func supportTemplate() string {
+	return fmt.Sprintf(`
+name: %s
+work_item_types:
+- id: 8fd7b7d2-4bf7-4d0f-8a4b-6f3ad4c7a1a1
+  name: Incident
+  extends: %s
+- id: %s
+  reuse: true
+type_groups:
+- name: Queue
+  bucket: requirement
+  types:
+  - 8fd7b7d2-4bf7-4d0f-8a4b-6f3ad4c7a1a1
+  - %s
+areas:
+- Frontend
+iterations:
+- Week 1
+labels:
+- name: urgent
+`, testsupport.CreateRandomValidTestName("Support"), workitem.SystemPlannerItem, workitem.SystemBug, workitem.SystemBug)
+}
+
+func (s *spaceTemplateSuite) TestImport() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(1))
+	newController := func(svc *goa.Service) *SpaceTemplateController {
+		return NewSpaceTemplateController(svc, gormapplication.NewGormDB(s.DB))
+	}
+
+	s.T().Run("ok - service account", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsServiceAccountUser("SpaceTemplate-ServiceAccount-Service", *fxt.Identities[0])
+		payload := &app.SpaceTemplateImport{Data: &app.SpaceTemplateImportData{Content: supportTemplate()}}
+		// when
+		_, imported := test.ImportSpaceTemplateCreated(t, svc.Context, svc, newController(svc), payload)
+		// then
+		require.NotNil(t, imported.Data.ID)
+		st, err := spacetemplate.NewRepository(s.DB).Load(s.Ctx, *imported.Data.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, st.Version)
+		require.Len(t, st.Template.WorkItemTypes, 2)
+	})
+
+	s.T().Run("bad request - invalid template", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsServiceAccountUser("SpaceTemplate-ServiceAccount-Service", *fxt.Identities[0])
+		payload := &app.SpaceTemplateImport{Data: &app.SpaceTemplateImportData{Content: "description: no name"}}
+		// when/then
+		test.ImportSpaceTemplateBadRequest(t, svc.Context, svc, newController(svc), payload)
+	})
+
+	s.T().Run("forbidden - not the service account", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsUser("SpaceTemplate-Service", *fxt.Identities[0])
+		payload := &app.SpaceTemplateImport{Data: &app.SpaceTemplateImportData{Content: supportTemplate()}}
+		// when/then
+		test.ImportSpaceTemplateForbidden(t, svc.Context, svc, newController(svc), payload)
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		svc := goa.New("SpaceTemplate-Service")
+		payload := &app.SpaceTemplateImport{Data: &app.SpaceTemplateImportData{Content: supportTemplate()}}
+		// when/then
+		test.ImportSpaceTemplateUnauthorized(t, svc.Context, svc, newController(svc), payload)
+	})
+}
+
+func (s *spaceTemplateSuite) TestCreateSpaceWithTemplate() {
+	// createSpace creates a space from the template with the given ID
+	createSpace := func(t *testing.T, fxt *tf.TestFixture, templateID string) *app.SpaceSingle {
+		svc := testsupport.ServiceAsUser("Space-Service", *fxt.Identities[0])
+		ctrl := NewSpaceController(svc, gormapplication.NewGormDB(s.DB), s.Configuration, &DummyResourceManager{})
+		name := testsupport.CreateRandomValidTestName("space")
+		payload := newCreateSpacePayload(&name, nil)
+		payload.Data.Relationships = &app.SpaceRelationships{
+			SpaceTemplate: &app.RelationGeneric{Data: &app.GenericData{ID: &templateID}},
+		}
+		_, created := test.CreateSpaceCreated(t, svc.Context, svc, ctrl, payload)
+		require.NotNil(t, created.Data.ID)
+		return created
+	}
+
+	s.T().Run("ok - built-in template", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		// when
+		created := createSpace(t, fxt, spacetemplate.KanbanTemplateID.String())
+		// then
+		groups, err := workitem.NewWorkItemTypeGroupRepository(s.DB).List(s.Ctx, *created.Data.ID)
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "Backlog", groups[0].Name)
+		assert.Equal(t, workitem.TypeList{workitem.SystemFeature, workitem.SystemBug}, groups[0].TypeList)
+		assert.Equal(t, "Board", groups[1].Name)
+		labels, err := label.NewLabelRepository(s.DB).List(s.Ctx, *created.Data.ID)
+		require.NoError(t, err)
+		require.Len(t, labels, 2)
+	})
+
+	s.T().Run("ok - imported template", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		tmpl, err := spacetemplate.Parse([]byte(supportTemplate()))
+		require.NoError(t, err)
+		st, err := spacetemplate.NewRepository(s.DB).Import(s.Ctx, *tmpl)
+		require.NoError(t, err)
+		// when
+		created := createSpace(t, fxt, st.ID.String())
+		// then the new work item type is created in the space
+		wits, err := workitem.NewWorkItemTypeRepository(s.DB).List(s.Ctx, *created.Data.ID, nil, nil)
+		require.NoError(t, err)
+		require.Len(t, wits, 1)
+		assert.Equal(t, "Incident", wits[0].Name)
+		// and the type group references it next to the reused type
+		groups, err := workitem.NewWorkItemTypeGroupRepository(s.DB).List(s.Ctx, *created.Data.ID)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, workitem.TypeList{wits[0].ID, workitem.SystemBug}, groups[0].TypeList)
+		// and the iteration is created below the root iteration
+		iterations, err := iteration.NewIterationRepository(s.DB).List(s.Ctx, *created.Data.ID)
+		require.NoError(t, err)
+		var names []string
+		for _, itr := range iterations {
+			names = append(names, itr.Name)
+		}
+		assert.Contains(t, names, "Week 1")
+	})
+
+	s.T().Run("ok - search by the type group of the space", func(t *testing.T) {
+		// given a Kanban space with a feature and a task
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		created := createSpace(t, fxt, spacetemplate.KanbanTemplateID.String())
+		spaceID := *created.Data.ID
+		types := []uuid.UUID{workitem.SystemFeature, workitem.SystemTask}
+		fxt = tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.WorkItems(2, tf.SetWorkItemTitles("feature", "task"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].SpaceID = spaceID
+			fxt.WorkItems[idx].Type = types[idx]
+			return nil
+		}))
+		svc := testsupport.ServiceAsUser("Search-Service", *fxt.Identities[0])
+		ctrl := NewSearchController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
+		search := func(t *testing.T, typeGroup string) []string {
+			filter := fmt.Sprintf(`{"$AND": [{"typegroup.name": "%s"}, {"space": "%s"}]}`, typeGroup, spaceID)
+			_, sr := test.ShowSearchOK(t, nil, nil, ctrl, &filter, nil, nil, nil, nil, nil)
+			var titles []string
+			for _, wi := range sr.Data {
+				titles = append(titles, wi.Attributes[workitem.SystemTitle].(string))
+			}
+			return titles
+		}
+		// when/then
+		assert.ElementsMatch(t, []string{"feature", "task"}, search(t, "Board"))
+		assert.ElementsMatch(t, []string{"feature"}, search(t, "Backlog"))
+		// the type groups of the spaces without a template don't apply
+		assert.Empty(t, search(t, "Execution"))
+	})
+
+	s.T().Run("bad request - invalid template ID", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc := testsupport.ServiceAsUser("Space-Service", *fxt.Identities[0])
+		ctrl := NewSpaceController(svc, gormapplication.NewGormDB(s.DB), s.Configuration, &DummyResourceManager{})
+		name := testsupport.CreateRandomValidTestName("space")
+		payload := newCreateSpacePayload(&name, nil)
+		templateID := "foo"
+		payload.Data.Relationships = &app.SpaceRelationships{
+			SpaceTemplate: &app.RelationGeneric{Data: &app.GenericData{ID: &templateID}},
+		}
+		// when/then
+		test.CreateSpaceBadRequest(t, svc.Context, svc, ctrl, payload)
+	})
+}
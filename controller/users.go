@@ -326,3 +326,15 @@ func createUserLinks(request *http.Request, identityID interface{}) *app.Generic
 		Related: &relatedURL,
 	}
 }
+
+// mentionLinks returns the links to the profiles of the given mentioned
+// users, to be used when rendering their mentions
+func mentionLinks(request *http.Request, mentions account.Mentions) func(username string) (string, bool) {
+	return func(username string) (string, bool) {
+		identityID, ok := mentions[username]
+		if !ok {
+			return "", false
+		}
+		return rest.AbsoluteURL(request, app.UsersHref(identityID)), true
+	}
+}
@@ -0,0 +1,385 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WebhookControllerConfiguration the configuration for the WebhookController
+type WebhookControllerConfiguration interface {
+	GetWebhookAllowPrivateAddresses() bool
+}
+
+// WebhookController implements the webhook resource.
+type WebhookController struct {
+	*goa.Controller
+	db     application.DB
+	config WebhookControllerConfiguration
+}
+
+// NewWebhookController creates a webhook controller.
+func NewWebhookController(service *goa.Service, db application.DB, config WebhookControllerConfiguration) *WebhookController {
+	return &WebhookController{
+		Controller: service.NewController("WebhookController"),
+		db:         db,
+		config:     config,
+	}
+}
+
+// validateHost rejects webhook URLs whose host resolves to an address that
+// is not public, unless private addresses are allowed
+func (c *WebhookController) validateHost(ctx context.Context, w webhook.Webhook) error {
+	if c.config.GetWebhookAllowPrivateAddresses() {
+		return nil
+	}
+	return w.ValidateHost(ctx)
+}
+
+// checkSpaceOwner verifies that the current user owns the given space. Only
+// space owners can see and manage the webhooks of a space because they give
+// access to the signing secrets.
+func checkSpaceOwner(ctx context.Context, appl application.Application, spaceID uuid.UUID) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return errors.NewUnauthorizedError(err.Error())
+	}
+	s, err := appl.Spaces().Load(ctx, spaceID)
+	if err != nil {
+		return err
+	}
+	if !uuid.Equal(*currentUser, s.OwnerID) {
+		log.Warn(ctx, map[string]interface{}{
+			"space_id":     spaceID,
+			"space_owner":  s.OwnerID,
+			"current_user": *currentUser,
+		}, "user is not the space owner")
+		return errors.NewForbiddenError("user is not the space owner")
+	}
+	return nil
+}
+
+// Show runs the show action.
+func (c *WebhookController) Show(ctx *app.ShowWebhookContext) error {
+	var w *webhook.Webhook
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		w, err = appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, *w),
+	})
+}
+
+// List runs the list action.
+func (c *WebhookController) List(ctx *app.ListWebhookContext) error {
+	var webhooks []webhook.Webhook
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		webhooks, err = appl.Webhooks().List(ctx, ctx.SpaceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookList{
+		Data: make([]*app.Webhook, len(webhooks)),
+		Meta: &app.WorkItemListResponseMeta{TotalCount: len(webhooks)},
+	}
+	for i, w := range webhooks {
+		res.Data[i] = ConvertWebhook(ctx.Request, w)
+	}
+	return ctx.OK(res)
+}
+
+// Create runs the create action.
+func (c *WebhookController) Create(ctx *app.CreateWebhookContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrUnauthorized(err.Error()))
+	}
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.URL == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.url", nil).Expected("not nil"))
+	}
+	w := webhook.Webhook{
+		SpaceID: ctx.SpaceID,
+		URL:     strings.TrimSpace(*attrs.URL),
+		Events:  attrs.Events,
+		Active:  true,
+		Creator: *currentUser,
+	}
+	if attrs.Active != nil {
+		w.Active = *attrs.Active
+	}
+	if attrs.Secret != nil {
+		w.Secret = *attrs.Secret
+	}
+	if err := w.Validate(); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if err := c.validateHost(ctx, w); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		return appl.Webhooks().Create(ctx, &w)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, w),
+	}
+	// the secret is only revealed once so that the receiver can verify the signatures
+	res.Data.Attributes.Secret = &w.Secret
+	ctx.ResponseData.Header().Set("Location", rest.AbsoluteURL(ctx.Request, app.WebhookHref(ctx.SpaceID, w.ID)))
+	return ctx.Created(res)
+}
+
+// Update runs the update action.
+func (c *WebhookController) Update(ctx *app.UpdateWebhookContext) error {
+	if ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	if attrs.Version == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.version", nil).Expected("not nil"))
+	}
+	if attrs.URL != nil {
+		// resolve the host before the transaction to not hold it open
+		if err := c.validateHost(ctx, webhook.Webhook{URL: strings.TrimSpace(*attrs.URL)}); err != nil {
+			return jsonapi.JSONErrorResponse(ctx, err)
+		}
+	}
+	var w *webhook.Webhook
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		w, err = appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID)
+		if err != nil {
+			return err
+		}
+		if w.Version != *attrs.Version {
+			return errors.NewVersionConflictError("version conflict")
+		}
+		if attrs.URL != nil {
+			w.URL = strings.TrimSpace(*attrs.URL)
+		}
+		if attrs.Events != nil {
+			w.Events = attrs.Events
+		}
+		if attrs.Active != nil {
+			w.Active = *attrs.Active
+		}
+		if attrs.Secret != nil {
+			w.Secret = *attrs.Secret
+		}
+		w, err = appl.Webhooks().Save(ctx, *w)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WebhookSingle{
+		Data: ConvertWebhook(ctx.Request, *w),
+	})
+}
+
+// Delete runs the delete action.
+func (c *WebhookController) Delete(ctx *app.DeleteWebhookContext) error {
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		return appl.Webhooks().Delete(ctx, ctx.SpaceID, ctx.WebhookID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// ListDeliveries runs the list-deliveries action.
+func (c *WebhookController) ListDeliveries(ctx *app.ListDeliveriesWebhookContext) error {
+	offset, limit := computePagingLimits(ctx.PageOffset, ctx.PageLimit)
+	var deliveries []webhook.Delivery
+	var count int
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		if _, err := appl.Webhooks().Load(ctx, ctx.SpaceID, ctx.WebhookID); err != nil {
+			return err
+		}
+		var err error
+		deliveries, count, err = appl.WebhookDeliveries().List(ctx, ctx.WebhookID, offset, limit)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WebhookDeliveryList{
+		Data:  make([]*app.WebhookDelivery, len(deliveries)),
+		Links: &app.PagingLinks{},
+		Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
+	}
+	for i, d := range deliveries {
+		res.Data[i] = ConvertWebhookDelivery(d)
+	}
+	setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(deliveries), offset, limit, count)
+	return ctx.OK(res)
+}
+
+// ConvertWebhook converts from internal to external REST representation. The
+// secret is never part of the result.
+func ConvertWebhook(request *http.Request, w webhook.Webhook) *app.Webhook {
+	spaceID := w.SpaceID.String()
+	selfURL := rest.AbsoluteURL(request, app.WebhookHref(spaceID, w.ID))
+	deliveriesURL := fmt.Sprintf("%s/deliveries", selfURL)
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(spaceID))
+	events := []string(w.Events)
+	if events == nil {
+		events = []string{}
+	}
+	return &app.Webhook{
+		Type: webhook.APIStringTypeWebhooks,
+		ID:   &w.ID,
+		Attributes: &app.WebhookAttributes{
+			URL:       &w.URL,
+			Events:    events,
+			Active:    &w.Active,
+			CreatedAt: &w.CreatedAt,
+			UpdatedAt: &w.UpdatedAt,
+			Version:   &w.Version,
+		},
+		Relationships: &app.WebhookRelations{
+			Space: &app.RelationGeneric{
+				Data: &app.GenericData{
+					Type: &space.SpaceType,
+					ID:   &spaceID,
+				},
+				Links: &app.GenericLinks{
+					Self:    &spaceRelatedURL,
+					Related: &spaceRelatedURL,
+				},
+			},
+			Deliveries: &app.RelationGeneric{
+				Links: &app.GenericLinks{
+					Related: &deliveriesURL,
+				},
+			},
+		},
+		Links: &app.GenericLinks{
+			Self:    &selfURL,
+			Related: &selfURL,
+		},
+	}
+}
+
+// ConvertWebhookDelivery converts from internal to external REST representation
+func ConvertWebhookDelivery(d webhook.Delivery) *app.WebhookDelivery {
+	var payload interface{}
+	if err := json.Unmarshal([]byte(d.Payload), &payload); err != nil {
+		payload = d.Payload
+	}
+	res := &app.WebhookDelivery{
+		Type: "webhookdeliveries",
+		ID:   d.ID,
+		Attributes: &app.WebhookDeliveryAttributes{
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Payload:        payload,
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			ResponseStatus: d.ResponseStatus,
+			LastError:      d.LastError,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		},
+	}
+	if d.Status == webhook.DeliveryStatusPending {
+		res.Attributes.NextAttemptAt = &d.NextAttemptAt
+	}
+	return res
+}
+
+// webhookEvent is the JSON document posted to the webhooks of a space. It
+// always carries the JSON-API representation of the affected work item.
+type webhookEvent struct {
+	ID        uuid.UUID              `json:"id"`
+	Event     string                 `json:"event"`
+	SpaceID   uuid.UUID              `json:"space_id"`
+	Timestamp time.Time              `json:"timestamp"`
+	WorkItem  *app.WorkItem          `json:"data"`
+	Changes   []workitem.FieldChange `json:"changes,omitempty"`
+	Comment   *app.Comment           `json:"comment,omitempty"`
+	Link      *app.WorkItemLinkData  `json:"link,omitempty"`
+}
+
+// enqueueWebhookEvent stores a delivery of the event for every active
+// webhook of the space that subscribed to it. It is meant to be called in the
+// transaction that performed the change so that nothing is sent when the
+// change is rolled back.
+func enqueueWebhookEvent(ctx context.Context, appl application.Application, spaceID uuid.UUID, event webhookEvent) error {
+	webhooks, err := appl.Webhooks().ListForEvent(ctx, spaceID, event.Event)
+	if err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+	event.ID = uuid.NewV4()
+	event.SpaceID = spaceID
+	event.Timestamp = time.Now().UTC()
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to marshal webhook payload"))
+	}
+	for _, w := range webhooks {
+		err := appl.WebhookDeliveries().Create(ctx, &webhook.Delivery{
+			WebhookID: w.ID,
+			EventID:   event.ID,
+			EventType: event.Event,
+			Payload:   string(payload),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
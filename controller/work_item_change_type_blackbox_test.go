@@ -0,0 +1,165 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemChangeTypeREST struct {
+	gormtestsupport.DBTestSuite
+	db *gormapplication.GormDB
+}
+
+func TestRunWorkItemChangeTypeREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemChangeTypeREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWorkItemChangeTypeREST) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.db = gormapplication.NewGormDB(s.DB)
+}
+
+// changeTypeFixture returns a fixture with a "bug" of a type with a severity,
+// a "feature" type without one and a type of another space
+func (s *TestWorkItemChangeTypeREST) changeTypeFixture(t *testing.T) *tf.TestFixture {
+	return tf.NewTestFixture(t, s.DB,
+		tf.Identities(2),
+		tf.Spaces(2),
+		tf.WorkItemTypes(3, tf.SetWorkItemTypeNames("bug", "feature", "other"), func(fxt *tf.TestFixture, idx int) error {
+			switch idx {
+			case 0:
+				fxt.WorkItemTypes[idx].Fields["severity"] = workitem.FieldDefinition{
+					Label: "Severity",
+					Type:  workitem.SimpleType{Kind: workitem.KindString},
+				}
+			case 2:
+				fxt.WorkItemTypes[idx].SpaceID = fxt.Spaces[1].ID
+			}
+			return nil
+		}),
+		tf.WorkItems(1, tf.SetWorkItemTitles("bug"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields["severity"] = "high"
+			return nil
+		}),
+	)
+}
+
+func newChangeTypePayload(typeID uuid.UUID, version int, confirm *bool) *app.WorkItemChangeType {
+	return &app.WorkItemChangeType{
+		Data: &app.WorkItemChangeTypeData{
+			Type:    typeID,
+			Version: version,
+			Confirm: confirm,
+		},
+	}
+}
+
+func (s *TestWorkItemChangeTypeREST) TestChangeType() {
+	s.T().Run("ok - confirmed", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when
+		_, res := test.ChangeTypeWorkitemOK(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[1].ID, wi.Version, ptr.Bool(true)))
+		// then
+		require.Len(t, res.Data, 1)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, res.Data[0].Relationships.BaseType.Data.ID)
+		assert.Equal(t, "bug", res.Data[0].Attributes[workitem.SystemTitle])
+		assert.Equal(t, wi.Version+1, res.Data[0].Attributes["version"])
+		assert.NotContains(t, res.Data[0].Attributes, "severity")
+		assert.Equal(t, []string{"severity"}, res.Meta.Dropped)
+		assert.Contains(t, res.Meta.Kept, workitem.SystemTitle)
+	})
+
+	s.T().Run("conflict - dropped fields not confirmed", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when
+		test.ChangeTypeWorkitemConflict(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[1].ID, wi.Version, nil))
+		// then the work item is left unchanged
+		loaded, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.Type)
+		assert.Equal(t, "high", loaded.Fields["severity"])
+	})
+
+	s.T().Run("conflict - version", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemConflict(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[1].ID, wi.Version+1, ptr.Bool(true)))
+	})
+
+	s.T().Run("bad request - type of another space", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemBadRequest(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[2].ID, wi.Version, ptr.Bool(true)))
+	})
+
+	s.T().Run("bad request - unknown type", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemBadRequest(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(uuid.NewV4(), wi.Version, ptr.Bool(true)))
+	})
+
+	s.T().Run("forbidden - not the creator nor a collaborator", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := testsupport.ServiceAsSpaceUser("WorkItem-Service", *fxt.Identities[1], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemForbidden(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[1].ID, wi.Version, ptr.Bool(true)))
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newChangeTypePayload(fxt.WorkItemTypes[1].ID, 0, ptr.Bool(true)))
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := s.changeTypeFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		svc := goa.New("WorkItem-Service")
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when/then
+		test.ChangeTypeWorkitemUnauthorized(t, svc.Context, svc, ctrl, wi.ID, newChangeTypePayload(fxt.WorkItemTypes[1].ID, wi.Version, ptr.Bool(true)))
+	})
+}
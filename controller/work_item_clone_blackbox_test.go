@@ -0,0 +1,120 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemCloneREST struct {
+	gormtestsupport.DBTestSuite
+	db *gormapplication.GormDB
+}
+
+func TestRunWorkItemCloneREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemCloneREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWorkItemCloneREST) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.db = gormapplication.NewGormDB(s.DB)
+}
+
+func (s *TestWorkItemCloneREST) TestClone() {
+	// given an epic -> story -> task hierarchy
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.Spaces(2),
+		tf.Iterations(3, func(fxt *tf.TestFixture, idx int) error {
+			if idx == 2 {
+				fxt.Iterations[idx].SpaceID = fxt.Spaces[1].ID
+			}
+			return nil
+		}),
+		tf.WorkItems(3, tf.SetWorkItemTitles("epic", "story", "task"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateInProgress
+			fxt.WorkItems[idx].Fields[workitem.SystemAssignees] = []string{fxt.Identities[0].ID.String()}
+			fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+			return nil
+		}),
+		tf.WorkItemLinksCustom(2,
+			tf.BuildLinks(tf.LinkChain("epic", "story", "task")...),
+			func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				return nil
+			},
+		),
+	)
+	svc := testsupport.ServiceAsSpaceUser("WorkItem-Service", *fxt.Identities[0], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+	ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+	epic := fxt.WorkItemByTitle("epic")
+
+	s.T().Run("ok - with children", func(t *testing.T) {
+		// when
+		children := true
+		resetState := true
+		clearAssignees := true
+		payload := app.WorkItemClone{
+			Data: &app.WorkItemCloneData{
+				Children:       &children,
+				ResetState:     &resetState,
+				ClearAssignees: &clearAssignees,
+				Iteration:      &fxt.Iterations[1].ID,
+			},
+		}
+		_, res := test.CloneWorkitemCreated(t, svc.Context, svc, ctrl, epic.ID, &payload)
+		// then
+		require.Len(t, res.Data, 3)
+		require.Len(t, res.Meta.Mapping, 3)
+		for i, title := range []string{"epic", "story", "task"} {
+			cloneID, ok := res.Meta.Mapping[fxt.WorkItemByTitle(title).ID.String()]
+			require.True(t, ok, "missing clone of %s", title)
+			assert.Equal(t, cloneID, *res.Data[i].ID)
+			clone, err := s.db.WorkItems().LoadByID(s.Ctx, cloneID)
+			require.NoError(t, err)
+			assert.Equal(t, title, clone.Fields[workitem.SystemTitle])
+			assert.Equal(t, workitem.SystemStateNew, clone.Fields[workitem.SystemState])
+			assert.Empty(t, clone.Fields[workitem.SystemAssignees])
+			assert.Equal(t, fxt.Iterations[1].ID.String(), clone.Fields[workitem.SystemIteration])
+		}
+		childLinks, err := s.db.WorkItemLinks().ListChildLinks(s.Ctx, link.SystemWorkItemLinkTypeParentChildID, *res.Data[0].ID, *res.Data[1].ID)
+		require.NoError(t, err)
+		require.Len(t, childLinks, 2)
+		targets := []string{childLinks[0].TargetID.String(), childLinks[1].TargetID.String()}
+		assert.Contains(t, targets, res.Data[1].ID.String())
+		assert.Contains(t, targets, res.Data[2].ID.String())
+	})
+	s.T().Run("ok - without children", func(t *testing.T) {
+		// when
+		payload := app.WorkItemClone{Data: &app.WorkItemCloneData{}}
+		_, res := test.CloneWorkitemCreated(t, svc.Context, svc, ctrl, epic.ID, &payload)
+		// then
+		require.Len(t, res.Data, 1)
+		clone, err := s.db.WorkItems().LoadByID(s.Ctx, *res.Data[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateInProgress, clone.Fields[workitem.SystemState])
+		assert.Len(t, clone.Fields[workitem.SystemAssignees], 1)
+		assert.Equal(t, fxt.Iterations[0].ID.String(), clone.Fields[workitem.SystemIteration])
+	})
+	s.T().Run("fail - iteration of another space", func(t *testing.T) {
+		// when
+		payload := app.WorkItemClone{
+			Data: &app.WorkItemCloneData{
+				Iteration: &fxt.Iterations[2].ID,
+			},
+		}
+		test.CloneWorkitemBadRequest(t, svc.Context, svc, ctrl, epic.ID, &payload)
+	})
+}
@@ -7,11 +7,13 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
@@ -54,7 +56,7 @@ func NewNotifyingWorkItemCommentsController(service *goa.Service, db application
 func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsContext) error {
 	var newComment comment.Comment
 	err := application.Transactional(c.db, func(appl application.Application) error {
-		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		wi, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
 		if err != nil {
 			return goa.ErrNotFound(err.Error())
 		}
@@ -71,11 +73,44 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 			Markup:   markup,
 			Creator:  *currentUserIdentityID,
 		}
+		// a comment that references another comment is a reply in its thread
+		if reqComment.Relationships != nil && reqComment.Relationships.ParentComment != nil &&
+			reqComment.Relationships.ParentComment.Data != nil && reqComment.Relationships.ParentComment.Data.ID != nil {
+			parentCommentID, err := uuid.FromString(*reqComment.Relationships.ParentComment.Data.ID)
+			if err != nil {
+				return errors.NewBadParameterError("data.relationships.parent-comment.data.id", *reqComment.Relationships.ParentComment.Data.ID).Expected("a comment ID")
+			}
+			newComment.ParentCommentID = &parentCommentID
+		}
 
 		err = appl.Comments().Create(ctx, &newComment, *currentUserIdentityID)
 		if err != nil {
+			if badParam, _ := errors.IsBadParameterError(err); badParam {
+				return err
+			}
 			return goa.ErrInternal(err.Error())
 		}
+		// commenters follow the work item
+		err = appl.WorkItemWatchers().Watch(ctx, wi.ID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		err = enqueueWorkItemMessage(ctx, appl, c.notification, wi.ID, notification.NewCommentCreated(newComment.ID.String(), wi.SpaceID))
+		if err != nil {
+			return err
+		}
+		err = enqueueMentions(ctx, appl, c.notification, "comments", newComment.ID.String(), wi.SpaceID, newComment.Mentions.IdentityIDs())
+		if err != nil {
+			return err
+		}
+		err = enqueueWebhookEvent(ctx, appl, wi.SpaceID, webhookEvent{
+			Event:    webhook.EventCommentCreate,
+			WorkItem: ConvertWorkItem(ctx.Request, *wi),
+			Comment:  ConvertComment(ctx.Request, newComment),
+		})
+		if err != nil {
+			return err
+		}
 
 		res := &app.CommentSingle{
 			Data: ConvertComment(ctx.Request, newComment),
@@ -85,9 +120,6 @@ func (c *WorkItemCommentsController) Create(ctx *app.CreateWorkItemCommentsConte
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
-	if ctx.ResponseData.Status == 200 {
-		c.notification.Send(ctx, notification.NewCommentCreated(newComment.ID.String()))
-	}
 	return nil
 }
 
@@ -104,11 +136,25 @@ func (c *WorkItemCommentsController) List(ctx *app.ListWorkItemCommentsContext)
 		if err != nil {
 			return goa.ErrInternal(err.Error())
 		}
-		return ctx.ConditionalEntities(comments, c.config.GetCacheControlComments, func() error {
+		threadIDs := make([]uuid.UUID, len(comments))
+		for i, cm := range comments {
+			threadIDs[i] = cm.ID
+		}
+		replies, err := appl.Comments().ListReplies(ctx, threadIDs)
+		if err != nil {
+			return err
+		}
+		// a new reply changes the list as well
+		entities := append(append([]comment.Comment{}, comments...), replies...)
+		return ctx.ConditionalEntities(entities, c.config.GetCacheControlComments, func() error {
 			res := &app.CommentList{}
 			res.Data = []*app.Comment{}
 			res.Meta = &app.CommentListMeta{TotalCount: count}
-			res.Data = ConvertComments(ctx.Request, comments)
+			res.Data = ConvertComments(ctx.Request, comments, CommentIncludeReplies(replies))
+			res.Included = make([]interface{}, len(replies))
+			for i, reply := range replies {
+				res.Included[i] = ConvertComment(ctx.Request, reply)
+			}
 			res.Links = &app.PagingLinks{}
 			setPagingLinks(res.Links, buildAbsoluteURL(ctx.Request), len(comments), offset, limit, count)
 			return ctx.OK(res)
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemGraphController implements the work_item_graph resource.
+type WorkItemGraphController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemGraphController creates a work_item_graph controller.
+func NewWorkItemGraphController(service *goa.Service, db application.DB) *WorkItemGraphController {
+	return &WorkItemGraphController{
+		Controller: service.NewController("WorkItemGraphController"),
+		db:         db,
+	}
+}
+
+// depthToLevel returns the level up to which the descendants of a work item
+// are loaded for the given optional depth parameter
+func depthToLevel(depth *int) int {
+	if depth == nil {
+		return link.AncestorLevelAll
+	}
+	return *depth
+}
+
+// listGraphLinks loads the links of the given graph ("tree" or
+// "dependencies") of the given work item
+func listGraphLinks(ctx context.Context, appl application.Application, wiID uuid.UUID, graph string, depth *int) ([]link.WorkItemLink, error) {
+	// make sure the work item exists
+	if _, err := appl.WorkItems().LoadByID(ctx, wiID); err != nil {
+		return nil, err
+	}
+	if graph == "dependencies" {
+		return appl.WorkItemLinks().ListDependencyLinks(ctx, wiID)
+	}
+	return appl.WorkItemLinks().ListDescendantLinks(ctx, wiID, depthToLevel(depth))
+}
+
+// convertGraphLinks converts the given links and includes their link types
+// and work items
+func (c *WorkItemGraphController) convertGraphLinks(ctx context.Context, req *http.Request, modelLinks []link.WorkItemLink) (*app.WorkItemLinkList, error) {
+	appLinks := app.WorkItemLinkList{}
+	appLinks.Data = make([]*app.WorkItemLinkData, len(modelLinks))
+	for index, modelLink := range modelLinks {
+		appLink := ConvertLinkFromModel(req, modelLink)
+		appLinks.Data[index] = appLink.Data
+	}
+	appLinks.Meta = &app.WorkItemLinkListMeta{
+		TotalCount: len(modelLinks),
+	}
+	if err := enrichLinkList(ctx, c.db, req, &appLinks); err != nil {
+		return nil, err
+	}
+	return &appLinks, nil
+}
+
+// Tree runs the tree action.
+func (c *WorkItemGraphController) Tree(ctx *app.TreeWorkItemGraphContext) error {
+	var modelLinks []link.WorkItemLink
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		modelLinks, err = listGraphLinks(ctx, appl, ctx.WiID, "tree", ctx.Depth)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appLinks, err := c.convertGraphLinks(ctx.Context, ctx.Request, modelLinks)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(appLinks)
+}
+
+// Dependencies runs the dependencies action.
+func (c *WorkItemGraphController) Dependencies(ctx *app.DependenciesWorkItemGraphContext) error {
+	var modelLinks []link.WorkItemLink
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		modelLinks, err = listGraphLinks(ctx, appl, ctx.WiID, "dependencies", nil)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appLinks, err := c.convertGraphLinks(ctx.Context, ctx.Request, modelLinks)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(appLinks)
+}
+
+// Export runs the export action.
+func (c *WorkItemGraphController) Export(ctx *app.ExportWorkItemGraphContext) error {
+	graph := link.Graph{
+		LinkTypeNames: map[uuid.UUID]string{},
+	}
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		graph.Links, err = listGraphLinks(ctx, appl, ctx.WiID, ctx.Graph, ctx.Depth)
+		if err != nil {
+			return err
+		}
+		// the given work item comes first, followed by the other work items
+		// in the order of the links
+		ids := []uuid.UUID{ctx.WiID}
+		seen := map[uuid.UUID]bool{ctx.WiID: true}
+		for _, l := range graph.Links {
+			for _, id := range []uuid.UUID{l.SourceID, l.TargetID} {
+				if !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+			if _, ok := graph.LinkTypeNames[l.LinkTypeID]; !ok {
+				linkType, err := appl.WorkItemLinkTypes().Load(ctx, l.LinkTypeID)
+				if err != nil {
+					return err
+				}
+				graph.LinkTypeNames[l.LinkTypeID] = linkType.ForwardName
+			}
+		}
+		wis, err := appl.WorkItems().LoadBatchByID(ctx, ids)
+		if err != nil {
+			return err
+		}
+		titles := make(map[uuid.UUID]string, len(wis))
+		for _, wi := range wis {
+			title, _ := wi.Fields[workitem.SystemTitle].(string)
+			titles[wi.ID] = title
+		}
+		graph.Nodes = make([]link.GraphNode, len(ids))
+		for i, id := range ids {
+			graph.Nodes[i] = link.GraphNode{ID: id, Label: titles[id]}
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	var buf bytes.Buffer
+	contentType, extension := "text/vnd.graphviz", ".dot"
+	if ctx.Format == "graphml" {
+		contentType, extension = "application/graphml+xml", ".graphml"
+		err = graph.WriteGraphML(&buf)
+	} else {
+		err = graph.WriteDOT(&buf)
+	}
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errs.Wrapf(err, "failed to export the %s graph of work item %s", ctx.Graph, ctx.WiID))
+	}
+	ctx.ResponseData.Header().Set("Content-Type", contentType)
+	ctx.ResponseData.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": ctx.WiID.String() + extension}))
+	return ctx.OK(buf.Bytes())
+}
+
+// Schedule runs the schedule action.
+func (c *WorkItemGraphController) Schedule(ctx *app.ScheduleWorkItemGraphContext) error {
+	start := time.Now()
+	if ctx.Start != nil {
+		start = *ctx.Start
+	}
+	var schedule *link.Schedule
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		descendantLinks, err := listGraphLinks(ctx, appl, ctx.WiID, "tree", nil)
+		if err != nil {
+			return err
+		}
+		ids := []uuid.UUID{}
+		seen := map[uuid.UUID]bool{}
+		for _, l := range descendantLinks {
+			if !seen[l.TargetID] {
+				seen[l.TargetID] = true
+				ids = append(ids, l.TargetID)
+			}
+		}
+		if len(ids) == 0 {
+			ids = append(ids, ctx.WiID)
+		}
+		schedule, err = appl.WorkItemLinks().ComputeSchedule(ctx, ids, start)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertScheduleFromModel(ctx.Request, *schedule))
+}
+
+// ConvertScheduleFromModel converts a schedule from the model to the app
+// representation
+func ConvertScheduleFromModel(request *http.Request, schedule link.Schedule) *app.WorkItemScheduleList {
+	result := app.WorkItemScheduleList{
+		Data: make([]*app.WorkItemScheduleEntry, len(schedule.Items)),
+		Meta: &app.WorkItemScheduleMeta{
+			TotalCount:   len(schedule.Items),
+			Start:        schedule.Start.UTC(),
+			Finish:       schedule.Finish.UTC(),
+			CriticalPath: schedule.CriticalPath,
+		},
+	}
+	if result.Meta.CriticalPath == nil {
+		result.Meta.CriticalPath = []uuid.UUID{}
+	}
+	for i, item := range schedule.Items {
+		selfURL := rest.AbsoluteURL(request, app.WorkitemHref(item.ID))
+		result.Data[i] = &app.WorkItemScheduleEntry{
+			Type: APIStringTypeWorkItem,
+			ID:   item.ID,
+			Attributes: &app.WorkItemScheduleAttributes{
+				Title:          item.Title,
+				Duration:       int(item.Duration / time.Second),
+				EarliestStart:  item.EarliestStart.UTC(),
+				EarliestFinish: item.EarliestFinish.UTC(),
+				LatestStart:    item.LatestStart.UTC(),
+				LatestFinish:   item.LatestFinish.UTC(),
+				Slack:          int(item.Slack / time.Second),
+				Critical:       item.Critical,
+			},
+			Links: &app.GenericLinks{
+				Self: &selfURL,
+			},
+		}
+	}
+	return &result
+}
@@ -0,0 +1,234 @@
+package controller_test
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type workItemGraphSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestSuiteWorkItemGraph(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &workItemGraphSuite{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *workItemGraphSuite) UnSecuredController() (*goa.Service, *WorkItemGraphController) {
+	svc := goa.New("WorkItemGraph-Service")
+	return svc, NewWorkItemGraphController(svc, gormapplication.NewGormDB(s.DB))
+}
+
+// graphLinkNames returns the titles of the source and target of the given
+// links as "source-target"
+func graphLinkNames(fxt *tf.TestFixture, links *app.WorkItemLinkList) []string {
+	res := make([]string, len(links.Data))
+	for i, l := range links.Data {
+		source := fxt.WorkItemByID(l.Relationships.Source.Data.ID)
+		target := fxt.WorkItemByID(l.Relationships.Target.Data.ID)
+		res[i] = source.Fields[workitem.SystemTitle].(string) + "-" + target.Fields[workitem.SystemTitle].(string)
+	}
+	return res
+}
+
+// treeFixture returns a fixture with the tree A -> B -> C and A -> D
+func (s *workItemGraphSuite) treeFixture(t *testing.T) *tf.TestFixture {
+	return tf.NewTestFixture(t, s.DB,
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+		tf.WorkItems(4, tf.SetWorkItemTitles("A", "B", "C", "D")),
+		tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("A", "D"))),
+	)
+}
+
+// scheduleFixture returns a fixture with the epic E and its children A, B and
+// C that take one, two and three hours and where C depends on A
+func scheduleFixture(t *testing.T, db *gorm.DB) *tf.TestFixture {
+	return tf.NewTestFixture(t, db,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemRemainingEstimate] = workitem.FieldDefinition{
+				Label: "Remaining estimate",
+				Type:  workitem.SimpleType{Kind: workitem.KindDuration},
+			}
+			return nil
+		}),
+		tf.WorkItemLinkTypes(2,
+			tf.SetTopologies(link.TopologyTree, link.TopologyDependency),
+			tf.SetWorkItemLinkTypeNames("parenting", "blocking"),
+		),
+		tf.WorkItems(4, tf.SetWorkItemTitles("E", "A", "B", "C"), func(fxt *tf.TestFixture, idx int) error {
+			if idx > 0 {
+				fxt.WorkItems[idx].Fields[workitem.SystemRemainingEstimate] = float64(idx * 3600)
+			}
+			return nil
+		}),
+		tf.WorkItemLinksCustom(4, tf.BuildLinks(
+			tf.L("E", "A", "parenting"),
+			tf.L("E", "B", "parenting"),
+			tf.L("E", "C", "parenting"),
+			tf.L("A", "C", "blocking"),
+		)),
+	)
+}
+
+func (s *workItemGraphSuite) TestTree() {
+	fxt := s.treeFixture(s.T())
+	svc, ctrl := s.UnSecuredController()
+
+	s.T().Run("ok - all levels", func(t *testing.T) {
+		// when
+		_, links := test.TreeWorkItemGraphOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("A").ID, nil)
+		// then
+		assert.ElementsMatch(t, []string{"A-B", "B-C", "A-D"}, graphLinkNames(fxt, links))
+		assert.Equal(t, 3, links.Meta.TotalCount)
+		assert.NotEmpty(t, links.Included)
+	})
+
+	s.T().Run("ok - one level", func(t *testing.T) {
+		// when
+		_, links := test.TreeWorkItemGraphOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("A").ID, ptr.Int(1))
+		// then
+		assert.ElementsMatch(t, []string{"A-B", "A-D"}, graphLinkNames(fxt, links))
+	})
+
+	s.T().Run("ok - leaf", func(t *testing.T) {
+		// when
+		_, links := test.TreeWorkItemGraphOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("C").ID, nil)
+		// then
+		assert.Empty(t, links.Data)
+		assert.Equal(t, 0, links.Meta.TotalCount)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// when/then
+		test.TreeWorkItemGraphNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+}
+
+func (s *workItemGraphSuite) TestDependencies() {
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "D", "E")),
+		tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("D", "E"))),
+	)
+	svc, ctrl := s.UnSecuredController()
+
+	s.T().Run("ok", func(t *testing.T) {
+		// when
+		_, links := test.DependenciesWorkItemGraphOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("B").ID)
+		// then the links of the work items that B depends on and that depend
+		// on B are listed
+		assert.ElementsMatch(t, []string{"A-B", "B-C"}, graphLinkNames(fxt, links))
+		assert.Equal(t, 2, links.Meta.TotalCount)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// when/then
+		test.DependenciesWorkItemGraphNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
+
+func (s *workItemGraphSuite) TestExport() {
+	fxt := s.treeFixture(s.T())
+	svc, ctrl := s.UnSecuredController()
+	a := fxt.WorkItemByTitle("A")
+	b := fxt.WorkItemByTitle("B")
+
+	s.T().Run("ok - dot", func(t *testing.T) {
+		// when
+		rw := test.ExportWorkItemGraphOK(t, svc.Context, svc, ctrl, a.ID, nil, "dot", "tree")
+		// then
+		recorder, ok := rw.(*httptest.ResponseRecorder)
+		require.True(t, ok)
+		assert.Equal(t, "text/vnd.graphviz", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=`+a.ID.String()+".dot", recorder.Header().Get("Content-Disposition"))
+		body := recorder.Body.String()
+		assert.Contains(t, body, "digraph workitems {")
+		assert.Contains(t, body, `"`+a.ID.String()+`" [label="A"];`)
+		assert.Contains(t, body, `"`+a.ID.String()+`" -> "`+b.ID.String()+`"`)
+	})
+
+	s.T().Run("ok - graphml with depth", func(t *testing.T) {
+		// when
+		rw := test.ExportWorkItemGraphOK(t, svc.Context, svc, ctrl, a.ID, ptr.Int(1), "graphml", "tree")
+		// then
+		recorder, ok := rw.(*httptest.ResponseRecorder)
+		require.True(t, ok)
+		assert.Equal(t, "application/graphml+xml", recorder.Header().Get("Content-Type"))
+		body := recorder.Body.String()
+		assert.Contains(t, body, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+		assert.Contains(t, body, `source="`+a.ID.String()+`" target="`+b.ID.String()+`"`)
+		// C is below the requested depth
+		assert.NotContains(t, body, fxt.WorkItemByTitle("C").ID.String())
+	})
+
+	s.T().Run("ok - dependencies", func(t *testing.T) {
+		// when
+		rw := test.ExportWorkItemGraphOK(t, svc.Context, svc, ctrl, a.ID, nil, "dot", "dependencies")
+		// then only the work item itself is exported as it has no
+		// dependencies
+		recorder, ok := rw.(*httptest.ResponseRecorder)
+		require.True(t, ok)
+		body := recorder.Body.String()
+		assert.Contains(t, body, `"`+a.ID.String()+`" [label="A"];`)
+		assert.NotContains(t, body, "->")
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// when/then
+		test.ExportWorkItemGraphNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, "dot", "tree")
+	})
+}
+
+func (s *workItemGraphSuite) TestSchedule() {
+	fxt := scheduleFixture(s.T(), s.DB)
+	svc, ctrl := s.UnSecuredController()
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	a, b, c := fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID
+
+	s.T().Run("ok - descendants", func(t *testing.T) {
+		// when
+		_, schedule := test.ScheduleWorkItemGraphOK(t, svc.Context, svc, ctrl, fxt.WorkItemByTitle("E").ID, &start)
+		// then
+		require.Len(t, schedule.Data, 3)
+		assert.Equal(t, 3, schedule.Meta.TotalCount)
+		assert.Equal(t, start, schedule.Meta.Start)
+		assert.Equal(t, start.Add(4*time.Hour), schedule.Meta.Finish)
+		assert.Equal(t, []uuid.UUID{a, c}, schedule.Meta.CriticalPath)
+		assert.Equal(t, b, schedule.Data[1].ID)
+		assert.Equal(t, 7200, schedule.Data[1].Attributes.Slack)
+		assert.False(t, schedule.Data[1].Attributes.Critical)
+	})
+
+	s.T().Run("ok - work item without descendants", func(t *testing.T) {
+		// when
+		_, schedule := test.ScheduleWorkItemGraphOK(t, svc.Context, svc, ctrl, b, &start)
+		// then
+		require.Len(t, schedule.Data, 1)
+		assert.Equal(t, b, schedule.Data[0].ID)
+		assert.Equal(t, start.Add(2*time.Hour), schedule.Meta.Finish)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// when/then
+		test.ScheduleWorkItemGraphNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil)
+	})
+}
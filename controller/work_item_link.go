@@ -9,8 +9,10 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
@@ -21,8 +23,9 @@ import (
 // WorkItemLinkController implements the work-item-link resource.
 type WorkItemLinkController struct {
 	*goa.Controller
-	db     application.DB
-	config WorkItemLinkControllerConfig
+	db           application.DB
+	config       WorkItemLinkControllerConfig
+	notification notification.Channel
 }
 
 // WorkItemLinkControllerConfig the config interface for the WorkitemLinkController
@@ -33,10 +36,20 @@ type WorkItemLinkControllerConfig interface {
 
 // NewWorkItemLinkController creates a work-item-link controller.
 func NewWorkItemLinkController(service *goa.Service, db application.DB, config WorkItemLinkControllerConfig) *WorkItemLinkController {
+	return NewNotifyingWorkItemLinkController(service, db, &notification.DevNullChannel{}, config)
+}
+
+// NewNotifyingWorkItemLinkController creates a work-item-link controller with notification broadcast.
+func NewNotifyingWorkItemLinkController(service *goa.Service, db application.DB, notificationChannel notification.Channel, config WorkItemLinkControllerConfig) *WorkItemLinkController {
+	n := notificationChannel
+	if n == nil {
+		n = &notification.DevNullChannel{}
+	}
 	return &WorkItemLinkController{
-		Controller: service.NewController("WorkItemLinkController"),
-		db:         db,
-		config:     config,
+		Controller:   service.NewController("WorkItemLinkController"),
+		db:           db,
+		config:       config,
+		notification: n,
 	}
 }
 
@@ -213,7 +226,10 @@ func (c *WorkItemLinkController) Create(ctx *app.CreateWorkItemLinkContext) erro
 	err = application.Transactional(c.db, func(appl application.Application) error {
 		var err error
 		createdModelLink, err = appl.WorkItemLinks().Create(ctx.Context, modelLink.SourceID, modelLink.TargetID, modelLink.LinkTypeID, *currentUserIdentityID)
-		return err
+		if err != nil {
+			return err
+		}
+		return enqueueLinkEvent(ctx, appl, c.notification, ctx.Request, webhook.EventLinkCreate, *createdModelLink)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -296,7 +312,15 @@ func (c *WorkItemLinkController) Delete(ctx *app.DeleteWorkItemLinkContext) erro
 		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to delete the link"))
 	}
 	err = application.Transactional(c.db, func(appl application.Application) error {
-		return appl.WorkItemLinks().Delete(ctx.Context, ctx.LinkID, *currentUserIdentityID)
+		l, err := appl.WorkItemLinks().Load(ctx.Context, ctx.LinkID)
+		if err != nil {
+			return err
+		}
+		err = appl.WorkItemLinks().Delete(ctx.Context, ctx.LinkID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		return enqueueLinkEvent(ctx, appl, c.notification, ctx.Request, webhook.EventLinkDelete, *l)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -304,6 +328,46 @@ func (c *WorkItemLinkController) Delete(ctx *app.DeleteWorkItemLinkContext) erro
 	return ctx.OK([]byte{})
 }
 
+// Restore runs the restore action.
+func (c *WorkItemLinkController) Restore(ctx *app.RestoreWorkItemLinkContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var restoredModelLink *link.WorkItemLink
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		restoredModelLink, err = appl.WorkItemLinks().Restore(ctx.Context, ctx.LinkID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		// the link can only be loaded once it is restored, which is why the
+		// restoration is rolled back if the user is not authorized
+		authorized, spaceID, err := c.checkWorkItemCreatorOrSpaceOwner(ctx, appl, restoredModelLink.SourceID, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		if !authorized {
+			authorized, err = authz.Authorize(ctx, spaceID.String())
+			if err != nil {
+				return errors.NewUnauthorizedError(err.Error())
+			}
+		}
+		if !authorized {
+			return errors.NewForbiddenError("user is not authorized to restore the link")
+		}
+		return enqueueLinkEvent(ctx, appl, c.notification, ctx.Request, webhook.EventLinkCreate, *restoredModelLink)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	restoredAppLink := ConvertLinkFromModel(ctx.Request, *restoredModelLink)
+	if err := enrichLinkSingle(ctx.Context, c.db, ctx.Request, &restoredAppLink); err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&restoredAppLink)
+}
+
 // Show runs the show action.
 func (c *WorkItemLinkController) Show(ctx *app.ShowWorkItemLinkContext) error {
 	var modelLink *link.WorkItemLink
@@ -325,6 +389,28 @@ func (c *WorkItemLinkController) Show(ctx *app.ShowWorkItemLinkContext) error {
 	})
 }
 
+// enqueueLinkEvent notifies the notification channel and the webhooks of the
+// source work item's space about the creation or deletion of a link
+func enqueueLinkEvent(ctx context.Context, appl application.Application, channel notification.Channel, req *http.Request, event string, l link.WorkItemLink) error {
+	source, err := appl.WorkItems().LoadByID(ctx, l.SourceID)
+	if err != nil {
+		return errs.WithStack(err)
+	}
+	msg := notification.NewLinkCreated(l, source.SpaceID)
+	if event == webhook.EventLinkDelete {
+		msg = notification.NewLinkDeleted(l, source.SpaceID)
+	}
+	if err := notification.Enqueue(ctx, channel, appl.NotificationOutbox(), msg); err != nil {
+		return err
+	}
+	appLink := ConvertLinkFromModel(req, l)
+	return enqueueWebhookEvent(ctx, appl, source.SpaceID, webhookEvent{
+		Event:    event,
+		WorkItem: ConvertWorkItem(req, *source),
+		Link:     appLink.Data,
+	})
+}
+
 // ConvertLinkFromModel converts a work item from model to REST representation
 func ConvertLinkFromModel(request *http.Request, t link.WorkItemLink) app.WorkItemLinkSingle {
 	linkSelfURL := rest.AbsoluteURL(request, app.WorkItemLinkHref(t.ID.String()))
@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemLinkCategoriesController implements the work-item-link-categories resource.
+type WorkItemLinkCategoriesController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemLinkCategoriesController creates a WorkItemLinkCategoriesController.
+func NewWorkItemLinkCategoriesController(service *goa.Service, db application.DB) *WorkItemLinkCategoriesController {
+	return &WorkItemLinkCategoriesController{
+		Controller: service.NewController("WorkItemLinkCategoriesController"),
+		db:         db,
+	}
+}
+
+// loadSpaceLinkCategory loads the work item link category with the given ID
+// and makes sure that it belongs to the given space. The categories of the
+// system space are shared by all spaces and cannot be modified.
+func loadSpaceLinkCategory(ctx context.Context, appl application.Application, spaceID, categoryID uuid.UUID) (*link.WorkItemLinkCategory, error) {
+	modelCategory, err := appl.WorkItemLinkCategories().Load(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if uuid.Equal(modelCategory.SpaceID, space.SystemSpace) {
+		return nil, errors.NewForbiddenError("work item link categories of the system space cannot be modified")
+	}
+	if !uuid.Equal(modelCategory.SpaceID, spaceID) {
+		return nil, errors.NewNotFoundError("work item link category", categoryID.String())
+	}
+	return modelCategory, nil
+}
+
+// List runs the list action.
+func (c *WorkItemLinkCategoriesController) List(ctx *app.ListWorkItemLinkCategoriesContext) error {
+	var modelCategories []link.WorkItemLinkCategory
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		modelCategories, err = appl.WorkItemLinkCategories().ListBySpace(ctx, ctx.SpaceID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appCategories := app.WorkItemLinkCategoryList{}
+	appCategories.Data = make([]*app.WorkItemLinkCategoryData, len(modelCategories))
+	for index, value := range modelCategories {
+		cat := ConvertLinkCategoryFromModel(value)
+		appCategories.Data[index] = cat.Data
+	}
+	// TODO: When adding pagination, this must not be len(rows) but
+	// the overall total number of elements from all pages.
+	appCategories.Meta = &app.WorkItemLinkCategoryListMeta{
+		TotalCount: len(modelCategories),
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		linkCtx := newWorkItemLinkContext(ctx.Context, ctx.Service, appl, c.db, ctx.Request, ctx.ResponseWriter, app.WorkItemLinkCategoryHref, nil)
+		return enrichLinkCategoryList(linkCtx, &appCategories)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, goa.ErrInternal("Failed to enrich link categories: %s", err.Error()))
+	}
+	return ctx.OK(&appCategories)
+}
+
+// Create runs the create action.
+func (c *WorkItemLinkCategoriesController) Create(ctx *app.CreateWorkItemLinkCategoriesContext) error {
+	if ctx.Payload.Data.Attributes == nil || ctx.Payload.Data.Attributes.Name == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes.name", nil).Expected("not nil"))
+	}
+	modelCategory := ConvertLinkCategoryToModel(app.WorkItemLinkCategorySingle{Data: ctx.Payload.Data})
+	// categories are always created in the space of the request
+	modelCategory.SpaceID = ctx.SpaceID
+	var created *link.WorkItemLinkCategory
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		created, err = appl.WorkItemLinkCategories().Create(ctx, &modelCategory)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appCategory := convertLinkCategoryWithLinks(ctx.Request, *created)
+	ctx.ResponseData.Header().Set("Location", app.WorkItemLinkCategoryHref(created.ID))
+	return ctx.Created(&appCategory)
+}
+
+// Update runs the update action.
+func (c *WorkItemLinkCategoriesController) Update(ctx *app.UpdateWorkItemLinkCategoriesContext) error {
+	attrs := ctx.Payload.Data.Attributes
+	if attrs == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("not nil"))
+	}
+	var updated *link.WorkItemLinkCategory
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		modelCategory, err := loadSpaceLinkCategory(ctx, appl, ctx.SpaceID, ctx.ID)
+		if err != nil {
+			return err
+		}
+		if attrs.Name != nil {
+			if *attrs.Name == "" {
+				return errors.NewBadParameterError("data.attributes.name", *attrs.Name)
+			}
+			modelCategory.Name = *attrs.Name
+		}
+		if attrs.Description != nil {
+			modelCategory.Description = attrs.Description
+		}
+		if attrs.Version != nil {
+			modelCategory.Version = *attrs.Version
+		}
+		updated, err = appl.WorkItemLinkCategories().Save(ctx, *modelCategory)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appCategory := convertLinkCategoryWithLinks(ctx.Request, *updated)
+	return ctx.OK(&appCategory)
+}
+
+// Delete runs the delete action.
+func (c *WorkItemLinkCategoriesController) Delete(ctx *app.DeleteWorkItemLinkCategoriesContext) error {
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		if _, err := loadSpaceLinkCategory(ctx, appl, ctx.SpaceID, ctx.ID); err != nil {
+			return err
+		}
+		return appl.WorkItemLinkCategories().Delete(ctx, ctx.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// convertLinkCategoryWithLinks converts the given work item link category
+// from model to app representation including its "links" elements
+func convertLinkCategoryWithLinks(request *http.Request, modelCategory link.WorkItemLinkCategory) app.WorkItemLinkCategorySingle {
+	appCategory := ConvertLinkCategoryFromModel(modelCategory)
+	relatedURL := rest.AbsoluteURL(request, app.WorkItemLinkCategoryHref(modelCategory.ID))
+	appCategory.Data.Links = &app.GenericLinks{
+		Self:    &relatedURL,
+		Related: &relatedURL,
+	}
+	spaceRelatedURL := rest.AbsoluteURL(request, app.SpaceHref(modelCategory.SpaceID.String()))
+	appCategory.Data.Relationships.Space.Links = &app.GenericLinks{
+		Self:    &spaceRelatedURL,
+		Related: &spaceRelatedURL,
+	}
+	return appCategory
+}
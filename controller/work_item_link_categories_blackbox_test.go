@@ -0,0 +1,268 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/account"
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSuiteWorkItemLinkCategories(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &workItemLinkCategoriesSuite{
+		DBTestSuite: gormtestsupport.NewDBTestSuite(""),
+	})
+}
+
+type workItemLinkCategoriesSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func (s *workItemLinkCategoriesSuite) UnSecuredController() (*goa.Service, *WorkItemLinkCategoriesController) {
+	svc := goa.New("WorkItemLinkCategories-Service")
+	return svc, NewWorkItemLinkCategoriesController(svc, gormapplication.NewGormDB(s.DB))
+}
+
+func (s *workItemLinkCategoriesSuite) SecuredController(identity account.Identity) (*goa.Service, *WorkItemLinkCategoriesController) {
+	svc := testsupport.ServiceAsUser("WorkItemLinkCategories-Service", identity)
+	return svc, NewWorkItemLinkCategoriesController(svc, gormapplication.NewGormDB(s.DB))
+}
+
+// inFirstSpace puts the work item link categories of the fixture in its first
+// space instead of the system space
+func inFirstSpace(fxt *tf.TestFixture, idx int) error {
+	fxt.WorkItemLinkCategories[idx].SpaceID = fxt.Spaces[0].ID
+	return nil
+}
+
+// newUpdateWorkItemLinkCategoryPayload returns the payload to rename the given
+// work item link category
+func newUpdateWorkItemLinkCategoryPayload(cat link.WorkItemLinkCategory, name string) *app.UpdateWorkItemLinkCategoryPayload {
+	return &app.UpdateWorkItemLinkCategoryPayload{
+		Data: &app.WorkItemLinkCategoryData{
+			ID:   &cat.ID,
+			Type: link.EndpointWorkItemLinkCategories,
+			Attributes: &app.WorkItemLinkCategoryAttributes{
+				Name:    &name,
+				Version: &cat.Version,
+			},
+		},
+	}
+}
+
+func (s *workItemLinkCategoriesSuite) TestList() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a category of the space and one of another space
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkCategories(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkCategories[idx].SpaceID = fxt.Spaces[idx].ID
+			return nil
+		}))
+		svc, ctrl := s.UnSecuredController()
+		// when
+		_, list := test.ListWorkItemLinkCategoriesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID)
+		// then the categories of the space and of the system space are listed
+		ids := map[uuid.UUID]struct{}{}
+		for _, cat := range list.Data {
+			ids[*cat.ID] = struct{}{}
+		}
+		assert.Contains(t, ids, fxt.WorkItemLinkCategories[0].ID)
+		assert.NotContains(t, ids, fxt.WorkItemLinkCategories[1].ID)
+		assert.Contains(t, ids, link.SystemWorkItemLinkCategorySystemID)
+		assert.Equal(t, len(list.Data), list.Meta.TotalCount)
+	})
+
+	s.T().Run("not found - unknown space", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.ListWorkItemLinkCategoriesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
+
+func (s *workItemLinkCategoriesSuite) TestCreate() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(2), tf.Spaces(1))
+
+	s.T().Run("ok - space owner", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		name := testsupport.CreateRandomValidTestName("link category ")
+		// when
+		res, created := test.CreateWorkItemLinkCategoriesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newCreateWorkItemLinkCategoryPayload(name))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, name, *created.Data.Attributes.Name)
+		assert.Equal(t, fxt.Spaces[0].ID, *created.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, app.WorkItemLinkCategoryHref(*created.Data.ID), res.Header().Get("Location"))
+	})
+
+	s.T().Run("bad request - no name", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkCategoryPayload("")
+		payload.Data.Attributes.Name = nil
+		// when/then
+		test.CreateWorkItemLinkCategoriesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("conflict - name already used", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		name := testsupport.CreateRandomValidTestName("link category ")
+		test.CreateWorkItemLinkCategoriesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newCreateWorkItemLinkCategoryPayload(name))
+		// when/then
+		test.CreateWorkItemLinkCategoriesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newCreateWorkItemLinkCategoryPayload(name))
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		// when/then
+		test.CreateWorkItemLinkCategoriesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newCreateWorkItemLinkCategoryPayload(testsupport.CreateRandomValidTestName("link category ")))
+	})
+
+	s.T().Run("not found - unknown space", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.CreateWorkItemLinkCategoriesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), newCreateWorkItemLinkCategoryPayload(testsupport.CreateRandomValidTestName("link category ")))
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.CreateWorkItemLinkCategoriesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, newCreateWorkItemLinkCategoryPayload(testsupport.CreateRandomValidTestName("link category ")))
+	})
+}
+
+func (s *workItemLinkCategoriesSuite) TestUpdate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		name := testsupport.CreateRandomValidTestName("updated link category ")
+		// when
+		_, updated := test.UpdateWorkItemLinkCategoriesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], name))
+		// then
+		assert.Equal(t, name, *updated.Data.Attributes.Name)
+		assert.Equal(t, fxt.WorkItemLinkCategories[0].Version+1, *updated.Data.Attributes.Version)
+		assert.Equal(t, fxt.Spaces[0].ID, *updated.Data.Relationships.Space.Data.ID)
+	})
+
+	s.T().Run("bad request - empty name", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.UpdateWorkItemLinkCategoriesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], ""))
+	})
+
+	s.T().Run("conflict - version", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		cat := *fxt.WorkItemLinkCategories[0]
+		cat.Version++
+		// when/then
+		test.UpdateWorkItemLinkCategoriesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, cat.ID, newUpdateWorkItemLinkCategoryPayload(cat, "some name"))
+	})
+
+	s.T().Run("forbidden - category of the system space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.UpdateWorkItemLinkCategoriesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], "some name"))
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		// when/then
+		test.UpdateWorkItemLinkCategoriesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], "some name"))
+	})
+
+	s.T().Run("not found - category of another space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.UpdateWorkItemLinkCategoriesNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[1].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], "some name"))
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.UpdateWorkItemLinkCategoriesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID, newUpdateWorkItemLinkCategoryPayload(*fxt.WorkItemLinkCategories[0], "some name"))
+	})
+}
+
+func (s *workItemLinkCategoriesSuite) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when
+		test.DeleteWorkItemLinkCategoriesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID)
+		// then
+		_, err := link.NewWorkItemLinkCategoryRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinkCategories[0].ID)
+		require.Error(t, err)
+	})
+
+	s.T().Run("conflict - used by link types", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace), tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkCategoriesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID)
+	})
+
+	s.T().Run("forbidden - category of the system space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkCategoriesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, link.SystemWorkItemLinkCategorySystemID)
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		// when/then
+		test.DeleteWorkItemLinkCategoriesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkCategoriesNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemLinkCategories(1, inFirstSpace))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.DeleteWorkItemLinkCategoriesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkCategories[0].ID)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
@@ -32,9 +33,23 @@ func enrichLinkCategorySingle(ctx *workItemLinkContext, single app.WorkItemLinkC
 		Self:    &relatedURL,
 		Related: &relatedURL,
 	}
+	enrichLinkCategorySpace(ctx, single.Data)
 	return nil
 }
 
+// enrichLinkCategorySpace adds the "links" element to the relationship of the
+// given work item link category with its space
+func enrichLinkCategorySpace(ctx *workItemLinkContext, data *app.WorkItemLinkCategoryData) {
+	if data.Relationships == nil || data.Relationships.Space == nil || data.Relationships.Space.Data == nil {
+		return
+	}
+	spaceRelatedURL := rest.AbsoluteURL(ctx.Request, app.SpaceHref(data.Relationships.Space.Data.ID.String()))
+	data.Relationships.Space.Links = &app.GenericLinks{
+		Self:    &spaceRelatedURL,
+		Related: &spaceRelatedURL,
+	}
+}
+
 // enrichLinkCategoryList includes related resources in the list's "included" array
 func enrichLinkCategoryList(ctx *workItemLinkContext, list *app.WorkItemLinkCategoryList) error {
 	// Add "links" element
@@ -44,6 +59,7 @@ func enrichLinkCategoryList(ctx *workItemLinkContext, list *app.WorkItemLinkCate
 			Self:    &relatedURL,
 			Related: &relatedURL,
 		}
+		enrichLinkCategorySpace(ctx, data)
 	}
 	return nil
 }
@@ -114,6 +130,14 @@ func ConvertLinkCategoryFromModel(t link.WorkItemLinkCategory) app.WorkItemLinkC
 				Description: t.Description,
 				Version:     &t.Version,
 			},
+			Relationships: &app.WorkItemLinkCategoryRelationships{
+				Space: &app.RelationSpaces{
+					Data: &app.RelationSpacesData{
+						Type: ptr.String(APIStringTypeSpace),
+						ID:   &t.SpaceID,
+					},
+				},
+			},
 		},
 	}
 	return converted
@@ -12,6 +12,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 
 	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
 )
 
 // WorkItemLinkTypeController implements the work-item-link-type resource.
@@ -108,6 +109,7 @@ func ConvertWorkItemLinkTypeFromModel(request *http.Request, modelLinkType link.
 				ForwardName: &modelLinkType.ForwardName,
 				ReverseName: &modelLinkType.ReverseName,
 				Topology:    &topologyStr,
+				Blocking:    &modelLinkType.Blocking,
 			},
 			Relationships: &app.WorkItemLinkTypeRelationships{
 				LinkCategory: &app.RelationWorkItemLinkCategory{
@@ -124,9 +126,31 @@ func ConvertWorkItemLinkTypeFromModel(request *http.Request, modelLinkType link.
 			},
 		},
 	}
+	converted.Data.Relationships.SourceType = convertLinkTypeWorkItemTypeRelation(request, modelLinkType.SourceTypeID)
+	converted.Data.Relationships.TargetType = convertLinkTypeWorkItemTypeRelation(request, modelLinkType.TargetTypeID)
 	return converted
 }
 
+// convertLinkTypeWorkItemTypeRelation returns the relationship to the given
+// work item type of a type constraint of a link type or nil if there is no
+// such constraint
+func convertLinkTypeWorkItemTypeRelation(request *http.Request, witID *uuid.UUID) *app.RelationWorkItemType {
+	if witID == nil {
+		return nil
+	}
+	witRelatedURL := rest.AbsoluteURL(request, app.WorkitemtypeHref(*witID))
+	return &app.RelationWorkItemType{
+		Data: &app.RelationWorkItemTypeData{
+			Type: APIStringTypeWorkItemType,
+			ID:   *witID,
+		},
+		Links: &app.GenericLinks{
+			Self:    &witRelatedURL,
+			Related: &witRelatedURL,
+		},
+	}
+}
+
 // ConvertWorkItemLinkTypeToModel converts the incoming app representation of a work item link type to the model layout.
 // Values are only overwrriten if they are set in "in", otherwise the values in "out" remain.
 func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*link.WorkItemLinkType, error) {
@@ -141,18 +165,26 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 		return nil, errors.NewBadParameterError("data.relationships", nil).Expected("not <nil>")
 	}
 
-	attrs := appLinkType.Data.Attributes
-	rel := appLinkType.Data.Relationships
-
 	if appLinkType.Data.ID != nil {
 		modelLinkType.ID = *appLinkType.Data.ID
 	}
+	if err := mergeWorkItemLinkType(appLinkType.Data, &modelLinkType); err != nil {
+		return nil, err
+	}
+	return &modelLinkType, nil
+}
+
+// mergeWorkItemLinkType overwrites the values of the given work item link type
+// with the ones that are set in the given app representation.
+func mergeWorkItemLinkType(data *app.WorkItemLinkTypeData, modelLinkType *link.WorkItemLinkType) error {
+	attrs := data.Attributes
+	rel := data.Relationships
 
 	if attrs != nil {
 		// If the name is not nil, it MUST NOT be empty
 		if attrs.Name != nil {
 			if *attrs.Name == "" {
-				return nil, errors.NewBadParameterError("data.attributes.name", *attrs.Name)
+				return errors.NewBadParameterError("data.attributes.name", *attrs.Name)
 			}
 			modelLinkType.Name = *attrs.Name
 		}
@@ -168,7 +200,7 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 		// If the forwardName is not nil, it MUST NOT be empty
 		if attrs.ForwardName != nil {
 			if *attrs.ForwardName == "" {
-				return nil, errors.NewBadParameterError("data.attributes.forward_name", *attrs.ForwardName)
+				return errors.NewBadParameterError("data.attributes.forward_name", *attrs.ForwardName)
 			}
 			modelLinkType.ForwardName = *attrs.ForwardName
 		}
@@ -176,7 +208,7 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 		// If the ReverseName is not nil, it MUST NOT be empty
 		if attrs.ReverseName != nil {
 			if *attrs.ReverseName == "" {
-				return nil, errors.NewBadParameterError("data.attributes.reverse_name", *attrs.ReverseName)
+				return errors.NewBadParameterError("data.attributes.reverse_name", *attrs.ReverseName)
 			}
 			modelLinkType.ReverseName = *attrs.ReverseName
 		}
@@ -184,9 +216,13 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 		if attrs.Topology != nil {
 			modelLinkType.Topology = link.Topology(*attrs.Topology)
 			if err := modelLinkType.Topology.CheckValid(); err != nil {
-				return nil, err
+				return err
 			}
 		}
+
+		if attrs.Blocking != nil {
+			modelLinkType.Blocking = *attrs.Blocking
+		}
 	}
 
 	if rel != nil && rel.LinkCategory != nil && rel.LinkCategory.Data != nil {
@@ -195,8 +231,22 @@ func ConvertWorkItemLinkTypeToModel(appLinkType app.WorkItemLinkTypeSingle) (*li
 	if rel != nil && rel.Space != nil && rel.Space.Data != nil {
 		modelLinkType.SpaceID = *rel.Space.Data.ID
 	}
+	// A type constraint is only changed when its relationship is given, an
+	// explicit null "data" removes the constraint.
+	if rel != nil && rel.SourceType != nil {
+		modelLinkType.SourceTypeID = nil
+		if rel.SourceType.Data != nil {
+			modelLinkType.SourceTypeID = &rel.SourceType.Data.ID
+		}
+	}
+	if rel != nil && rel.TargetType != nil {
+		modelLinkType.TargetTypeID = nil
+		if rel.TargetType.Data != nil {
+			modelLinkType.TargetTypeID = &rel.TargetType.Data.ID
+		}
+	}
 
-	return &modelLinkType, nil
+	return nil
 }
 
 func ConvertLinkTypesFromModels(request *http.Request, modelLinkTypes []link.WorkItemLinkType) (*app.WorkItemLinkTypeList, error) {
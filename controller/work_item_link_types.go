@@ -1,15 +1,20 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	errs "github.com/pkg/errors"
 
 	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
 )
 
 // WorkItemLinkTypesController implements the work-item-link-type resource.
@@ -72,3 +77,100 @@ func (c *WorkItemLinkTypesController) List(ctx *app.ListWorkItemLinkTypesContext
 		return ctx.OK(&appLinkTypes)
 	})
 }
+
+// loadSpaceLinkType loads the work item link type with the given ID and makes
+// sure that it belongs to the given space. The link types of the system space
+// are shared by all spaces and cannot be modified.
+func loadSpaceLinkType(ctx context.Context, appl application.Application, spaceID, linkTypeID uuid.UUID) (*link.WorkItemLinkType, error) {
+	modelLinkType, err := appl.WorkItemLinkTypes().Load(ctx, linkTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if uuid.Equal(modelLinkType.SpaceID, space.SystemSpace) {
+		return nil, errors.NewForbiddenError("work item link types of the system space cannot be modified")
+	}
+	if !uuid.Equal(modelLinkType.SpaceID, spaceID) {
+		return nil, errors.NewNotFoundError("work item link type", linkTypeID.String())
+	}
+	return modelLinkType, nil
+}
+
+// Create runs the create action.
+func (c *WorkItemLinkTypesController) Create(ctx *app.CreateWorkItemLinkTypesContext) error {
+	modelLinkType, err := ConvertWorkItemLinkTypeToModel(app.WorkItemLinkTypeSingle{Data: ctx.Payload.Data})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// link types are always created in the space of the request
+	modelLinkType.SpaceID = ctx.SpaceID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		modelLinkType, err = appl.WorkItemLinkTypes().Create(ctx, modelLinkType)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appLinkType := ConvertWorkItemLinkTypeFromModel(ctx.Request, *modelLinkType)
+	relatedURL := rest.AbsoluteURL(ctx.Request, app.WorkItemLinkTypeHref(modelLinkType.ID))
+	appLinkType.Data.Links = &app.GenericLinks{
+		Self:    &relatedURL,
+		Related: &relatedURL,
+	}
+	ctx.ResponseData.Header().Set("Location", app.WorkItemLinkTypeHref(modelLinkType.ID))
+	return ctx.Created(&appLinkType)
+}
+
+// Update runs the update action.
+func (c *WorkItemLinkTypesController) Update(ctx *app.UpdateWorkItemLinkTypesContext) error {
+	if ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data", nil).Expected("not nil"))
+	}
+	var modelLinkType *link.WorkItemLinkType
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		var err error
+		modelLinkType, err = loadSpaceLinkType(ctx, appl, ctx.SpaceID, ctx.WiltID)
+		if err != nil {
+			return err
+		}
+		if err := mergeWorkItemLinkType(ctx.Payload.Data, modelLinkType); err != nil {
+			return err
+		}
+		// a link type cannot be moved to another space
+		modelLinkType.SpaceID = ctx.SpaceID
+		modelLinkType, err = appl.WorkItemLinkTypes().Save(ctx, *modelLinkType)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	appLinkType := ConvertWorkItemLinkTypeFromModel(ctx.Request, *modelLinkType)
+	relatedURL := rest.AbsoluteURL(ctx.Request, app.WorkItemLinkTypeHref(modelLinkType.ID))
+	appLinkType.Data.Links = &app.GenericLinks{
+		Self:    &relatedURL,
+		Related: &relatedURL,
+	}
+	return ctx.OK(&appLinkType)
+}
+
+// Delete runs the delete action.
+func (c *WorkItemLinkTypesController) Delete(ctx *app.DeleteWorkItemLinkTypesContext) error {
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := checkSpaceOwner(ctx, appl, ctx.SpaceID); err != nil {
+			return err
+		}
+		if _, err := loadSpaceLinkType(ctx, appl, ctx.SpaceID, ctx.WiltID); err != nil {
+			return err
+		}
+		return appl.WorkItemLinkTypes().Delete(ctx, ctx.SpaceID, ctx.WiltID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
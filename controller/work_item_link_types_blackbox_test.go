@@ -1,20 +1,25 @@
 package controller_test
 
 import (
+	"net/http"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/app/test"
 	. "github.com/fabric8-services/fabric8-wit/controller"
 	"github.com/fabric8-services/fabric8-wit/gormapplication"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
 	uuid "github.com/satori/go.uuid"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
@@ -47,6 +52,20 @@ func (s *workItemLinkTypesSuite) UnSecuredController() (*goa.Service, *WorkItemL
 	return svc, NewWorkItemLinkTypesController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
 }
 
+func (s *workItemLinkTypesSuite) SecuredController(identity account.Identity) (*goa.Service, *WorkItemLinkTypesController) {
+	svc := testsupport.ServiceAsUser("WorkItemLinkTypes-Service", identity)
+	return svc, NewWorkItemLinkTypesController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
+}
+
+// newUpdateWorkItemLinkTypePayload returns the payload to update the given
+// work item link type with its current values
+func newUpdateWorkItemLinkTypePayload(linkType link.WorkItemLinkType) *app.UpdateWorkItemLinkTypePayload {
+	payload := ConvertWorkItemLinkTypeFromModel(&http.Request{Host: "api.service.domain.org"}, linkType)
+	return &app.UpdateWorkItemLinkTypePayload{
+		Data: payload.Data,
+	}
+}
+
 func (s *workItemLinkTypesSuite) TestList() {
 	// given
 	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItemLinkTypes(2))
@@ -120,3 +139,237 @@ func (s *workItemLinkTypesSuite) TestList() {
 		assertResponseHeaders(t, res)
 	})
 }
+
+func (s *workItemLinkTypesSuite) TestCreate() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(2), tf.Spaces(1), tf.WorkItemLinkCategories(1))
+
+	s.T().Run("ok - space owner", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), fxt.WorkItemLinkCategories[0].ID, fxt.Spaces[0].ID)
+		// when
+		res, created := test.CreateWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, *payload.Data.Attributes.Name, *created.Data.Attributes.Name)
+		assert.Equal(t, fxt.Spaces[0].ID, *created.Data.Relationships.Space.Data.ID)
+		assert.Equal(t, app.WorkItemLinkTypeHref(*created.Data.ID), res.Header().Get("Location"))
+	})
+
+	s.T().Run("ok - created in the space of the request", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), fxt.WorkItemLinkCategories[0].ID, uuid.NewV4())
+		// when
+		_, created := test.CreateWorkItemLinkTypesCreated(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+		// then
+		assert.Equal(t, fxt.Spaces[0].ID, *created.Data.Relationships.Space.Data.ID)
+	})
+
+	s.T().Run("bad request - empty name", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkTypePayload("", fxt.WorkItemLinkCategories[0].ID, fxt.Spaces[0].ID)
+		// when/then
+		test.CreateWorkItemLinkTypesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("bad request - unknown link category", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), uuid.NewV4(), fxt.Spaces[0].ID)
+		// when/then
+		test.CreateWorkItemLinkTypesBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), fxt.WorkItemLinkCategories[0].ID, fxt.Spaces[0].ID)
+		// when/then
+		test.CreateWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+
+	s.T().Run("not found - unknown space", func(t *testing.T) {
+		// given
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), fxt.WorkItemLinkCategories[0].ID, fxt.Spaces[0].ID)
+		// when/then
+		test.CreateWorkItemLinkTypesNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), payload)
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		svc, ctrl := s.UnSecuredController()
+		payload := newCreateWorkItemLinkTypePayload(testsupport.CreateRandomValidTestName("link type "), fxt.WorkItemLinkCategories[0].ID, fxt.Spaces[0].ID)
+		// when/then
+		test.CreateWorkItemLinkTypesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, payload)
+	})
+}
+
+func (s *workItemLinkTypesSuite) TestUpdate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0])
+		newName := testsupport.CreateRandomValidTestName("updated link type ")
+		payload.Data.Attributes.Name = &newName
+		// when
+		_, updated := test.UpdateWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, payload)
+		// then
+		assert.Equal(t, newName, *updated.Data.Attributes.Name)
+		assert.Equal(t, fxt.WorkItemLinkTypes[0].Version+1, *updated.Data.Attributes.Version)
+	})
+
+	s.T().Run("ok - type constraints kept when omitted", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(2), tf.WorkItemLinkTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkTypes[idx].SourceTypeID = &fxt.WorkItemTypes[0].ID
+			fxt.WorkItemLinkTypes[idx].TargetTypeID = &fxt.WorkItemTypes[1].ID
+			return nil
+		}))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0])
+		payload.Data.Relationships.SourceType = nil
+		payload.Data.Relationships.TargetType = nil
+		// when
+		_, updated := test.UpdateWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, payload)
+		// then
+		require.NotNil(t, updated.Data.Relationships.SourceType)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, updated.Data.Relationships.SourceType.Data.ID)
+		require.NotNil(t, updated.Data.Relationships.TargetType)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, updated.Data.Relationships.TargetType.Data.ID)
+	})
+
+	s.T().Run("ok - type constraints cleared with null data", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(2), tf.WorkItemLinkTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkTypes[idx].SourceTypeID = &fxt.WorkItemTypes[0].ID
+			fxt.WorkItemLinkTypes[idx].TargetTypeID = &fxt.WorkItemTypes[1].ID
+			return nil
+		}))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0])
+		payload.Data.Relationships.SourceType = &app.RelationWorkItemType{}
+		payload.Data.Relationships.TargetType = &app.RelationWorkItemType{}
+		// when
+		_, updated := test.UpdateWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, payload)
+		// then
+		assert.Nil(t, updated.Data.Relationships.SourceType)
+		assert.Nil(t, updated.Data.Relationships.TargetType)
+		loaded, err := link.NewWorkItemLinkTypeRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinkTypes[0].ID)
+		require.NoError(t, err)
+		assert.Nil(t, loaded.SourceTypeID)
+		assert.Nil(t, loaded.TargetTypeID)
+	})
+
+	s.T().Run("conflict - version", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0])
+		version := fxt.WorkItemLinkTypes[0].Version + 1
+		payload.Data.Attributes.Version = &version
+		// when/then
+		test.UpdateWorkItemLinkTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, payload)
+	})
+
+	s.T().Run("conflict - topology of a used link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.WorkItemLinks(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		payload := newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0])
+		topology := link.TopologyNetwork.String()
+		payload.Data.Attributes.Topology = &topology
+		// when/then
+		test.UpdateWorkItemLinkTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, payload)
+	})
+
+	s.T().Run("forbidden - link type of the system space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		systemLinkType, err := link.NewWorkItemLinkTypeRepository(s.DB).Load(s.Ctx, link.SystemWorkItemLinkTypeParentChildID)
+		require.NoError(t, err)
+		// when/then
+		test.UpdateWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, systemLinkType.ID, newUpdateWorkItemLinkTypePayload(*systemLinkType))
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		// when/then
+		test.UpdateWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0]))
+	})
+
+	s.T().Run("not found - link type of another space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.UpdateWorkItemLinkTypesNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[1].ID, fxt.WorkItemLinkTypes[0].ID, newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0]))
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.UpdateWorkItemLinkTypesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID, newUpdateWorkItemLinkTypePayload(*fxt.WorkItemLinkTypes[0]))
+	})
+}
+
+func (s *workItemLinkTypesSuite) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when
+		test.DeleteWorkItemLinkTypesOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+		// then
+		_, err := link.NewWorkItemLinkTypeRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinkTypes[0].ID)
+		require.Error(t, err)
+	})
+
+	s.T().Run("conflict - used by links", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.WorkItemLinks(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkTypesConflict(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+	})
+
+	s.T().Run("forbidden - link type of the system space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, link.SystemWorkItemLinkTypeParentChildID)
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[1])
+		// when/then
+		test.DeleteWorkItemLinkTypesForbidden(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		svc, ctrl := s.SecuredController(*fxt.Identities[0])
+		// when/then
+		test.DeleteWorkItemLinkTypesNotFound(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		svc, ctrl := s.UnSecuredController()
+		// when/then
+		test.DeleteWorkItemLinkTypesUnauthorized(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+	})
+}
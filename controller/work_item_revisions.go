@@ -0,0 +1,274 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/comment"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Defines the constants to be used in json api "type" attribute of the
+// revision history
+const (
+	APIStringTypeWorkItemRevision = "workitemrevisions"
+	APIStringTypeWorkItemActivity = "workitemactivities"
+)
+
+// The kinds of entries of the activity feed of a work item
+const (
+	activityKindWorkItem = "workitem"
+	activityKindComment  = "comment"
+	activityKindLink     = "link"
+)
+
+// WorkItemRevisionsController implements the work_item_revisions resource.
+type WorkItemRevisionsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemRevisionsController creates a work_item_revisions controller.
+func NewWorkItemRevisionsController(service *goa.Service, db application.DB) *WorkItemRevisionsController {
+	return &WorkItemRevisionsController{Controller: service.NewController("WorkItemRevisionsController"), db: db}
+}
+
+// List runs the list action.
+func (c *WorkItemRevisionsController) List(ctx *app.ListWorkItemRevisionsContext) error {
+	var revisions []workitem.Revision
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		if err != nil {
+			return err
+		}
+		revisions, err = appl.WorkItemRevisions().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorkItemRevisionList{
+		Data: make([]*app.WorkItemRevision, len(revisions)),
+	}
+	for i, r := range revisions {
+		var previous *workitem.Revision
+		if i > 0 {
+			previous = &revisions[i-1]
+		}
+		res.Data[i] = ConvertWorkItemRevision(ctx.Request, r, previous)
+	}
+	return ctx.OK(res)
+}
+
+// Activity runs the activity action.
+func (c *WorkItemRevisionsController) Activity(ctx *app.ActivityWorkItemRevisionsContext) error {
+	var revisions []workitem.Revision
+	var commentRevisions []comment.Revision
+	var linkRevisions []link.Revision
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		_, err := appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		if err != nil {
+			return err
+		}
+		revisions, err = appl.WorkItemRevisions().List(ctx, ctx.WiID)
+		if err != nil {
+			return err
+		}
+		commentRevisions, err = appl.CommentRevisions().ListForParent(ctx, ctx.WiID)
+		if err != nil {
+			return err
+		}
+		linkRevisions, err = appl.WorkItemLinkRevisions().ListForWorkItem(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	activities := make([]*app.WorkItemActivity, 0, len(revisions)+len(commentRevisions)+len(linkRevisions))
+	for i, r := range revisions {
+		var previous *workitem.Revision
+		if i > 0 {
+			previous = &revisions[i-1]
+		}
+		activities = append(activities, convertWorkItemRevisionActivity(ctx.Request, r, previous))
+	}
+	previousCommentRevisions := map[uuid.UUID]comment.Revision{}
+	for _, r := range commentRevisions {
+		var previous *comment.Revision
+		if p, ok := previousCommentRevisions[r.CommentID]; ok {
+			previous = &p
+		}
+		activities = append(activities, convertCommentRevisionActivity(ctx.Request, r, previous))
+		previousCommentRevisions[r.CommentID] = r
+	}
+	for _, r := range linkRevisions {
+		activities = append(activities, convertLinkRevisionActivity(ctx.Request, r))
+	}
+	// each of the lists is sorted by time already, a stable sort keeps the
+	// order of entries that happened at the same time
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Attributes.CreatedAt.Before(activities[j].Attributes.CreatedAt)
+	})
+	return ctx.OK(&app.WorkItemActivityList{Data: activities})
+}
+
+// ConvertWorkItemRevision converts a work item revision from model to REST
+// representation. The changes are computed against the given previous
+// revision, which is nil for the first revision.
+func ConvertWorkItemRevision(request *http.Request, r workitem.Revision, previous *workitem.Revision) *app.WorkItemRevision {
+	return &app.WorkItemRevision{
+		Type: APIStringTypeWorkItemRevision,
+		ID:   r.ID,
+		Attributes: &app.WorkItemRevisionAttributes{
+			RevisionType: workItemRevisionTypeName(r.Type),
+			CreatedAt:    r.Time,
+			Version:      r.WorkItemVersion,
+			Changes:      convertFieldChanges(r.Diff(previous)),
+			Fields:       r.WorkItemFields,
+		},
+		Relationships: &app.WorkItemRevisionRelations{
+			Modifier:     modifierRelation(request, r.ModifierIdentity),
+			Workitem:     genericRelation(request, APIStringTypeWorkItem, r.WorkItemID.String(), app.WorkitemHref(r.WorkItemID)),
+			Workitemtype: genericRelation(request, APIStringTypeWorkItemType, r.WorkItemTypeID.String(), app.WorkitemtypeHref(r.WorkItemTypeID)),
+		},
+	}
+}
+
+func convertWorkItemRevisionActivity(request *http.Request, r workitem.Revision, previous *workitem.Revision) *app.WorkItemActivity {
+	return &app.WorkItemActivity{
+		Type: APIStringTypeWorkItemActivity,
+		ID:   r.ID,
+		Attributes: &app.WorkItemActivityAttributes{
+			Kind:         activityKindWorkItem,
+			RevisionType: workItemRevisionTypeName(r.Type),
+			CreatedAt:    r.Time,
+			Changes:      convertFieldChanges(r.Diff(previous)),
+		},
+		Relationships: &app.WorkItemActivityRelations{
+			Modifier: modifierRelation(request, r.ModifierIdentity),
+		},
+	}
+}
+
+func convertCommentRevisionActivity(request *http.Request, r comment.Revision, previous *comment.Revision) *app.WorkItemActivity {
+	fields := workitem.Fields{}
+	if r.CommentBody != nil {
+		fields["body"] = *r.CommentBody
+	}
+	if r.CommentMarkup != nil {
+		fields["markup"] = *r.CommentMarkup
+	}
+	previousFields := workitem.Fields{}
+	if previous != nil {
+		if previous.CommentBody != nil {
+			previousFields["body"] = *previous.CommentBody
+		}
+		if previous.CommentMarkup != nil {
+			previousFields["markup"] = *previous.CommentMarkup
+		}
+	}
+	revisionType := revisionTypeName(int(r.Type))
+	switch r.Type {
+	case comment.RevisionTypeResolve:
+		revisionType = "resolve"
+	case comment.RevisionTypeUnresolve:
+		revisionType = "unresolve"
+	}
+	return &app.WorkItemActivity{
+		Type: APIStringTypeWorkItemActivity,
+		ID:   r.ID,
+		Attributes: &app.WorkItemActivityAttributes{
+			Kind:         activityKindComment,
+			RevisionType: revisionType,
+			CreatedAt:    r.Time,
+			Changes:      convertFieldChanges(workitem.DiffFields(previousFields, fields)),
+		},
+		Relationships: &app.WorkItemActivityRelations{
+			Modifier: modifierRelation(request, r.ModifierIdentity),
+			Comment:  genericRelation(request, "comments", r.CommentID.String(), app.CommentsHref(r.CommentID)),
+		},
+	}
+}
+
+func convertLinkRevisionActivity(request *http.Request, r link.Revision) *app.WorkItemActivity {
+	return &app.WorkItemActivity{
+		Type: APIStringTypeWorkItemActivity,
+		ID:   r.ID,
+		Attributes: &app.WorkItemActivityAttributes{
+			Kind:         activityKindLink,
+			RevisionType: revisionTypeName(int(r.Type)),
+			CreatedAt:    r.Time,
+		},
+		Relationships: &app.WorkItemActivityRelations{
+			Modifier: modifierRelation(request, r.ModifierIdentity),
+			Link:     genericRelation(request, link.EndpointWorkItemLinks, r.WorkItemLinkID.String(), app.WorkItemLinkHref(r.WorkItemLinkID)),
+			LinkType: genericRelation(request, link.EndpointWorkItemLinkTypes, r.WorkItemLinkTypeID.String(), app.WorkItemLinkTypeHref(r.WorkItemLinkTypeID)),
+			Source:   genericRelation(request, APIStringTypeWorkItem, r.WorkItemLinkSourceID.String(), app.WorkitemHref(r.WorkItemLinkSourceID)),
+			Target:   genericRelation(request, APIStringTypeWorkItem, r.WorkItemLinkTargetID.String(), app.WorkitemHref(r.WorkItemLinkTargetID)),
+		},
+	}
+}
+
+// revisionTypeName returns the name of the revision type. The work item,
+// comment and link revisions share the same revision type values.
+func revisionTypeName(revisionType int) string {
+	switch revisionType {
+	case int(workitem.RevisionTypeCreate):
+		return "create"
+	case int(workitem.RevisionTypeDelete):
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// workItemRevisionTypeName returns the name of the type of a work item
+// revision, which can also be a move to another space or a change of the
+// work item type
+func workItemRevisionTypeName(revisionType workitem.RevisionType) string {
+	switch revisionType {
+	case workitem.RevisionTypeMove:
+		return "move"
+	case workitem.RevisionTypeChangeType:
+		return "type-change"
+	}
+	return revisionTypeName(int(revisionType))
+}
+
+func convertFieldChanges(changes []workitem.FieldChange) []*app.FieldChange {
+	res := make([]*app.FieldChange, len(changes))
+	for i, change := range changes {
+		res[i] = &app.FieldChange{
+			Name: change.Name,
+			Old:  change.Old,
+			New:  change.New,
+		}
+	}
+	return res
+}
+
+func modifierRelation(request *http.Request, modifierID uuid.UUID) *app.RelationGeneric {
+	return genericRelation(request, APIStringTypeUser, modifierID.String(), fmt.Sprintf("%s/%s", usersEndpoint, modifierID))
+}
+
+func genericRelation(request *http.Request, resourceType, id, href string) *app.RelationGeneric {
+	related := rest.AbsoluteURL(request, href)
+	return &app.RelationGeneric{
+		Data: &app.GenericData{
+			Type: ptr.String(resourceType),
+			ID:   ptr.String(id),
+			Links: &app.GenericLinks{
+				Related: &related,
+			},
+		},
+	}
+}
@@ -0,0 +1,187 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	"github.com/fabric8-services/fabric8-wit/application"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemRevisionsREST struct {
+	gormtestsupport.DBTestSuite
+	db *gormapplication.GormDB
+}
+
+func TestRunWorkItemRevisionsREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemRevisionsREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWorkItemRevisionsREST) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.db = gormapplication.NewGormDB(s.DB)
+}
+
+func (s *TestWorkItemRevisionsREST) updateTitle(fxt *tf.TestFixture, title string) {
+	wi := fxt.WorkItems[0]
+	wi.Fields[workitem.SystemTitle] = title
+	updated, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	fxt.WorkItems[0] = updated
+}
+
+func (s *TestWorkItemRevisionsREST) TestList() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1, tf.SetWorkItemTitles("foo")))
+		s.updateTitle(fxt, "bar")
+		svc := goa.New("WorkItemRevisions-Service")
+		ctrl := NewWorkItemRevisionsController(svc, s.db)
+		// when
+		_, res := test.ListWorkItemRevisionsOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+		// then
+		require.Len(t, res.Data, 2)
+		assert.Equal(t, "create", res.Data[0].Attributes.RevisionType)
+		assert.Equal(t, "update", res.Data[1].Attributes.RevisionType)
+		require.Len(t, res.Data[1].Attributes.Changes, 1)
+		assert.Equal(t, workitem.SystemTitle, res.Data[1].Attributes.Changes[0].Name)
+		assert.Equal(t, "foo", res.Data[1].Attributes.Changes[0].Old)
+		assert.Equal(t, "bar", res.Data[1].Attributes.Changes[0].New)
+		require.NotNil(t, res.Data[1].Relationships.Modifier)
+		assert.Equal(t, fxt.Identities[0].ID.String(), *res.Data[1].Relationships.Modifier.Data.ID)
+	})
+	s.T().Run("unknown work item", func(t *testing.T) {
+		svc := goa.New("WorkItemRevisions-Service")
+		ctrl := NewWorkItemRevisionsController(svc, s.db)
+		test.ListWorkItemRevisionsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4())
+	})
+}
+
+func (s *TestWorkItemRevisionsREST) TestActivity() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(2, tf.SetWorkItemTitles("foo", "other")), tf.Comments(1), tf.WorkItemLinks(1))
+	s.updateTitle(fxt, "bar")
+	svc := goa.New("WorkItemRevisions-Service")
+	ctrl := NewWorkItemRevisionsController(svc, s.db)
+	// when
+	_, res := test.ActivityWorkItemRevisionsOK(s.T(), svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+	// then
+	kinds := []string{}
+	for _, a := range res.Data {
+		kinds = append(kinds, a.Attributes.Kind)
+	}
+	assert.Equal(s.T(), []string{"workitem", "comment", "link", "workitem"}, kinds)
+	assert.Equal(s.T(), fxt.Comments[0].ID.String(), *res.Data[1].Relationships.Comment.Data.ID)
+	assert.Equal(s.T(), fxt.WorkItemLinks[0].ID.String(), *res.Data[2].Relationships.Link.Data.ID)
+	for i := 1; i < len(res.Data); i++ {
+		assert.False(s.T(), res.Data[i].Attributes.CreatedAt.Before(res.Data[i-1].Attributes.CreatedAt))
+	}
+}
+
+// revertFixture creates a work item whose title was changed from "foo" to
+// "bar" and returns it together with its two revisions
+func (s *TestWorkItemRevisionsREST) revertFixture(t *testing.T) (*tf.TestFixture, []workitem.Revision) {
+	fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1, tf.SetWorkItemTitles("foo")))
+	s.updateTitle(fxt, "bar")
+	revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	return fxt, revisions
+}
+
+func (s *TestWorkItemRevisionsREST) TestRevert() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt, revisions := s.revertFixture(t)
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when
+		payload := app.WorkItemRevert{
+			Data: &app.WorkItemRevertData{
+				Revision: revisions[0].ID,
+				Version:  revisions[1].WorkItemVersion,
+				Fields:   []string{workitem.SystemTitle},
+			},
+		}
+		_, res := test.RevertWorkitemOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, &payload)
+		// then
+		assert.Equal(t, "foo", res.Data.Attributes[workitem.SystemTitle])
+		assert.Equal(t, revisions[1].WorkItemVersion+1, res.Data.Attributes["version"])
+	})
+	s.T().Run("version conflict", func(t *testing.T) {
+		// given a work item that was changed after the version sent by the client
+		fxt, revisions := s.revertFixture(t)
+		s.updateTitle(fxt, "baz")
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		payload := app.WorkItemRevert{
+			Data: &app.WorkItemRevertData{
+				Revision: revisions[0].ID,
+				Version:  revisions[1].WorkItemVersion,
+			},
+		}
+		// when/then
+		test.RevertWorkitemConflict(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, &payload)
+	})
+	s.T().Run("unknown revision", func(t *testing.T) {
+		// given
+		fxt, revisions := s.revertFixture(t)
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		payload := app.WorkItemRevert{
+			Data: &app.WorkItemRevertData{
+				Revision: uuid.NewV4(),
+				Version:  revisions[1].WorkItemVersion,
+			},
+		}
+		// when/then
+		test.RevertWorkitemNotFound(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID, &payload)
+	})
+}
+
+func (s *TestWorkItemRevisionsREST) TestRestore() {
+	s.T().Run("ok - with links", func(t *testing.T) {
+		// given a deleted work item that had a parent
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItemLinks(1, tf.BuildLinks(tf.L("parent", "child"))),
+		)
+		child := fxt.WorkItemByTitle("child")
+		err := application.Transactional(s.db, func(appl application.Application) error {
+			if err := appl.WorkItems().Delete(s.Ctx, child.ID, fxt.Identities[0].ID); err != nil {
+				return err
+			}
+			return appl.WorkItemLinks().DeleteRelatedLinks(s.Ctx, child.ID, fxt.Identities[0].ID)
+		})
+		require.NoError(t, err)
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		// when
+		_, res := test.RestoreWorkitemOK(t, svc.Context, svc, ctrl, child.ID)
+		// then the work item and its link to the parent are back
+		assert.Equal(t, child.ID, *res.Data.ID)
+		_, err = link.NewWorkItemLinkRepository(s.DB).Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.NoError(t, err)
+	})
+	s.T().Run("not deleted", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		svc := testsupport.ServiceAsUser("WorkItem-Service", *fxt.Identities[0])
+		ctrl := NewWorkitemController(svc, s.db, s.Configuration)
+		test.RestoreWorkitemNotFound(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+	})
+}
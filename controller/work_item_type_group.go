@@ -5,12 +5,13 @@ import (
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
-	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
 )
 
 // WorkItemTypeGroupController implements the work_item_type_group resource.
@@ -33,16 +34,28 @@ func NewWorkItemTypeGroupController(service *goa.Service, db application.DB) *Wo
 
 // Show runs the list action.
 func (c *WorkItemTypeGroupController) Show(ctx *app.ShowWorkItemTypeGroupContext) error {
-	// TODO(kwk): Replace with loading from DB once type groups are persistently
-	// stored in there.
-	for _, group := range workitem.TypeGroups() {
-		if group.ID == ctx.GroupID {
-			return ctx.OK(&app.WorkItemTypeGroupSingle{
-				Data: ConvertTypeGroup(ctx.Request, group),
-			})
+	for _, t := range spacetemplate.BuiltInTemplates() {
+		for _, group := range t.Template.BuiltInTypeGroups() {
+			if group.ID == ctx.GroupID {
+				return ctx.OK(&app.WorkItemTypeGroupSingle{
+					Data: ConvertTypeGroup(ctx.Request, group),
+				})
+			}
 		}
 	}
-	return jsonapi.JSONErrorResponse(ctx, errors.NewNotFoundError("type group", ctx.GroupID.String()))
+	// the groups of the spaces created from a template are stored in the DB
+	var group *workitem.WorkItemTypeGroup
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		var err error
+		group, err = appl.WorkItemTypeGroups().Load(ctx, ctx.GroupID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemTypeGroupSingle{
+		Data: ConvertTypeGroup(ctx.Request, *group),
+	})
 }
 
 // ConvertTypeGroup converts WorkitemTypeGroup model to a response resource
@@ -50,6 +63,9 @@ func (c *WorkItemTypeGroupController) Show(ctx *app.ShowWorkItemTypeGroupContext
 func ConvertTypeGroup(request *http.Request, tg workitem.WorkItemTypeGroup) *app.WorkItemTypeGroupData {
 
 	spaceTemplateID := space.SystemSpace
+	if tg.SpaceID != uuid.Nil {
+		spaceTemplateID = tg.SpaceID
+	}
 	spaceTemplateIDStr := spaceTemplateID.String()
 	workitemtypes := "workitemtypes"
 	workItemTypeGroupRelatedURL := rest.AbsoluteURL(request, app.WorkItemTypeGroupHref(tg.ID))
@@ -10,8 +10,8 @@ import (
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
 	testsupport "github.com/fabric8-services/fabric8-wit/test"
-	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/suite"
@@ -67,7 +67,7 @@ func (s *workItemTypeGroupSuite) TestList() {
 func (s *workItemTypeGroupSuite) TestShow() {
 	s.T().Run("ok", func(t *testing.T) {
 		// given
-		typeGroupID := workitem.TypeGroups()[0].ID
+		typeGroupID := spacetemplate.TypeGroups()[0].ID
 		// when
 		res, group := test.ShowWorkItemTypeGroupOK(t, nil, s.svc, s.typeGroupCtrl, typeGroupID)
 		// then
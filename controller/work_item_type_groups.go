@@ -6,6 +6,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/goadesign/goa"
 )
@@ -26,17 +27,28 @@ func NewWorkItemTypeGroupsController(service *goa.Service, db application.DB) *W
 
 // List runs the list action.
 func (c *WorkItemTypeGroupsController) List(ctx *app.ListWorkItemTypeGroupsContext) error {
+	var typeGroups []workitem.WorkItemTypeGroup
 	err := application.Transactional(c.db, func(appl application.Application) error {
-		return appl.Spaces().CheckExists(ctx, ctx.SpaceTemplateID)
+		err := appl.Spaces().CheckExists(ctx, ctx.SpaceTemplateID)
+		if err != nil {
+			return err
+		}
+		typeGroups, err = appl.WorkItemTypeGroups().List(ctx, ctx.SpaceTemplateID)
+		return err
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
-	typeGroups := workitem.TypeGroups()
+	selfSpaceID := ctx.SpaceTemplateID
+	if len(typeGroups) == 0 {
+		// the space was created without a template
+		typeGroups = spacetemplate.TypeGroups()
+		selfSpaceID = space.SystemSpace
+	}
 	res := &app.WorkItemTypeGroupList{
 		Data: make([]*app.WorkItemTypeGroupData, len(typeGroups)),
 		Links: &app.WorkItemTypeGroupLinks{
-			Self: rest.AbsoluteURL(ctx.Request, app.SpaceTemplateHref(space.SystemSpace)) + "/" + APIWorkItemTypeGroups,
+			Self: rest.AbsoluteURL(ctx.Request, app.SpaceTemplateHref(selfSpaceID)) + "/" + APIWorkItemTypeGroups,
 		},
 	}
 	for i, group := range typeGroups {
@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemWatchersController implements the work_item_watchers resource.
+type WorkItemWatchersController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemWatchersController creates a work_item_watchers controller.
+func NewWorkItemWatchersController(service *goa.Service, db application.DB) *WorkItemWatchersController {
+	return &WorkItemWatchersController{
+		Controller: service.NewController("WorkItemWatchersController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemWatchersController) List(ctx *app.ListWorkItemWatchersContext) error {
+	// the current user is optional when listing the watchers
+	currentIdentityID, _ := login.ContextIdentity(ctx)
+	var watchers []uuid.UUID
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.WorkItems().LoadByID(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		watchers, err = appl.WorkItemWatchers().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertWatchers(ctx.Request, watchers, currentIdentityID))
+}
+
+// Watch runs the watch action.
+func (c *WorkItemWatchersController) Watch(ctx *app.WatchWorkItemWatchersContext) error {
+	currentIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var watchers []uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.WorkItems().LoadByID(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		if err := appl.WorkItemWatchers().Watch(ctx, ctx.WiID, *currentIdentityID); err != nil {
+			return err
+		}
+		watchers, err = appl.WorkItemWatchers().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertWatchers(ctx.Request, watchers, currentIdentityID))
+}
+
+// Unwatch runs the unwatch action.
+func (c *WorkItemWatchersController) Unwatch(ctx *app.UnwatchWorkItemWatchersContext) error {
+	currentIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var watchers []uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.WorkItems().LoadByID(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		if err := appl.WorkItemWatchers().Unwatch(ctx, ctx.WiID, *currentIdentityID); err != nil {
+			return err
+		}
+		watchers, err = appl.WorkItemWatchers().List(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertWatchers(ctx.Request, watchers, currentIdentityID))
+}
+
+// ConvertWatchers converts the identity IDs of the watchers of a work item
+// into a list of users, the meta tells whether the given current identity
+// (if any) is one of them
+func ConvertWatchers(request *http.Request, watchers []uuid.UUID, currentIdentityID *uuid.UUID) *app.WatcherList {
+	res := &app.WatcherList{
+		Data: make([]*app.GenericData, len(watchers)),
+		Meta: &app.WatcherListMeta{TotalCount: len(watchers)},
+	}
+	for i, id := range watchers {
+		res.Data[i] = ConvertUserSimple(request, id)
+		if currentIdentityID != nil && uuid.Equal(*currentIdentityID, id) {
+			res.Meta.Watching = true
+		}
+	}
+	return res
+}
+
+// workItemIncludeWatchers adds the "watchers" relationship to the work item,
+// its meta tells whether the current user watches the work item
+func workItemIncludeWatchers(ctx context.Context, db application.DB) WorkItemConvertFunc {
+	return func(request *http.Request, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		related := rest.AbsoluteURL(request, app.WorkitemHref(wi.ID)) + "/watchers"
+		currentIdentityID, _ := login.ContextIdentity(ctx)
+		var watchers []uuid.UUID
+		err := application.Transactional(db, func(appl application.Application) error {
+			var err error
+			watchers, err = appl.WorkItemWatchers().List(ctx, wi.ID)
+			return err
+		})
+		if err != nil {
+			log.Error(ctx, map[string]interface{}{
+				"wi_id": wi.ID,
+				"err":   err,
+			}, "unable to list the watchers of the work item")
+		}
+		list := ConvertWatchers(request, watchers, currentIdentityID)
+		wi2.Relationships.Watchers = &app.RelationGenericList{
+			Data: list.Data,
+			Links: &app.GenericLinks{
+				Related: &related,
+			},
+			Meta: map[string]interface{}{
+				"totalCount": list.Meta.TotalCount,
+				"watching":   list.Meta.Watching,
+			},
+		}
+	}
+}
+
+// enqueueWorkItemMessage adds the watchers of the given work item to the
+// message before it is handed over to the notification channel, so that the
+// notification service knows the recipients
+func enqueueWorkItemMessage(ctx context.Context, appl application.Application, channel notification.Channel, workItemID uuid.UUID, msg notification.Message) error {
+	watchers, err := appl.WorkItemWatchers().List(ctx, workItemID)
+	if err != nil {
+		return err
+	}
+	msg.Watchers = watchers
+	return notification.Enqueue(ctx, channel, appl.NotificationOutbox(), msg)
+}
@@ -0,0 +1,138 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type workItemWatchersSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestSuiteWorkItemWatchers(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &workItemWatchersSuite{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *workItemWatchersSuite) newController(svc *goa.Service) *WorkItemWatchersController {
+	return NewWorkItemWatchersController(svc, gormapplication.NewGormDB(s.DB))
+}
+
+func (s *workItemWatchersSuite) TestList() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(2), tf.WorkItems(1))
+
+	s.T().Run("ok - watching", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[0])
+		// when
+		_, list := test.ListWorkItemWatchersOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+		// then the creator watches the work item
+		require.Len(t, list.Data, 1)
+		assert.Equal(t, fxt.Identities[0].ID.String(), *list.Data[0].ID)
+		assert.Equal(t, 1, list.Meta.TotalCount)
+		assert.True(t, list.Meta.Watching)
+	})
+
+	s.T().Run("ok - not watching", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[1])
+		// when
+		_, list := test.ListWorkItemWatchersOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+		// then
+		assert.Equal(t, 1, list.Meta.TotalCount)
+		assert.False(t, list.Meta.Watching)
+	})
+
+	s.T().Run("ok - no token", func(t *testing.T) {
+		// given
+		svc := goa.New("Watchers-Service")
+		// when
+		_, list := test.ListWorkItemWatchersOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+		// then
+		assert.Equal(t, 1, list.Meta.TotalCount)
+		assert.False(t, list.Meta.Watching)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		svc := goa.New("Watchers-Service")
+		// when/then
+		test.ListWorkItemWatchersNotFound(t, svc.Context, svc, s.newController(svc), uuid.NewV4())
+	})
+}
+
+func (s *workItemWatchersSuite) TestWatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItems(1))
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[1])
+		ctrl := s.newController(svc)
+		// when
+		_, list := test.WatchWorkItemWatchersOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+		// then
+		assert.Equal(t, 2, list.Meta.TotalCount)
+		assert.True(t, list.Meta.Watching)
+		// watching twice is fine
+		_, list = test.WatchWorkItemWatchersOK(t, svc.Context, svc, ctrl, fxt.WorkItems[0].ID)
+		assert.Equal(t, 2, list.Meta.TotalCount)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[0])
+		// when/then
+		test.WatchWorkItemWatchersNotFound(t, svc.Context, svc, s.newController(svc), uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		svc := goa.New("Watchers-Service")
+		// when/then
+		test.WatchWorkItemWatchersUnauthorized(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+	})
+}
+
+func (s *workItemWatchersSuite) TestUnwatch() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.WorkItems(1))
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[0])
+		// when
+		_, list := test.UnwatchWorkItemWatchersOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+		// then
+		assert.Empty(t, list.Data)
+		assert.Equal(t, 0, list.Meta.TotalCount)
+		assert.False(t, list.Meta.Watching)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc := testsupport.ServiceAsUser("Watchers-Service", *fxt.Identities[0])
+		// when/then
+		test.UnwatchWorkItemWatchersNotFound(t, svc.Context, svc, s.newController(svc), uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		svc := goa.New("Watchers-Service")
+		// when/then
+		test.UnwatchWorkItemWatchersUnauthorized(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID)
+	})
+}
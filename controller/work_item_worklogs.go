@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemWorklogsController implements the work_item_worklogs resource.
+type WorkItemWorklogsController struct {
+	*goa.Controller
+	db application.DB
+}
+
+// NewWorkItemWorklogsController creates a work_item_worklogs controller.
+func NewWorkItemWorklogsController(service *goa.Service, db application.DB) *WorkItemWorklogsController {
+	return &WorkItemWorklogsController{
+		Controller: service.NewController("WorkItemWorklogsController"),
+		db:         db,
+	}
+}
+
+// List runs the list action.
+func (c *WorkItemWorklogsController) List(ctx *app.ListWorkItemWorklogsContext) error {
+	var worklogs []workitem.Worklog
+	var totals, rolledUp *workitem.TimeTotals
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if _, err := appl.WorkItems().LoadByID(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		var err error
+		if worklogs, err = appl.WorkItemWorklogs().List(ctx, ctx.WiID); err != nil {
+			return err
+		}
+		if totals, err = appl.WorkItemWorklogs().Totals(ctx, ctx.WiID, false); err != nil {
+			return err
+		}
+		rolledUp, err = appl.WorkItemWorklogs().Totals(ctx, ctx.WiID, true)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorklogList{
+		Data: make([]*app.Worklog, len(worklogs)),
+		Meta: &app.WorklogListMeta{
+			TotalCount:                len(worklogs),
+			TimeSpent:                 int(totals.TimeSpent),
+			OriginalEstimate:          int(totals.OriginalEstimate),
+			RemainingEstimate:         int(totals.RemainingEstimate),
+			RolledUpTimeSpent:         int(rolledUp.TimeSpent),
+			RolledUpOriginalEstimate:  int(rolledUp.OriginalEstimate),
+			RolledUpRemainingEstimate: int(rolledUp.RemainingEstimate),
+		},
+	}
+	for i, w := range worklogs {
+		res.Data[i] = ConvertWorklog(ctx.Request, w)
+	}
+	return ctx.OK(res)
+}
+
+// Create runs the create action.
+func (c *WorkItemWorklogsController) Create(ctx *app.CreateWorkItemWorklogsContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Attributes == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.attributes", nil).Expected("the time spent"))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not a space collaborator"))
+	}
+	attrs := ctx.Payload.Data.Attributes
+	w := workitem.Worklog{
+		WorkItemID: wi.ID,
+		Creator:    *currentUser,
+		Duration:   int64(attrs.Duration),
+	}
+	if attrs.StartedAt != nil {
+		w.StartedAt = *attrs.StartedAt
+	}
+	if attrs.Note != nil {
+		w.Note = *attrs.Note
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.WorkItemWorklogs().Create(ctx, &w)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	res := &app.WorklogSingle{
+		Data: ConvertWorklog(ctx.Request, w),
+	}
+	ctx.ResponseData.Header().Set("Location", *res.Data.Links.Self)
+	return ctx.Created(res)
+}
+
+// Delete runs the delete action.
+func (c *WorkItemWorklogsController) Delete(ctx *app.DeleteWorkItemWorklogsContext) error {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var w *workitem.Worklog
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		w, err = appl.WorkItemWorklogs().Load(ctx, ctx.WorklogID)
+		if err != nil {
+			return err
+		}
+		if !uuid.Equal(w.WorkItemID, ctx.WiID) {
+			return errors.NewNotFoundError("worklog", ctx.WorklogID.String())
+		}
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	// User is allowed to delete if user is creator of the worklog OR user is
+	// a space collaborator
+	if !uuid.Equal(*currentUser, w.Creator) {
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+		}
+		if !authorized {
+			return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not a space collaborator"))
+		}
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		return appl.WorkItemWorklogs().Delete(ctx, w.ID)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK([]byte{})
+}
+
+// ConvertWorklog converts from internal to external REST representation
+func ConvertWorklog(request *http.Request, w workitem.Worklog) *app.Worklog {
+	parentHref := app.WorkitemHref(w.WorkItemID)
+	selfURL := rest.AbsoluteURL(request, parentHref+"/worklogs/"+w.ID.String())
+	res := &app.Worklog{
+		Type: workitem.APIStringTypeWorklogs,
+		ID:   &w.ID,
+		Attributes: &app.WorklogAttributes{
+			StartedAt: &w.StartedAt,
+			Duration:  int(w.Duration),
+			CreatedAt: &w.CreatedAt,
+		},
+		Relationships: &app.WorklogRelations{
+			Creator: &app.RelationGeneric{
+				Data: ConvertUserSimple(request, w.Creator),
+			},
+			Parent: genericRelation(request, APIStringTypeWorkItem, w.WorkItemID.String(), parentHref),
+		},
+		Links: &app.GenericLinks{
+			Self: &selfURL,
+		},
+	}
+	if w.Note != "" {
+		res.Attributes.Note = &w.Note
+	}
+	return res
+}
@@ -0,0 +1,194 @@
+package controller_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type workItemWorklogsSuite struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestSuiteWorkItemWorklogs(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &workItemWorklogsSuite{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *workItemWorklogsSuite) newController(svc *goa.Service) *WorkItemWorklogsController {
+	return NewWorkItemWorklogsController(svc, gormapplication.NewGormDB(s.DB))
+}
+
+func newCreateWorklogPayload(duration int, note *string) *app.CreateWorkItemWorklogsPayload {
+	return &app.CreateWorkItemWorklogsPayload{
+		Data: &app.Worklog{
+			Type: workitem.APIStringTypeWorklogs,
+			Attributes: &app.WorklogAttributes{
+				Duration: duration,
+				Note:     note,
+			},
+		},
+	}
+}
+
+// createWorklog logs the given time on the given work item as the given user
+func (s *workItemWorklogsSuite) createWorklog(t *testing.T, svc *goa.Service, wiID uuid.UUID, duration int) *app.WorklogSingle {
+	_, created := test.CreateWorkItemWorklogsCreated(t, svc.Context, svc, s.newController(svc), wiID, newCreateWorklogPayload(duration, nil))
+	require.NotNil(t, created.Data.ID)
+	return created
+}
+
+func (s *workItemWorklogsSuite) TestCreate() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(2), tf.WorkItems(1))
+	spaceAuthz := &TestSpaceAuthzService{*fxt.Identities[0], ""}
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[0], spaceAuthz)
+		// when
+		_, created := test.CreateWorkItemWorklogsCreated(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, newCreateWorklogPayload(5400, ptr.String("Reproduced the issue")))
+		// then
+		require.NotNil(t, created.Data.ID)
+		assert.Equal(t, 5400, created.Data.Attributes.Duration)
+		require.NotNil(t, created.Data.Attributes.Note)
+		assert.Equal(t, "Reproduced the issue", *created.Data.Attributes.Note)
+		assert.NotNil(t, created.Data.Attributes.StartedAt)
+		assert.Equal(t, fxt.Identities[0].ID.String(), *created.Data.Relationships.Creator.Data.ID)
+		assert.Equal(t, fxt.WorkItems[0].ID.String(), *created.Data.Relationships.Parent.Data.ID)
+	})
+
+	s.T().Run("bad request - no duration", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[0], spaceAuthz)
+		// when/then
+		test.CreateWorkItemWorklogsBadRequest(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, newCreateWorklogPayload(0, nil))
+	})
+
+	s.T().Run("forbidden - not a collaborator", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[1], spaceAuthz)
+		// when/then
+		test.CreateWorkItemWorklogsForbidden(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, newCreateWorklogPayload(60, nil))
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		svc := testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[0], spaceAuthz)
+		// when/then
+		test.CreateWorkItemWorklogsNotFound(t, svc.Context, svc, s.newController(svc), uuid.NewV4(), newCreateWorklogPayload(60, nil))
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		svc := goa.New("Worklogs-Service")
+		// when/then
+		test.CreateWorkItemWorklogsUnauthorized(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, newCreateWorklogPayload(60, nil))
+	})
+}
+
+func (s *workItemWorklogsSuite) TestList() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a parent with a child and time logged on both
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(2, tf.SetWorkItemTitles("parent", "child")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("parent", "child"))),
+		)
+		parent := fxt.WorkItemByTitle("parent")
+		child := fxt.WorkItemByTitle("child")
+		spaceAuthz := &TestSpaceAuthzService{*fxt.Identities[0], ""}
+		svc := testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[0], spaceAuthz)
+		s.createWorklog(t, svc, parent.ID, 60)
+		s.createWorklog(t, svc, parent.ID, 120)
+		s.createWorklog(t, svc, child.ID, 30)
+		// when
+		_, list := test.ListWorkItemWorklogsOK(t, nil, nil, s.newController(svc), parent.ID)
+		// then
+		require.Len(t, list.Data, 2)
+		assert.Equal(t, 2, list.Meta.TotalCount)
+		assert.Equal(t, 180, list.Meta.TimeSpent)
+		assert.Equal(t, 210, list.Meta.RolledUpTimeSpent)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		// given
+		svc := goa.New("Worklogs-Service")
+		// when/then
+		test.ListWorkItemWorklogsNotFound(t, nil, nil, s.newController(svc), uuid.NewV4())
+	})
+}
+
+func (s *workItemWorklogsSuite) TestDelete() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Identities(3), tf.WorkItems(1))
+	// the second identity is a collaborator, the third one is not
+	spaceAuthz := &TestSpaceAuthzService{*fxt.Identities[0], fxt.Identities[1].ID.String()}
+	asUser := func(idx int) *goa.Service {
+		return testsupport.ServiceAsSpaceUser("Worklogs-Service", *fxt.Identities[idx], spaceAuthz)
+	}
+
+	s.T().Run("ok - creator", func(t *testing.T) {
+		// given
+		created := s.createWorklog(t, asUser(0), fxt.WorkItems[0].ID, 60)
+		svc := asUser(0)
+		// when
+		test.DeleteWorkItemWorklogsOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, *created.Data.ID)
+		// then
+		_, list := test.ListWorkItemWorklogsOK(t, nil, nil, s.newController(svc), fxt.WorkItems[0].ID)
+		for _, w := range list.Data {
+			assert.NotEqual(t, *created.Data.ID, *w.ID)
+		}
+	})
+
+	s.T().Run("ok - collaborator", func(t *testing.T) {
+		// given
+		created := s.createWorklog(t, asUser(0), fxt.WorkItems[0].ID, 60)
+		svc := asUser(1)
+		// when/then
+		test.DeleteWorkItemWorklogsOK(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, *created.Data.ID)
+	})
+
+	s.T().Run("forbidden - not a collaborator", func(t *testing.T) {
+		// given
+		created := s.createWorklog(t, asUser(0), fxt.WorkItems[0].ID, 60)
+		svc := asUser(2)
+		// when/then
+		test.DeleteWorkItemWorklogsForbidden(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, *created.Data.ID)
+	})
+
+	s.T().Run("not found - worklog of another work item", func(t *testing.T) {
+		// given
+		created := s.createWorklog(t, asUser(0), fxt.WorkItems[0].ID, 60)
+		svc := asUser(0)
+		// when/then
+		test.DeleteWorkItemWorklogsNotFound(t, svc.Context, svc, s.newController(svc), uuid.NewV4(), *created.Data.ID)
+	})
+
+	s.T().Run("not found - unknown worklog", func(t *testing.T) {
+		// given
+		svc := asUser(0)
+		// when/then
+		test.DeleteWorkItemWorklogsNotFound(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, uuid.NewV4())
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		created := s.createWorklog(t, asUser(0), fxt.WorkItems[0].ID, 60)
+		svc := goa.New("Worklogs-Service")
+		// when/then
+		test.DeleteWorkItemWorklogsUnauthorized(t, svc.Context, svc, s.newController(svc), fxt.WorkItems[0].ID, *created.Data.ID)
+	})
+}
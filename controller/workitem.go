@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"html"
 	"net/http"
+	"sort"
 	"strconv"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 
 	"github.com/goadesign/goa"
@@ -51,6 +53,8 @@ type WorkitemController struct {
 type WorkItemControllerConfig interface {
 	GetCacheControlWorkItems() string
 	GetCacheControlWorkItem() string
+	GetWorkItemBulkUpdateLimit() int
+	GetWorkItemScheduleLimit() int
 }
 
 // NewWorkitemController creates a workitem controller.
@@ -127,14 +131,13 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 		if err != nil {
 			return errs.Wrap(err, "Error updating work item")
 		}
-		return nil
+		return enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *wi)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
 	}
-	c.notification.Send(ctx, notification.NewWorkItemUpdated(ctx.Payload.Data.ID.String()))
 	resp := &app.WorkItemSingle{
-		Data: ConvertWorkItem(ctx.Request, *wi, workItemIncludeHasChildren(ctx, c.db)),
+		Data: ConvertWorkItem(ctx.Request, *wi, workItemIncludeHasChildren(ctx, c.db), workItemIncludeBlockers(ctx, c.db, *wi), workItemIncludeRollUps(ctx, c.db, *wi)),
 		Links: &app.WorkItemLinks{
 			Self: buildAbsoluteURL(ctx.Request),
 		},
@@ -143,6 +146,514 @@ func (c *WorkitemController) Update(ctx *app.UpdateWorkitemContext) error {
 	return ctx.OK(resp)
 }
 
+// Revert does POST workitem revert
+func (c *WorkitemController) Revert(ctx *app.RevertWorkitemContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data element in request", nil))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, c.db, wi.SpaceID, creator.(string), currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		data := ctx.Payload.Data
+		wi, err = appl.WorkItems().Revert(ctx, ctx.WiID, data.Revision, data.Version, data.Fields, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to revert work item %s to revision %s", ctx.WiID, data.Revision)
+		}
+		return enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *wi)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	resp := &app.WorkItemSingle{
+		Data: ConvertWorkItem(ctx.Request, *wi, workItemIncludeHasChildren(ctx, c.db)),
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(resp)
+}
+
+// ChangeType does POST workitem change-type
+func (c *WorkitemController) ChangeType(ctx *app.ChangeTypeWorkitemContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data element in request", nil))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, c.db, wi.SpaceID, creator.(string), currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	data := ctx.Payload.Data
+	var change *workitem.TypeChange
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, change, err = appl.WorkItems().ChangeType(ctx, ctx.WiID, data.Type, data.Version, data.Confirm != nil && *data.Confirm, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to change the type of work item %s to %s", ctx.WiID, data.Type)
+		}
+		return enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *wi)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(&app.WorkItemChangeTypeList{
+		Data: ConvertWorkItems(ctx.Request, []workitem.WorkItem{*wi}, workItemIncludeHasChildren(ctx, c.db)),
+		Meta: &app.WorkItemChangeTypeMeta{Kept: change.Kept, Dropped: change.Dropped},
+	})
+}
+
+// Restore does POST workitem restore
+func (c *WorkitemController) Restore(ctx *app.RestoreWorkitemContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().Restore(ctx, ctx.WiID, *currentUserIdentityID)
+		if err != nil {
+			return errs.Wrapf(err, "failed to restore work item %s", ctx.WiID)
+		}
+		// the space of a deleted work item can only be checked once the work
+		// item is restored, which is why the restoration is rolled back if
+		// the user is not authorized
+		authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+		if err != nil {
+			return errors.NewUnauthorizedError(err.Error())
+		}
+		if !authorized {
+			return errors.NewForbiddenError("user is not authorized to access the space")
+		}
+		// the links deleted together with the work item are restored as well
+		if _, err := appl.WorkItemLinks().RestoreRelatedLinks(ctx, ctx.WiID, *currentUserIdentityID); err != nil {
+			return errs.Wrapf(err, "failed to restore the links of work item %s", ctx.WiID)
+		}
+		return enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *wi)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	resp := &app.WorkItemSingle{
+		Data: ConvertWorkItem(ctx.Request, *wi, workItemIncludeHasChildren(ctx, c.db)),
+	}
+	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
+	return ctx.OK(resp)
+}
+
+// Move does POST workitem move
+func (c *WorkitemController) Move(ctx *app.MoveWorkitemContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data element in request", nil))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	creator := wi.Fields[workitem.SystemCreator]
+	if creator == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewInternalError(ctx, errs.New("work item doesn't have creator")))
+	}
+	authorized, err := authorizeWorkitemEditor(ctx, c.db, wi.SpaceID, creator.(string), currentUserIdentityID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	targetSpaceID := ctx.Payload.Data.Space
+	authorized, err = authz.Authorize(ctx, targetSpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the target space"))
+	}
+	moved := []workitem.WorkItem{}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		ids := []uuid.UUID{wi.ID}
+		if ctx.Payload.Data.Children != nil && *ctx.Payload.Data.Children {
+			children, err := listWorkItemDescendants(ctx, appl, *wi)
+			if err != nil {
+				return err
+			}
+			ids = append(ids, children...)
+		}
+		for _, id := range ids {
+			movedWI, err := appl.WorkItems().Move(ctx, id, targetSpaceID, *currentUserIdentityID)
+			if err != nil {
+				return errs.Wrapf(err, "failed to move work item %s to space %s", id, targetSpaceID)
+			}
+			if err := enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *movedWI); err != nil {
+				return err
+			}
+			moved = append(moved, *movedWI)
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(&app.WorkItemList{
+		Links: &app.PagingLinks{},
+		Meta:  &app.WorkItemListResponseMeta{TotalCount: len(moved)},
+		Data:  ConvertWorkItems(ctx.Request, moved, workItemIncludeHasChildren(ctx, c.db)),
+	})
+}
+
+// listWorkItemDescendants returns the IDs of the children of the given work
+// item (following the parent-child link type), of their children and so on.
+// Only the descendants that are in the same space as the given work item are
+// returned, parents come before their children.
+func listWorkItemDescendants(ctx context.Context, appl application.Application, wi workitem.WorkItem) ([]uuid.UUID, error) {
+	visited := map[uuid.UUID]bool{wi.ID: true}
+	descendants := []uuid.UUID{}
+	parentIDs := []uuid.UUID{wi.ID}
+	for len(parentIDs) > 0 {
+		childLinks, err := appl.WorkItemLinks().ListChildLinks(ctx, link.SystemWorkItemLinkTypeParentChildID, parentIDs...)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to list the children of work items %v", parentIDs)
+		}
+		childIDs := []uuid.UUID{}
+		for _, l := range childLinks {
+			if !visited[l.TargetID] {
+				visited[l.TargetID] = true
+				childIDs = append(childIDs, l.TargetID)
+			}
+		}
+		parentIDs = []uuid.UUID{}
+		if len(childIDs) == 0 {
+			break
+		}
+		children, err := appl.WorkItems().LoadBatchByID(ctx, childIDs)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to load the work items %v", childIDs)
+		}
+		for _, child := range children {
+			if uuid.Equal(child.SpaceID, wi.SpaceID) {
+				descendants = append(descendants, child.ID)
+				parentIDs = append(parentIDs, child.ID)
+			}
+		}
+	}
+	return descendants, nil
+}
+
+// Clone does POST workitem clone
+func (c *WorkitemController) Clone(ctx *app.CloneWorkitemContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data element in request", nil))
+	}
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	var wi *workitem.WorkItem
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wi, err = appl.WorkItems().LoadByID(ctx, ctx.WiID)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	authorized, err := authz.Authorize(ctx, wi.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	data := ctx.Payload.Data
+	opts := workitem.CloneOptions{
+		ResetState:     data.ResetState != nil && *data.ResetState,
+		ClearAssignees: data.ClearAssignees != nil && *data.ClearAssignees,
+		IterationID:    data.Iteration,
+		AreaID:         data.Area,
+	}
+	var clones []workitem.WorkItem
+	var clonedIDs map[uuid.UUID]uuid.UUID
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		if opts.IterationID != nil {
+			itr, err := appl.Iterations().Load(ctx, *opts.IterationID)
+			if err != nil || !uuid.Equal(itr.SpaceID, wi.SpaceID) {
+				return errors.NewBadParameterError("iteration", *opts.IterationID).Expected("an iteration of the space of the work item")
+			}
+		}
+		if opts.AreaID != nil {
+			ar, err := appl.Areas().Load(ctx, *opts.AreaID)
+			if err != nil || !uuid.Equal(ar.SpaceID, wi.SpaceID) {
+				return errors.NewBadParameterError("area", *opts.AreaID).Expected("an area of the space of the work item")
+			}
+		}
+		clones, clonedIDs, err = cloneWorkItemTree(ctx, appl, *wi, data.Children != nil && *data.Children, opts, *currentUserIdentityID)
+		if err != nil {
+			return err
+		}
+		for _, clone := range clones {
+			if err := enqueueWorkItemCreated(ctx, appl, c.notification, ctx.Request, clone); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	mapping := make(map[string]uuid.UUID, len(clonedIDs))
+	for originalID, cloneID := range clonedIDs {
+		mapping[originalID.String()] = cloneID
+	}
+	return ctx.Created(&app.WorkItemCloneList{
+		Data: ConvertWorkItems(ctx.Request, clones, workItemIncludeHasChildren(ctx, c.db)),
+		Meta: &app.WorkItemCloneMeta{Mapping: mapping},
+	})
+}
+
+// cloneWorkItemTree creates a clone of the given work item and, if children
+// is true, of its descendants in the same space reached through links of a
+// tree topology. The links between the cloned work items are cloned as well.
+// The clones are returned (parents before their children) along with the IDs
+// of the clones by the IDs of the cloned work items.
+func cloneWorkItemTree(ctx context.Context, appl application.Application, wi workitem.WorkItem, children bool, opts workitem.CloneOptions, creatorID uuid.UUID) ([]workitem.WorkItem, map[uuid.UUID]uuid.UUID, error) {
+	var treeLinkTypes []link.WorkItemLinkType
+	if children {
+		linkTypes, err := appl.WorkItemLinkTypes().List(ctx, wi.SpaceID)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to list the link types of space %s", wi.SpaceID)
+		}
+		for _, lt := range linkTypes {
+			if lt.Topology == link.TopologyTree {
+				treeLinkTypes = append(treeLinkTypes, lt)
+			}
+		}
+	}
+	clones := []workitem.WorkItem{}
+	clonedIDs := map[uuid.UUID]uuid.UUID{}
+	queued := map[uuid.UUID]bool{wi.ID: true}
+	links := link.WorkItemLinkList{}
+	toClone := []workitem.WorkItem{wi}
+	for len(toClone) > 0 {
+		parentIDs := []uuid.UUID{}
+		for _, original := range toClone {
+			wiType, err := appl.WorkItemTypes().Load(ctx, original.Type)
+			if err != nil {
+				return nil, nil, errs.Wrapf(err, "failed to load the type of work item %s", original.ID)
+			}
+			clone, err := appl.WorkItems().Create(ctx, original.SpaceID, original.Type, opts.CloneFields(*wiType, original), creatorID)
+			if err != nil {
+				return nil, nil, errs.Wrapf(err, "failed to clone work item %s", original.ID)
+			}
+			clones = append(clones, *clone)
+			clonedIDs[original.ID] = clone.ID
+			parentIDs = append(parentIDs, original.ID)
+		}
+		childIDs := []uuid.UUID{}
+		for _, lt := range treeLinkTypes {
+			childLinks, err := appl.WorkItemLinks().ListChildLinks(ctx, lt.ID, parentIDs...)
+			if err != nil {
+				return nil, nil, errs.Wrapf(err, "failed to list the children of work items %v", parentIDs)
+			}
+			for _, l := range childLinks {
+				links = append(links, l)
+				if !queued[l.TargetID] {
+					queued[l.TargetID] = true
+					childIDs = append(childIDs, l.TargetID)
+				}
+			}
+		}
+		toClone = []workitem.WorkItem{}
+		if len(childIDs) == 0 {
+			break
+		}
+		childWIs, err := appl.WorkItems().LoadBatchByID(ctx, childIDs)
+		if err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to load the work items %v", childIDs)
+		}
+		for _, child := range childWIs {
+			if uuid.Equal(child.SpaceID, wi.SpaceID) {
+				toClone = append(toClone, *child)
+			}
+		}
+	}
+	for _, l := range links {
+		sourceID, sourceCloned := clonedIDs[l.SourceID]
+		targetID, targetCloned := clonedIDs[l.TargetID]
+		if !sourceCloned || !targetCloned {
+			continue
+		}
+		if _, err := appl.WorkItemLinks().Create(ctx, sourceID, targetID, l.LinkTypeID, creatorID); err != nil {
+			return nil, nil, errs.Wrapf(err, "failed to clone work item link %s", l.ID)
+		}
+	}
+	return clones, clonedIDs, nil
+}
+
+// enqueueWorkItemCreated notifies the notification channel, the users
+// mentioned in the description and the webhooks of the space about the
+// creation of the given work item
+func enqueueWorkItemCreated(ctx context.Context, appl application.Application, channel notification.Channel, request *http.Request, wi workitem.WorkItem) error {
+	err := enqueueWorkItemMessage(ctx, appl, channel, wi.ID, notification.NewWorkItemCreated(wi))
+	if err != nil {
+		return err
+	}
+	err = enqueueMentions(ctx, appl, channel, APIStringTypeWorkItem, wi.ID.String(), wi.SpaceID, wi.Mentions.IdentityIDs())
+	if err != nil {
+		return err
+	}
+	return enqueueWebhookEvent(ctx, appl, wi.SpaceID, webhookEvent{
+		Event:    webhook.EventWorkItemCreate,
+		WorkItem: ConvertWorkItem(request, wi),
+	})
+}
+
+// enqueueWorkItemUpdated notifies the notification channel and the webhooks
+// of the space about the latest modification of the given work item
+func enqueueWorkItemUpdated(ctx context.Context, appl application.Application, channel notification.Channel, request *http.Request, wi workitem.WorkItem) error {
+	latest, previous, err := latestWorkItemRevisions(ctx, appl, wi)
+	if err != nil {
+		return err
+	}
+	changes := []workitem.FieldChange{}
+	if latest != nil {
+		changes = latest.Diff(previous)
+	}
+	err = enqueueWorkItemMessage(ctx, appl, channel, wi.ID, notification.NewWorkItemUpdated(wi, changes))
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		if change.Name != workitem.SystemLabels {
+			continue
+		}
+		added, removed := listDifference(change.Old, change.New)
+		err = enqueueWorkItemMessage(ctx, appl, channel, wi.ID, notification.NewWorkItemLabelsChanged(wi, added, removed))
+		if err != nil {
+			return err
+		}
+	}
+	if latest != nil {
+		err = enqueueMentions(ctx, appl, channel, APIStringTypeWorkItem, wi.ID.String(), wi.SpaceID, latest.AddedMentions(previous))
+		if err != nil {
+			return err
+		}
+	}
+	return enqueueWebhookEvent(ctx, appl, wi.SpaceID, webhookEvent{
+		Event:    webhook.EventWorkItemUpdate,
+		WorkItem: ConvertWorkItem(request, wi),
+		Changes:  changes,
+	})
+}
+
+// enqueueMentions notifies the users that were newly mentioned in the comment
+// or work item with the given ID. Users who mention themselves are not
+// notified.
+func enqueueMentions(ctx context.Context, appl application.Application, channel notification.Channel, targetType, targetID string, spaceID uuid.UUID, mentionedIDs []uuid.UUID) error {
+	currentIdentityID, _ := login.ContextIdentity(ctx)
+	for _, mentionedID := range mentionedIDs {
+		if currentIdentityID != nil && uuid.Equal(*currentIdentityID, mentionedID) {
+			continue
+		}
+		err := notification.Enqueue(ctx, channel, appl.NotificationOutbox(), notification.NewMention(targetType, targetID, spaceID, mentionedID))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latestWorkItemRevisions returns the two most recent revisions of the given
+// work item, the latest one is nil if the work item has no revision yet and
+// the previous one is nil if there is only one
+func latestWorkItemRevisions(ctx context.Context, appl application.Application, wi workitem.WorkItem) (latest *workitem.Revision, previous *workitem.Revision, err error) {
+	revisions, err := appl.WorkItemRevisions().ListLatest(ctx, wi.ID, 2)
+	if err != nil {
+		return nil, nil, errs.Wrapf(err, "failed to load the revisions of work item %s", wi.ID)
+	}
+	if len(revisions) > 0 {
+		latest = &revisions[0]
+	}
+	if len(revisions) > 1 {
+		previous = &revisions[1]
+	}
+	return latest, previous, nil
+}
+
+// listDifference returns the string values that are only in the new list
+// and the ones that are only in the old list, as found in the fields of a
+// work item revision
+func listDifference(oldList, newList interface{}) (added []string, removed []string) {
+	toSet := func(list interface{}) map[string]struct{} {
+		set := map[string]struct{}{}
+		if values, ok := list.([]interface{}); ok {
+			for _, v := range values {
+				if s, ok := v.(string); ok {
+					set[s] = struct{}{}
+				}
+			}
+		}
+		return set
+	}
+	oldSet, newSet := toSet(oldList), toSet(newList)
+	added, removed = []string{}, []string{}
+	for s := range newSet {
+		if _, ok := oldSet[s]; !ok {
+			added = append(added, s)
+		}
+	}
+	for s := range oldSet {
+		if _, ok := newSet[s]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
 // Show does GET workitem
 func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	var wi *workitem.WorkItem
@@ -157,7 +668,11 @@ func (c *WorkitemController) Show(ctx *app.ShowWorkitemContext) error {
 	return ctx.ConditionalRequest(*wi, c.config.GetCacheControlWorkItem, func() error {
 		comments := workItemIncludeCommentsAndTotal(ctx, c.db, ctx.WiID)
 		hasChildren := workItemIncludeHasChildren(ctx, c.db)
-		wi2 := ConvertWorkItem(ctx.Request, *wi, comments, hasChildren)
+		attachments := workItemIncludeAttachments(ctx, c.db)
+		watchers := workItemIncludeWatchers(ctx, c.db)
+		blockers := workItemIncludeBlockers(ctx, c.db, *wi)
+		rollUps := workItemIncludeRollUps(ctx, c.db, *wi)
+		wi2 := ConvertWorkItem(ctx.Request, *wi, comments, hasChildren, attachments, watchers, blockers, rollUps)
 		resp := &app.WorkItemSingle{
 			Data: wi2,
 		}
@@ -394,6 +909,21 @@ func ConvertJSONAPIToWorkItem(ctx context.Context, method string, appl applicati
 			}
 			setupCodebase(appl, m, spaceID)
 			target.Fields[key] = *m
+		case workitem.SystemDueDate:
+			// the due date is optional, null removes it
+			if val == nil {
+				delete(target.Fields, key)
+				continue
+			}
+			s, ok := val.(string)
+			if !ok {
+				return errors.NewBadParameterError("data.attributes."+key, val).Expected("a RFC3339 timestamp or a date")
+			}
+			dueDate, err := workitem.ParseDueDate(s)
+			if err != nil {
+				return err
+			}
+			target.Fields[key] = dueDate
 		default:
 			target.Fields[key] = val
 		}
@@ -496,6 +1026,9 @@ func ConvertWorkItem(request *http.Request, wi workitem.WorkItem, additional ...
 		},
 	}
 
+	if wi.Key != nil {
+		op.Attributes[workitem.SystemKey] = *wi.Key
+	}
 	// Move fields into Relationships or Attributes as needed
 	// TODO: Loop based on WorkItemType and match against Field.Type instead of directly to field value
 	for name, val := range wi.Fields {
@@ -548,8 +1081,9 @@ func ConvertWorkItem(request *http.Request, wi workitem.WorkItem, additional ...
 				op.Attributes[name] = (*description).Content
 				op.Attributes[workitem.SystemDescriptionMarkup] = (*description).Markup
 				// let's include the rendered description while 'HTML escaping' it to prevent script injection
-				op.Attributes[workitem.SystemDescriptionRendered] =
-					rendering.RenderMarkupToHTML(html.EscapeString((*description).Content), (*description).Markup)
+				op.Attributes[workitem.SystemDescriptionRendered] = rendering.RenderMentions(
+					rendering.RenderMarkupToHTML(html.EscapeString((*description).Content), (*description).Markup),
+					mentionLinks(request, wi.Mentions))
 			}
 		case workitem.SystemCodebase:
 			if val != nil {
@@ -621,6 +1155,68 @@ func workItemIncludeHasChildren(ctx context.Context, appl application.Applicatio
 	}
 }
 
+// workItemIncludeBlockers adds the computed "system.blocked" flag and the
+// "blockers" relationship with the open work items that block the given WI.
+// The blockers of all the given work items are loaded at once, so pass all
+// the work items that are converted with the returned function.
+func workItemIncludeBlockers(ctx context.Context, appl application.Application, wis ...workitem.WorkItem) WorkItemConvertFunc {
+	ids := make([]uuid.UUID, len(wis))
+	for i, wi := range wis {
+		ids[i] = wi.ID
+	}
+	blockersByID, err := appl.WorkItems().ListOpenBlockersBatch(ctx, ids)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_ids": ids,
+			"err":    err,
+		}, "unable to find the open blockers of the work items")
+		// enforce to have no blockers
+		blockersByID = map[uuid.UUID][]uuid.UUID{}
+	}
+	return func(request *http.Request, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		blockers := blockersByID[wi.ID]
+		data := make([]*app.GenericData, len(blockers))
+		for i, blockerID := range blockers {
+			id := blockerID.String()
+			relatedURL := rest.AbsoluteURL(request, app.WorkitemHref(id))
+			data[i] = &app.GenericData{
+				Type: ptr.String(APIStringTypeWorkItem),
+				ID:   &id,
+				Links: &app.GenericLinks{
+					Self:    &relatedURL,
+					Related: &relatedURL,
+				},
+			}
+		}
+		dependenciesRelated := rest.AbsoluteURL(request, app.WorkitemHref(wi.ID)) + "/graph/dependencies"
+		wi2.Relationships.Blockers = &app.RelationGenericList{
+			Data: data,
+			Links: &app.GenericLinks{
+				Related: &dependenciesRelated,
+			},
+		}
+		wi2.Attributes[workitem.SystemBlocked] = len(blockers) > 0
+	}
+}
+
+// workItemIncludeRollUps adds the computed "system.rollups" attribute with the
+// values of the roll-ups of the type of the given WI (if any). The roll-ups of
+// all the given work items are computed at once, so pass all the work items
+// that are converted with the returned function.
+func workItemIncludeRollUps(ctx context.Context, appl application.Application, wis ...workitem.WorkItem) WorkItemConvertFunc {
+	valuesByID, err := appl.WorkItemLinks().LoadRollUps(ctx, wis...)
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to compute the roll-ups of the work items")
+	}
+	return func(request *http.Request, wi *workitem.WorkItem, wi2 *app.WorkItem) {
+		if values, ok := valuesByID[wi.ID]; ok {
+			wi2.Attributes[workitem.SystemRollUps] = values
+		}
+	}
+}
+
 // includeParentWorkItem adds the parent of given WI to relationships & included object
 func includeParentWorkItem(ctx context.Context, ancestors link.AncestorList, childLinks link.WorkItemLinkList) WorkItemConvertFunc {
 	return func(request *http.Request, wi *workitem.WorkItem, wi2 *app.WorkItem) {
@@ -673,10 +1269,12 @@ func (c *WorkitemController) ListChildren(ctx *app.ListChildrenWorkitemContext)
 		var response app.WorkItemList
 		application.Transactional(c.db, func(appl application.Application) error {
 			hasChildren := workItemIncludeHasChildren(ctx, appl)
+			blockers := workItemIncludeBlockers(ctx, appl, result...)
+			rollUps := workItemIncludeRollUps(ctx, appl, result...)
 			response = app.WorkItemList{
 				Links: &app.PagingLinks{},
 				Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-				Data:  ConvertWorkItems(ctx.Request, result, hasChildren),
+				Data:  ConvertWorkItems(ctx.Request, result, hasChildren, blockers, rollUps),
 			}
 			return nil
 		})
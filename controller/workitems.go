@@ -1,8 +1,10 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
@@ -18,6 +20,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/goadesign/goa"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
@@ -127,7 +130,7 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 		if err != nil {
 			return errs.Wrap(err, fmt.Sprintf("Error creating work item"))
 		}
-		return nil
+		return enqueueWorkItemCreated(ctx, appl, c.notification, ctx.Request, *wi)
 	})
 	if err != nil {
 		return jsonapi.JSONErrorResponse(ctx, err)
@@ -142,7 +145,6 @@ func (c *WorkitemsController) Create(ctx *app.CreateWorkitemsContext) error {
 	}
 	ctx.ResponseData.Header().Set("Last-Modified", lastModified(*wi))
 	ctx.ResponseData.Header().Set("Location", app.WorkitemHref(wi2.ID))
-	c.notification.Send(ctx, notification.NewWorkItemCreated(wi.ID.String()))
 	return ctx.Created(resp)
 }
 
@@ -231,10 +233,12 @@ func (c *WorkitemsController) List(ctx *app.ListWorkitemsContext) error {
 	}
 	return ctx.ConditionalEntities(workitems, c.config.GetCacheControlWorkItems, func() error {
 		hasChildren := workItemIncludeHasChildren(ctx, c.db)
+		blockers := workItemIncludeBlockers(ctx, c.db, workitems...)
+		rollUps := workItemIncludeRollUps(ctx, c.db, workitems...)
 		response := app.WorkItemList{
 			Links: &app.PagingLinks{},
 			Meta:  &app.WorkItemListResponseMeta{TotalCount: count},
-			Data:  ConvertWorkItems(ctx.Request, workitems, hasChildren),
+			Data:  ConvertWorkItems(ctx.Request, workitems, hasChildren, blockers, rollUps),
 		}
 		setPagingLinks(response.Links, buildAbsoluteURL(ctx.Request), len(workitems), offset, limit, count, additionalQuery...)
 		addFilterLinks(response.Links, ctx.Request)
@@ -300,3 +304,312 @@ func (c *WorkitemsController) Reorder(ctx *app.ReorderWorkitemsContext) error {
 	}
 	return ctx.OK(resp)
 }
+
+// The status of a work item in the result of a bulk update
+const (
+	bulkUpdateStatusUpdated    = "updated"
+	bulkUpdateStatusFailed     = "failed"
+	bulkUpdateStatusRolledBack = "rolled-back"
+)
+
+// errBulkUpdateRolledBack is returned in the transaction of a bulk update to
+// roll back the modifications when a work item could not be modified
+var errBulkUpdateRolledBack = errs.New("bulk update rolled back")
+
+// bulkUpdateTarget is a work item to modify in a bulk update with its
+// expected version, if any
+type bulkUpdateTarget struct {
+	ID      uuid.UUID
+	Version *int
+}
+
+// BulkUpdate does PATCH workitems/bulk
+func (c *WorkitemsController) BulkUpdate(ctx *app.BulkUpdateWorkitemsContext) error {
+	currentUserIdentityID, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	authorized, err := authz.Authorize(ctx, ctx.SpaceID.String())
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewUnauthorizedError(err.Error()))
+	}
+	if !authorized {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewForbiddenError("user is not authorized to access the space"))
+	}
+	if ctx.Payload == nil || ctx.Payload.Data == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("missing data element in request", nil))
+	}
+	data := ctx.Payload.Data
+	if (data.Filter == nil) == (len(data.Items) == 0) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data", nil).Expected("either a filter or a list of items"))
+	}
+	limit := c.config.GetWorkItemBulkUpdateLimit()
+	if len(data.Items) > limit {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.items", len(data.Items)).Expected(fmt.Sprintf("at most %d items", limit)))
+	}
+	ops, err := convertBulkOperations(data.Operations)
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	results := []*app.WorkItemBulkUpdateResult{}
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		for _, op := range ops {
+			if err := checkBulkOperation(ctx, appl, ctx.SpaceID, op); err != nil {
+				return err
+			}
+		}
+		targets, err := bulkUpdateTargets(ctx, appl, ctx.SpaceID, *data, limit)
+		if err != nil {
+			return err
+		}
+		failed := false
+		for _, target := range targets {
+			wi, err := bulkUpdateWorkItem(ctx, appl, ctx.SpaceID, target, ops, *currentUserIdentityID)
+			if err == nil {
+				err = enqueueWorkItemUpdated(ctx, appl, c.notification, ctx.Request, *wi)
+			}
+			if err != nil {
+				if !isBulkUpdateItemError(err) {
+					// the failed statement aborted the transaction, so the
+					// remaining work items cannot be modified anymore
+					return err
+				}
+				failed = true
+				jerr, _ := jsonapi.ErrorToJSONAPIError(ctx, err)
+				results = append(results, &app.WorkItemBulkUpdateResult{
+					ID:     target.ID,
+					Status: bulkUpdateStatusFailed,
+					Error:  &jerr,
+				})
+				continue
+			}
+			version := wi.Version
+			results = append(results, &app.WorkItemBulkUpdateResult{
+				ID:      wi.ID,
+				Status:  bulkUpdateStatusUpdated,
+				Version: &version,
+			})
+		}
+		if failed {
+			for _, r := range results {
+				if r.Status == bulkUpdateStatusUpdated {
+					r.Status = bulkUpdateStatusRolledBack
+					r.Version = nil
+				}
+			}
+			return errBulkUpdateRolledBack
+		}
+		return nil
+	})
+	if err != nil && errs.Cause(err) != errBulkUpdateRolledBack {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"space_id":  ctx.SpaceID,
+		"committed": err == nil,
+	}, "Bulk updated %d work items", len(results))
+	return ctx.OK(&app.WorkItemBulkUpdateResultList{
+		Data: results,
+		Meta: &app.WorkItemBulkUpdateMeta{
+			Committed: err == nil,
+			Total:     len(results),
+		},
+	})
+}
+
+// isBulkUpdateItemError returns true if the given error only concerns the
+// work item that was modified in a bulk update. Such errors are reported with
+// the work item and the bulk update carries on with the next one. All other
+// errors, e.g. a failed database statement, end the bulk update.
+func isBulkUpdateItemError(err error) bool {
+	switch errs.Cause(err).(type) {
+	case errors.BadParameterError, errors.ConversionError, errors.VersionConflictError, errors.ForbiddenError, errors.NotFoundError:
+		return true
+	}
+	return false
+}
+
+// convertBulkOperations converts the operations of a bulk update from REST
+// to model representation
+func convertBulkOperations(source []*app.WorkItemBulkOperation) ([]workitem.FieldOperation, error) {
+	if len(source) == 0 {
+		return nil, errors.NewBadParameterError("data.operations", nil).Expected("at least one operation")
+	}
+	ops := make([]workitem.FieldOperation, len(source))
+	for i, op := range source {
+		ops[i] = workitem.FieldOperation{
+			Kind:  workitem.FieldOperationKind(op.Op),
+			Field: op.Field,
+			Value: op.Value,
+		}
+	}
+	return ops, nil
+}
+
+// checkBulkOperation verifies that the iterations, areas, labels and
+// identities referenced by the given operation exist. Iterations and areas
+// must belong to the given space.
+func checkBulkOperation(ctx context.Context, appl application.Application, spaceID uuid.UUID, op workitem.FieldOperation) error {
+	values := []interface{}{op.Value}
+	if list, ok := op.Value.([]interface{}); ok {
+		values = list
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		s, ok := value.(string)
+		if !ok {
+			continue
+		}
+		id, err := uuid.FromString(s)
+		switch op.Field {
+		case workitem.SystemIteration:
+			if err != nil {
+				return errors.NewBadParameterError(op.Field, s)
+			}
+			itr, err := appl.Iterations().Load(ctx, id)
+			if err != nil || itr.SpaceID != spaceID {
+				return errors.NewBadParameterError(op.Field, s).Expected("an iteration of the space")
+			}
+		case workitem.SystemArea:
+			if err != nil {
+				return errors.NewBadParameterError(op.Field, s)
+			}
+			a, err := appl.Areas().Load(ctx, id)
+			if err != nil || a.SpaceID != spaceID {
+				return errors.NewBadParameterError(op.Field, s).Expected("an area of the space")
+			}
+		case workitem.SystemLabels:
+			if err != nil || !appl.Labels().IsValid(ctx, id) {
+				return errors.NewBadParameterError(op.Field, s).Expected("a label")
+			}
+		case workitem.SystemAssignees:
+			if err != nil || !appl.Identities().IsValid(ctx, id) {
+				return errors.NewBadParameterError(op.Field, s).Expected("an identity")
+			}
+		}
+	}
+	return nil
+}
+
+// bulkUpdateTargets returns the work items selected by the filter or the
+// list of items of a bulk update. The filter is restricted to the work items
+// of the given space.
+func bulkUpdateTargets(ctx context.Context, appl application.Application, spaceID uuid.UUID, data app.WorkItemBulkUpdateData, limit int) ([]bulkUpdateTarget, error) {
+	if data.Filter == nil {
+		targets := make([]bulkUpdateTarget, len(data.Items))
+		for i, item := range data.Items {
+			targets[i] = bulkUpdateTarget{ID: item.ID, Version: item.Version}
+		}
+		return targets, nil
+	}
+	filterWithSpaceID := fmt.Sprintf(`{"%s":[{"space": "%s" }, %s]}`, search.AND, spaceID, *data.Filter)
+	// load one more item than allowed to detect if there are too many
+	length := limit + 1
+	matches, _, _, _, err := appl.SearchItems().Filter(ctx, filterWithSpaceID, nil, nil, &length)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to find the work items to update")
+	}
+	targets := make([]bulkUpdateTarget, len(matches))
+	for i, wi := range matches {
+		targets[i] = bulkUpdateTarget{ID: wi.ID}
+	}
+	if len(targets) > limit {
+		return nil, errors.NewBadParameterError("data.filter", *data.Filter).Expected(fmt.Sprintf("a filter matching at most %d work items", limit))
+	}
+	return targets, nil
+}
+
+// bulkUpdateWorkItem applies the operations of a bulk update to a single
+// work item and stores it
+func bulkUpdateWorkItem(ctx context.Context, appl application.Application, spaceID uuid.UUID, target bulkUpdateTarget, ops []workitem.FieldOperation, modifierID uuid.UUID) (*workitem.WorkItem, error) {
+	wi, err := appl.WorkItems().LoadByID(ctx, target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if wi.SpaceID != spaceID {
+		return nil, errors.NewNotFoundError("work item", target.ID.String())
+	}
+	if target.Version != nil && *target.Version != wi.Version {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	wiType, err := appl.WorkItemTypes().Load(ctx, wi.Type)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load the type of work item %s", wi.ID)
+	}
+	if err := workitem.ApplyAll(*wiType, wi, ops); err != nil {
+		return nil, err
+	}
+	return appl.WorkItems().Save(ctx, spaceID, *wi, modifierID)
+}
+
+// Schedule does GET workitems/schedule
+func (c *WorkitemsController) Schedule(ctx *app.ScheduleWorkitemsContext) error {
+	if (ctx.FilterExpression == nil) == (len(ctx.FilterWorkitems) == 0) {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("filter", nil).Expected("either a filter expression or a list of work item IDs"))
+	}
+	limit := c.config.GetWorkItemScheduleLimit()
+	if len(ctx.FilterWorkitems) > limit {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("filter[workitems]", len(ctx.FilterWorkitems)).Expected(fmt.Sprintf("at most %d work items", limit)))
+	}
+	start := time.Now()
+	if ctx.Start != nil {
+		start = *ctx.Start
+	}
+	var schedule *link.Schedule
+	err := application.Transactional(c.db, func(appl application.Application) error {
+		if err := appl.Spaces().CheckExists(ctx, ctx.SpaceID); err != nil {
+			return err
+		}
+		ids, err := scheduleTargets(ctx, appl, ctx.SpaceID, ctx.FilterExpression, ctx.FilterWorkitems, limit)
+		if err != nil {
+			return err
+		}
+		schedule, err = appl.WorkItemLinks().ComputeSchedule(ctx, ids, start)
+		return err
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	return ctx.OK(ConvertScheduleFromModel(ctx.Request, *schedule))
+}
+
+// scheduleTargets returns the IDs of the work items selected by the filter or
+// the list of IDs of a schedule request. Both are restricted to the work items
+// of the given space.
+func scheduleTargets(ctx context.Context, appl application.Application, spaceID uuid.UUID, filter *string, ids []uuid.UUID, limit int) ([]uuid.UUID, error) {
+	if filter == nil {
+		wis, err := appl.WorkItems().LoadBatchByID(ctx, ids)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to load the work items to schedule")
+		}
+		inSpace := map[uuid.UUID]bool{}
+		for _, wi := range wis {
+			if wi.SpaceID == spaceID {
+				inSpace[wi.ID] = true
+			}
+		}
+		for _, id := range ids {
+			if !inSpace[id] {
+				return nil, errors.NewNotFoundError("work item", id.String())
+			}
+		}
+		return ids, nil
+	}
+	filterWithSpaceID := fmt.Sprintf(`{"%s":[{"space": "%s" }, %s]}`, search.AND, spaceID, *filter)
+	// load one more item than allowed to detect if there are too many
+	length := limit + 1
+	matches, _, _, _, err := appl.SearchItems().Filter(ctx, filterWithSpaceID, nil, nil, &length)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to find the work items to schedule")
+	}
+	if len(matches) > limit {
+		return nil, errors.NewBadParameterError("filter[expression]", *filter).Expected(fmt.Sprintf("a filter matching at most %d work items", limit))
+	}
+	result := make([]uuid.UUID, len(matches))
+	for i, wi := range matches {
+		result[i] = wi.ID
+	}
+	return result, nil
+}
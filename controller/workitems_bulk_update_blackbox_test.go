@@ -0,0 +1,203 @@
+package controller_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/app"
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	testsupport "github.com/fabric8-services/fabric8-wit/test"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemsBulkUpdateREST struct {
+	gormtestsupport.DBTestSuite
+	db *gormapplication.GormDB
+}
+
+func TestRunWorkItemsBulkUpdateREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemsBulkUpdateREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWorkItemsBulkUpdateREST) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.db = gormapplication.NewGormDB(s.DB)
+}
+
+func (s *TestWorkItemsBulkUpdateREST) newController(fxt *tf.TestFixture) (*goa.Service, *WorkitemsController) {
+	svc := testsupport.ServiceAsSpaceUser("WorkItems-Service", *fxt.Identities[0], &TestSpaceAuthzService{*fxt.Identities[0], ""})
+	return svc, NewWorkitemsController(svc, s.db, s.Configuration)
+}
+
+func (s *TestWorkItemsBulkUpdateREST) TestBulkUpdate() {
+	s.T().Run("ok - items", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2), tf.Iterations(2), tf.Labels(1))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Items: []*app.WorkItemBulkItem{
+					{ID: fxt.WorkItems[0].ID, Version: ptr.Int(fxt.WorkItems[0].Version)},
+					{ID: fxt.WorkItems[1].ID},
+				},
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemIteration, Value: fxt.Iterations[1].ID.String()},
+					{Op: "add", Field: workitem.SystemLabels, Value: fxt.Labels[0].ID.String()},
+				},
+			},
+		}
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+		// then
+		assert.True(t, res.Meta.Committed)
+		require.Len(t, res.Data, 2)
+		for i, r := range res.Data {
+			assert.Equal(t, "updated", r.Status)
+			wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, fxt.WorkItems[i].ID)
+			require.NoError(t, err)
+			assert.Equal(t, wi.Version, *r.Version)
+			assert.Equal(t, fxt.Iterations[1].ID.String(), wi.Fields[workitem.SystemIteration])
+			assert.Equal(t, []interface{}{fxt.Labels[0].ID.String()}, wi.Fields[workitem.SystemLabels])
+		}
+	})
+	s.T().Run("ok - filter", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(3))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Filter: ptr.String(fmt.Sprintf(`{"$AND": [{"space": "%s"}]}`, fxt.Spaces[0].ID)),
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemTitle, Value: "bulk"},
+				},
+			},
+		}
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+		// then
+		assert.True(t, res.Meta.Committed)
+		assert.Equal(t, 3, res.Meta.Total)
+		for _, wi := range fxt.WorkItems {
+			loaded, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, wi.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "bulk", loaded.Fields[workitem.SystemTitle])
+		}
+	})
+	s.T().Run("ok - filter restricted to the space", func(t *testing.T) {
+		// given work items with the same title in two spaces
+		title := "bulk-" + uuid.NewV4().String()
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2, tf.SetWorkItemTitles(title, title)))
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItems(2, tf.SetWorkItemTitles(title, title)))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Filter: ptr.String(fmt.Sprintf(`{"title": "%s"}`, title)),
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemTitle, Value: "bulk"},
+				},
+			},
+		}
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+		// then only the work items of the space are updated
+		assert.True(t, res.Meta.Committed)
+		assert.Equal(t, 2, res.Meta.Total)
+		for _, wi := range other.WorkItems {
+			loaded, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, wi.ID)
+			require.NoError(t, err)
+			assert.Equal(t, title, loaded.Fields[workitem.SystemTitle])
+		}
+	})
+	s.T().Run("version conflict rolls back all items", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(2, tf.SetWorkItemTitles("foo", "bar")))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Items: []*app.WorkItemBulkItem{
+					{ID: fxt.WorkItems[0].ID, Version: ptr.Int(fxt.WorkItems[0].Version)},
+					{ID: fxt.WorkItems[1].ID, Version: ptr.Int(fxt.WorkItems[1].Version + 1)},
+				},
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemTitle, Value: "bulk"},
+				},
+			},
+		}
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+		// then
+		assert.False(t, res.Meta.Committed)
+		require.Len(t, res.Data, 2)
+		assert.Equal(t, "rolled-back", res.Data[0].Status)
+		assert.Equal(t, "failed", res.Data[1].Status)
+		require.NotNil(t, res.Data[1].Error)
+		loaded, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "foo", loaded.Fields[workitem.SystemTitle])
+	})
+	s.T().Run("all version conflicts are reported", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(3))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Items: []*app.WorkItemBulkItem{
+					{ID: fxt.WorkItems[0].ID, Version: ptr.Int(fxt.WorkItems[0].Version + 1)},
+					{ID: fxt.WorkItems[1].ID, Version: ptr.Int(fxt.WorkItems[1].Version + 1)},
+					{ID: fxt.WorkItems[2].ID, Version: ptr.Int(fxt.WorkItems[2].Version)},
+				},
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemTitle, Value: "bulk"},
+				},
+			},
+		}
+		// when
+		_, res := test.BulkUpdateWorkitemsOK(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+		// then
+		assert.False(t, res.Meta.Committed)
+		require.Len(t, res.Data, 3)
+		assert.Equal(t, "failed", res.Data[0].Status)
+		assert.Equal(t, "failed", res.Data[1].Status)
+		assert.Equal(t, "rolled-back", res.Data[2].Status)
+	})
+	s.T().Run("fail - filter and items", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Filter: ptr.String(fmt.Sprintf(`{"space": "%s"}`, fxt.Spaces[0].ID)),
+				Items:  []*app.WorkItemBulkItem{{ID: fxt.WorkItems[0].ID}},
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemTitle, Value: "bulk"},
+				},
+			},
+		}
+		test.BulkUpdateWorkitemsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+	})
+	s.T().Run("fail - iteration of another space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		other := tf.NewTestFixture(t, s.DB, tf.Iterations(1))
+		svc, ctrl := s.newController(fxt)
+		payload := app.WorkItemBulkUpdate{
+			Data: &app.WorkItemBulkUpdateData{
+				Items: []*app.WorkItemBulkItem{{ID: fxt.WorkItems[0].ID}},
+				Operations: []*app.WorkItemBulkOperation{
+					{Op: "set", Field: workitem.SystemIteration, Value: other.Iterations[0].ID.String()},
+				},
+			},
+		}
+		test.BulkUpdateWorkitemsBadRequest(t, svc.Context, svc, ctrl, fxt.Spaces[0].ID, &payload)
+	})
+}
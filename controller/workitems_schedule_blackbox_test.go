@@ -0,0 +1,112 @@
+package controller_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/app/test"
+	. "github.com/fabric8-services/fabric8-wit/controller"
+	"github.com/fabric8-services/fabric8-wit/gormapplication"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/goadesign/goa"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWorkItemsScheduleREST struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWorkItemsScheduleREST(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWorkItemsScheduleREST{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWorkItemsScheduleREST) newController() (*goa.Service, *WorkitemsController) {
+	svc := goa.New("WorkItems-Service")
+	return svc, NewWorkitemsController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
+}
+
+func (s *TestWorkItemsScheduleREST) TestSchedule() {
+	fxt := scheduleFixture(s.T(), s.DB)
+	svc, ctrl := s.newController()
+	spaceID := fxt.Spaces[0].ID
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	e, a, b, c := fxt.WorkItemByTitle("E").ID, fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID
+
+	s.T().Run("ok - IDs", func(t *testing.T) {
+		// when
+		_, schedule := test.ScheduleWorkitemsOK(t, svc.Context, svc, ctrl, spaceID, nil, []uuid.UUID{a, c}, &start)
+		// then
+		require.Len(t, schedule.Data, 2)
+		assert.Equal(t, start.Add(4*time.Hour), schedule.Meta.Finish)
+		assert.Equal(t, []uuid.UUID{a, c}, schedule.Meta.CriticalPath)
+	})
+
+	s.T().Run("ok - filter", func(t *testing.T) {
+		// when
+		_, schedule := test.ScheduleWorkitemsOK(t, svc.Context, svc, ctrl, spaceID, ptr.String(fmt.Sprintf(`{"space": "%s"}`, spaceID)), nil, &start)
+		// then all work items of the space are scheduled
+		require.Len(t, schedule.Data, 4)
+		ids := make([]uuid.UUID, len(schedule.Data))
+		for i, entry := range schedule.Data {
+			ids[i] = entry.ID
+		}
+		assert.ElementsMatch(t, []uuid.UUID{e, a, b, c}, ids)
+		assert.Equal(t, start.Add(4*time.Hour), schedule.Meta.Finish)
+	})
+
+	s.T().Run("ok - filter restricted to the space", func(t *testing.T) {
+		// given a work item with the same title in another space
+		title := "schedule-" + uuid.NewV4().String()
+		tf.NewTestFixture(t, s.DB, tf.WorkItems(1, tf.SetWorkItemTitles(title)))
+		// when
+		_, schedule := test.ScheduleWorkitemsOK(t, svc.Context, svc, ctrl, spaceID, ptr.String(fmt.Sprintf(`{"title": "%s"}`, title)), nil, &start)
+		// then
+		assert.Empty(t, schedule.Data)
+		assert.Equal(t, start, schedule.Meta.Finish)
+	})
+
+	s.T().Run("bad request - neither filter nor IDs", func(t *testing.T) {
+		// when/then
+		test.ScheduleWorkitemsBadRequest(t, svc.Context, svc, ctrl, spaceID, nil, nil, nil)
+	})
+
+	s.T().Run("bad request - both filter and IDs", func(t *testing.T) {
+		// when/then
+		test.ScheduleWorkitemsBadRequest(t, svc.Context, svc, ctrl, spaceID, ptr.String(fmt.Sprintf(`{"space": "%s"}`, spaceID)), []uuid.UUID{a}, nil)
+	})
+
+	s.T().Run("bad request - too many IDs", func(t *testing.T) {
+		// given
+		ids := make([]uuid.UUID, s.Configuration.GetWorkItemScheduleLimit()+1)
+		for i := range ids {
+			ids[i] = uuid.NewV4()
+		}
+		// when/then
+		test.ScheduleWorkitemsBadRequest(t, svc.Context, svc, ctrl, spaceID, nil, ids, nil)
+	})
+
+	s.T().Run("not found - work item of another space", func(t *testing.T) {
+		// given
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		// when/then
+		test.ScheduleWorkitemsNotFound(t, svc.Context, svc, ctrl, spaceID, nil, []uuid.UUID{a, other.WorkItems[0].ID}, nil)
+	})
+
+	s.T().Run("not found - unknown work item", func(t *testing.T) {
+		// when/then
+		test.ScheduleWorkitemsNotFound(t, svc.Context, svc, ctrl, spaceID, nil, []uuid.UUID{uuid.NewV4()}, nil)
+	})
+
+	s.T().Run("not found - unknown space", func(t *testing.T) {
+		// when/then
+		test.ScheduleWorkitemsNotFound(t, svc.Context, svc, ctrl, uuid.NewV4(), nil, []uuid.UUID{a}, nil)
+	})
+}
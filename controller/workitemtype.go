@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/jsonapi"
+	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rest"
 	"github.com/fabric8-services/fabric8-wit/workitem"
@@ -62,6 +65,174 @@ func (c *WorkitemtypeController) Show(ctx *app.ShowWorkitemtypeContext) error {
 	return nil
 }
 
+// UpdateWorkflow runs the update-workflow action.
+func (c *WorkitemtypeController) UpdateWorkflow(ctx *app.UpdateWorkflowWorkitemtypeContext) error {
+	var wf *workitem.Workflow
+	if ctx.Payload != nil && ctx.Payload.Data != nil {
+		wf = ConvertWorkflowToModel(*ctx.Payload.Data)
+	}
+	witModel, err := c.updateOwnedWorkItemType(ctx, ctx.WitID, func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error) {
+		return witRepo.SetWorkflow(ctx, ctx.WitID, wf)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.Request, witModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+}
+
+// UpdateRollups runs the update-rollups action.
+func (c *WorkitemtypeController) UpdateRollups(ctx *app.UpdateRollupsWorkitemtypeContext) error {
+	var rollUps workitem.RollUps
+	if ctx.Payload != nil {
+		rollUps = ConvertRollUpsToModel(ctx.Payload.Data)
+	}
+	witModel, err := c.updateOwnedWorkItemType(ctx, ctx.WitID, func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error) {
+		return witRepo.SetRollUps(ctx, ctx.WitID, rollUps)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.Request, witModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+}
+
+// AddField runs the add-field action.
+func (c *WorkitemtypeController) AddField(ctx *app.AddFieldWorkitemtypeContext) error {
+	if ctx.Payload == nil || ctx.Payload.Data == nil || ctx.Payload.Data.Definition == nil || ctx.Payload.Data.Definition.Type == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.definition", nil).Expected("a field definition"))
+	}
+	definitions, err := ConvertFieldDefinitionsToModel(map[string]app.FieldDefinition{ctx.Payload.Data.Name: *ctx.Payload.Data.Definition})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data.definition", err.Error()))
+	}
+	definition := definitions[ctx.Payload.Data.Name]
+	definition.DefaultValue = ctx.Payload.Data.DefaultValue
+	witModel, err := c.updateOwnedWorkItemType(ctx, ctx.WitID, func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error) {
+		return witRepo.AddField(ctx, ctx.WitID, ctx.Payload.Data.Name, definition)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.Request, witModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+}
+
+// DeprecateField runs the deprecate-field action.
+func (c *WorkitemtypeController) DeprecateField(ctx *app.DeprecateFieldWorkitemtypeContext) error {
+	witModel, err := c.updateOwnedWorkItemType(ctx, ctx.WitID, func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error) {
+		return witRepo.DeprecateField(ctx, ctx.WitID, ctx.FieldName)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.Request, witModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+}
+
+// ReorderFields runs the reorder-fields action.
+func (c *WorkitemtypeController) ReorderFields(ctx *app.ReorderFieldsWorkitemtypeContext) error {
+	if ctx.Payload == nil {
+		return jsonapi.JSONErrorResponse(ctx, errors.NewBadParameterError("data", nil).Expected("a list of field names"))
+	}
+	witModel, err := c.updateOwnedWorkItemType(ctx, ctx.WitID, func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error) {
+		return witRepo.ReorderFields(ctx, ctx.WitID, ctx.Payload.Data)
+	})
+	if err != nil {
+		return jsonapi.JSONErrorResponse(ctx, err)
+	}
+	witData := ConvertWorkItemTypeFromModel(ctx.Request, witModel)
+	return ctx.OK(&app.WorkItemTypeSingle{Data: &witData})
+}
+
+// updateOwnedWorkItemType verifies that the current user owns the space of
+// the work item type with the given ID and applies the given update to the
+// type in a transaction.
+func (c *WorkitemtypeController) updateOwnedWorkItemType(ctx context.Context, witID uuid.UUID, update func(witRepo workitem.WorkItemTypeRepository) (*workitem.WorkItemType, error)) (*workitem.WorkItemType, error) {
+	currentUser, err := login.ContextIdentity(ctx)
+	if err != nil {
+		return nil, goa.ErrUnauthorized(err.Error())
+	}
+	var witModel *workitem.WorkItemType
+	err = application.Transactional(c.db, func(appl application.Application) error {
+		wit, err := appl.WorkItemTypes().Load(ctx, witID)
+		if err != nil {
+			return err
+		}
+		s, err := appl.Spaces().Load(ctx, wit.SpaceID)
+		if err != nil {
+			return err
+		}
+		if !uuid.Equal(*currentUser, s.OwnerID) {
+			return errors.NewForbiddenError("user is not the space owner")
+		}
+		witModel, err = update(appl.WorkItemTypes())
+		return err
+	})
+	return witModel, err
+}
+
+// ConvertWorkflowToModel converts a workflow from the app representation to
+// the model
+func ConvertWorkflowToModel(wf app.Workflow) *workitem.Workflow {
+	result := workitem.Workflow{
+		States:      make([]workitem.WorkflowState, len(wf.States)),
+		Transitions: make([]workitem.WorkflowTransition, len(wf.Transitions)),
+	}
+	for i, s := range wf.States {
+		result.States[i] = workitem.WorkflowState{Name: s.Name, RequiredFields: s.RequiredFields}
+	}
+	for i, t := range wf.Transitions {
+		result.Transitions[i] = workitem.WorkflowTransition{From: t.From, To: t.To, Roles: t.Roles}
+	}
+	return &result
+}
+
+// ConvertWorkflowFromModel converts a workflow from the model to the app
+// representation
+func ConvertWorkflowFromModel(wf workitem.Workflow) *app.Workflow {
+	result := app.Workflow{
+		States:      make([]*app.WorkflowState, len(wf.States)),
+		Transitions: make([]*app.WorkflowTransition, len(wf.Transitions)),
+	}
+	for i, s := range wf.States {
+		result.States[i] = &app.WorkflowState{Name: s.Name, RequiredFields: s.RequiredFields}
+	}
+	for i, t := range wf.Transitions {
+		result.Transitions[i] = &app.WorkflowTransition{From: t.From, To: t.To, Roles: t.Roles}
+	}
+	return &result
+}
+
+// ConvertRollUpsToModel converts roll-ups from the app representation to the
+// model
+func ConvertRollUpsToModel(rollUps []*app.RollUp) workitem.RollUps {
+	if len(rollUps) == 0 {
+		return nil
+	}
+	result := make(workitem.RollUps, len(rollUps))
+	for i, r := range rollUps {
+		result[i] = workitem.RollUp{Name: r.Name, Function: workitem.RollUpFunction(r.Function)}
+		if r.Field != nil {
+			result[i].Field = *r.Field
+		}
+	}
+	return result
+}
+
+// ConvertRollUpsFromModel converts roll-ups from the model to the app
+// representation
+func ConvertRollUpsFromModel(rollUps workitem.RollUps) []*app.RollUp {
+	result := make([]*app.RollUp, len(rollUps))
+	for i, r := range rollUps {
+		result[i] = &app.RollUp{Name: r.Name, Function: string(r.Function)}
+		if r.Field != "" {
+			result[i].Field = ptr.String(r.Field)
+		}
+	}
+	return result
+}
+
 // ConvertWorkItemTypeFromModel converts from models to app representation
 func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemType) app.WorkItemTypeData {
 	spaceSelfURL := rest.AbsoluteURL(request, app.SpaceHref(t.SpaceID.String()))
@@ -81,6 +252,12 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 			Space: app.NewSpaceRelation(t.SpaceID, spaceSelfURL),
 		},
 	}
+	if t.Workflow != nil {
+		converted.Attributes.Workflow = ConvertWorkflowFromModel(*t.Workflow)
+	}
+	if len(t.RollUps) > 0 {
+		converted.Attributes.Rollups = ConvertRollUpsFromModel(t.RollUps)
+	}
 	for name, def := range t.Fields {
 		ct := ConvertFieldTypeFromModel(def.Type)
 		converted.Attributes.Fields[name] = &app.FieldDefinition{
@@ -89,6 +266,12 @@ func ConvertWorkItemTypeFromModel(request *http.Request, t *workitem.WorkItemTyp
 			Description: def.Description,
 			Type:        &ct,
 		}
+		if def.Deprecated {
+			converted.Attributes.Fields[name].Deprecated = ptr.Bool(true)
+		}
+		if def.Order != 0 {
+			converted.Attributes.Fields[name].Order = ptr.Int(def.Order)
+		}
 	}
 	// TODO(kwk): Replaces this temporary static hack with a more dynamic solution
 	getGuidedChildTypes := func(witIDs ...uuid.UUID) *app.RelationGenericList {
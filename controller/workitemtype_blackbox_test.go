@@ -112,6 +112,118 @@ func (s *workItemTypeSuite) TestShow() {
 	})
 }
 
+func (s *workItemTypeSuite) TestUpdateWorkflow() {
+	newWorkflow := func() *app.WorkItemTypeWorkflow {
+		return &app.WorkItemTypeWorkflow{
+			Data: &app.Workflow{
+				States: []*app.WorkflowState{
+					{Name: workitem.SystemStateNew},
+					{Name: workitem.SystemStateResolved, RequiredFields: []string{workitem.SystemAssignees}},
+				},
+				Transitions: []*app.WorkflowTransition{
+					{From: workitem.SystemStateNew, To: workitem.SystemStateResolved, Roles: []string{workitem.WorkflowRoleAssignee}},
+				},
+			},
+		}
+	}
+	newController := func(svc *goa.Service) *WorkitemtypeController {
+		return NewWorkitemtypeController(svc, gormapplication.NewGormDB(s.DB), s.Configuration)
+	}
+
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		// when
+		_, actual := test.UpdateWorkflowWorkitemtypeOK(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, newWorkflow())
+		// then
+		require.NotNil(t, actual.Data.Attributes.Workflow)
+		require.Len(t, actual.Data.Attributes.Workflow.States, 2)
+		require.Equal(t, workitem.SystemStateResolved, actual.Data.Attributes.Workflow.States[1].Name)
+		require.Equal(t, []string{workitem.SystemAssignees}, actual.Data.Attributes.Workflow.States[1].RequiredFields)
+		require.Len(t, actual.Data.Attributes.Workflow.Transitions, 1)
+		require.Equal(t, []string{workitem.WorkflowRoleAssignee}, actual.Data.Attributes.Workflow.Transitions[0].Roles)
+		// the workflow is stored
+		wit, err := workitem.NewWorkItemTypeRepository(s.DB).Load(s.Ctx, fxt.WorkItemTypes[0].ID)
+		require.NoError(t, err)
+		require.NotNil(t, wit.Workflow)
+		require.Len(t, wit.Workflow.States, 2)
+	})
+
+	s.T().Run("ok - remove the workflow", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		ctrl := newController(svc)
+		test.UpdateWorkflowWorkitemtypeOK(t, svc.Context, svc, ctrl, fxt.WorkItemTypes[0].ID, newWorkflow())
+		// when
+		_, actual := test.UpdateWorkflowWorkitemtypeOK(t, svc.Context, svc, ctrl, fxt.WorkItemTypes[0].ID, &app.WorkItemTypeWorkflow{})
+		// then
+		require.Nil(t, actual.Data.Attributes.Workflow)
+		wit, err := workitem.NewWorkItemTypeRepository(s.DB).Load(s.Ctx, fxt.WorkItemTypes[0].ID)
+		require.NoError(t, err)
+		require.Nil(t, wit.Workflow)
+	})
+
+	s.T().Run("bad request - unknown state", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		payload := newWorkflow()
+		payload.Data.States[1].Name = "foo"
+		// when/then
+		test.UpdateWorkflowWorkitemtypeBadRequest(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, payload)
+	})
+
+	s.T().Run("bad request - transition to a state outside of the workflow", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		payload := newWorkflow()
+		payload.Data.Transitions[0].To = workitem.SystemStateClosed
+		// when/then
+		test.UpdateWorkflowWorkitemtypeBadRequest(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, payload)
+	})
+
+	s.T().Run("bad request - unknown required field", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		payload := newWorkflow()
+		payload.Data.States[1].RequiredFields = []string{"custom.foo"}
+		// when/then
+		test.UpdateWorkflowWorkitemtypeBadRequest(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, payload)
+	})
+
+	s.T().Run("forbidden - not the space owner", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[1])
+		// when
+		test.UpdateWorkflowWorkitemtypeForbidden(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, newWorkflow())
+		// then the workflow is left untouched
+		wit, err := workitem.NewWorkItemTypeRepository(s.DB).Load(s.Ctx, fxt.WorkItemTypes[0].ID)
+		require.NoError(t, err)
+		require.Nil(t, wit.Workflow)
+	})
+
+	s.T().Run("unauthorized - no token", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1), tf.WorkItemTypes(1))
+		svc := goa.New("WorkItemType-Service")
+		// when/then
+		test.UpdateWorkflowWorkitemtypeUnauthorized(t, svc.Context, svc, newController(svc), fxt.WorkItemTypes[0].ID, newWorkflow())
+	})
+
+	s.T().Run("not found - unknown work item type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		svc := testsupport.ServiceAsUser("WorkItemType-Service", *fxt.Identities[0])
+		// when/then
+		test.UpdateWorkflowWorkitemtypeNotFound(t, svc.Context, svc, newController(svc), uuid.NewV4(), newWorkflow())
+	})
+}
+
 // used for testing purpose only
 func ConvertWorkItemTypeToModel(data app.WorkItemTypeData) workitem.WorkItemType {
 	return workitem.WorkItemType{
@@ -0,0 +1,20 @@
+package criteria
+
+// LessThanExpression represents the less than operator
+type LessThanExpression struct {
+	binaryExpression
+}
+
+// Ensure LessThanExpression implements the Expression interface
+var _ Expression = &LessThanExpression{}
+var _ Expression = (*LessThanExpression)(nil)
+
+// Accept implements ExpressionVisitor
+func (t *LessThanExpression) Accept(visitor ExpressionVisitor) interface{} {
+	return visitor.LessThan(t)
+}
+
+// LessThan constructs a LessThanExpression
+func LessThan(left Expression, right Expression) Expression {
+	return reparent(&LessThanExpression{binaryExpression{expression{}, left, right}})
+}
@@ -7,6 +7,8 @@ type ExpressionVisitor interface {
 	Or(a *OrExpression) interface{}
 	Equals(e *EqualsExpression) interface{}
 	Substring(e *SubstringExpression) interface{}
+	LessThan(e *LessThanExpression) interface{}
+	GreaterThan(e *GreaterThanExpression) interface{}
 	Parameter(v *ParameterExpression) interface{}
 	Literal(c *LiteralExpression) interface{}
 	Not(e *NotExpression) interface{}
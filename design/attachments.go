@@ -0,0 +1,132 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var attachment = a.Type("Attachment", func() {
+	a.Description(`JSONAPI store for the data of a file attached to a work item or a comment. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("attachments")
+	})
+	a.Attribute("id", d.UUID, "ID of the attachment", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", attachmentAttributes)
+	a.Attribute("relationships", attachmentRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var attachmentAttributes = a.Type("AttachmentAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an attachment. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("file-name", d.String, "The name of the attached file", func() {
+		a.Example("screenshot.png")
+	})
+	a.Attribute("mime-type", d.String, "The MIME type of the attached file", func() {
+		a.Example("image/png")
+	})
+	a.Attribute("size", d.Integer, "The size of the attached file in bytes", func() {
+		a.Example(48213)
+	})
+	a.Attribute("created-at", d.DateTime, "When the file was attached", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var attachmentRelationships = a.Type("AttachmentRelations", func() {
+	a.Attribute("creator", relationGeneric, "The user who attached the file")
+	a.Attribute("parent", relationGeneric, "The work item or comment the file is attached to")
+})
+
+var attachmentList = JSONList(
+	"Attachment", "Holds the list of attachments",
+	attachment,
+	nil,
+	nil)
+
+var attachmentSingle = JSONSingle(
+	"Attachment", "Holds a single attachment",
+	attachment,
+	nil)
+
+var attachmentUpload = a.Type("AttachmentUpload", func() {
+	a.Description("A file to attach, sent as multipart/form-data")
+	a.Attribute("file", d.File, "The file to attach")
+	a.Required("file")
+})
+
+// attachmentActions defines the list, upload, download and delete actions of
+// the attachments of a parent resource
+func attachmentActions(parent string) {
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("attachments"),
+		)
+		a.Description("List the files attached to the given " + parent)
+		a.Response(d.OK, attachmentList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("upload", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("attachments"),
+		)
+		a.Description("Attach a file to the given " + parent)
+		a.MultipartForm()
+		a.Payload(attachmentUpload)
+		a.Response(d.Created, "/attachments/.*", func() {
+			a.Media(attachmentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("download", func() {
+		a.Routing(
+			a.GET("attachments/:attachmentID"),
+		)
+		a.Description("Download the content of a file attached to the given " + parent)
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("attachments/:attachmentID"),
+		)
+		a.Description("Delete a file attached to the given " + parent)
+		a.Params(func() {
+			a.Param("attachmentID", d.UUID, "ID of the attachment")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+}
+
+var _ = a.Resource("work_item_attachments", func() {
+	a.Parent("workitem")
+	attachmentActions("work item")
+})
+
+var _ = a.Resource("comment_attachments", func() {
+	a.Parent("comments")
+	attachmentActions("comment")
+})
@@ -25,9 +25,14 @@ var createComment = a.Type("CreateComment", func() {
 		a.Enum("comments")
 	})
 	a.Attribute("attributes", createCommentAttributes)
+	a.Attribute("relationships", createCommentRelationships)
 	a.Required("type", "attributes")
 })
 
+var createCommentRelationships = a.Type("CreateCommentRelations", func() {
+	a.Attribute("parent-comment", relationGeneric, "The comment this comment replies to")
+})
+
 var commentAttributes = a.Type("CommentAttributes", func() {
 	a.Description(`JSONAPI store for all the "attributes" of a comment. +See also see http://jsonapi.org/format/#document-resource-object-attributes`)
 	a.Attribute("created-at", d.DateTime, "When the comment was created", func() {
@@ -45,6 +50,12 @@ var commentAttributes = a.Type("CommentAttributes", func() {
 	a.Attribute("markup", d.String, "The comment markup associated with the body", func() {
 		a.Example("Markdown")
 	})
+	a.Attribute("resolved", d.Boolean, "Whether the thread started by this comment was resolved", func() {
+		a.Example(false)
+	})
+	a.Attribute("resolved-at", d.DateTime, "When the thread started by this comment was resolved", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
 })
 
 var createCommentAttributes = a.Type("CreateCommentAttributes", func() {
@@ -63,6 +74,9 @@ var commentRelationships = a.Type("CommentRelations", func() {
 	a.Attribute("creator", relationGeneric, "This defines the creator of the comment")
 	a.Attribute("created-by", commentCreatedBy, "DEPRECATED. This defines the creator of the comment.")
 	a.Attribute("parent", relationGeneric, "This defines the owning resource of the comment")
+	a.Attribute("parent-comment", relationGeneric, "The top-level comment of the thread this comment replies to")
+	a.Attribute("replies", relationGenericList, "The replies to this comment, which are part of the included resources of a list of comments")
+	a.Attribute("resolved-by", relationGeneric, "The user who resolved the thread started by this comment")
 })
 
 var commentCreatedBy = a.Type("CommentCreatedBy", func() {
@@ -87,7 +101,7 @@ var commentRelationshipsArray = JSONList(
 )
 
 var commentListMeta = a.Type("CommentListMeta", func() {
-	a.Attribute("totalCount", d.Integer)
+	a.Attribute("totalCount", d.Integer, "The number of threads")
 	a.Required("totalCount")
 })
 
@@ -162,6 +176,60 @@ var _ = a.Resource("comments", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/restore"),
+		)
+		a.Description("undelete the deleted comment with the given commentId.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Response(d.OK, func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("resolve", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/resolve"),
+		)
+		a.Description("mark the thread started by the comment with the given commentId as resolved.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Response(d.OK, func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("unresolve", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:commentId/unresolve"),
+		)
+		a.Description("mark the thread started by the comment with the given commentId as unresolved.")
+		a.Params(func() {
+			a.Param("commentId", d.UUID, "commentId")
+		})
+		a.Response(d.OK, func() {
+			a.Media(commentSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 
 })
 
@@ -172,7 +240,8 @@ var _ = a.Resource("work_item_comments", func() {
 		a.Routing(
 			a.GET("comments"),
 		)
-		a.Description("List comments associated with the given work item")
+		a.Description(`List the threads of comments associated with the given work item. The
+		top-level comments are paged, their replies are included.`)
 		a.Params(func() {
 			a.Param("page[offset]", d.String, `Paging start position is a string pointing to
 			the beginning of pagination.  The value starts from 0 onwards.`)
@@ -0,0 +1,62 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var notificationOutboxEntry = a.Type("NotificationOutboxEntry", func() {
+	a.Description("A notification that could not be delivered to the notification service")
+	a.Attribute("messageID", d.UUID, "ID of the notification message")
+	a.Attribute("messageType", d.String, "Type of the notification message", func() {
+		a.Example("workitem.update")
+	})
+	a.Attribute("targetID", d.String, "ID of the entity the notification is about")
+	a.Attribute("attempts", d.Integer, "Number of failed delivery attempts")
+	a.Attribute("lastError", d.String, "Error of the last delivery attempt")
+	a.Attribute("createdAt", d.DateTime, "When the notification was created")
+	a.Attribute("updatedAt", d.DateTime, "When the notification was last attempted to be delivered")
+	a.Required("messageID", "messageType", "targetID", "attempts", "createdAt", "updatedAt")
+})
+
+// NotificationOutboxStatus defines the delivery status of the notification outbox
+var NotificationOutboxStatus = a.MediaType("application/vnd.notificationoutboxstatus+json", func() {
+	a.Description("The delivery status of the notification outbox")
+	a.TypeName("NotificationOutboxStatus")
+	a.Attributes(func() {
+		a.Attribute("pending", d.Integer, "Number of notifications waiting for delivery")
+		a.Attribute("delivered", d.Integer, "Number of delivered notifications")
+		a.Attribute("dead", d.Integer, "Number of notifications that exceeded the maximum number of delivery attempts")
+		a.Attribute("oldestPending", d.DateTime, "Creation time of the oldest notification waiting for delivery")
+		a.Attribute("deadLetters", a.ArrayOf(notificationOutboxEntry), "The most recent dead lettered notifications")
+		a.Required("pending", "delivered", "dead", "deadLetters")
+	})
+	a.View("default", func() {
+		a.Attribute("pending")
+		a.Attribute("delivered")
+		a.Attribute("dead")
+		a.Attribute("oldestPending")
+		a.Attribute("deadLetters")
+	})
+})
+
+var _ = a.Resource("notification_outbox", func() {
+	a.BasePath("/notifications/outbox")
+
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("Show the delivery status of the notification outbox (service account only)")
+		a.Params(func() {
+			a.Param("page[offset]", d.String, "Paging start position of the dead lettered notifications")
+			a.Param("page[limit]", d.Integer, "Paging size of the dead lettered notifications")
+		})
+		a.Response(d.OK, NotificationOutboxStatus)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -0,0 +1,30 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var _ = a.Resource("space_events", func() {
+	a.Parent("space")
+
+	a.Action("stream", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("events"),
+		)
+		a.Description(`Stream the work item, comment, link and iteration changes of the space as server-sent events (text/event-stream).
+Every event carries the same message that is handed over to the notification service. The "id" of an event can be passed
+as "last_event_id" parameter or as "Last-Event-ID" header to resume the stream after that event.
+Only the owner and the collaborators of the space can stream its events.`)
+		a.Params(func() {
+			a.Param("last_event_id", d.Integer, "ID of the last event seen by the client")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -5,6 +5,66 @@ import (
 	a "github.com/goadesign/goa/design/apidsl"
 )
 
+var spaceTemplate = a.Type("SpaceTemplate", func() {
+	a.Description(`JSONAPI store for the data of a space template. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("spacetemplates")
+	})
+	a.Attribute("id", d.UUID, "ID of the space template", func() {
+		a.Example("368c8024-c347-42d3-b27b-5bd8f30ca81d")
+	})
+	a.Attribute("attributes", spaceTemplateAttributes)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var spaceTemplateAttributes = a.Type("SpaceTemplateAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a space template. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("name", d.String, "The name of the space template", func() {
+		a.Example("Scrum")
+	})
+	a.Attribute("description", d.String, "The description of the space template", func() {
+		a.Example("Scenarios and experiences broken down into features and bugs that are planned in sprints.")
+	})
+	a.Attribute("version", d.Integer, "The version of the template, importing a template with an existing name creates a new version", func() {
+		a.Example(2)
+	})
+	a.Attribute("built-in", d.Boolean, "Whether the template ships with the service", func() {
+		a.Example(true)
+	})
+	a.Attribute("content", d.Any, "The work item types, type groups, link types, areas, iterations and labels of the template")
+	a.Attribute("created-at", d.DateTime, "When the space template was imported", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the space template was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+})
+
+var spaceTemplateList = JSONList(
+	"SpaceTemplate", "Holds the list of space templates",
+	spaceTemplate,
+	nil,
+	meta)
+
+var spaceTemplateSingle = JSONSingle(
+	"SpaceTemplate", "Holds a single space template",
+	spaceTemplate,
+	nil)
+
+var spaceTemplateImport = a.Type("SpaceTemplateImport", func() {
+	a.Description(`A space template document to import`)
+	a.Attribute("data", spaceTemplateImportData)
+	a.Required("data")
+})
+
+var spaceTemplateImportData = a.Type("SpaceTemplateImportData", func() {
+	a.Attribute("content", d.String, "The space template as a YAML or JSON document", func() {
+		a.Example("name: Kanban\nwork_item_types:\n- id: 0a24d3c2-e0a6-4686-8051-ec0ea1915a28\n  reuse: true\n")
+	})
+	a.Required("content")
+})
+
 var _ = a.Resource("space_template", func() {
 	a.BasePath("/spacetemplates")
 
@@ -16,9 +76,36 @@ var _ = a.Resource("space_template", func() {
 		a.Params(func() {
 			a.Param("spaceTemplateID", d.UUID, "id of the space template to fetch")
 		})
+		a.Response(d.OK, spaceTemplateSingle)
 		a.Response(d.MethodNotAllowed)
 		a.Response(d.TemporaryRedirect)
 		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the built-in and all versions of the imported space templates")
+		a.Response(d.OK, spaceTemplateList)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("import", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Import a space template from a YAML or JSON document, only the service account may import templates")
+		a.Payload(spaceTemplateImport)
+		a.Response(d.Created, "/spacetemplates/.*", func() {
+			a.Media(spaceTemplateSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
 })
@@ -49,6 +49,7 @@ var spaceRelationships = a.Type("SpaceRelationships", func() {
 	a.Attribute("iterations", relationGeneric, "Space can have one or many iterations")
 	a.Attribute("labels", relationGeneric, "Space can have one or many labels")
 	a.Attribute("owned-by", spaceOwnedBy, "The owner of the Space")
+	a.Attribute("space-template", relationGeneric, "The space template that is applied when the space is created")
 	a.Attribute("workitems", relationGeneric, "Space can have one or many work items")
 	a.Attribute("workitemlinktypes", relationGeneric, "Space can have one or many work item link types")
 	a.Attribute("workitemtypes", relationGeneric, "Space can have one or many work item types")
@@ -66,6 +67,10 @@ var spaceAttributes = a.Type("SpaceAttributes", func() {
 	a.Attribute("description", d.String, "Description for the space", func() {
 		a.Example("This is the foobar collaboration space")
 	})
+	a.Attribute("key-prefix", d.String, "Prefix of the human-readable keys of the work items in the space", func() {
+		a.Pattern("^[A-Z][A-Z0-9]{1,9}$")
+		a.Example("PLAT")
+	})
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
 		a.Example(23)
 	})
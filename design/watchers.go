@@ -0,0 +1,59 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var watcherList = JSONList(
+	"Watcher", "Holds the list of the users who watch a work item",
+	genericData,
+	nil,
+	watcherListMeta)
+
+var watcherListMeta = a.Type("WatcherListMeta", func() {
+	a.Attribute("totalCount", d.Integer, "The number of watchers")
+	a.Attribute("watching", d.Boolean, "Whether the current user watches the work item")
+	a.Required("totalCount", "watching")
+})
+
+var _ = a.Resource("work_item_watchers", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("watchers"),
+		)
+		a.Description("List the users who watch the given work item")
+		a.Response(d.OK, watcherList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("watch", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("watchers"),
+		)
+		a.Description("Make the current user a watcher of the given work item")
+		a.Response(d.OK, watcherList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+
+	a.Action("unwatch", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("watchers"),
+		)
+		a.Description("Stop the current user from watching the given work item")
+		a.Response(d.OK, watcherList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+	})
+})
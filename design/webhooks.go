@@ -0,0 +1,201 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var webhook = a.Type("Webhook", func() {
+	a.Description(`JSONAPI store for the data of a webhook. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("webhooks")
+	})
+	a.Attribute("id", d.UUID, "ID of the webhook", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", webhookAttributes)
+	a.Attribute("relationships", webhookRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var webhookAttributes = a.Type("WebhookAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a webhook. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("url", d.String, "The URL the event payloads are posted to", func() {
+		a.Example("https://example.com/hooks/wit")
+	})
+	a.Attribute("events", a.ArrayOf(d.String), "The events the webhook subscribed to", func() {
+		a.Example([]string{"workitem.create", "workitem.update", "comment.create", "link.create", "link.delete"})
+	})
+	a.Attribute("active", d.Boolean, "Whether events are sent to the webhook")
+	a.Attribute("secret", d.String, `The secret used to compute the HMAC-SHA256 signature sent in the "X-WIT-Signature" header. Only returned when the webhook is created.`)
+	a.Attribute("created-at", d.DateTime, "When the webhook was created", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("updated-at", d.DateTime, "When the webhook was updated", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "Version for optimistic concurrency control (optional during creating)", func() {
+		a.Example(23)
+	})
+})
+
+var webhookRelationships = a.Type("WebhookRelations", func() {
+	a.Attribute("space", relationGeneric, "This defines the owning space")
+	a.Attribute("deliveries", relationGeneric, "This defines the delivery history of the webhook")
+})
+
+var webhookList = JSONList(
+	"Webhook", "Holds the list of webhooks",
+	webhook,
+	pagingLinks,
+	meta)
+
+var webhookSingle = JSONSingle(
+	"Webhook", "Holds a single webhook",
+	webhook,
+	nil)
+
+var webhookDelivery = a.Type("WebhookDelivery", func() {
+	a.Description(`JSONAPI store for the data of a webhook delivery. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("webhookdeliveries")
+	})
+	a.Attribute("id", d.UUID, "ID of the delivery")
+	a.Attribute("attributes", webhookDeliveryAttributes)
+	a.Required("type", "id", "attributes")
+})
+
+var webhookDeliveryAttributes = a.Type("WebhookDeliveryAttributes", func() {
+	a.Attribute("event-id", d.UUID, "ID of the event that was delivered")
+	a.Attribute("event-type", d.String, "Type of the event that was delivered", func() {
+		a.Example("workitem.update")
+	})
+	a.Attribute("payload", d.Any, "The payload posted to the webhook")
+	a.Attribute("status", d.String, "State of the delivery", func() {
+		a.Enum("pending", "delivered", "failed")
+	})
+	a.Attribute("attempts", d.Integer, "Number of delivery attempts")
+	a.Attribute("response-status", d.Integer, "HTTP status returned by the webhook on the last attempt")
+	a.Attribute("last-error", d.String, "Error of the last failed attempt")
+	a.Attribute("created-at", d.DateTime, "When the event occurred")
+	a.Attribute("next-attempt-at", d.DateTime, "When the delivery is attempted next if it is pending")
+	a.Attribute("delivered-at", d.DateTime, "When the payload was delivered")
+	a.Required("event-id", "event-type", "status", "attempts", "created-at")
+})
+
+var webhookDeliveryList = JSONList(
+	"WebhookDelivery", "Holds the paginated delivery history of a webhook",
+	webhookDelivery,
+	pagingLinks,
+	meta)
+
+var _ = a.Resource("webhook", func() {
+	a.Parent("space")
+	a.BasePath("/webhooks")
+
+	a.Action("show", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:webhookID"),
+		)
+		a.Description("Retrieve the webhook for the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook")
+		})
+		a.Response(d.OK, webhookSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("list", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the webhooks of the space.")
+		a.Response(d.OK, webhookList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("create a webhook in the space.")
+		a.Payload(webhookSingle)
+		a.Response(d.Created, "/webhooks/.*", func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:webhookID"),
+		)
+		a.Description("update the webhook for the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook to update")
+		})
+		a.Payload(webhookSingle)
+		a.Response(d.OK, func() {
+			a.Media(webhookSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:webhookID"),
+		)
+		a.Description("Delete the webhook for the given id.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook to delete")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("list-deliveries", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.GET("/:webhookID/deliveries"),
+		)
+		a.Description("List the delivery history of the webhook, most recent first.")
+		a.Params(func() {
+			a.Param("webhookID", d.UUID, "ID of the webhook")
+			a.Param("page[offset]", d.String, "Paging start position")
+			a.Param("page[limit]", d.Integer, "Paging size")
+		})
+		a.Response(d.OK, webhookDeliveryList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
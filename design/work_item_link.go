@@ -173,6 +173,23 @@ var _ = a.Resource("work_item_link", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+	a.Action("restore", func() {
+		a.Description("Undelete the deleted work item link with given id.")
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:linkId/restore"),
+		)
+		a.Params(func() {
+			a.Param("linkId", d.UUID, "ID of the deleted work item link to be restored")
+		})
+		a.Response(d.OK, workItemLink)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 var _ = a.Resource("work_item_relationships_links", func() {
@@ -194,3 +211,122 @@ var _ = a.Resource("work_item_relationships_links", func() {
 		})
 	})
 })
+
+// workItemScheduleAttributes are the dates computed for a work item by the
+// critical path method
+var workItemScheduleAttributes = a.Type("WorkItemScheduleAttributes", func() {
+	a.Attribute("title", d.String, "The title of the work item")
+	a.Attribute("duration", d.Integer, "The remaining effort of the work item in seconds (its remaining or original estimate, 0 when resolved or closed)", func() {
+		a.Example(28800)
+	})
+	a.Attribute("earliest-start", d.DateTime, "The earliest time the work item can start after the work items it depends on")
+	a.Attribute("earliest-finish", d.DateTime, "The earliest time the work item can finish")
+	a.Attribute("latest-start", d.DateTime, "The latest time the work item must start")
+	a.Attribute("latest-finish", d.DateTime, "The latest time the work item must finish to not delay the schedule or miss a due date")
+	a.Attribute("slack", d.Integer, "The time in seconds by which the work item can be delayed, negative if a due date cannot be met")
+	a.Attribute("critical", d.Boolean, "True if the work item cannot be delayed")
+	a.Required("title", "duration", "earliest-start", "earliest-finish", "latest-start", "latest-finish", "slack", "critical")
+})
+
+// workItemScheduleEntry is a work item in a schedule
+var workItemScheduleEntry = a.Type("WorkItemScheduleEntry", func() {
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitems")
+	})
+	a.Attribute("id", d.UUID, "ID of the work item", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemScheduleAttributes)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemScheduleMeta = a.Type("WorkItemScheduleMeta", func() {
+	a.Attribute("totalCount", d.Integer, "The number of scheduled work items")
+	a.Attribute("start", d.DateTime, "The start of the schedule")
+	a.Attribute("finish", d.DateTime, "The earliest finish of all scheduled work items")
+	a.Attribute("critical-path", a.ArrayOf(d.UUID), "The IDs of the chain of dependent work items that determines the finish, from the first to the last work item")
+	a.Required("totalCount", "start", "finish", "critical-path")
+})
+
+var workItemScheduleList = JSONList(
+	"WorkItemSchedule", "Holds the schedule of work items computed over their dependency links",
+	workItemScheduleEntry,
+	nil,
+	workItemScheduleMeta)
+
+var _ = a.Resource("work_item_graph", func() {
+	a.BasePath("/graph")
+	a.Parent("workitem")
+	a.Action("tree", func() {
+		a.Description("List the links to all descendants of the given work item in link types of the tree topology (with the work items and link types included).")
+		a.Routing(
+			a.GET("/tree"),
+		)
+		a.Params(func() {
+			a.Param("depth", d.Integer, "Number of levels below the given work item to include (all levels when not given)", func() {
+				a.Minimum(1)
+			})
+		})
+		a.Response(d.OK, workItemLinkList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors, func() {
+			a.Description("This error arises when the given work item does not exist.")
+		})
+	})
+	a.Action("dependencies", func() {
+		a.Description("List the transitive closure of the links of the given work item in link types of the dependency topology (with the work items and link types included).")
+		a.Routing(
+			a.GET("/dependencies"),
+		)
+		a.Response(d.OK, workItemLinkList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors, func() {
+			a.Description("This error arises when the given work item does not exist.")
+		})
+	})
+	a.Action("export", func() {
+		a.Description("Export the descendant tree or the dependency graph of the given work item as Graphviz DOT or GraphML document.")
+		a.Routing(
+			a.GET("/export"),
+		)
+		a.Params(func() {
+			a.Param("graph", d.String, "The graph to export", func() {
+				a.Enum("tree", "dependencies")
+				a.Default("tree")
+			})
+			a.Param("format", d.String, "The format of the exported document", func() {
+				a.Enum("dot", "graphml")
+				a.Default("dot")
+			})
+			a.Param("depth", d.Integer, "Number of levels below the given work item to include in the tree (all levels when not given)", func() {
+				a.Minimum(1)
+			})
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors, func() {
+			a.Description("This error arises when the given work item does not exist.")
+		})
+	})
+	a.Action("schedule", func() {
+		a.Description("Compute the earliest and latest start and finish, the slack and the critical path of the descendants of the given work item (or of the work item itself if it has none) over the links of the dependency topology between them, using their estimates and due dates.")
+		a.Routing(
+			a.GET("/schedule"),
+		)
+		a.Params(func() {
+			a.Param("start", d.DateTime, "The start of the schedule (now when not given)")
+		})
+		a.Response(d.OK, workItemScheduleList)
+		a.Response(d.BadRequest, JSONAPIErrors, func() {
+			a.Description("This error arises when the dependency links form a cycle.")
+		})
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors, func() {
+			a.Description("This error arises when the given work item does not exist.")
+		})
+	})
+})
@@ -44,6 +44,7 @@ See also http://jsonapi.org/format/#document-resource-object`)
 		a.Example("6c5610be-30b2-4880-9fec-81e4f8e4fd76")
 	})
 	a.Attribute("attributes", workItemLinkCategoryAttributes)
+	a.Attribute("relationships", workItemLinkCategoryRelationships)
 	a.Attribute("links", genericLinks)
 	a.Required("type", "attributes")
 })
@@ -61,6 +62,13 @@ See also http://jsonapi.org/format/#document-resource-object-attributes`)
 	})
 })
 
+// workItemLinkCategoryRelationships is the JSONAPI store for the relationships of a work item link category.
+var workItemLinkCategoryRelationships = a.Type("WorkItemLinkCategoryRelationships", func() {
+	a.Description(`JSONAPI store for the relationships of a work item link category.
+See also http://jsonapi.org/format/#document-resource-object-relationships`)
+	a.Attribute("space", relationSpaces, "This defines the owning space of this work item link category.")
+})
+
 // relationWorkItemLinkCategory is the JSONAPI store for the links
 var relationWorkItemLinkCategory = a.Type("RelationWorkItemLinkCategory", func() {
 	a.Attribute("data", relationWorkItemLinkCategoryData)
@@ -139,3 +147,78 @@ var _ = a.Resource("work_item_link_category", func() {
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("work_item_link_categories", func() {
+	a.BasePath("/workitemlinkcategories")
+	a.Parent("space")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET(""),
+		)
+		a.Description("List the work item link categories that can be used in the space.")
+		a.Response(d.OK, func() {
+			a.Media(workItemLinkCategoryList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a work item link category in the space.")
+		a.Payload(createWorkItemLinkCategoryPayload)
+		a.Response(d.Created, "/workitemlinkcategories/.*", func() {
+			a.Media(workItemLinkCategory)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:id"),
+		)
+		a.Description("Update the work item link category of the space with the given ID.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "ID of the work item link category to update")
+		})
+		a.Payload(updateWorkItemLinkCategoryPayload)
+		a.Response(d.OK, func() {
+			a.Media(workItemLinkCategory)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:id"),
+		)
+		a.Description("Delete the work item link category of the space with the given ID, unless it is used by link types.")
+		a.Params(func() {
+			a.Param("id", d.UUID, "ID of the work item link category to delete")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+})
@@ -61,7 +61,10 @@ For example, if a bug blocks a user story, the reverse name name is "blocked by"
 		a.Example("tested by")
 	})
 	a.Attribute("topology", d.String, `The topology determines the restrictions placed on the usage of each work item link type.`, func() {
-		a.Enum("network", "tree")
+		a.Enum("network", "directed_network", "dependency", "tree")
+	})
+	a.Attribute("blocking", d.Boolean, `Only for the dependency topology: the target of a link of a blocking type cannot be resolved or closed as long as the source of the link is open.`, func() {
+		a.Example(false)
 	})
 
 	// IMPORTANT: We cannot require any field here because these "attributes" will be used
@@ -77,6 +80,8 @@ var workItemLinkTypeRelationships = a.Type("WorkItemLinkTypeRelationships", func
 See also http://jsonapi.org/format/#document-resource-object-relationships`)
 	a.Attribute("link_category", relationWorkItemLinkCategory, "The work item link category of this work item link type.")
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item link type.")
+	a.Attribute("source_type", relationWorkItemType, "The type of the work items that can be the source of links of this type (optional, any type if omitted).")
+	a.Attribute("target_type", relationWorkItemType, "The type of the work items that can be the target of links of this type (optional, any type if omitted).")
 })
 
 // relationWorkItemType is the JSONAPI store for the work item type relationship objects
@@ -181,4 +186,62 @@ var _ = a.Resource("work_item_link_types", func() {
 		a.Response(d.BadRequest, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST(""),
+		)
+		a.Description("Create a work item link type in the space.")
+		a.Payload(createWorkItemLinkTypePayload)
+		a.Response(d.Created, "/workitemlinktypes/.*", func() {
+			a.Media(workItemLinkType)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/:wiltID"),
+		)
+		a.Description(`Update the work item link type of the space with the given ID.
+The topology and the type constraints cannot be changed while the link type is used by links.`)
+		a.Params(func() {
+			a.Param("wiltID", d.UUID, "ID of the work item link type to update")
+		})
+		a.Payload(updateWorkItemLinkTypePayload)
+		a.Response(d.OK, func() {
+			a.Media(workItemLinkType)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("/:wiltID"),
+		)
+		a.Description("Delete the work item link type of the space with the given ID, unless it is used by links.")
+		a.Params(func() {
+			a.Param("wiltID", d.UUID, "ID of the work item link type to delete")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
@@ -0,0 +1,124 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var fieldChange = a.Type("FieldChange", func() {
+	a.Description(`The change of a single field between two revisions`)
+	a.Attribute("name", d.String, "Name of the field", func() {
+		a.Example("system.title")
+	})
+	a.Attribute("old", d.Any, "Value of the field before the change (null if the field was not set)")
+	a.Attribute("new", d.Any, "Value of the field after the change (null if the field was removed)")
+	a.Required("name")
+})
+
+var workItemRevision = a.Type("WorkItemRevision", func() {
+	a.Description(`JSONAPI store for the data of a work item revision. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemrevisions")
+	})
+	a.Attribute("id", d.UUID, "ID of the revision", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemRevisionAttributes)
+	a.Attribute("relationships", workItemRevisionRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemRevisionAttributes = a.Type("WorkItemRevisionAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a work item revision. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("revision-type", d.String, "The kind of modification", func() {
+		a.Enum("create", "update", "delete", "move", "type-change")
+	})
+	a.Attribute("created-at", d.DateTime, "When the modification happened", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("version", d.Integer, "The version of the work item after the modification")
+	a.Attribute("changes", a.ArrayOf(fieldChange), "The fields that changed compared to the previous revision")
+	a.Attribute("fields", a.HashOf(d.String, d.Any), "The field values of the work item after the modification")
+	a.Required("revision-type", "created-at", "version", "changes")
+})
+
+var workItemRevisionRelationships = a.Type("WorkItemRevisionRelations", func() {
+	a.Attribute("modifier", relationGeneric, "This defines the identity that made the modification")
+	a.Attribute("workitem", relationGeneric, "This defines the modified work item")
+	a.Attribute("workitemtype", relationGeneric, "This defines the type of the work item at the time of the modification")
+})
+
+var workItemRevisionList = JSONList(
+	"WorkItemRevision", "Holds the revisions of a work item, oldest first",
+	workItemRevision,
+	nil,
+	nil)
+
+var workItemActivity = a.Type("WorkItemActivity", func() {
+	a.Description(`JSONAPI store for an entry of the activity feed of a work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("workitemactivities")
+	})
+	a.Attribute("id", d.UUID, "ID of the underlying work item, comment or link revision", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", workItemActivityAttributes)
+	a.Attribute("relationships", workItemActivityRelationships)
+	a.Required("type", "id", "attributes")
+})
+
+var workItemActivityAttributes = a.Type("WorkItemActivityAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of an activity entry. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("kind", d.String, "What was modified", func() {
+		a.Enum("workitem", "comment", "link")
+	})
+	a.Attribute("revision-type", d.String, "The kind of modification", func() {
+		a.Enum("create", "update", "delete", "move", "type-change", "resolve", "unresolve")
+	})
+	a.Attribute("created-at", d.DateTime, "When the modification happened", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("changes", a.ArrayOf(fieldChange), "The fields of the work item or comment that changed compared to their previous revision")
+	a.Required("kind", "revision-type", "created-at")
+})
+
+var workItemActivityRelationships = a.Type("WorkItemActivityRelations", func() {
+	a.Attribute("modifier", relationGeneric, "This defines the identity that made the modification")
+	a.Attribute("comment", relationGeneric, "This defines the modified comment")
+	a.Attribute("link", relationGeneric, "This defines the modified work item link")
+	a.Attribute("link_type", relationGeneric, "This defines the type of the modified work item link")
+	a.Attribute("source", relationGeneric, "This defines the source of the modified work item link")
+	a.Attribute("target", relationGeneric, "This defines the target of the modified work item link")
+})
+
+var workItemActivityList = JSONList(
+	"WorkItemActivity", "Holds the activity feed of a work item, oldest first",
+	workItemActivity,
+	nil,
+	nil)
+
+var _ = a.Resource("work_item_revisions", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("revisions"),
+		)
+		a.Description("List the revisions of the given work item together with the changes compared to the previous revision")
+		a.Response(d.OK, workItemRevisionList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+
+	a.Action("activity", func() {
+		a.Routing(
+			a.GET("activity"),
+		)
+		a.Description("List the revisions of the given work item, its comments and its links in the order they happened")
+		a.Response(d.OK, workItemActivityList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
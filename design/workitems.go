@@ -44,6 +44,9 @@ var workItemRelationships = a.Type("WorkItemRelationships", func() {
 	a.Attribute("space", relationSpaces, "This defines the owning space of this work item.")
 	a.Attribute("parent", relationKindUUID, "This defines the parent of this work item.")
 	a.Attribute("workItemLinks", relationGeneric, "List of links in which this work item is involved")
+	a.Attribute("attachments", relationGenericList, "List of files attached to the Work Item")
+	a.Attribute("watchers", relationGenericList, "The users who watch the Work Item, the meta tells whether the current user is one of them")
+	a.Attribute("blockers", relationGenericList, "The open work items that block the Work Item through blocking dependency links")
 })
 
 // relationBaseType is top level block for WorkItemType relationship
@@ -104,6 +107,143 @@ var workItemReorder = Reorder(
 	workItem,
 	position)
 
+var workItemRevertData = a.Type("WorkItemRevertData", func() {
+	a.Attribute("revision", d.UUID, "ID of the revision to revert the work item to", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("version", d.Integer, "Current version of the work item, used to detect concurrent modifications")
+	a.Attribute("fields", a.ArrayOf(d.String), "Names of the fields to revert (all fields are reverted if omitted)", func() {
+		a.Example([]string{"system.title", "system.description"})
+	})
+	a.Required("revision", "version")
+})
+
+// workItemRevert is the payload to revert a work item to a previous revision
+var workItemRevert = a.Type("WorkItemRevert", func() {
+	a.Attribute("data", workItemRevertData)
+	a.Required("data")
+})
+
+var workItemMoveData = a.Type("WorkItemMoveData", func() {
+	a.Attribute("space", d.UUID, "ID of the space to move the work item to", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("children", d.Boolean, "Whether the children of the work item (in the same space) are moved along with it, defaults to false")
+	a.Required("space")
+})
+
+// workItemMove is the payload to move a work item to another space
+var workItemMove = a.Type("WorkItemMove", func() {
+	a.Attribute("data", workItemMoveData)
+	a.Required("data")
+})
+
+var workItemChangeTypeData = a.Type("WorkItemChangeTypeData", func() {
+	a.Attribute("type", d.UUID, "ID of the new work item type, which must belong to the space of the work item", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("version", d.Integer, "Current version of the work item, used to detect concurrent modifications")
+	a.Attribute("confirm", d.Boolean, "Whether the loss of the values of fields that the new type has no compatible field for is accepted, defaults to false")
+	a.Required("type", "version")
+})
+
+// workItemChangeType is the payload to change the type of a work item
+var workItemChangeType = a.Type("WorkItemChangeType", func() {
+	a.Attribute("data", workItemChangeTypeData)
+	a.Required("data")
+})
+
+var workItemChangeTypeMeta = a.Type("WorkItemChangeTypeMeta", func() {
+	a.Attribute("kept", a.ArrayOf(d.String), "The names of the fields whose values were carried over")
+	a.Attribute("dropped", a.ArrayOf(d.String), "The names of the fields whose values were dropped")
+	a.Required("kept", "dropped")
+})
+
+var workItemChangeTypeList = JSONList(
+	"WorkItemChangeType", "Holds the work item with its new type",
+	workItem,
+	nil,
+	workItemChangeTypeMeta)
+
+var workItemCloneData = a.Type("WorkItemCloneData", func() {
+	a.Attribute("children", d.Boolean, "Whether the children of the work item (reached through links of a tree topology, in the same space) are cloned recursively, defaults to false")
+	a.Attribute("reset-state", d.Boolean, "Whether the clones are put into the initial state of their type, defaults to false")
+	a.Attribute("clear-assignees", d.Boolean, "Whether the clones are left unassigned, defaults to false")
+	a.Attribute("iteration", d.UUID, "ID of the iteration of the clones, the iterations of the cloned work items are kept if omitted")
+	a.Attribute("area", d.UUID, "ID of the area of the clones, the areas of the cloned work items are kept if omitted")
+})
+
+// workItemClone is the payload to clone a work item
+var workItemClone = a.Type("WorkItemClone", func() {
+	a.Attribute("data", workItemCloneData)
+	a.Required("data")
+})
+
+var workItemCloneMeta = a.Type("WorkItemCloneMeta", func() {
+	a.Attribute("mapping", a.HashOf(d.String, d.UUID), "The IDs of the clones by the IDs of the cloned work items")
+	a.Required("mapping")
+})
+
+var workItemCloneList = JSONList(
+	"WorkItemClone", "Holds the clones of a work item and of its children",
+	workItem,
+	nil,
+	workItemCloneMeta)
+
+var workItemBulkOperation = a.Type("WorkItemBulkOperation", func() {
+	a.Description(`An operation on a single field of the work items of a bulk update`)
+	a.Attribute("op", d.String, "How the field is modified", func() {
+		a.Enum("set", "add", "remove")
+	})
+	a.Attribute("field", d.String, "Name of the field to modify", func() {
+		a.Example("system.iteration")
+	})
+	a.Attribute("value", d.Any, "The value to set, or the value(s) to add to or remove from a list field")
+	a.Required("op", "field")
+})
+
+var workItemBulkItem = a.Type("WorkItemBulkItem", func() {
+	a.Attribute("id", d.UUID, "ID of the work item to modify")
+	a.Attribute("version", d.Integer, "Expected version of the work item, the work item is not modified if it was changed in the meantime")
+	a.Required("id")
+})
+
+var workItemBulkUpdateData = a.Type("WorkItemBulkUpdateData", func() {
+	a.Attribute("filter", d.String, "a query language expression selecting the work items to modify (see search)")
+	a.Attribute("items", a.ArrayOf(workItemBulkItem), "the work items to modify, as an alternative to the filter")
+	a.Attribute("operations", a.ArrayOf(workItemBulkOperation), "the operations to apply to each work item, in the given order")
+	a.Required("operations")
+})
+
+// workItemBulkUpdate is the payload to modify many work items at once
+var workItemBulkUpdate = a.Type("WorkItemBulkUpdate", func() {
+	a.Attribute("data", workItemBulkUpdateData)
+	a.Required("data")
+})
+
+var workItemBulkUpdateResult = a.Type("WorkItemBulkUpdateResult", func() {
+	a.Description(`The outcome of a bulk update for a single work item`)
+	a.Attribute("id", d.UUID, "ID of the work item")
+	a.Attribute("status", d.String, "updated: the work item was modified; failed: the work item could not be modified; rolled-back: the modification was undone because another work item failed", func() {
+		a.Enum("updated", "failed", "rolled-back")
+	})
+	a.Attribute("version", d.Integer, "Version of the work item after the update")
+	a.Attribute("error", JSONAPIError, "Why the work item could not be modified")
+	a.Required("id", "status")
+})
+
+var workItemBulkUpdateMeta = a.Type("WorkItemBulkUpdateMeta", func() {
+	a.Attribute("committed", d.Boolean, "true if all work items were modified, false if no work item was modified")
+	a.Attribute("total", d.Integer, "The number of selected work items")
+	a.Required("committed", "total")
+})
+
+var workItemBulkUpdateResultList = JSONList(
+	"WorkItemBulkUpdateResult", "Holds the outcome of a bulk update for each work item",
+	workItemBulkUpdateResult,
+	nil,
+	workItemBulkUpdateMeta)
+
 // endpoints that DO NOT depend on the space id (ie, when the work item ID is specified in the URL, there's no need to pass the space ID)
 var _ = a.Resource("workitem", func() {
 	a.BasePath("/workitems")
@@ -179,6 +319,113 @@ var _ = a.Resource("workitem", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+
+	a.Action("revert", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/revert"),
+		)
+		a.Description("revert all or the selected fields of the work item to their values in the given revision.")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to revert")
+		})
+		a.Payload(workItemRevert)
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("move", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/move"),
+		)
+		a.Description(`move the work item with the given id (and optionally its children) to another space.
+The moved work items get new numbers in the target space, their former URLs redirect to them.`)
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to move")
+		})
+		a.Payload(workItemMove)
+		a.Response(d.OK, func() {
+			a.Media(workItemList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("change-type", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/type"),
+		)
+		a.Description(`change the type of the work item with the given id.
+The values of the fields with the same name and a compatible kind in the new type are kept. If other values would be
+dropped, the change is rejected with a conflict that lists the fields unless it is confirmed.`)
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item whose type is changed")
+		})
+		a.Payload(workItemChangeType)
+		a.Response(d.OK, func() {
+			a.Media(workItemChangeTypeList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("clone", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/clone"),
+		)
+		a.Description(`clone the work item with the given id (and optionally its children) in its space.
+The links between the cloned work items are cloned as well.`)
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the work item to clone")
+		})
+		a.Payload(workItemClone)
+		a.Response(d.Created, func() {
+			a.Media(workItemCloneList)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("restore", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:wiID/restore"),
+		)
+		a.Description("undelete the deleted work item with the given id together with the links that were deleted with it.")
+		a.Params(func() {
+			a.Param("wiID", d.UUID, "ID of the deleted work item to restore")
+		})
+		a.Response(d.OK, func() {
+			a.Media(workItemSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 // endpoints that depend on the space id
@@ -246,6 +493,40 @@ var _ = a.Resource("workitems", func() {
 		a.Response(d.Unauthorized, JSONAPIErrors)
 		a.Response(d.Forbidden, JSONAPIErrors)
 	})
+
+	a.Action("bulk-update", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PATCH("/bulk"),
+		)
+		a.Description("modify the work items selected by a filter or by their IDs in a single transaction, either all work items are modified or none of them. Work items that cannot be modified as requested are reported in the result, any other failure fails the whole request.")
+		a.Payload(workItemBulkUpdate)
+		a.Response(d.OK, workItemBulkUpdateResultList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+	a.Action("schedule", func() {
+		a.Routing(
+			a.GET("/schedule"),
+		)
+		a.Description("Compute the earliest and latest start and finish, the slack and the critical path of the work items of the space selected by a filter or by their IDs over the links of the dependency topology between them, using their estimates and due dates.")
+		a.Params(func() {
+			a.Param("filter[expression]", d.String, "a query language expression selecting the work items to schedule")
+			a.Param("filter[workitems]", a.ArrayOf(d.UUID), "IDs of the work items to schedule")
+			a.Param("start", d.DateTime, "The start of the schedule (now when not given)")
+		})
+		a.Response(d.OK, workItemScheduleList)
+		a.Response(d.BadRequest, JSONAPIErrors, func() {
+			a.Description("This error arises when neither or both of a filter and IDs are given, when too many work items are selected or when the dependency links form a cycle.")
+		})
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors, func() {
+			a.Description("This error arises when the space or one of the given work items does not exist in the space.")
+		})
+	})
 })
 
 var _ = a.Resource("planner_backlog", func() {
@@ -290,3 +571,23 @@ var _ = a.Resource("named_work_items", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 	})
 })
+
+var _ = a.Resource("work_item_keys", func() {
+	a.BasePath("/workitemkeys")
+	a.Action("show", func() {
+		a.Routing(
+			a.GET("/:key"),
+		)
+		a.Description("Retrieve a work item from its human-readable key (e.g. PLAT-123).")
+		a.Params(func() {
+			a.Param("key", d.String, "Key of the work item to show, made of the key prefix of its space and its number", func() {
+				a.Pattern("^[A-Za-z][A-Za-z0-9]{1,9}-[0-9]+$")
+				a.Example("PLAT-123")
+			})
+		})
+		a.Response(d.TemporaryRedirect)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+	})
+})
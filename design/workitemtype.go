@@ -29,9 +29,97 @@ var fieldDefinition = a.Type("fieldDefinition", func() {
 		a.Example("The iteration field tells to which iteration a work item belongs.")
 		a.MinLength(1)
 	})
+	a.Attribute("deprecated", d.Boolean, "Deprecated fields keep their values but should no longer be offered for input")
+	a.Attribute("order", d.Integer, "The position of the field when presenting the fields of the work item type")
 	a.Required("required", "type", "label", "description")
 })
 
+// workItemTypeField is a custom field to add to a work item type
+var workItemTypeField = a.Type("WorkItemTypeField", func() {
+	a.Attribute("name", d.String, "The name of the custom field", func() {
+		a.Example("custom.story_points")
+		a.Pattern(`^custom\.[a-zA-Z0-9_\-]+$`)
+	})
+	a.Attribute("definition", fieldDefinition)
+	a.Attribute("defaultValue", d.Any, "The value of the field for the existing work items (required for required fields)")
+	a.Required("name", "definition")
+})
+
+// workItemTypeFieldAdd is the payload to add a custom field to a work item type
+var workItemTypeFieldAdd = a.Type("WorkItemTypeFieldAdd", func() {
+	a.Attribute("data", workItemTypeField)
+	a.Required("data")
+})
+
+// workItemTypeFieldOrder is the payload to reorder the fields of a work item type
+var workItemTypeFieldOrder = a.Type("WorkItemTypeFieldOrder", func() {
+	a.Attribute("data", a.ArrayOf(d.String), "The names of the fields to put first, in order", func() {
+		a.Example([]string{"system.title", "custom.story_points"})
+	})
+	a.Required("data")
+})
+
+// workflowState is a state of the workflow of a work item type
+var workflowState = a.Type("WorkflowState", func() {
+	a.Description("A state a work item of the type can be in")
+	a.Attribute("name", d.String, "The value of the system.state field", func() {
+		a.Example("resolved")
+	})
+	a.Attribute("required-fields", a.ArrayOf(d.String), "The fields that must have a value when a work item enters the state", func() {
+		a.Example([]string{"system.assignees"})
+	})
+	a.Required("name")
+})
+
+// workflowTransition is an allowed state change in the workflow of a work item type
+var workflowTransition = a.Type("WorkflowTransition", func() {
+	a.Description("A transition allows work items to move from one state to another")
+	a.Attribute("from", d.String, "The state the work item is in", func() {
+		a.Example("in progress")
+	})
+	a.Attribute("to", d.String, "The state the work item moves to", func() {
+		a.Example("resolved")
+	})
+	a.Attribute("roles", a.ArrayOf(d.String), "The roles that may perform the transition, anybody may perform it if no roles are given", func() {
+		a.Example([]string{"assignee", "space-owner"})
+	})
+	a.Required("from", "to")
+})
+
+// workflow restricts the states and state transitions of the work items of a type
+var workflow = a.Type("Workflow", func() {
+	a.Description("The states and the allowed transitions between the states of the work items of a type")
+	a.Attribute("states", a.ArrayOf(workflowState))
+	a.Attribute("transitions", a.ArrayOf(workflowTransition))
+	a.Required("states", "transitions")
+})
+
+// workItemTypeWorkflow is the payload to set the workflow of a work item type
+var workItemTypeWorkflow = a.Type("WorkItemTypeWorkflow", func() {
+	a.Attribute("data", workflow, "The workflow, leave it empty to remove the workflow of the work item type")
+})
+
+// rollUp is a value of the work items of a type that is computed from their children
+var rollUp = a.Type("RollUp", func() {
+	a.Description("A read-only value of the work items of a type that is computed from the children of a work item")
+	a.Attribute("name", d.String, "The name of the computed value", func() {
+		a.Example("story_points")
+	})
+	a.Attribute("function", d.String, "The function that computes the value from the children", func() {
+		a.Enum("sum", "min", "max", "count", "progress")
+		a.Example("sum")
+	})
+	a.Attribute("field", d.String, "The field of the children the function is applied to (not used by progress, optional for count)", func() {
+		a.Example("custom.story_points")
+	})
+	a.Required("name", "function")
+})
+
+// workItemTypeRollUps is the payload to set the roll-ups of a work item type
+var workItemTypeRollUps = a.Type("WorkItemTypeRollUps", func() {
+	a.Attribute("data", a.ArrayOf(rollUp), "The roll-ups, leave it empty to remove the roll-ups of the work item type")
+})
+
 var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 	a.Description("A work item type describes the values a work item type instance can hold.")
 	a.Attribute("version", d.Integer, "Version for optimistic concurrency control")
@@ -53,6 +141,9 @@ var workItemTypeAttributes = a.Type("WorkItemTypeAttributes", func() {
 		a.MinLength(1)
 	})
 
+	a.Attribute("workflow", workflow, "The workflow of the work item type (read-only, only present if the type has a workflow)")
+	a.Attribute("rollups", a.ArrayOf(rollUp), "The roll-ups of the work item type, their values are found in the system.rollups attribute of the work items (read-only, only present if the type has roll-ups)")
+
 	// TODO: Maybe this needs to be abandoned at some point
 	a.Attribute("extendedTypeName", d.UUID, "If newly created type extends any existing type (This is never present in any response and is only optional when creating.)")
 
@@ -129,6 +220,101 @@ var _ = a.Resource("workitemtype", func() {
 		a.Response(d.NotFound, JSONAPIErrors)
 		a.Response(d.InternalServerError, JSONAPIErrors)
 	})
+
+	a.Action("update-workflow", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PUT("/:witID/workflow"),
+		)
+		a.Description("Set the workflow of the work item type with the given ID (space owner only).")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+		})
+		a.Payload(workItemTypeWorkflow)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("update-rollups", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PUT("/:witID/rollups"),
+		)
+		a.Description("Set the roll-ups of the work item type with the given ID (space owner only).")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+		})
+		a.Payload(workItemTypeRollUps)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("add-field", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:witID/fields"),
+		)
+		a.Description("Add a custom field to the work item type with the given ID (space owner only).")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+		})
+		a.Payload(workItemTypeFieldAdd)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("deprecate-field", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("/:witID/fields/:fieldName/deprecate"),
+		)
+		a.Description("Deprecate a custom field of the work item type with the given ID, the values of the field are kept (space owner only).")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+			a.Param("fieldName", d.String, "Name of the custom field")
+		})
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
+
+	a.Action("reorder-fields", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.PUT("/:witID/fields/order"),
+		)
+		a.Description("Reorder the fields of the work item type with the given ID (space owner only).")
+		a.Params(func() {
+			a.Param("witID", d.UUID, "ID of the work item type")
+		})
+		a.Payload(workItemTypeFieldOrder)
+		a.Response(d.OK, workItemTypeSingle)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.Conflict, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+	})
 })
 
 var _ = a.Resource("workitemtypes", func() {
@@ -0,0 +1,115 @@
+package design
+
+import (
+	d "github.com/goadesign/goa/design"
+	a "github.com/goadesign/goa/design/apidsl"
+)
+
+var worklog = a.Type("Worklog", func() {
+	a.Description(`JSONAPI store for the data of the time spent on a work item. See also http://jsonapi.org/format/#document-resource-object`)
+	a.Attribute("type", d.String, func() {
+		a.Enum("worklogs")
+	})
+	a.Attribute("id", d.UUID, "ID of the worklog", func() {
+		a.Example("40bbdd3d-8b5d-4fd6-ac90-7236b669af04")
+	})
+	a.Attribute("attributes", worklogAttributes)
+	a.Attribute("relationships", worklogRelationships)
+	a.Attribute("links", genericLinks)
+	a.Required("type", "attributes")
+})
+
+var worklogAttributes = a.Type("WorklogAttributes", func() {
+	a.Description(`JSONAPI store for all the "attributes" of a worklog. See also http://jsonapi.org/format/#document-resource-object-attributes`)
+	a.Attribute("started-at", d.DateTime, "When the work started, defaults to the current time", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Attribute("duration", d.Integer, "The time spent in seconds", func() {
+		a.Minimum(1)
+		a.Example(5400)
+	})
+	a.Attribute("note", d.String, "What was done", func() {
+		a.Example("Reproduced the issue")
+	})
+	a.Attribute("created-at", d.DateTime, "When the time spent was logged", func() {
+		a.Example("2016-11-29T23:18:14Z")
+	})
+	a.Required("duration")
+})
+
+var worklogRelationships = a.Type("WorklogRelations", func() {
+	a.Attribute("creator", relationGeneric, "The user who spent the time")
+	a.Attribute("parent", relationGeneric, "The work item on which the time was spent")
+})
+
+var worklogListMeta = a.Type("WorklogListMeta", func() {
+	a.Attribute("totalCount", d.Integer, "The number of worklogs")
+	a.Attribute("time-spent", d.Integer, "The time spent on the work item in seconds")
+	a.Attribute("original-estimate", d.Integer, "The original estimate of the work item in seconds")
+	a.Attribute("remaining-estimate", d.Integer, "The remaining estimate of the work item in seconds")
+	a.Attribute("rolled-up-time-spent", d.Integer, "The time spent on the work item and its descendants in seconds")
+	a.Attribute("rolled-up-original-estimate", d.Integer, "The original estimates of the work item and its descendants in seconds")
+	a.Attribute("rolled-up-remaining-estimate", d.Integer, "The remaining estimates of the work item and its descendants in seconds")
+	a.Required("totalCount", "time-spent", "original-estimate", "remaining-estimate",
+		"rolled-up-time-spent", "rolled-up-original-estimate", "rolled-up-remaining-estimate")
+})
+
+var worklogList = JSONList(
+	"Worklog", "Holds the list of the time spent on a work item",
+	worklog,
+	nil,
+	worklogListMeta)
+
+var worklogSingle = JSONSingle(
+	"Worklog", "Holds the time spent on a work item",
+	worklog,
+	nil)
+
+var _ = a.Resource("work_item_worklogs", func() {
+	a.Parent("workitem")
+
+	a.Action("list", func() {
+		a.Routing(
+			a.GET("worklogs"),
+		)
+		a.Description("List the time spent on the given work item, the meta holds the totals of the work item and of its descendants")
+		a.Response(d.OK, worklogList)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("create", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.POST("worklogs"),
+		)
+		a.Description("Log time spent on the given work item by the current user")
+		a.Payload(worklogSingle)
+		a.Response(d.Created, "/worklogs/.*", func() {
+			a.Media(worklogSingle)
+		})
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+
+	a.Action("delete", func() {
+		a.Security("jwt")
+		a.Routing(
+			a.DELETE("worklogs/:worklogID"),
+		)
+		a.Description("Delete time logged on the given work item")
+		a.Params(func() {
+			a.Param("worklogID", d.UUID, "ID of the worklog")
+		})
+		a.Response(d.OK)
+		a.Response(d.BadRequest, JSONAPIErrors)
+		a.Response(d.Unauthorized, JSONAPIErrors)
+		a.Response(d.Forbidden, JSONAPIErrors)
+		a.Response(d.NotFound, JSONAPIErrors)
+		a.Response(d.InternalServerError, JSONAPIErrors)
+	})
+})
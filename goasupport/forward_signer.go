@@ -23,3 +23,8 @@ func (f forwardSigner) Sign(request *http.Request) error {
 func NewForwardSigner(ctx context.Context) goaclient.Signer {
 	return &forwardSigner{token: goajwt.ContextJWT(ctx).Raw}
 }
+
+// NewTokenSigner return a new signer that forwards the given raw token
+func NewTokenSigner(token string) goaclient.Signer {
+	return &forwardSigner{token: token}
+}
@@ -7,14 +7,18 @@ import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/application"
 	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	"github.com/fabric8-services/fabric8-wit/comment"
 	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
 	"github.com/fabric8-services/fabric8-wit/query"
 	"github.com/fabric8-services/fabric8-wit/remoteworkitem"
 	"github.com/fabric8-services/fabric8-wit/search"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/jinzhu/gorm"
@@ -75,6 +79,11 @@ func (g *GormBase) WorkItemTypes() workitem.WorkItemTypeRepository {
 	return workitem.NewWorkItemTypeRepository(g.db)
 }
 
+// WorkItemRevisions returns a work item revision repository
+func (g *GormBase) WorkItemRevisions() workitem.RevisionRepository {
+	return workitem.NewRevisionRepository(g.db)
+}
+
 func (g *GormBase) Spaces() space.Repository {
 	return space.NewRepository(g.db)
 }
@@ -115,11 +124,21 @@ func (g *GormBase) WorkItemLinks() link.WorkItemLinkRepository {
 	return link.NewWorkItemLinkRepository(g.db)
 }
 
+// WorkItemLinkRevisions returns a work item link revision repository
+func (g *GormBase) WorkItemLinkRevisions() link.RevisionRepository {
+	return link.NewRevisionRepository(g.db)
+}
+
 // Comments returns a work item comments repository
 func (g *GormBase) Comments() comment.Repository {
 	return comment.NewRepository(g.db)
 }
 
+// CommentRevisions returns a comment revision repository
+func (g *GormBase) CommentRevisions() comment.RevisionRepository {
+	return comment.NewRevisionRepository(g.db)
+}
+
 // Iterations returns a iteration repository
 func (g *GormBase) Iterations() iteration.Repository {
 	return iteration.NewIterationRepository(g.db)
@@ -145,6 +164,46 @@ func (g *GormBase) Codebases() codebase.Repository {
 	return codebase.NewCodebaseRepository(g.db)
 }
 
+// NotificationOutbox returns a notification outbox repository
+func (g *GormBase) NotificationOutbox() outbox.Repository {
+	return outbox.NewRepository(g.db)
+}
+
+// Webhooks returns a webhook repository
+func (g *GormBase) Webhooks() webhook.Repository {
+	return webhook.NewRepository(g.db)
+}
+
+// WebhookDeliveries returns a webhook delivery repository
+func (g *GormBase) WebhookDeliveries() webhook.DeliveryRepository {
+	return webhook.NewDeliveryRepository(g.db)
+}
+
+// SpaceTemplates returns a space template repository
+func (g *GormBase) SpaceTemplates() spacetemplate.Repository {
+	return spacetemplate.NewRepository(g.db)
+}
+
+// WorkItemTypeGroups returns a work item type group repository
+func (g *GormBase) WorkItemTypeGroups() workitem.WorkItemTypeGroupRepository {
+	return workitem.NewWorkItemTypeGroupRepository(g.db)
+}
+
+// Attachments returns an attachment repository
+func (g *GormBase) Attachments() attachment.Repository {
+	return attachment.NewRepository(g.db)
+}
+
+// WorkItemWatchers returns a work item watcher repository
+func (g *GormBase) WorkItemWatchers() workitem.WatcherRepository {
+	return workitem.NewWatcherRepository(g.db)
+}
+
+// WorkItemWorklogs returns a work item worklog repository
+func (g *GormBase) WorkItemWorklogs() workitem.WorklogRepository {
+	return workitem.NewWorklogRepository(g.db)
+}
+
 func (g *GormBase) DB() *gorm.DB {
 	return g.db
 }
@@ -14,6 +14,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/app"
 	"github.com/fabric8-services/fabric8-wit/application"
+	"github.com/fabric8-services/fabric8-wit/attachment"
 	"github.com/fabric8-services/fabric8-wit/auth"
 	"github.com/fabric8-services/fabric8-wit/configuration"
 	"github.com/fabric8-services/fabric8-wit/controller"
@@ -31,6 +32,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/space"
 	"github.com/fabric8-services/fabric8-wit/space/authz"
 	"github.com/fabric8-services/fabric8-wit/token"
+	"github.com/fabric8-services/fabric8-wit/webhook"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 
@@ -180,21 +182,39 @@ func main() {
 	identityRepository := account.NewIdentityRepository(db)
 	userRepository := account.NewUserRepository(db)
 
-	var notificationChannel notification.Channel = &notification.DevNullChannel{}
+	// Notifications are written to the outbox together with the change that
+	// triggered them and delivered by the dispatcher in the background. The
+	// outbox also feeds the activity streams of the spaces, which is why it is
+	// used even if no notification service is configured.
+	var sender notification.Sender = &notification.DevNullSender{}
 	if config.GetNotificationServiceURL() != "" {
 		log.Logger().Infof("Enabling Notification service %v", config.GetNotificationServiceURL())
-		channel, err := notification.NewServiceChannel(config)
+		sender, err = notification.NewServiceSender(config)
 		if err != nil {
 			log.Panic(nil, map[string]interface{}{
 				"err": err,
 				"url": config.GetNotificationServiceURL(),
 			}, "failed to parse notification service url")
 		}
-		notificationChannel = channel
 	}
+	dispatcher := notification.NewDispatcher(db, sender, config)
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	var notificationChannel notification.Channel = notification.NewOutboxChannel(db)
+
+	// Reminders about the due dates of work items are sent in the background
+	reminder := notification.NewReminder(db, notificationChannel, config)
+	reminder.Start()
+	defer reminder.Stop()
 
 	appDB := gormapplication.NewGormDB(db)
 
+	// Webhook payloads are stored together with the change that triggered
+	// them and posted to the subscribed endpoints in the background
+	webhookDispatcher := webhook.NewDispatcher(db, config)
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+
 	tokenManager, err := token.NewManager(config)
 	if err != nil {
 		log.Panic(nil, map[string]interface{}{
@@ -225,6 +245,10 @@ func main() {
 	statusCtrl := controller.NewStatusController(service, db)
 	app.MountStatusController(service, statusCtrl)
 
+	// Mount "notification outbox" controller
+	notificationOutboxCtrl := controller.NewNotificationOutboxController(service, appDB)
+	app.MountNotificationOutboxController(service, notificationOutboxCtrl)
+
 	// Mount "workitem" controller
 	//workitemCtrl := controller.NewWorkitemController(service, appDB, config)
 	workitemCtrl := controller.NewNotifyingWorkitemController(service, appDB, notificationChannel, config)
@@ -234,6 +258,10 @@ func main() {
 	namedWorkitemsCtrl := controller.NewNamedWorkItemsController(service, appDB)
 	app.MountNamedWorkItemsController(service, namedWorkitemsCtrl)
 
+	// Mount "work item keys" controller
+	workItemKeysCtrl := controller.NewWorkItemKeysController(service, appDB)
+	app.MountWorkItemKeysController(service, workItemKeysCtrl)
+
 	// Mount "workitems" controller
 	//workitemsCtrl := controller.NewWorkitemsController(service, appDB, config)
 	workitemsCtrl := controller.NewNotifyingWorkitemsController(service, appDB, notificationChannel, config)
@@ -251,6 +279,10 @@ func main() {
 	workItemLinkCategoryCtrl := controller.NewWorkItemLinkCategoryController(service, appDB)
 	app.MountWorkItemLinkCategoryController(service, workItemLinkCategoryCtrl)
 
+	// Mount "work item link categories" controller
+	workItemLinkCategoriesCtrl := controller.NewWorkItemLinkCategoriesController(service, appDB)
+	app.MountWorkItemLinkCategoriesController(service, workItemLinkCategoriesCtrl)
+
 	// Mount "work item link type" controller
 	workItemLinkTypeCtrl := controller.NewWorkItemLinkTypeController(service, appDB, config)
 	app.MountWorkItemLinkTypeController(service, workItemLinkTypeCtrl)
@@ -260,7 +292,7 @@ func main() {
 	app.MountWorkItemLinkTypesController(service, workItemLinkTypesCtrl)
 
 	// Mount "work item link" controller
-	workItemLinkCtrl := controller.NewWorkItemLinkController(service, appDB, config)
+	workItemLinkCtrl := controller.NewNotifyingWorkItemLinkController(service, appDB, notificationChannel, config)
 	app.MountWorkItemLinkController(service, workItemLinkCtrl)
 
 	// Mount "work item comments" controller
@@ -272,15 +304,55 @@ func main() {
 	workItemRelationshipsLinksCtrl := controller.NewWorkItemRelationshipsLinksController(service, appDB, config)
 	app.MountWorkItemRelationshipsLinksController(service, workItemRelationshipsLinksCtrl)
 
+	// Mount "work item graph" controller
+	workItemGraphCtrl := controller.NewWorkItemGraphController(service, appDB)
+	app.MountWorkItemGraphController(service, workItemGraphCtrl)
+
 	// Mount "comments" controller
 	//commentsCtrl := controller.NewCommentsController(service, appDB, config)
 	commentsCtrl := controller.NewNotifyingCommentsController(service, appDB, notificationChannel, config)
 	app.MountCommentsController(service, commentsCtrl)
 
+	// The content of the files attached to work items and comments is kept
+	// in a blob store next to the database
+	blobStore, err := attachment.NewBlobStore(config)
+	if err != nil {
+		log.Panic(nil, map[string]interface{}{
+			"err":   err,
+			"store": config.GetAttachmentStore(),
+		}, "failed to create the attachment store")
+	}
+
+	// The attachments of deleted work items and comments are removed in the
+	// background
+	attachmentCollector := attachment.NewCollector(db, blobStore, config)
+	attachmentCollector.Start()
+	defer attachmentCollector.Stop()
+
+	// Mount "work item attachments" controller
+	workItemAttachmentsCtrl := controller.NewWorkItemAttachmentsController(service, appDB, blobStore, config)
+	app.MountWorkItemAttachmentsController(service, workItemAttachmentsCtrl)
+
+	// Mount "comment attachments" controller
+	commentAttachmentsCtrl := controller.NewCommentAttachmentsController(service, appDB, blobStore, config)
+	app.MountCommentAttachmentsController(service, commentAttachmentsCtrl)
+
+	// Mount "webhook" controller
+	webhookCtrl := controller.NewWebhookController(service, appDB, config)
+	app.MountWebhookController(service, webhookCtrl)
+
 	// Mount "work item labels relationships" controller
 	workItemLabelCtrl := controller.NewWorkItemLabelsController(service, appDB, config)
 	app.MountWorkItemLabelsController(service, workItemLabelCtrl)
 
+	// Mount "work item watchers" controller
+	workItemWatchersCtrl := controller.NewWorkItemWatchersController(service, appDB)
+	app.MountWorkItemWatchersController(service, workItemWatchersCtrl)
+
+	// Mount "work item worklogs" controller
+	workItemWorklogsCtrl := controller.NewWorkItemWorklogsController(service, appDB)
+	app.MountWorkItemWorklogsController(service, workItemWorklogsCtrl)
+
 	if config.GetFeatureWorkitemRemote() {
 		// Scheduler to fetch and import remote tracker items
 		scheduler = remoteworkitem.NewScheduler(db)
@@ -333,13 +405,21 @@ func main() {
 	app.MountLabelController(service, labelCtrl)
 
 	// Mount "iterations" controller
-	iterationCtrl := controller.NewIterationController(service, appDB, config)
+	iterationCtrl := controller.NewNotifyingIterationController(service, appDB, notificationChannel, config)
 	app.MountIterationController(service, iterationCtrl)
 
 	// Mount "spaceiterations" controller
 	spaceIterationCtrl := controller.NewSpaceIterationsController(service, appDB, config)
 	app.MountSpaceIterationsController(service, spaceIterationCtrl)
 
+	// Mount "work_item_revisions" controller
+	workItemRevisionsCtrl := controller.NewWorkItemRevisionsController(service, appDB)
+	app.MountWorkItemRevisionsController(service, workItemRevisionsCtrl)
+
+	// Mount "space_events" controller
+	spaceEventsCtrl := controller.NewSpaceEventsController(service, appDB, config)
+	app.MountSpaceEventsController(service, spaceEventsCtrl)
+
 	// Mount "userspace" controller
 	userspaceCtrl := controller.NewUserspaceController(service, db)
 	app.MountUserspaceController(service, userspaceCtrl)
@@ -379,6 +379,60 @@ func GetMigrations() Migrations {
 	// Version 83
 	m = append(m, steps{ExecuteSQLFile("083-index-comments-parent.sql")})
 
+	// Version 84
+	m = append(m, steps{ExecuteSQLFile("084-notification-outbox.sql")})
+
+	// Version 85
+	m = append(m, steps{ExecuteSQLFile("085-webhooks.sql")})
+
+	// Version 86
+	m = append(m, steps{ExecuteSQLFile("086-notification-outbox-payload.sql")})
+
+	// Version 87
+	m = append(m, steps{ExecuteSQLFile("087-notification-outbox-sequence.sql")})
+
+	// Version 88
+	m = append(m, steps{ExecuteSQLFile("088-revision-history-indexes.sql")})
+
+	// Version 89
+	m = append(m, steps{ExecuteSQLFile("089-work-item-type-workflow.sql")})
+
+	// Version 90
+	m = append(m, steps{ExecuteSQLFile("090-space-templates.sql")})
+
+	// Version 91
+	m = append(m, steps{ExecuteSQLFile("091-attachments.sql")})
+
+	// Version 92
+	m = append(m, steps{ExecuteSQLFile("092-comment-threads.sql")})
+
+	// Version 93
+	m = append(m, steps{ExecuteSQLFile("093-mentions.sql")})
+
+	// Version 94
+	m = append(m, steps{ExecuteSQLFile("094-work-item-watchers.sql")})
+
+	// Version 95
+	m = append(m, steps{ExecuteSQLFile("095-work-item-moves.sql")})
+
+	// Version 96
+	m = append(m, steps{ExecuteSQLFile("096-work-item-keys.sql")})
+
+	// Version 97
+	m = append(m, steps{ExecuteSQLFile("097-work-item-worklogs.sql")})
+
+	// Version 98
+	m = append(m, steps{ExecuteSQLFile("098-work-item-reminders.sql")})
+
+	// Version 99
+	m = append(m, steps{ExecuteSQLFile("099-space-scoped-link-types.sql", space.SystemSpace.String())})
+
+	// Version 100
+	m = append(m, steps{ExecuteSQLFile("100-blocking-dependency-links.sql")})
+
+	// Version 101
+	m = append(m, steps{ExecuteSQLFile("101-work-item-type-roll-ups.sql")})
+
 	// Version N
 	//
 	// In order to add an upgrade, simply append an array of MigrationFunc to the
@@ -557,6 +611,7 @@ func BootstrapWorkItemLinking(ctx context.Context, linkCatRepo *link.GormWorkIte
 		ID:          link.SystemWorkItemLinkCategorySystemID,
 		Name:        "system",
 		Description: &systemCatDesc,
+		SpaceID:     space.SystemSpace,
 	}
 	_, err := createOrUpdateWorkItemLinkCategory(ctx, linkCatRepo, &systemCat)
 	if err != nil {
@@ -567,6 +622,7 @@ func BootstrapWorkItemLinking(ctx context.Context, linkCatRepo *link.GormWorkIte
 		ID:          link.SystemWorkItemLinkCategoryUserID,
 		Name:        "user",
 		Description: &userCatDesc,
+		SpaceID:     space.SystemSpace,
 	}
 	_, err = createOrUpdateWorkItemLinkCategory(ctx, linkCatRepo, &userCat)
 	if err != nil {
@@ -794,6 +850,24 @@ func createOrUpdateSystemPlannerItemType(ctx context.Context, witr *workitem.Gor
 			Label:       "Labels",
 			Description: "List of labels attached to the work item",
 		},
+		workitem.SystemOriginalEstimate: {
+			Type:        workitem.SimpleType{Kind: workitem.KindDuration},
+			Required:    false,
+			Label:       "Original estimate",
+			Description: "The effort (in seconds) that was initially estimated for the work item",
+		},
+		workitem.SystemRemainingEstimate: {
+			Type:        workitem.SimpleType{Kind: workitem.KindDuration},
+			Required:    false,
+			Label:       "Remaining estimate",
+			Description: "The effort (in seconds) that is still needed to complete the work item",
+		},
+		workitem.SystemDueDate: {
+			Type:        workitem.SimpleType{Kind: workitem.KindInstant},
+			Required:    false,
+			Label:       "Due date",
+			Description: "The date by which the work item should be done",
+		},
 		workitem.SystemState: {
 			Type: &workitem.EnumType{
 				SimpleType: workitem.SimpleType{Kind: workitem.KindEnum},
@@ -862,7 +936,7 @@ func createOrUpdateType(ctx context.Context, typeID uuid.UUID, spaceID uuid.UUID
 		wit.Name = name
 		wit.Description = &description
 		wit.Icon = icon
-		wit.Fields = fields
+		wit.Fields = mergeStoredFields(wit.Fields, fields)
 		wit.Path = path
 		db = db.Save(wit)
 		return db.Error
@@ -871,6 +945,30 @@ func createOrUpdateType(ctx context.Context, typeID uuid.UUID, spaceID uuid.UUID
 	return nil
 }
 
+// mergeStoredFields returns the given field definitions extended by the custom
+// fields that were added to the stored work item type at runtime. The order
+// and the deprecation of the stored fields are kept so that the changes made
+// through the API survive the bootstrapping of the type.
+func mergeStoredFields(stored workitem.FieldDefinitions, fields workitem.FieldDefinitions) workitem.FieldDefinitions {
+	result := workitem.FieldDefinitions{}
+	for name, definition := range fields {
+		if storedDefinition, exists := stored[name]; exists {
+			definition.Order = storedDefinition.Order
+			if storedDefinition.Deprecated {
+				definition.Deprecated = true
+				definition.Required = false
+			}
+		}
+		result[name] = definition
+	}
+	for name, definition := range stored {
+		if _, exists := result[name]; !exists && workitem.IsCustomField(name) {
+			result[name] = definition
+		}
+	}
+	return result
+}
+
 func loadFields(ctx context.Context, wit *workitem.WorkItemType, into workitem.FieldDefinitions) error {
 	// copy fields from wit
 	for key, value := range wit.Fields {
@@ -129,6 +129,24 @@ func TestMigrations(t *testing.T) {
 	t.Run("TestMigration80", testMigration80)
 	t.Run("TestMigration81", testMigration81)
 	t.Run("TestMigration82", testMigration82)
+	t.Run("TestMigration84", testMigration84)
+	t.Run("TestMigration85", testMigration85)
+	t.Run("TestMigration86", testMigration86)
+	t.Run("TestMigration87", testMigration87)
+	t.Run("TestMigration88", testMigration88)
+	t.Run("TestMigration89", testMigration89)
+	t.Run("TestMigration90", testMigration90)
+	t.Run("TestMigration91", testMigration91)
+	t.Run("TestMigration92", testMigration92)
+	t.Run("TestMigration93", testMigration93)
+	t.Run("TestMigration94", testMigration94)
+	t.Run("TestMigration95", testMigration95)
+	t.Run("TestMigration96", testMigration96)
+	t.Run("TestMigration97", testMigration97)
+	t.Run("TestMigration98", testMigration98)
+	t.Run("TestMigration99", testMigration99)
+	t.Run("TestMigration100", testMigration100)
+	t.Run("TestMigration101", testMigration101)
 
 	// Perform the migration
 	err = migration.Migrate(sqlDB, databaseName)
@@ -686,6 +704,130 @@ func testMigration82(t *testing.T) {
 	assert.Equal(t, updatedAt.String(), relationshipsChangedAt.String())
 }
 
+func testMigration84(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:85], 85)
+	assert.True(t, dialect.HasTable("notification_outbox"))
+	assert.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_message_id_unique"))
+	assert.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_pending_idx"))
+	assert.False(t, dialect.HasColumn("notification_outbox", "token"))
+}
+
+func testMigration85(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:86], 86)
+	assert.True(t, dialect.HasTable("webhooks"))
+	assert.True(t, dialect.HasTable("webhook_deliveries"))
+	assert.True(t, dialect.HasIndex("webhooks", "webhooks_space_id_idx"))
+	assert.True(t, dialect.HasIndex("webhook_deliveries", "webhook_deliveries_pending_idx"))
+}
+
+func testMigration86(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:87], 87)
+	assert.True(t, dialect.HasColumn("notification_outbox", "payload"))
+}
+
+func testMigration87(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:88], 88)
+	assert.True(t, dialect.HasColumn("notification_outbox", "sequence"))
+	assert.True(t, dialect.HasColumn("notification_outbox", "space_id"))
+	assert.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_space_sequence_idx"))
+	assert.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_unsequenced_idx"))
+	assert.True(t, dialect.HasIndex("notification_outbox", "notification_outbox_done_idx"))
+}
+
+func testMigration88(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:89], 89)
+	assert.True(t, dialect.HasIndex("comment_revisions", "comment_revisions_comment_parent_id_idx"))
+	assert.True(t, dialect.HasIndex("work_item_link_revisions", "work_item_link_revisions_source_id_idx"))
+	assert.True(t, dialect.HasIndex("work_item_link_revisions", "work_item_link_revisions_target_id_idx"))
+}
+
+func testMigration89(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:90], 90)
+	assert.True(t, dialect.HasColumn("work_item_types", "workflow"))
+}
+
+func testMigration90(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:91], 91)
+	assert.True(t, dialect.HasTable("space_templates"))
+	assert.True(t, dialect.HasIndex("space_templates", "space_templates_name_version_idx"))
+	assert.True(t, dialect.HasTable("work_item_type_groups"))
+	assert.True(t, dialect.HasIndex("work_item_type_groups", "work_item_type_groups_space_id_idx"))
+}
+
+func testMigration91(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:92], 92)
+	assert.True(t, dialect.HasTable("attachments"))
+	assert.True(t, dialect.HasIndex("attachments", "attachments_parent_idx"))
+}
+
+func testMigration92(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:93], 93)
+	assert.True(t, dialect.HasColumn("comments", "parent_comment_id"))
+	assert.True(t, dialect.HasColumn("comments", "resolved_at"))
+	assert.True(t, dialect.HasColumn("comments", "resolved_by"))
+	assert.True(t, dialect.HasIndex("comments", "comments_parent_comment_id_idx"))
+	assert.True(t, dialect.HasColumn("comment_revisions", "comment_parent_comment_id"))
+}
+
+func testMigration93(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:94], 94)
+	assert.True(t, dialect.HasColumn("comments", "mentions"))
+	assert.True(t, dialect.HasColumn("work_items", "mentions"))
+	assert.True(t, dialect.HasColumn("work_item_revisions", "work_item_mentions"))
+}
+
+func testMigration94(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:95], 95)
+	assert.True(t, dialect.HasTable("work_item_watchers"))
+	assert.True(t, dialect.HasIndex("work_item_watchers", "work_item_watchers_identity_id_idx"))
+}
+
+func testMigration95(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:96], 96)
+	assert.True(t, dialect.HasTable("work_item_moves"))
+	assert.True(t, dialect.HasIndex("work_item_moves", "work_item_moves_from_idx"))
+	assert.True(t, dialect.HasIndex("work_item_moves", "work_item_moves_work_item_id_idx"))
+}
+
+func testMigration96(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:97], 97)
+	assert.True(t, dialect.HasColumn("spaces", "key_prefix"))
+	assert.True(t, dialect.HasIndex("spaces", "spaces_key_prefix_idx"))
+	assert.True(t, dialect.HasColumn("work_items", "key"))
+	assert.True(t, dialect.HasIndex("work_items", "work_items_key_idx"))
+}
+
+func testMigration97(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:98], 98)
+	assert.True(t, dialect.HasTable("work_item_worklogs"))
+	assert.True(t, dialect.HasIndex("work_item_worklogs", "work_item_worklogs_work_item_id_idx"))
+}
+
+func testMigration98(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:99], 99)
+	assert.True(t, dialect.HasTable("work_item_reminders"))
+	assert.True(t, dialect.HasIndex("work_items", "work_items_due_date_idx"))
+}
+
+func testMigration99(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:100], 100)
+	assert.True(t, dialect.HasColumn("work_item_link_categories", "space_id"))
+	assert.True(t, dialect.HasIndex("work_item_link_categories", "work_item_link_categories_space_id_idx"))
+	assert.True(t, dialect.HasColumn("work_item_link_types", "source_type_id"))
+	assert.True(t, dialect.HasColumn("work_item_link_types", "target_type_id"))
+}
+
+func testMigration100(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:101], 101)
+	assert.True(t, dialect.HasColumn("work_item_link_types", "blocking"))
+}
+
+func testMigration101(t *testing.T) {
+	migrateToVersion(t, sqlDB, migrations[:102], 102)
+	assert.True(t, dialect.HasColumn("work_item_types", "roll_ups"))
+	assert.True(t, dialect.HasTable("work_item_roll_up_values"))
+}
+
 // runSQLscript loads the given filename from the packaged SQL test files and
 // executes it on the given database. Golang text/template module is used
 // to handle all the optional arguments passed to the sql test files
@@ -8,6 +8,7 @@ import (
 
 	config "github.com/fabric8-services/fabric8-wit/configuration"
 	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/stretchr/testify/require"
 
 	_ "github.com/lib/pq"
@@ -39,3 +40,28 @@ func TestConcurrentMigrations(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestMergeStoredFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	title := workitem.FieldDefinition{Label: "Title", Required: true, Type: workitem.SimpleType{Kind: workitem.KindString}}
+	storyPoints := workitem.FieldDefinition{Label: "Story Points", Order: 1, Type: workitem.SimpleType{Kind: workitem.KindInteger}}
+	customer := workitem.FieldDefinition{Label: "Customer", Order: 3, Deprecated: true, Type: workitem.SimpleType{Kind: workitem.KindString}}
+	storedTitle := title
+	storedTitle.Order = 2
+	stored := workitem.FieldDefinitions{
+		workitem.SystemTitle:  storedTitle,
+		"custom.story_points": storyPoints,
+		"custom.customer":     customer,
+		"obsolete":            {Label: "Obsolete", Type: workitem.SimpleType{Kind: workitem.KindString}},
+	}
+	// when
+	merged := mergeStoredFields(stored, workitem.FieldDefinitions{workitem.SystemTitle: title})
+	// then
+	require.Len(t, merged, 3)
+	require.Equal(t, 2, merged[workitem.SystemTitle].Order)
+	require.True(t, merged[workitem.SystemTitle].Required)
+	require.Equal(t, storyPoints, merged["custom.story_points"])
+	require.Equal(t, customer, merged["custom.customer"])
+	require.NotContains(t, merged, "obsolete")
+}
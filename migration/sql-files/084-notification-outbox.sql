@@ -0,0 +1,20 @@
+-- notification_outbox holds notification messages that were written in the
+-- same transaction as the change that triggered them. A background dispatcher
+-- delivers them to the notification service and retries failed deliveries.
+CREATE TABLE notification_outbox (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    message_id uuid NOT NULL,
+    message_type text NOT NULL CHECK(message_type <> ''),
+    target_id text NOT NULL,
+    user_id text,
+    status text NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'delivered', 'dead')),
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_error text,
+    delivered_at timestamp with time zone
+);
+CREATE UNIQUE INDEX notification_outbox_message_id_unique ON notification_outbox (message_id);
+CREATE INDEX notification_outbox_pending_idx ON notification_outbox USING btree (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,35 @@
+-- webhooks are HTTP endpoints that get notified about events in a space
+CREATE TABLE webhooks (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    url text NOT NULL CHECK(url <> ''),
+    secret text NOT NULL CHECK(secret <> ''),
+    events jsonb NOT NULL DEFAULT '[]',
+    active boolean NOT NULL DEFAULT TRUE,
+    creator uuid NOT NULL,
+    version integer NOT NULL DEFAULT 0
+);
+CREATE INDEX webhooks_space_id_idx ON webhooks USING btree (space_id) WHERE deleted_at IS NULL;
+
+-- webhook_deliveries keeps the history of the payloads sent to a webhook
+CREATE TABLE webhook_deliveries (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id uuid NOT NULL,
+    event_type text NOT NULL CHECK(event_type <> ''),
+    payload jsonb NOT NULL,
+    status text NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'delivered', 'failed')),
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    response_status integer,
+    last_error text,
+    delivered_at timestamp with time zone
+);
+CREATE INDEX webhook_deliveries_webhook_id_idx ON webhook_deliveries USING btree (webhook_id, created_at);
+CREATE INDEX webhook_deliveries_pending_idx ON webhook_deliveries USING btree (next_attempt_at) WHERE status = 'pending';
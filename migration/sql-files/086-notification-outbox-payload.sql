@@ -0,0 +1,3 @@
+-- the payload holds the optional parts of a notification message like the
+-- space, the version and the field level changes of its target
+ALTER TABLE notification_outbox ADD COLUMN payload jsonb;
@@ -0,0 +1,13 @@
+-- the sequence orders the messages of the outbox in the order they were
+-- committed so that the activity stream of a space can be resumed from the
+-- last event a client has seen. Messages get their number after their
+-- transaction committed and have a sequence of 0 until then.
+CREATE SEQUENCE notification_outbox_sequence_seq;
+ALTER TABLE notification_outbox ADD COLUMN sequence bigint NOT NULL DEFAULT 0;
+ALTER TABLE notification_outbox ADD COLUMN space_id uuid;
+UPDATE notification_outbox SET space_id = (payload->>'space_id')::uuid WHERE payload ? 'space_id';
+CREATE INDEX notification_outbox_space_sequence_idx ON notification_outbox USING btree (space_id, sequence);
+CREATE INDEX notification_outbox_unsequenced_idx ON notification_outbox USING btree (created_at) WHERE sequence = 0 AND space_id IS NOT NULL;
+-- delivered and dead lettered notifications are purged after the retention
+-- period
+CREATE INDEX notification_outbox_done_idx ON notification_outbox USING btree (updated_at) WHERE status <> 'pending';
@@ -0,0 +1,4 @@
+-- indexes to retrieve the revisions of the comments and links of a work item
+CREATE INDEX comment_revisions_comment_parent_id_idx ON comment_revisions USING BTREE (comment_parent_id);
+CREATE INDEX work_item_link_revisions_source_id_idx ON work_item_link_revisions USING BTREE (work_item_link_source_id);
+CREATE INDEX work_item_link_revisions_target_id_idx ON work_item_link_revisions USING BTREE (work_item_link_target_id);
@@ -0,0 +1,2 @@
+-- optional workflow (states, transitions, required fields and roles) of a work item type
+ALTER TABLE work_item_types ADD COLUMN workflow jsonb;
@@ -0,0 +1,29 @@
+-- space_templates holds the imported space templates. Importing a template
+-- with the name of an existing one creates a new version of it.
+CREATE TABLE space_templates (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    name text NOT NULL CHECK(name <> ''),
+    version integer NOT NULL DEFAULT 1,
+    description text,
+    template jsonb NOT NULL
+);
+CREATE UNIQUE INDEX space_templates_name_version_idx ON space_templates (name, version) WHERE deleted_at IS NULL;
+
+-- work_item_type_groups holds the type groups that were created in a space
+-- when a template was applied to it
+CREATE TABLE work_item_type_groups (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    bucket text NOT NULL CHECK(bucket IN ('portfolio', 'requirement', 'iteration')),
+    name text NOT NULL CHECK(name <> ''),
+    icon text,
+    position integer NOT NULL DEFAULT 0,
+    type_list jsonb NOT NULL DEFAULT '[]'
+);
+CREATE INDEX work_item_type_groups_space_id_idx ON work_item_type_groups USING btree (space_id, position) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+-- attachments holds the metadata of the files attached to work items and
+-- comments, their content is kept in a blob store under the blob_key
+CREATE TABLE attachments (
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    id uuid primary key DEFAULT uuid_generate_v4() NOT NULL,
+    parent_type text NOT NULL CHECK(parent_type IN ('workitems', 'comments')),
+    parent_id uuid NOT NULL,
+    space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    file_name text NOT NULL CHECK(file_name <> ''),
+    mime_type text NOT NULL,
+    size bigint NOT NULL CHECK(size >= 0),
+    creator uuid NOT NULL,
+    blob_key text NOT NULL
+);
+CREATE INDEX attachments_parent_idx ON attachments USING btree (parent_type, parent_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,8 @@
+-- replies reference the top-level comment of their thread, a thread can be
+-- marked as resolved
+ALTER TABLE comments ADD COLUMN parent_comment_id uuid REFERENCES comments (id) ON DELETE CASCADE;
+ALTER TABLE comments ADD COLUMN resolved_at timestamp with time zone;
+ALTER TABLE comments ADD COLUMN resolved_by uuid;
+CREATE INDEX comments_parent_comment_id_idx ON comments USING btree (parent_comment_id) WHERE parent_comment_id IS NOT NULL;
+
+ALTER TABLE comment_revisions ADD COLUMN comment_parent_comment_id uuid;
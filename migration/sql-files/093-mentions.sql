@@ -0,0 +1,6 @@
+-- the users mentioned in a comment or in the description of a work item,
+-- mapped from their username to the ID of their identity
+ALTER TABLE comments ADD COLUMN mentions jsonb NOT NULL DEFAULT '{}';
+ALTER TABLE work_items ADD COLUMN mentions jsonb NOT NULL DEFAULT '{}';
+
+ALTER TABLE work_item_revisions ADD COLUMN work_item_mentions jsonb;
@@ -0,0 +1,8 @@
+-- the identities that follow a work item and get notified about its changes
+CREATE TABLE work_item_watchers (
+    created_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items (id) ON DELETE CASCADE,
+    identity_id uuid NOT NULL,
+    PRIMARY KEY (work_item_id, identity_id)
+);
+CREATE INDEX work_item_watchers_identity_id_idx ON work_item_watchers USING btree (identity_id);
@@ -0,0 +1,14 @@
+-- the moves of work items from one space to another, the previous space and
+-- number of a work item are kept to redirect from its former URL
+CREATE TABLE work_item_moves (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items (id) ON DELETE CASCADE,
+    modifier_id uuid NOT NULL,
+    from_space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    from_number integer NOT NULL,
+    to_space_id uuid NOT NULL REFERENCES spaces (id) ON DELETE CASCADE,
+    to_number integer NOT NULL
+);
+CREATE UNIQUE INDEX work_item_moves_from_idx ON work_item_moves USING btree (from_space_id, from_number);
+CREATE INDEX work_item_moves_work_item_id_idx ON work_item_moves USING btree (work_item_id);
@@ -0,0 +1,35 @@
+-- a space can define a short key prefix (e.g. 'PLAT') which is combined with
+-- the number of its work items to form human-readable keys (e.g. 'PLAT-123')
+ALTER TABLE spaces ADD COLUMN key_prefix text;
+ALTER TABLE spaces ADD CONSTRAINT spaces_key_prefix_check CHECK (key_prefix ~ '^[A-Z][A-Z0-9]{1,9}$');
+CREATE UNIQUE INDEX spaces_key_prefix_idx ON spaces (key_prefix) WHERE deleted_at IS NULL;
+
+ALTER TABLE work_items ADD COLUMN key text;
+COMMENT ON COLUMN work_items.key IS 'see triggers on the ''work_items'' and ''spaces'' tables.';
+CREATE INDEX work_items_key_idx ON work_items (upper(key));
+
+-- the key of a work item is computed from the key prefix of its space and its
+-- number whenever the work item is created or its space or number changes
+CREATE FUNCTION work_item_set_key() RETURNS trigger AS $$
+    BEGIN
+        NEW.key := (SELECT s.key_prefix || '-' || NEW.number FROM spaces s WHERE s.id = NEW.space_id);
+        RETURN NEW;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER work_item_set_key_trigger BEFORE INSERT OR UPDATE OF space_id, number, key ON work_items
+    FOR EACH ROW
+    EXECUTE PROCEDURE work_item_set_key();
+
+-- the keys of all work items in a space are updated when its key prefix changes
+CREATE FUNCTION space_update_work_item_keys() RETURNS trigger AS $$
+    BEGIN
+        UPDATE work_items wi SET key = NEW.key_prefix || '-' || wi.number WHERE wi.space_id = NEW.id;
+        RETURN NEW;
+    END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER space_update_work_item_keys_trigger AFTER UPDATE OF key_prefix ON spaces
+    FOR EACH ROW
+    WHEN (NEW.key_prefix IS DISTINCT FROM OLD.key_prefix)
+    EXECUTE PROCEDURE space_update_work_item_keys();
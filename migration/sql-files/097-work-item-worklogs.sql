@@ -0,0 +1,14 @@
+-- the time spent on work items, the duration is in seconds
+CREATE TABLE work_item_worklogs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    created_at timestamp with time zone,
+    updated_at timestamp with time zone,
+    deleted_at timestamp with time zone,
+    work_item_id uuid NOT NULL REFERENCES work_items (id) ON DELETE CASCADE,
+    creator uuid NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    duration bigint NOT NULL,
+    note text,
+    CONSTRAINT work_item_worklogs_duration_check CHECK (duration > 0)
+);
+CREATE INDEX work_item_worklogs_work_item_id_idx ON work_item_worklogs USING btree (work_item_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+-- the reminders sent for the due dates of work items; the primary key makes
+-- sure that every reminder is sent only once, no matter how many instances of
+-- the reminder job are running or how often they are restarted
+CREATE TABLE work_item_reminders (
+    work_item_id uuid NOT NULL REFERENCES work_items (id) ON DELETE CASCADE,
+    kind text NOT NULL,
+    -- the due date (in seconds since the epoch) that the reminder was sent for
+    due_date bigint NOT NULL,
+    -- how long (in seconds) before the due date the reminder was sent
+    lead_time bigint NOT NULL,
+    sent_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (work_item_id, kind, due_date, lead_time),
+    CONSTRAINT work_item_reminders_kind_check CHECK (kind IN ('due_soon', 'overdue'))
+);
+
+-- the reminder job looks up the work items by their due date
+CREATE INDEX work_items_due_date_idx ON work_items USING btree (((fields->>'system.due_date')::numeric)) WHERE deleted_at IS NULL;
@@ -0,0 +1,15 @@
+-- Link categories belong to a space like the link types do. The existing
+-- categories become the categories of the system space.
+ALTER TABLE work_item_link_categories ADD space_id uuid DEFAULT '{{index . 0}}' NOT NULL;
+ALTER TABLE work_item_link_categories ALTER space_id DROP DEFAULT;
+ALTER TABLE work_item_link_categories ADD FOREIGN KEY (space_id) REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX work_item_link_categories_space_id_idx ON work_item_link_categories USING btree (space_id);
+
+-- Category names only need to be unique within a space
+DROP INDEX work_item_link_categories_name_idx;
+CREATE UNIQUE INDEX work_item_link_categories_name_idx ON work_item_link_categories (name, space_id) WHERE deleted_at IS NULL;
+
+-- Optionally restrict the types of the work items that can be the source and
+-- the target of a link of a given type
+ALTER TABLE work_item_link_types ADD source_type_id uuid REFERENCES work_item_types(id) ON DELETE CASCADE;
+ALTER TABLE work_item_link_types ADD target_type_id uuid REFERENCES work_item_types(id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- The links of a blocking dependency link type prevent their target from
+-- being resolved or closed as long as their source is open
+ALTER TABLE work_item_link_types ADD blocking boolean DEFAULT false NOT NULL;
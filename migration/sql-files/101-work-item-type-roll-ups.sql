@@ -0,0 +1,11 @@
+-- optional roll-ups of a work item type, which compute values from the fields
+-- and states of the children of its work items
+ALTER TABLE work_item_types ADD COLUMN roll_ups jsonb;
+
+-- the cached roll-up values of the work items, the values are NULL when they
+-- are stale and the generation is incremented whenever they are invalidated
+CREATE TABLE work_item_roll_up_values (
+    work_item_id uuid PRIMARY KEY REFERENCES work_items (id) ON DELETE CASCADE,
+    generation integer NOT NULL DEFAULT 0,
+    roll_ups jsonb
+);
@@ -0,0 +1,303 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// OutboxChannel is a Channel that stores messages in the notification outbox
+// from where they are delivered by a Dispatcher. It replaces the fire and
+// forget Service channel.
+type OutboxChannel struct {
+	db *gorm.DB
+}
+
+// NewOutboxChannel creates a channel that writes into the outbox
+func NewOutboxChannel(db *gorm.DB) *OutboxChannel {
+	return &OutboxChannel{db: db}
+}
+
+// Send stores the message in the outbox in a transaction of its own. Callers
+// that announce a change made in a transaction should use Enqueue instead, so
+// that the message is only stored if the change is committed.
+func (c *OutboxChannel) Send(ctx context.Context, msg Message) {
+	entry, err := newOutboxEntry(ctx, msg)
+	if err == nil {
+		err = outbox.NewRepository(c.db).Create(ctx, entry)
+	}
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"message_id": msg.MessageID,
+			"err":        err,
+		}, "unable to store notification in outbox")
+	}
+}
+
+// Enqueue hands the message over to the given channel. If the channel is
+// backed by the outbox the message is stored with the given repository, which
+// is expected to be bound to the transaction of the change being announced.
+// Other channels receive the message right away.
+func Enqueue(ctx context.Context, channel Channel, repo outbox.Repository, msg Message) error {
+	if _, ok := channel.(*OutboxChannel); ok {
+		entry, err := newOutboxEntry(ctx, msg)
+		if err != nil {
+			return err
+		}
+		return repo.Create(ctx, entry)
+	}
+	channel.Send(ctx, msg)
+	return nil
+}
+
+// outboxPayload holds the optional parts of a message stored in the outbox
+type outboxPayload struct {
+	SpaceID *uuid.UUID             `json:"space_id,omitempty"`
+	Version *int                   `json:"version,omitempty"`
+	Changes []workitem.FieldChange `json:"changes,omitempty"`
+	Details map[string]interface{} `json:"details,omitempty"`
+	// Watchers are the identities watching the work item of the message
+	Watchers []uuid.UUID `json:"watchers,omitempty"`
+}
+
+// newOutboxEntry converts the message into an outbox entry. The identity of
+// the current request is stored along with the message. The token of the
+// request is not stored, messages are delivered with the token of the service
+// account.
+func newOutboxEntry(ctx context.Context, msg Message) (*outbox.Entry, error) {
+	setCurrentIdentity(ctx, &msg)
+	entry := outbox.Entry{
+		MessageID:   msg.MessageID,
+		MessageType: msg.MessageType,
+		TargetID:    msg.TargetID,
+		UserID:      msg.UserID,
+		SpaceID:     msg.SpaceID,
+	}
+	if msg.SpaceID != nil || msg.Version != nil || len(msg.Changes) > 0 || len(msg.Details) > 0 || len(msg.Watchers) > 0 {
+		payload, err := json.Marshal(outboxPayload{
+			SpaceID:  msg.SpaceID,
+			Version:  msg.Version,
+			Changes:  msg.Changes,
+			Details:  msg.Details,
+			Watchers: msg.Watchers,
+		})
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to encode the payload of notification %s", msg.MessageID)
+		}
+		p := string(payload)
+		entry.Payload = &p
+	}
+	return &entry, nil
+}
+
+// outboxMessage converts the outbox entry back into a message
+func outboxMessage(entry outbox.Entry) (Message, error) {
+	msg := Message{
+		MessageID:   entry.MessageID,
+		MessageType: entry.MessageType,
+		TargetID:    entry.TargetID,
+		UserID:      entry.UserID,
+		SpaceID:     entry.SpaceID,
+	}
+	if entry.Payload != nil {
+		var payload outboxPayload
+		if err := json.Unmarshal([]byte(*entry.Payload), &payload); err != nil {
+			return msg, errs.Wrapf(err, "failed to decode the payload of notification %s", entry.MessageID)
+		}
+		if payload.SpaceID != nil {
+			msg.SpaceID = payload.SpaceID
+		}
+		msg.Version = payload.Version
+		msg.Changes = payload.Changes
+		msg.Details = payload.Details
+		msg.Watchers = payload.Watchers
+	}
+	return msg, nil
+}
+
+// DispatcherConfiguration holds the options that control the delivery of the
+// messages stored in the outbox
+type DispatcherConfiguration interface {
+	GetNotificationDispatchInterval() time.Duration
+	GetNotificationDispatchBatchSize() int
+	GetNotificationMaxDeliveryAttempts() int
+	GetNotificationRetryBackoff() time.Duration
+	GetNotificationMaxRetryBackoff() time.Duration
+	GetNotificationDeliveryTimeout() time.Duration
+	GetNotificationOutboxRetention() time.Duration
+}
+
+// purgeInterval is the interval in which the expired outbox entries are purged
+const purgeInterval = time.Hour
+
+// Dispatcher periodically delivers the due messages of the outbox. Failed
+// deliveries are retried with an exponential backoff until the maximum
+// number of attempts is reached, after which the message is dead lettered.
+// Several dispatchers can run concurrently against the same database.
+type Dispatcher struct {
+	db     *gorm.DB
+	sender Sender
+	config DispatcherConfiguration
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher that delivers outbox messages with the
+// given sender
+func NewDispatcher(db *gorm.DB, sender Sender, config DispatcherConfiguration) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		sender: sender,
+		config: config,
+	}
+}
+
+// Start runs the dispatcher in the background until Stop is called
+func (d *Dispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.config.GetNotificationDispatchInterval())
+		defer ticker.Stop()
+		purgeTicker := time.NewTicker(purgeInterval)
+		defer purgeTicker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				if _, err := d.DispatchDue(context.Background()); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "failed to dispatch notifications")
+				}
+			case <-purgeTicker.C:
+				if _, err := d.PurgeExpired(context.Background()); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "failed to purge the notification outbox")
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the background dispatching and waits for a running batch to
+// complete
+func (d *Dispatcher) Stop() {
+	if d.stop == nil {
+		return
+	}
+	close(d.stop)
+	d.wg.Wait()
+	d.stop = nil
+}
+
+// deliveryResult is the outcome of the delivery of an outbox entry
+type deliveryResult struct {
+	entry outbox.Entry
+	err   error
+}
+
+// DispatchDue delivers one batch of due messages and returns the number of
+// successfully delivered ones. The batch is claimed in a short transaction,
+// the messages are delivered outside of any transaction and the outcome is
+// recorded in a second transaction, so that no database connection and no row
+// lock is held while waiting for the notification service.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	batchSize := d.config.GetNotificationDispatchBatchSize()
+	// the lease must outlast the delivery of the whole batch
+	leaseUntil := now.Add(time.Duration(batchSize+1) * d.config.GetNotificationDeliveryTimeout())
+	var entries []outbox.Entry
+	err := models.Transactional(d.db, func(tx *gorm.DB) error {
+		var err error
+		entries, err = outbox.NewRepository(tx).ClaimDue(ctx, now, batchSize, leaseUntil)
+		return err
+	})
+	if err != nil {
+		return 0, errs.Wrap(err, "failed to claim due notifications")
+	}
+	if len(entries) == 0 {
+		return 0, nil
+	}
+
+	results := make([]deliveryResult, len(entries))
+	for i, entry := range entries {
+		msg, deliveryErr := outboxMessage(entry)
+		if deliveryErr == nil {
+			deliveryErr = d.sender.Deliver(ctx, msg)
+		}
+		results[i] = deliveryResult{entry: entry, err: deliveryErr}
+	}
+
+	delivered := 0
+	err = models.Transactional(d.db, func(tx *gorm.DB) error {
+		repo := outbox.NewRepository(tx)
+		failedAt := time.Now()
+		for _, result := range results {
+			entry := result.entry
+			if result.err == nil {
+				if err := repo.MarkDelivered(ctx, entry.ID); err != nil {
+					return err
+				}
+				delivered++
+				continue
+			}
+			attempt := entry.Attempts + 1
+			var nextAttemptAt *time.Time
+			if attempt < d.config.GetNotificationMaxDeliveryAttempts() {
+				next := failedAt.Add(outbox.RetryBackoff(attempt, d.config.GetNotificationRetryBackoff(), d.config.GetNotificationMaxRetryBackoff()))
+				nextAttemptAt = &next
+			}
+			log.Warn(ctx, map[string]interface{}{
+				"message_id":  entry.MessageID,
+				"type":        entry.MessageType,
+				"target_id":   entry.TargetID,
+				"attempt":     attempt,
+				"dead_letter": nextAttemptAt == nil,
+				"err":         result.err,
+			}, "failed to deliver notification")
+			if err := repo.MarkFailed(ctx, entry.ID, result.err, nextAttemptAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// the claimed entries are retried once their lease expires
+		return 0, errs.Wrap(err, "failed to record the delivery of notifications")
+	}
+	return delivered, nil
+}
+
+// PurgeExpired deletes the delivered and dead lettered messages that are
+// older than the retention period of the outbox and returns their number.
+// The events of a space can't be resumed from purged messages.
+func (d *Dispatcher) PurgeExpired(ctx context.Context) (int64, error) {
+	var purged int64
+	err := models.Transactional(d.db, func(tx *gorm.DB) error {
+		var err error
+		purged, err = outbox.NewRepository(tx).Purge(ctx, time.Now().Add(-d.config.GetNotificationOutboxRetention()))
+		return err
+	})
+	if err != nil {
+		return 0, errs.Wrap(err, "failed to purge expired notifications")
+	}
+	if purged > 0 {
+		log.Info(ctx, map[string]interface{}{
+			"purged": purged,
+		}, "purged expired notifications from the outbox")
+	}
+	return purged, nil
+}
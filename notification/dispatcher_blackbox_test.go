@@ -0,0 +1,193 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeSender struct {
+	fail      bool
+	delivered []notification.Message
+}
+
+func (s *fakeSender) Deliver(ctx context.Context, msg notification.Message) error {
+	if s.fail {
+		return errs.New("notification service unavailable")
+	}
+	s.delivered = append(s.delivered, msg)
+	return nil
+}
+
+type fakeDispatcherConfig struct {
+	maxAttempts int
+}
+
+func (c fakeDispatcherConfig) GetNotificationDispatchInterval() time.Duration { return time.Second }
+func (c fakeDispatcherConfig) GetNotificationDispatchBatchSize() int          { return 100 }
+func (c fakeDispatcherConfig) GetNotificationMaxDeliveryAttempts() int        { return c.maxAttempts }
+func (c fakeDispatcherConfig) GetNotificationRetryBackoff() time.Duration     { return 0 }
+func (c fakeDispatcherConfig) GetNotificationMaxRetryBackoff() time.Duration  { return 0 }
+func (c fakeDispatcherConfig) GetNotificationDeliveryTimeout() time.Duration  { return time.Second }
+func (c fakeDispatcherConfig) GetNotificationOutboxRetention() time.Duration  { return time.Hour }
+
+type TestDispatcher struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunDispatcher(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestDispatcher{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestDispatcher) enqueue() notification.Message {
+	wi := workitem.WorkItem{ID: uuid.NewV4(), SpaceID: uuid.NewV4(), Version: 3}
+	msg := notification.NewWorkItemUpdated(wi, []workitem.FieldChange{
+		{Name: workitem.SystemTitle, Old: "foo", New: "bar"},
+	})
+	msg.Watchers = []uuid.UUID{uuid.NewV4()}
+	notification.NewOutboxChannel(s.DB).Send(context.Background(), msg)
+	return msg
+}
+
+func (s *TestDispatcher) entry(msg notification.Message) outbox.Entry {
+	var entry outbox.Entry
+	require.NoError(s.T(), s.DB.Where("message_id = ?", msg.MessageID).First(&entry).Error)
+	return entry
+}
+
+func (s *TestDispatcher) TestDeliverPending() {
+	// given
+	msg := s.enqueue()
+	sender := &fakeSender{}
+	// when
+	delivered, err := notification.NewDispatcher(s.DB, sender, fakeDispatcherConfig{maxAttempts: 3}).DispatchDue(context.Background())
+	// then
+	require.NoError(s.T(), err)
+	assert.True(s.T(), delivered >= 1)
+	entry := s.entry(msg)
+	assert.Equal(s.T(), outbox.StatusDelivered, entry.Status)
+	assert.NotNil(s.T(), entry.DeliveredAt)
+	require.NotEmpty(s.T(), sender.delivered)
+	for _, m := range sender.delivered {
+		if m.MessageID == msg.MessageID {
+			assert.Equal(s.T(), msg.SpaceID, m.SpaceID)
+			assert.Equal(s.T(), msg.Version, m.Version)
+			assert.Equal(s.T(), msg.Changes, m.Changes)
+			assert.Equal(s.T(), msg.Watchers, m.Watchers)
+		}
+	}
+}
+
+func (s *TestDispatcher) TestRetryAndDeadLetter() {
+	// given
+	msg := s.enqueue()
+	sender := &fakeSender{fail: true}
+	dispatcher := notification.NewDispatcher(s.DB, sender, fakeDispatcherConfig{maxAttempts: 2})
+	// when the first attempt fails the message stays pending
+	_, err := dispatcher.DispatchDue(context.Background())
+	require.NoError(s.T(), err)
+	entry := s.entry(msg)
+	assert.Equal(s.T(), outbox.StatusPending, entry.Status)
+	assert.Equal(s.T(), 1, entry.Attempts)
+	require.NotNil(s.T(), entry.LastError)
+	// when the second attempt fails the message is dead lettered
+	_, err = dispatcher.DispatchDue(context.Background())
+	require.NoError(s.T(), err)
+	entry = s.entry(msg)
+	assert.Equal(s.T(), outbox.StatusDead, entry.Status)
+	assert.Equal(s.T(), 2, entry.Attempts)
+	// and it is no longer picked up
+	sender.fail = false
+	_, err = dispatcher.DispatchDue(context.Background())
+	require.NoError(s.T(), err)
+	for _, m := range sender.delivered {
+		assert.NotEqual(s.T(), msg.MessageID, m.MessageID)
+	}
+	stats, err := outbox.NewRepository(s.DB).Statistics(context.Background())
+	require.NoError(s.T(), err)
+	assert.True(s.T(), stats.Dead >= 1)
+}
+
+func (s *TestDispatcher) TestClaimDueLeasesEntries() {
+	// given
+	msg := s.enqueue()
+	repo := outbox.NewRepository(s.DB)
+	now := time.Now()
+	// when
+	claimed, err := repo.ClaimDue(context.Background(), now, 100, now.Add(time.Minute))
+	// then the claimed entry is leased
+	require.NoError(s.T(), err)
+	found := false
+	for _, entry := range claimed {
+		found = found || entry.MessageID == msg.MessageID
+	}
+	assert.True(s.T(), found)
+	assert.True(s.T(), s.entry(msg).NextAttemptAt.After(now))
+	// and it is not claimed again before the lease expires
+	claimed, err = repo.ClaimDue(context.Background(), now, 100, now.Add(time.Minute))
+	require.NoError(s.T(), err)
+	for _, entry := range claimed {
+		assert.NotEqual(s.T(), msg.MessageID, entry.MessageID)
+	}
+	// but once the lease has expired
+	claimed, err = repo.ClaimDue(context.Background(), now.Add(2*time.Minute), 100, now.Add(3*time.Minute))
+	require.NoError(s.T(), err)
+	found = false
+	for _, entry := range claimed {
+		found = found || entry.MessageID == msg.MessageID
+	}
+	assert.True(s.T(), found)
+}
+
+func (s *TestDispatcher) TestPurgeExpired() {
+	// given a delivered, a dead lettered and a pending message that are
+	// older than the retention period and a recently delivered one
+	delivered := s.enqueue()
+	dead := s.enqueue()
+	pending := s.enqueue()
+	recent := s.enqueue()
+	repo := outbox.NewRepository(s.DB)
+	require.NoError(s.T(), repo.MarkDelivered(context.Background(), s.entry(delivered).ID))
+	require.NoError(s.T(), repo.MarkFailed(context.Background(), s.entry(dead).ID, errs.New("failed"), nil))
+	require.NoError(s.T(), repo.MarkDelivered(context.Background(), s.entry(recent).ID))
+	expired := time.Now().Add(-2 * time.Hour)
+	require.NoError(s.T(), s.DB.Model(&outbox.Entry{}).
+		Where("message_id IN (?)", []uuid.UUID{delivered.MessageID, dead.MessageID, pending.MessageID}).
+		UpdateColumn("updated_at", expired).Error)
+	// when
+	purged, err := notification.NewDispatcher(s.DB, &fakeSender{}, fakeDispatcherConfig{maxAttempts: 3}).PurgeExpired(context.Background())
+	// then
+	require.NoError(s.T(), err)
+	assert.True(s.T(), purged >= 2)
+	count := func(msg notification.Message) int {
+		var c int
+		require.NoError(s.T(), s.DB.Model(&outbox.Entry{}).Where("message_id = ?", msg.MessageID).Count(&c).Error)
+		return c
+	}
+	assert.Equal(s.T(), 0, count(delivered))
+	assert.Equal(s.T(), 0, count(dead))
+	assert.Equal(s.T(), 1, count(pending))
+	assert.Equal(s.T(), 1, count(recent))
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	assert.Equal(t, 10*time.Second, outbox.RetryBackoff(1, 10*time.Second, time.Minute))
+	assert.Equal(t, 20*time.Second, outbox.RetryBackoff(2, 10*time.Second, time.Minute))
+	assert.Equal(t, 40*time.Second, outbox.RetryBackoff(3, 10*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, outbox.RetryBackoff(4, 10*time.Second, time.Minute))
+	assert.Equal(t, time.Minute, outbox.RetryBackoff(100, 10*time.Second, time.Minute))
+}
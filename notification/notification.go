@@ -4,16 +4,20 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"time"
 
 	"fmt"
 
 	"github.com/fabric8-services/fabric8-wit/goasupport"
-	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/iteration"
 	"github.com/fabric8-services/fabric8-wit/login"
 	"github.com/fabric8-services/fabric8-wit/notification/client"
 	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	goaclient "github.com/goadesign/goa/client"
 	goauuid "github.com/goadesign/goa/uuid"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -29,35 +33,171 @@ type Message struct {
 	UserID      *string
 	TargetID    string
 	MessageType string
+	SpaceID     *uuid.UUID             // the space of the target, if known
+	Version     *int                   // the version of the target after the change, if any
+	Changes     []workitem.FieldChange // the field level changes of the target, if any
+	Details     map[string]interface{} // additional information specific to the MessageType
+	Watchers    []uuid.UUID            // the identities watching the work item the message is about, if any
 }
 
 func (m Message) String() string {
 	return fmt.Sprintf("id:%v type:%v by:%v for:%v", m.MessageID, m.MessageType, m.UserID, m.TargetID)
 }
 
-// NewWorkItemCreated creates a new message instance for the newly created WorkItemID
-func NewWorkItemCreated(workitemID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "workitem.create", TargetID: workitemID}
+// NewWorkItemCreated creates a new message instance for the newly created work item
+func NewWorkItemCreated(wi workitem.WorkItem) Message {
+	return newWorkItemMessage("workitem.create", wi)
 }
 
-// NewWorkItemUpdated creates a new message instance for the updated WorkItemID
-func NewWorkItemUpdated(workitemID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "workitem.update", TargetID: workitemID}
+// NewWorkItemUpdated creates a new message instance for the updated work item
+// holding the field changes of the update
+func NewWorkItemUpdated(wi workitem.WorkItem, changes []workitem.FieldChange) Message {
+	msg := newWorkItemMessage("workitem.update", wi)
+	msg.Changes = changes
+	return msg
+}
+
+// NewWorkItemLabelsChanged creates a new message instance for a work item
+// whose labels were added or removed
+func NewWorkItemLabelsChanged(wi workitem.WorkItem, added, removed []string) Message {
+	msg := newWorkItemMessage("workitem.labels", wi)
+	msg.Details = map[string]interface{}{
+		"added":   added,
+		"removed": removed,
+	}
+	return msg
+}
+
+// NewWorkItemDueSoon creates a new message instance reminding of the due
+// date of the given work item, which is at most the given lead time away
+func NewWorkItemDueSoon(wi workitem.WorkItem, dueDate time.Time, leadTime time.Duration) Message {
+	msg := newWorkItemMessage("workitem.due_soon", wi)
+	msg.Details = map[string]interface{}{
+		"due_date":  dueDate.UTC().Format(time.RFC3339),
+		"lead_time": leadTime.String(),
+	}
+	return msg
+}
+
+// NewWorkItemOverdue creates a new message instance for the given work item
+// whose due date has passed
+func NewWorkItemOverdue(wi workitem.WorkItem, dueDate time.Time) Message {
+	msg := newWorkItemMessage("workitem.overdue", wi)
+	msg.Details = map[string]interface{}{
+		"due_date": dueDate.UTC().Format(time.RFC3339),
+	}
+	return msg
+}
+
+func newWorkItemMessage(msgType string, wi workitem.WorkItem) Message {
+	spaceID := wi.SpaceID
+	version := wi.Version
+	return Message{MessageID: uuid.NewV4(), MessageType: msgType, TargetID: wi.ID.String(), SpaceID: &spaceID, Version: &version}
+}
+
+// NewLinkCreated creates a new message instance for the newly created link
+// in the given space
+func NewLinkCreated(l link.WorkItemLink, spaceID uuid.UUID) Message {
+	return newLinkMessage("link.create", l, spaceID)
+}
+
+// NewLinkDeleted creates a new message instance for the deleted link in the
+// given space
+func NewLinkDeleted(l link.WorkItemLink, spaceID uuid.UUID) Message {
+	return newLinkMessage("link.delete", l, spaceID)
+}
+
+func newLinkMessage(msgType string, l link.WorkItemLink, spaceID uuid.UUID) Message {
+	version := l.Version
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: msgType,
+		TargetID:    l.ID.String(),
+		SpaceID:     &spaceID,
+		Version:     &version,
+		Details: map[string]interface{}{
+			"source_id":    l.SourceID.String(),
+			"target_id":    l.TargetID.String(),
+			"link_type_id": l.LinkTypeID.String(),
+		},
+	}
+}
+
+// NewIterationStateChanged creates a new message instance for an iteration
+// whose state changed from the given old state
+func NewIterationStateChanged(itr iteration.Iteration, oldState iteration.State) Message {
+	spaceID := itr.SpaceID
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: "iteration.state",
+		TargetID:    itr.ID.String(),
+		SpaceID:     &spaceID,
+		Changes: []workitem.FieldChange{
+			{Name: "state", Old: oldState.String(), New: itr.State.String()},
+		},
+	}
 }
 
 // NewCommentCreated creates a new message instance for the newly created CommentID
-func NewCommentCreated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.create", TargetID: commentID}
+// on a work item of the given space
+func NewCommentCreated(commentID string, spaceID uuid.UUID) Message {
+	return Message{MessageID: uuid.NewV4(), MessageType: "comment.create", TargetID: commentID, SpaceID: &spaceID}
 }
 
 // NewCommentUpdated creates a new message instance for the updated CommentID
-func NewCommentUpdated(commentID string) Message {
-	return Message{MessageID: uuid.NewV4(), MessageType: "comment.update", TargetID: commentID}
+// on a work item of the given space
+func NewCommentUpdated(commentID string, spaceID uuid.UUID) Message {
+	return Message{MessageID: uuid.NewV4(), MessageType: "comment.update", TargetID: commentID, SpaceID: &spaceID}
+}
+
+// NewMention creates a new message instance for the user with the given
+// identity ID who was newly mentioned in the comment or work item with the
+// given type and ID
+func NewMention(targetType, targetID string, spaceID, mentionedID uuid.UUID) Message {
+	return Message{
+		MessageID:   uuid.NewV4(),
+		MessageType: "mention",
+		TargetID:    targetID,
+		SpaceID:     &spaceID,
+		Details: map[string]interface{}{
+			"target_type":  targetType,
+			"mentioned_id": mentionedID.String(),
+		},
+	}
+}
+
+// custom returns the information of the message beyond its type and target
+// that is sent to the notification service
+func (m Message) custom() map[string]interface{} {
+	custom := map[string]interface{}{}
+	for k, v := range m.Details {
+		custom[k] = v
+	}
+	if m.UserID != nil {
+		custom["actor_id"] = *m.UserID
+	}
+	if m.SpaceID != nil {
+		custom["space_id"] = m.SpaceID.String()
+	}
+	if m.Version != nil {
+		custom["version"] = *m.Version
+	}
+	if len(m.Changes) > 0 {
+		custom["changes"] = m.Changes
+	}
+	if len(m.Watchers) > 0 {
+		watchers := make([]string, len(m.Watchers))
+		for i, id := range m.Watchers {
+			watchers[i] = id.String()
+		}
+		custom["watchers"] = watchers
+	}
+	return custom
 }
 
 func setCurrentIdentity(ctx context.Context, msg *Message) {
 	currentUserIdentityID, err := login.ContextIdentity(ctx)
-	if err != nil {
+	if err == nil {
 		uID := currentUserIdentityID.String()
 		msg.UserID = &uID
 	}
@@ -69,12 +209,26 @@ type DevNullChannel struct{}
 // Send NO-OP
 func (d *DevNullChannel) Send(context.Context, Message) {}
 
+// DevNullSender is used when no notification service is configured. It
+// accepts every message without sending it anywhere.
+type DevNullSender struct{}
+
+// Deliver NO-OP
+func (d *DevNullSender) Deliver(context.Context, Message) error { return nil }
+
 // ServiceConfiguration holds configuration options required to interact with the fabric8-notification API
 type ServiceConfiguration interface {
 	GetNotificationServiceURL() string
+	GetNotificationServiceToken() string
+	GetNotificationDeliveryTimeout() time.Duration
+}
+
+// Sender delivers a single message to the fabric8-notification service
+type Sender interface {
+	Deliver(ctx context.Context, msg Message) error
 }
 
-// Service is a simple client Channel to the fabric8-notification service
+// Service is a simple client Sender to the fabric8-notification service
 type Service struct {
 	config ServiceConfiguration
 }
@@ -87,8 +241,8 @@ func validateConfig(config ServiceConfiguration) error {
 	return nil
 }
 
-// NewServiceChannel sends notification messages to the fabric8-notification service
-func NewServiceChannel(config ServiceConfiguration) (Channel, error) {
+// NewServiceSender delivers notification messages to the fabric8-notification service
+func NewServiceSender(config ServiceConfiguration) (Sender, error) {
 	err := validateConfig(config)
 	if err != nil {
 		return nil, err
@@ -96,57 +250,44 @@ func NewServiceChannel(config ServiceConfiguration) (Channel, error) {
 	return &Service{config: config}, nil
 }
 
-// Send invokes the fabric8-notification API
-func (s *Service) Send(ctx context.Context, msg Message) {
-	go func(ctx context.Context, msg Message) {
-		setCurrentIdentity(ctx, &msg)
+// Deliver synchronously invokes the fabric8-notification API with the token of
+// the service account (if configured)
+func (s *Service) Deliver(ctx context.Context, msg Message) error {
+	u, err := url.Parse(s.config.GetNotificationServiceURL())
+	if err != nil {
+		return errs.Wrapf(err, "unable to parse notification service url %s", s.config.GetNotificationServiceURL())
+	}
+
+	cl := client.New(goaclient.HTTPClientDoer(&http.Client{Timeout: s.config.GetNotificationDeliveryTimeout()}))
+	cl.Host = u.Host
+	cl.Scheme = u.Scheme
+	if token := s.config.GetNotificationServiceToken(); token != "" {
+		cl.SetJWTSigner(goasupport.NewTokenSigner(token))
+	}
 
-		u, err := url.Parse(s.config.GetNotificationServiceURL())
-		if err != nil {
-			log.Error(ctx, map[string]interface{}{
-				"url": s.config.GetNotificationServiceURL(),
-				"err": err,
-			}, "unable to parse GetNotificationServiceURL")
-		}
+	msgID := goauuid.UUID(msg.MessageID)
 
-		cl := client.New(goaclient.HTTPClientDoer(http.DefaultClient))
-		cl.Host = u.Host
-		cl.Scheme = u.Scheme
-		cl.SetJWTSigner(goasupport.NewForwardSigner(ctx))
-
-		msgID := goauuid.UUID(msg.MessageID)
-
-		resp, err := cl.SendNotify(
-			goasupport.ForwardContextRequestID(ctx),
-			client.SendNotifyPath(),
-			&client.SendNotifyPayload{
-				Data: &client.Notification{
-					Type: "notifications",
-					ID:   &msgID,
-					Attributes: &client.NotificationAttributes{
-						Type: msg.MessageType,
-						ID:   msg.TargetID,
-					},
+	resp, err := cl.SendNotify(
+		goasupport.ForwardContextRequestID(ctx),
+		client.SendNotifyPath(),
+		&client.SendNotifyPayload{
+			Data: &client.Notification{
+				Type: "notifications",
+				ID:   &msgID,
+				Attributes: &client.NotificationAttributes{
+					Type:   msg.MessageType,
+					ID:     msg.TargetID,
+					Custom: msg.custom(),
 				},
 			},
-		)
-		if err != nil {
-			log.Error(ctx, map[string]interface{}{
-				"message_id": msg.MessageID,
-				"type":       msg.MessageType,
-				"target_id":  msg.TargetID,
-				"err":        err,
-			}, "unable to send notification")
-		} else if resp.StatusCode >= 400 {
-			log.Error(ctx, map[string]interface{}{
-				"status":     resp.StatusCode,
-				"message_id": msg.MessageID,
-				"type":       msg.MessageType,
-				"target_id":  msg.TargetID,
-				"err":        err,
-			}, "unexpected response code")
-		}
-		defer rest.CloseResponse(resp)
-
-	}(ctx, msg)
+		},
+	)
+	if err != nil {
+		return errs.Wrapf(err, "unable to send notification %s", msg.MessageID)
+	}
+	defer rest.CloseResponse(resp)
+	if resp.StatusCode >= 400 {
+		return errs.Errorf("unexpected response code %d when sending notification %s", resp.StatusCode, msg.MessageID)
+	}
+	return nil
 }
@@ -0,0 +1,329 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Status describes the delivery state of an entry in the outbox
+type Status string
+
+const (
+	// StatusPending marks entries that still have to be delivered
+	StatusPending Status = "pending"
+	// StatusDelivered marks entries that were accepted by the notification service
+	StatusDelivered Status = "delivered"
+	// StatusDead marks entries that exceeded the maximum number of delivery attempts
+	StatusDead Status = "dead"
+)
+
+// Entry is a notification message persisted for later delivery
+type Entry struct {
+	gormsupport.Lifecycle
+	ID            uuid.UUID  `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	Sequence      int64      // increases in the order entries are committed, 0 until AssignSequences numbered the entry
+	MessageID     uuid.UUID  `sql:"type:uuid"`
+	SpaceID       *uuid.UUID `sql:"type:uuid"`
+	MessageType   string
+	TargetID      string
+	UserID        *string
+	Payload       *string `sql:"type:jsonb"` // JSON encoded optional parts of the message
+	Status        Status
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	DeliveredAt   *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Entry) TableName() string {
+	return "notification_outbox"
+}
+
+// Statistics summarizes the content of the outbox
+type Statistics struct {
+	Pending       int
+	Delivered     int
+	Dead          int
+	OldestPending *time.Time
+}
+
+// Repository describes interactions with the notification outbox
+type Repository interface {
+	Create(ctx context.Context, entry *Entry) error
+	ClaimDue(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]Entry, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, cause error, nextAttemptAt *time.Time) error
+	ListDead(ctx context.Context, start int, limit int) ([]Entry, int, error)
+	AssignSequences(ctx context.Context) (int64, error)
+	ListForSpace(ctx context.Context, spaceID uuid.UUID, after int64, limit int) ([]Entry, error)
+	LastSequence(ctx context.Context, spaceID uuid.UUID) (int64, error)
+	Statistics(ctx context.Context) (*Statistics, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for the outbox.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// Create stores the given entry as pending. When used with a transaction the
+// entry only becomes visible to the dispatcher once the transaction commits.
+func (r *GormRepository) Create(ctx context.Context, entry *Entry) error {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "create"}, time.Now())
+	entry.ID = uuid.NewV4()
+	entry.Status = StatusPending
+	entry.Attempts = 0
+	if entry.NextAttemptAt.IsZero() {
+		entry.NextAttemptAt = time.Now()
+	}
+	if err := r.db.Create(entry).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"message_id": entry.MessageID,
+			"type":       entry.MessageType,
+			"target_id":  entry.TargetID,
+			"err":        err,
+		}, "unable to store notification in outbox")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit pending entries whose next delivery attempt is
+// due and leases them by moving their next attempt to leaseUntil. Rows locked
+// by other dispatchers are skipped. Once the surrounding transaction commits,
+// the claimed entries are not returned again before the lease expires, so they
+// can be delivered outside of a transaction; entries that are neither marked
+// as delivered nor as failed until then are picked up again.
+func (r *GormRepository) ClaimDue(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]Entry, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "claimdue"}, time.Now())
+	var entries []Entry
+	err := r.db.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	ids := make([]uuid.UUID, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	err = r.db.Model(&Entry{}).Where("id IN (?)", ids).UpdateColumn("next_attempt_at", leaseUntil).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to lease due notifications")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return entries, nil
+}
+
+// MarkDelivered records the successful delivery of the entry
+func (r *GormRepository) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "delivered"}, time.Now())
+	now := time.Now()
+	tx := r.db.Model(&Entry{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       StatusDelivered,
+		"attempts":     gorm.Expr("attempts + 1"),
+		"delivered_at": now,
+		"last_error":   nil,
+	})
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("notification outbox entry", id.String())
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt of the entry. When
+// nextAttemptAt is nil the entry is moved to the dead letter state and won't
+// be retried.
+func (r *GormRepository) MarkFailed(ctx context.Context, id uuid.UUID, cause error, nextAttemptAt *time.Time) error {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "failed"}, time.Now())
+	updates := map[string]interface{}{
+		"attempts":   gorm.Expr("attempts + 1"),
+		"last_error": cause.Error(),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = StatusDead
+	}
+	tx := r.db.Model(&Entry{}).Where("id = ?", id).Updates(updates)
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("notification outbox entry", id.String())
+	}
+	return nil
+}
+
+// ListDead returns the dead lettered entries, most recent first, together
+// with their total count
+func (r *GormRepository) ListDead(ctx context.Context, start int, limit int) ([]Entry, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "listdead"}, time.Now())
+	var count int
+	db := r.db.Model(&Entry{}).Where("status = ?", StatusDead)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, err)
+	}
+	var entries []Entry
+	err := db.Order("updated_at desc").Offset(start).Limit(limit).Find(&entries).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, 0, errors.NewInternalError(ctx, err)
+	}
+	return entries, count, nil
+}
+
+// sequenceLockID is the key of the advisory lock that serializes the
+// numbering of the outbox entries
+const sequenceLockID = 4711
+
+// AssignSequences numbers the entries of spaces that have no sequence number
+// yet in the order they were created and returns the number of entries it
+// numbered. Entries only become visible here once their transaction committed,
+// so an entry of a long running transaction can never get a lower sequence
+// number than an entry that a stream has already passed. The numbering is
+// serialized with an advisory lock; when another transaction holds it, nothing
+// is numbered and the entries are left to that transaction.
+func (r *GormRepository) AssignSequences(ctx context.Context) (int64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "assignsequences"}, time.Now())
+	var locked bool
+	if err := r.db.Raw("SELECT pg_try_advisory_xact_lock(?)", sequenceLockID).Row().Scan(&locked); err != nil {
+		return 0, errors.NewInternalError(ctx, err)
+	}
+	if !locked {
+		return 0, nil
+	}
+	tx := r.db.Exec(`UPDATE notification_outbox o SET sequence = s.sequence
+		FROM (
+			SELECT id, nextval('notification_outbox_sequence_seq') AS sequence
+			FROM (
+				SELECT id FROM notification_outbox
+				WHERE sequence = 0 AND space_id IS NOT NULL
+				ORDER BY created_at, id
+			) AS unsequenced
+		) AS s
+		WHERE o.id = s.id`)
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": tx.Error,
+		}, "unable to number the entries of the notification outbox")
+		return 0, errors.NewInternalError(ctx, tx.Error)
+	}
+	return tx.RowsAffected, nil
+}
+
+// ListForSpace returns up to limit entries of the given space whose sequence
+// number is greater than after, in the order they were numbered.
+func (r *GormRepository) ListForSpace(ctx context.Context, spaceID uuid.UUID, after int64, limit int) ([]Entry, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "listforspace"}, time.Now())
+	if after < 0 {
+		after = 0 // entries that are not numbered yet have a sequence of 0
+	}
+	var entries []Entry
+	err := r.db.Where("space_id = ? AND sequence > ?", spaceID, after).
+		Order("sequence").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return entries, nil
+}
+
+// LastSequence returns the highest sequence number of the entries of the
+// given space or 0 if the space has no entries
+func (r *GormRepository) LastSequence(ctx context.Context, spaceID uuid.UUID) (int64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "lastsequence"}, time.Now())
+	var last int64
+	row := r.db.Model(&Entry{}).Where("space_id = ?", spaceID).Select("coalesce(max(sequence), 0)").Row()
+	if err := row.Scan(&last); err != nil {
+		return 0, errors.NewInternalError(ctx, err)
+	}
+	return last, nil
+}
+
+// Statistics returns the number of entries per status and the creation time
+// of the oldest pending entry
+func (r *GormRepository) Statistics(ctx context.Context) (*Statistics, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "statistics"}, time.Now())
+	rows, err := r.db.Model(&Entry{}).Select("status, count(*), min(created_at)").Group("status").Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	defer rows.Close()
+	stats := Statistics{}
+	for rows.Next() {
+		var status Status
+		var count int
+		var oldest *time.Time
+		if err := rows.Scan(&status, &count, &oldest); err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		switch status {
+		case StatusPending:
+			stats.Pending = count
+			stats.OldestPending = oldest
+		case StatusDelivered:
+			stats.Delivered = count
+		case StatusDead:
+			stats.Dead = count
+		}
+	}
+	return &stats, nil
+}
+
+// Purge deletes the delivered and the dead lettered entries that were last
+// updated before the given time and returns the number of deleted entries.
+// Pending entries are never purged.
+func (r *GormRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "notification_outbox", "purge"}, time.Now())
+	tx := r.db.Unscoped().Where("status IN (?) AND updated_at < ?", []Status{StatusDelivered, StatusDead}, before).Delete(&Entry{})
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"before": before,
+			"err":    tx.Error,
+		}, "unable to purge the notification outbox")
+		return 0, errors.NewInternalError(ctx, tx.Error)
+	}
+	return tx.RowsAffected, nil
+}
+
+// RetryBackoff returns the delay before the next delivery attempt after the
+// given number of failed attempts. The delay doubles with every attempt,
+// starting with initial and never exceeding max.
+func RetryBackoff(attempt int, initial, max time.Duration) time.Duration {
+	backoff := initial
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= max || backoff <= 0 {
+			return max
+		}
+	}
+	if backoff > max {
+		return max
+	}
+	return backoff
+}
@@ -0,0 +1,186 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// the kinds of reminders as stored in the work_item_reminders table
+const (
+	reminderKindDueSoon = "due_soon"
+	reminderKindOverdue = "overdue"
+)
+
+// ReminderConfiguration holds the options that control the reminders about
+// the due dates of work items
+type ReminderConfiguration interface {
+	GetReminderInterval() time.Duration
+	GetReminderLeadTimes() []time.Duration
+	GetReminderOverdueMaxAge() time.Duration
+	GetReminderBatchSize() int
+}
+
+// Reminder periodically sends "workitem.due_soon" notifications for the work
+// items whose due date is within one of the configured lead times and
+// "workitem.overdue" notifications for the work items whose due date has
+// passed recently. Work items that are resolved, closed or in a final state of
+// their workflow are skipped. Every reminder is recorded in the
+// database together with its notification, so that it is sent only once per
+// due date and lead time, even if several reminders run concurrently against
+// the same database or are restarted.
+type Reminder struct {
+	db      *gorm.DB
+	channel Channel
+	config  ReminderConfiguration
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// NewReminder creates a reminder that sends its notifications to the given
+// channel
+func NewReminder(db *gorm.DB, channel Channel, config ReminderConfiguration) *Reminder {
+	return &Reminder{
+		db:      db,
+		channel: channel,
+		config:  config,
+	}
+}
+
+// Start runs the reminder in the background until Stop is called
+func (r *Reminder) Start() {
+	r.stop = make(chan struct{})
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.config.GetReminderInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				if _, err := r.SendDue(context.Background(), time.Now()); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "failed to send due date reminders")
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the background reminders and waits for a running batch to
+// complete
+func (r *Reminder) Stop() {
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	r.wg.Wait()
+	r.stop = nil
+}
+
+// dueWorkItem is a work item with a due date that a reminder is due for
+type dueWorkItem struct {
+	ID uuid.UUID `gorm:"column:id"`
+	// the due date in seconds, which is unaffected by the precision lost
+	// when the nanoseconds of the stored instant are read back as a float
+	DueDate int64 `gorm:"column:due_date"`
+}
+
+// SendDue sends the reminders that are due at the given time and returns the
+// number of sent reminders. A work item that is closer to its due date than
+// several lead times only gets the reminder of the shortest one.
+func (r *Reminder) SendDue(ctx context.Context, now time.Time) (int, error) {
+	leadTimes := r.config.GetReminderLeadTimes()
+	sort.Slice(leadTimes, func(i, j int) bool { return leadTimes[i] < leadTimes[j] })
+	sent := 0
+	err := models.Transactional(r.db, func(tx *gorm.DB) error {
+		for i, leadTime := range leadTimes {
+			from := now
+			if i > 0 {
+				from = now.Add(leadTimes[i-1])
+			}
+			n, err := r.send(ctx, tx, reminderKindDueSoon, leadTime, from, now.Add(leadTime))
+			if err != nil {
+				return err
+			}
+			sent += n
+		}
+		// due dates that passed long ago, e.g. before the reminders were
+		// enabled, are not reminded of
+		n, err := r.send(ctx, tx, reminderKindOverdue, 0, now.Add(-r.config.GetReminderOverdueMaxAge()), now)
+		sent += n
+		return err
+	})
+	return sent, err
+}
+
+// send sends the reminders of the given kind and lead time for the open work
+// items that are due after the given start and no later than the given end
+func (r *Reminder) send(ctx context.Context, tx *gorm.DB, kind string, leadTime time.Duration, from, until time.Time) (int, error) {
+	dueDate := fmt.Sprintf("(wi.fields->>'%s')::numeric", workitem.SystemDueDate)
+	query := fmt.Sprintf(`SELECT wi.id, floor(%[1]s / 1000000000)::bigint AS due_date FROM %[2]s wi
+		WHERE wi.deleted_at IS NULL
+		AND %[1]s > ? AND %[1]s <= ?
+		AND NOT %[3]s
+		AND NOT %[4]s
+		AND NOT EXISTS (
+			SELECT 1 FROM work_item_reminders r
+			WHERE r.work_item_id = wi.id AND r.kind = ? AND r.due_date = floor(%[1]s / 1000000000)::bigint AND r.lead_time = ?
+		)
+		ORDER BY 2 LIMIT ?`,
+		dueDate, workitem.WorkItemStorage{}.TableName(), workitem.ClosedStateCondition("wi"), workitem.FinalStateCondition("wi"))
+	var candidates []dueWorkItem
+	err := tx.Raw(query, from.UnixNano(), until.UnixNano(), kind, int64(leadTime.Seconds()), r.config.GetReminderBatchSize()).Scan(&candidates).Error
+	if err != nil {
+		return 0, errs.Wrapf(err, "failed to load the work items that are due for %s reminders", kind)
+	}
+	sent := 0
+	for _, c := range candidates {
+		// the primary key of the reminders makes sure that only one of
+		// several concurrent reminders sends the notification
+		db := tx.Exec(`INSERT INTO work_item_reminders (work_item_id, kind, due_date, lead_time, sent_at) VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT DO NOTHING`, c.ID, kind, c.DueDate, int64(leadTime.Seconds()), time.Now())
+		if db.Error != nil {
+			return sent, errs.Wrapf(db.Error, "failed to record the %s reminder of work item %s", kind, c.ID)
+		}
+		if db.RowsAffected == 0 {
+			continue
+		}
+		wi, err := workitem.NewWorkItemRepository(tx).LoadByID(ctx, c.ID)
+		if err != nil {
+			return sent, errs.Wrapf(err, "failed to load work item %s", c.ID)
+		}
+		watchers, err := workitem.NewWatcherRepository(tx).List(ctx, c.ID)
+		if err != nil {
+			return sent, errs.Wrapf(err, "failed to load the watchers of work item %s", c.ID)
+		}
+		msg := NewWorkItemOverdue(*wi, time.Unix(c.DueDate, 0))
+		if kind == reminderKindDueSoon {
+			msg = NewWorkItemDueSoon(*wi, time.Unix(c.DueDate, 0), leadTime)
+		}
+		msg.Watchers = watchers
+		if err := Enqueue(ctx, r.channel, outbox.NewRepository(tx), msg); err != nil {
+			return sent, errs.Wrapf(err, "failed to send the %s reminder of work item %s", kind, c.ID)
+		}
+		log.Debug(ctx, map[string]interface{}{
+			"wi_id":     c.ID,
+			"kind":      kind,
+			"lead_time": leadTime.String(),
+		}, "due date reminder sent")
+		sent++
+	}
+	return sent, nil
+}
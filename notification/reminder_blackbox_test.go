@@ -0,0 +1,162 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingChannel keeps the messages it receives
+type recordingChannel struct {
+	messages []notification.Message
+}
+
+func (c *recordingChannel) Send(ctx context.Context, msg notification.Message) {
+	c.messages = append(c.messages, msg)
+}
+
+// messagesFor returns the received messages about the given work item
+func (c *recordingChannel) messagesFor(wi *workitem.WorkItem) []notification.Message {
+	var res []notification.Message
+	for _, msg := range c.messages {
+		if msg.TargetID == wi.ID.String() {
+			res = append(res, msg)
+		}
+	}
+	return res
+}
+
+type fakeReminderConfig struct{}
+
+func (c fakeReminderConfig) GetReminderInterval() time.Duration { return time.Minute }
+func (c fakeReminderConfig) GetReminderLeadTimes() []time.Duration {
+	return []time.Duration{72 * time.Hour, 24 * time.Hour}
+}
+func (c fakeReminderConfig) GetReminderOverdueMaxAge() time.Duration { return 48 * time.Hour }
+func (c fakeReminderConfig) GetReminderBatchSize() int               { return 100 }
+
+type TestReminder struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunReminder(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestReminder{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestReminder) TestSendDue() {
+	// given
+	now := time.Now().Truncate(time.Second)
+	dueDates := map[string]time.Time{
+		"tomorrow":   now.Add(10 * time.Hour),
+		"next week":  now.Add(7 * 24 * time.Hour),
+		"in 2 days":  now.Add(48 * time.Hour),
+		"yesterday":  now.Add(-24 * time.Hour),
+		"closed":     now.Add(-24 * time.Hour),
+		"resolved":   now.Add(-24 * time.Hour),
+		"final":      now.Add(-24 * time.Hour),
+		"last month": now.Add(-30 * 24 * time.Hour),
+		"no due day": {},
+	}
+	states := map[string]string{
+		"closed":   workitem.SystemStateClosed,
+		"resolved": workitem.SystemStateResolved,
+		"final":    workitem.SystemStateInProgress,
+	}
+	titles := []interface{}{"tomorrow", "next week", "in 2 days", "yesterday", "closed", "resolved", "final", "last month", "no due day"}
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields[workitem.SystemDueDate] = workitem.FieldDefinition{
+				Label: "Due date",
+				Type:  workitem.SimpleType{Kind: workitem.KindInstant},
+			}
+			return nil
+		}),
+		tf.WorkItems(len(titles), tf.SetWorkItemTitles(titles...), func(fxt *tf.TestFixture, idx int) error {
+			title := fxt.WorkItems[idx].Fields[workitem.SystemTitle].(string)
+			if dueDate := dueDates[title]; !dueDate.IsZero() {
+				fxt.WorkItems[idx].Fields[workitem.SystemDueDate] = dueDate
+			}
+			if state, ok := states[title]; ok {
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = state
+			}
+			return nil
+		}),
+	)
+	// no transition leads out of the "in progress" state of the workflow
+	_, err := workitem.NewWorkItemTypeRepository(s.DB).SetWorkflow(context.Background(), fxt.WorkItemTypes[0].ID, &workitem.Workflow{
+		States: []workitem.WorkflowState{
+			{Name: workitem.SystemStateNew},
+			{Name: workitem.SystemStateOpen},
+			{Name: workitem.SystemStateInProgress},
+			{Name: workitem.SystemStateResolved},
+			{Name: workitem.SystemStateClosed},
+		},
+		Transitions: []workitem.WorkflowTransition{
+			{From: workitem.SystemStateNew, To: workitem.SystemStateOpen},
+			{From: workitem.SystemStateOpen, To: workitem.SystemStateInProgress},
+		},
+	})
+	require.NoError(s.T(), err)
+	channel := &recordingChannel{}
+	reminder := notification.NewReminder(s.DB, channel, fakeReminderConfig{})
+	// when
+	_, err = reminder.SendDue(context.Background(), now)
+	// then
+	require.NoError(s.T(), err)
+	s.T().Run("due soon with the shortest lead time only", func(t *testing.T) {
+		msgs := channel.messagesFor(fxt.WorkItemByTitle("tomorrow"))
+		require.Len(t, msgs, 1)
+		assert.Equal(t, "workitem.due_soon", msgs[0].MessageType)
+		assert.Equal(t, (24 * time.Hour).String(), msgs[0].Details["lead_time"])
+		assert.Equal(t, dueDates["tomorrow"].UTC().Format(time.RFC3339), msgs[0].Details["due_date"])
+	})
+	s.T().Run("due soon with a longer lead time", func(t *testing.T) {
+		msgs := channel.messagesFor(fxt.WorkItemByTitle("in 2 days"))
+		require.Len(t, msgs, 1)
+		assert.Equal(t, "workitem.due_soon", msgs[0].MessageType)
+		assert.Equal(t, (72 * time.Hour).String(), msgs[0].Details["lead_time"])
+	})
+	s.T().Run("overdue", func(t *testing.T) {
+		msgs := channel.messagesFor(fxt.WorkItemByTitle("yesterday"))
+		require.Len(t, msgs, 1)
+		assert.Equal(t, "workitem.overdue", msgs[0].MessageType)
+	})
+	s.T().Run("no reminders", func(t *testing.T) {
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("next week")))
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("closed")))
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("resolved")))
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("final")))
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("last month")))
+		assert.Empty(t, channel.messagesFor(fxt.WorkItemByTitle("no due day")))
+	})
+	s.T().Run("reminders are sent only once", func(t *testing.T) {
+		// given another reminder, e.g. of a restarted or another replica
+		other := &recordingChannel{}
+		// when
+		_, err := notification.NewReminder(s.DB, other, fakeReminderConfig{}).SendDue(context.Background(), now.Add(time.Minute))
+		// then
+		require.NoError(t, err)
+		for _, title := range titles {
+			assert.Empty(t, other.messagesFor(fxt.WorkItemByTitle(title.(string))), title)
+		}
+	})
+	s.T().Run("next lead time", func(t *testing.T) {
+		// when the work item due in 2 days gets closer than 24 hours
+		_, err := reminder.SendDue(context.Background(), now.Add(25*time.Hour))
+		// then
+		require.NoError(t, err)
+		msgs := channel.messagesFor(fxt.WorkItemByTitle("in 2 days"))
+		require.Len(t, msgs, 2)
+		assert.Equal(t, (24 * time.Hour).String(), msgs[1].Details["lead_time"])
+	})
+}
@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Event is a message as it appears in the activity stream of a space. The
+// stream is read from the outbox, so it contains exactly the messages that
+// were handed over to the notification Channel.
+type Event struct {
+	Message
+	// Sequence identifies the event within the stream. Clients resume the
+	// stream from the last Sequence they have seen.
+	Sequence  int64
+	Timestamp time.Time
+}
+
+// SequenceEvents numbers the events that were committed since it was last
+// called. Events only appear in ListEvents once they are numbered, and as they
+// are numbered after their transaction committed, an event whose transaction
+// commits late is never overtaken by events with a higher sequence number. The
+// numbering must be committed before the events are listed.
+func SequenceEvents(ctx context.Context, repo outbox.Repository) error {
+	_, err := repo.AssignSequences(ctx)
+	return err
+}
+
+// ListEvents returns up to limit events of the given space that were numbered
+// after the event with the given sequence number, oldest first.
+func ListEvents(ctx context.Context, repo outbox.Repository, spaceID uuid.UUID, after int64, limit int) ([]Event, error) {
+	entries, err := repo.ListForSpace(ctx, spaceID, after, limit)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		msg, err := outboxMessage(entry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, Event{Message: msg, Sequence: entry.Sequence, Timestamp: entry.CreatedAt})
+	}
+	return events, nil
+}
+
+// LastEventSequence returns the sequence number of the most recent event of
+// the given space. New streams start after it.
+func LastEventSequence(ctx context.Context, repo outbox.Repository, spaceID uuid.UUID) (int64, error) {
+	return repo.LastSequence(ctx, spaceID)
+}
@@ -0,0 +1,85 @@
+package notification_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/notification"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestStream struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunStream(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestStream{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestStream) TestListEvents() {
+	// given
+	spaceID := uuid.NewV4()
+	repo := outbox.NewRepository(s.DB)
+	last, err := notification.LastEventSequence(context.Background(), repo, spaceID)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), last)
+	created := notification.NewWorkItemCreated(workitem.WorkItem{ID: uuid.NewV4(), SpaceID: spaceID})
+	commented := notification.NewCommentCreated(uuid.NewV4().String(), spaceID)
+	other := notification.NewCommentCreated(uuid.NewV4().String(), uuid.NewV4())
+	for _, msg := range []notification.Message{created, other, commented} {
+		require.NoError(s.T(), notification.Enqueue(context.Background(), notification.NewOutboxChannel(s.DB), repo, msg))
+	}
+	s.T().Run("events are not listed before they are numbered", func(t *testing.T) {
+		// when
+		events, err := notification.ListEvents(context.Background(), repo, spaceID, 0, 10)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+	require.NoError(s.T(), notification.SequenceEvents(context.Background(), repo))
+	s.T().Run("all events of the space", func(t *testing.T) {
+		// when
+		events, err := notification.ListEvents(context.Background(), repo, spaceID, 0, 10)
+		// then
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, created.MessageID, events[0].MessageID)
+		assert.Equal(t, commented.MessageID, events[1].MessageID)
+		assert.True(t, events[0].Sequence < events[1].Sequence)
+		last, err := notification.LastEventSequence(context.Background(), repo, spaceID)
+		require.NoError(t, err)
+		assert.Equal(t, events[1].Sequence, last)
+	})
+	s.T().Run("resumed after an event", func(t *testing.T) {
+		events, err := notification.ListEvents(context.Background(), repo, spaceID, 0, 10)
+		require.NoError(t, err)
+		// when
+		resumed, err := notification.ListEvents(context.Background(), repo, spaceID, events[0].Sequence, 10)
+		// then
+		require.NoError(t, err)
+		require.Len(t, resumed, 1)
+		assert.Equal(t, commented.MessageID, resumed[0].MessageID)
+	})
+	s.T().Run("events committed late are numbered after the listed events", func(t *testing.T) {
+		// given
+		events, err := notification.ListEvents(context.Background(), repo, spaceID, 0, 10)
+		require.NoError(t, err)
+		late := notification.NewWorkItemUpdated(workitem.WorkItem{ID: uuid.NewV4(), SpaceID: spaceID}, nil)
+		require.NoError(t, notification.Enqueue(context.Background(), notification.NewOutboxChannel(s.DB), repo, late))
+		// when
+		require.NoError(t, notification.SequenceEvents(context.Background(), repo))
+		resumed, err := notification.ListEvents(context.Background(), repo, spaceID, events[len(events)-1].Sequence, 10)
+		// then
+		require.NoError(t, err)
+		require.Len(t, resumed, 1)
+		assert.Equal(t, late.MessageID, resumed[0].MessageID)
+	})
+}
@@ -0,0 +1,91 @@
+package rendering
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// mentionPattern matches an @username which doesn't directly follow a word
+// character, so that email addresses are not taken for mentions
+var mentionPattern = regexp.MustCompile(`(^|[^\w@/])@([A-Za-z0-9](?:[A-Za-z0-9._-]*[A-Za-z0-9])?)`)
+
+// ParseMentions returns the usernames mentioned in the given content in the
+// order of their first occurrence. Mentions in code and in links of the
+// rendered content are ignored.
+func ParseMentions(content, markup string) []string {
+	usernames := []string{}
+	seen := map[string]bool{}
+	rewriteMentionText(RenderMarkupToHTML(html.EscapeString(content), markup), func(text string) string {
+		for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			if !seen[m[2]] {
+				seen[m[2]] = true
+				usernames = append(usernames, m[2])
+			}
+		}
+		return text
+	})
+	return usernames
+}
+
+// RenderMentions turns the mentions in the given rendered HTML into links to
+// the profiles of the mentioned users. The given link func returns the URL
+// of the profile of a user or false if the username is unknown, in which
+// case the mention is left as it is.
+func RenderMentions(renderedHTML string, link func(username string) (string, bool)) string {
+	return rewriteMentionText(renderedHTML, func(text string) string {
+		return mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
+			m := mentionPattern.FindStringSubmatch(match)
+			href, ok := link(m[2])
+			if !ok {
+				return match
+			}
+			return m[1] + `<a href="` + html.EscapeString(href) + `" class="mention">@` + m[2] + `</a>`
+		})
+	})
+}
+
+// rewriteMentionText applies the given func to the text of the HTML document
+// that can hold mentions, i.e. all text outside of code blocks and links
+func rewriteMentionText(document string, rewrite func(text string) string) string {
+	var buf bytes.Buffer
+	z := xhtml.NewTokenizer(strings.NewReader(document))
+	// the number of open elements whose text is left untouched
+	skipped := 0
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			// the end of the document
+			return buf.String()
+		case xhtml.TextToken:
+			text := string(z.Raw())
+			if skipped == 0 {
+				text = rewrite(text)
+			}
+			buf.WriteString(text)
+		case xhtml.StartTagToken:
+			buf.Write(z.Raw())
+			if name, _ := z.TagName(); skipsMentions(string(name)) {
+				skipped++
+			}
+		case xhtml.EndTagToken:
+			buf.Write(z.Raw())
+			if name, _ := z.TagName(); skipsMentions(string(name)) && skipped > 0 {
+				skipped--
+			}
+		default:
+			buf.Write(z.Raw())
+		}
+	}
+}
+
+func skipsMentions(tagName string) bool {
+	switch tagName {
+	case "a", "code", "pre":
+		return true
+	}
+	return false
+}
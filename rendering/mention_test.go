@@ -0,0 +1,44 @@
+package rendering_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/rendering"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMentions(t *testing.T) {
+	t.Run("plain text", func(t *testing.T) {
+		mentions := rendering.ParseMentions("@jdoe please review, cc @alice and @jdoe.", rendering.SystemMarkupPlainText)
+		assert.Equal(t, []string{"jdoe", "alice"}, mentions)
+	})
+	t.Run("markdown", func(t *testing.T) {
+		mentions := rendering.ParseMentions("Hello **@jdoe**, see `@notme` and\n\n```\n@neither\n```", rendering.SystemMarkupMarkdown)
+		assert.Equal(t, []string{"jdoe"}, mentions)
+	})
+	t.Run("email addresses", func(t *testing.T) {
+		mentions := rendering.ParseMentions("write to jdoe@example.com", rendering.SystemMarkupPlainText)
+		assert.Empty(t, mentions)
+	})
+	t.Run("unknown markup", func(t *testing.T) {
+		mentions := rendering.ParseMentions("@jdoe", "foo")
+		assert.Empty(t, mentions)
+	})
+}
+
+func TestRenderMentions(t *testing.T) {
+	link := func(username string) (string, bool) {
+		if username == "jdoe" {
+			return "https://example.com/users/jdoe", true
+		}
+		return "", false
+	}
+	t.Run("known and unknown users", func(t *testing.T) {
+		rendered := rendering.RenderMentions("<p>@jdoe and @unknown</p>\n", link)
+		assert.Equal(t, `<p><a href="https://example.com/users/jdoe" class="mention">@jdoe</a> and @unknown</p>`+"\n", rendered)
+	})
+	t.Run("code is left untouched", func(t *testing.T) {
+		rendered := rendering.RenderMentions("<p><code>@jdoe</code></p>", link)
+		assert.Equal(t, "<p><code>@jdoe</code></p>", rendered)
+	})
+}
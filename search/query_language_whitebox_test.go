@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	c "github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -52,6 +56,44 @@ func TestParseMap(t *testing.T) {
 		assert.Equal(t, expectedQuery, actualQuery)
 	})
 
+	t.Run(LT+" and "+GT, func(t *testing.T) {
+		t.Parallel()
+		// given
+		from := "2018-01-01"
+		until := "2018-01-31"
+		input := fmt.Sprintf(`{"$AND": [{"due": { "%s": "%s"}}, {"due": { "%s": "%s"}}]}`, GT, from, LT, until)
+		// Parsing/Unmarshalling JSON encoding/json
+		fm := map[string]interface{}{}
+		err := json.Unmarshal([]byte(input), &fm)
+		require.NoError(t, err)
+		// when
+		actualQuery := Query{}
+		parseMap(fm, &actualQuery)
+		// then
+		expectedQuery := Query{Name: AND, Children: []Query{
+			{Name: "due", Value: &from, GreaterThan: true},
+			{Name: "due", Value: &until, LessThan: true}},
+		}
+		assert.Equal(t, expectedQuery, actualQuery)
+	})
+
+	t.Run(LT+" with a number", func(t *testing.T) {
+		t.Parallel()
+		// given
+		input := fmt.Sprintf(`{"custom.points": { "%s": 8}}`, LT)
+		// Parsing/Unmarshalling JSON encoding/json
+		fm := map[string]interface{}{}
+		err := json.Unmarshal([]byte(input), &fm)
+		require.NoError(t, err)
+		// when
+		actualQuery := Query{}
+		parseMap(fm, &actualQuery)
+		// then
+		points := "8"
+		expectedQuery := Query{Name: "custom.points", Value: &points, LessThan: true}
+		assert.Equal(t, expectedQuery, actualQuery)
+	})
+
 	t.Run("$SUBSTR within $AND", func(t *testing.T) {
 		t.Parallel()
 		// given
@@ -416,6 +458,140 @@ func TestGenerateExpression(t *testing.T) {
 		)
 		expectEqualExpr(t, expectedExpr, actualExpr)
 	})
+	t.Run("due date "+LT+" (top-level)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		until := "2018-01-31"
+		q := Query{Name: "due", Value: &until, LessThan: true}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.LessThan(
+			c.Field(workitem.SystemDueDate),
+			c.Literal(time.Date(2018, time.January, 31, 0, 0, 0, 0, time.UTC).UnixNano()),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+
+	t.Run("due date "+GT+" within "+AND, func(t *testing.T) {
+		t.Parallel()
+		// given
+		from := "2018-01-01T12:00:00Z"
+		spaceName := "openshiftio"
+		q := Query{
+			Name: AND,
+			Children: []Query{
+				{Name: "space", Value: &spaceName},
+				{Name: "due", Value: &from, GreaterThan: true},
+			},
+		}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.And(
+			c.Equals(
+				c.Field("SpaceID"),
+				c.Literal(spaceName),
+			),
+			c.GreaterThan(
+				c.Field(workitem.SystemDueDate),
+				c.Literal(time.Date(2018, time.January, 1, 12, 0, 0, 0, time.UTC).UnixNano()),
+			),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+
+	t.Run("custom field "+GT, func(t *testing.T) {
+		t.Parallel()
+		// given
+		points := "8"
+		q := Query{Name: "custom.points", Value: &points, GreaterThan: true}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.GreaterThan(
+			c.Field("custom.points"),
+			c.Literal(float64(8)),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+
+	t.Run("custom field "+GT+" with invalid value", func(t *testing.T) {
+		t.Parallel()
+		// given
+		points := "many"
+		q := Query{Name: "custom.points", Value: &points, GreaterThan: true}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		require.Nil(t, actualExpr)
+	})
+
+	t.Run(LT+" on a text field", func(t *testing.T) {
+		t.Parallel()
+		// given
+		title := "foo"
+		spaceName := "openshiftio"
+		q := Query{
+			Name: AND,
+			Children: []Query{
+				{Name: "space", Value: &spaceName},
+				{Name: "title", Value: &title, LessThan: true},
+			},
+		}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		require.Nil(t, actualExpr)
+	})
+
+	t.Run(GT+" without a value", func(t *testing.T) {
+		t.Parallel()
+		// given
+		q := Query{Name: "due", GreaterThan: true}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+		require.Nil(t, actualExpr)
+	})
+
+	t.Run("blocked (top-level)", func(t *testing.T) {
+		t.Parallel()
+		// given
+		blocked := "true"
+		q := Query{Name: "blocked", Value: &blocked}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.NoError(t, err)
+		expectedExpr := c.Equals(
+			c.Field(workitem.SystemBlocked),
+			c.Literal(blocked),
+		)
+		expectEqualExpr(t, expectedExpr, actualExpr)
+	})
+
+	t.Run("due date with invalid value", func(t *testing.T) {
+		t.Parallel()
+		// given
+		until := "tomorrow"
+		q := Query{Name: "due", Value: &until, LessThan: true}
+		// when
+		actualExpr, err := q.generateExpression()
+		// then
+		require.Error(t, err)
+		require.Nil(t, actualExpr)
+	})
+
 	t.Run(AND, func(t *testing.T) {
 		t.Parallel()
 		// given
@@ -625,36 +801,14 @@ func TestGenerateExpressionWithNonExistingKey(t *testing.T) {
 }
 
 func TestWorkItemTypeGroup(t *testing.T) {
-	typeGroups := workitem.TypeGroups()
+	typeGroups := spacetemplate.TypeGroups()
 
 	typeGroupToExpr := func(typeGroup workitem.WorkItemTypeGroup, negate bool) c.Expression {
-		var e c.Expression
-		if !negate {
-			for _, witID := range typeGroup.TypeList {
-				exp := c.Equals(
-					c.Field("Type"),
-					c.Literal(witID.String()),
-				)
-				if e != nil {
-					e = c.Or(e, exp)
-				} else {
-					e = exp
-				}
-			}
-		} else {
-			for _, witID := range typeGroup.TypeList {
-				exp := c.Not(
-					c.Field("Type"),
-					c.Literal(witID.String()),
-				)
-				if e != nil {
-					e = c.And(e, exp)
-				} else {
-					e = exp
-				}
-			}
+		membership := workitem.TypeGroupMembership{Name: typeGroup.Name, DefaultTypes: typeGroup.TypeList}
+		if negate {
+			return c.Not(c.Field(workitem.SystemTypeGroup), c.Literal(membership))
 		}
-		return e
+		return c.Equals(c.Field(workitem.SystemTypeGroup), c.Literal(membership))
 	}
 
 	for _, paramName := range []string{WITGROUP, TypeGroupName} {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -17,6 +18,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/id"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
 	"github.com/fabric8-services/fabric8-wit/workitem"
 	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	"github.com/jinzhu/gorm"
@@ -28,6 +30,7 @@ import (
 const (
 	HostRegistrationKeyForListWI  = "work-item-list-details"
 	HostRegistrationKeyForBoardWI = "work-item-board-details"
+	HostRegistrationKeyForWIKey   = "work-item-key"
 
 	EQ       = "$EQ"
 	NE       = "$NE"
@@ -36,6 +39,8 @@ const (
 	NOT      = "$NOT"
 	IN       = "$IN"
 	SUBSTR   = "$SUBSTR"
+	LT       = "$LT"
+	GT       = "$GT"
 	WITGROUP = "$WITGROUP"
 	OPTS     = "$OPTS"
 
@@ -65,6 +70,7 @@ func generateSearchQuery(q string) (string, error) {
 type searchKeyword struct {
 	workItemTypes []uuid.UUID
 	number        []string
+	keys          []string
 	words         []string
 }
 
@@ -193,6 +199,24 @@ func getSearchQueryFromURLPattern(patternName, stringToMatch string) string {
 	return match[0] + ":*"
 }
 
+// getKeyFromURLString returns the human-readable work item key (e.g.
+// "PLAT-123") contained in the given URL if it matches a known URL with a
+// "key" group, or an empty string otherwise
+func getKeyFromURLString(url string) string {
+	known, patternName := isKnownURL(url)
+	if !known {
+		return ""
+	}
+	pattern := knownURLs[patternName]
+	match := pattern.compiledRegex.FindStringSubmatch(url)
+	for i, name := range pattern.groupNamesInRegex {
+		if name == "key" && i < len(match) {
+			return match[i]
+		}
+	}
+	return ""
+}
+
 /*
 getSearchQueryFromURLString gets a url string and checks if that matches with any of known urls.
 Respectively it will return a string that can be directly used in search query
@@ -234,6 +258,19 @@ func parseSearchString(ctx context.Context, rawSearchString string) (searchKeywo
 		// TODO: need to find out the way to use ID fields.
 		if strings.HasPrefix(part, "number:") {
 			res.number = append(res.number, strings.TrimPrefix(part, "number:")+":*A")
+		} else if strings.HasPrefix(part, "key:") {
+			// IF part is for search with key:PLAT-1234
+			keyStr := strings.TrimPrefix(part, "key:")
+			prefix, number, err := workitem.ParseKey(keyStr)
+			if err != nil {
+				log.Error(ctx, map[string]interface{}{
+					"err": err,
+					"key": keyStr,
+				}, "failed to parse work item key")
+				return res, errs.WithStack(err)
+			}
+			res.keys = append(res.keys, fmt.Sprintf("%s-%d", prefix, number))
+			res.number = append(res.number, fmt.Sprintf("%d:A", number))
 		} else if strings.HasPrefix(part, "type:") {
 			typeIDStr := strings.TrimPrefix(part, "type:")
 			if len(typeIDStr) == 0 {
@@ -253,6 +290,14 @@ func parseSearchString(ctx context.Context, rawSearchString string) (searchKeywo
 			log.Debug(ctx, map[string]interface{}{"url": part}, "found a URL in the query string")
 			part := strings.ToLower(part)
 			part = trimProtocolFromURLString(part)
+			if key := getKeyFromURLString(part); key != "" {
+				if prefix, number, err := workitem.ParseKey(key); err == nil {
+					log.Debug(ctx, map[string]interface{}{"url": part, "key": key}, "found a work item key URL in the query string")
+					res.keys = append(res.keys, fmt.Sprintf("%s-%d", prefix, number))
+					res.number = append(res.number, fmt.Sprintf("%d:A", number))
+					continue
+				}
+			}
 			searchQueryFromURL := getSearchQueryFromURLString(part)
 			log.Debug(ctx, map[string]interface{}{"url": part, "search_query": searchQueryFromURL}, "found a URL in the query string")
 			res.words = append(res.words, searchQueryFromURL)
@@ -313,6 +358,12 @@ func parseMap(queryMap map[string]interface{}, q *Query) {
 				s := v.(string)
 				q.Value = &s
 				q.Substring = true
+			} else if v, ok := concreteVal[LT]; ok {
+				q.Value = comparisonValue(v)
+				q.LessThan = true
+			} else if v, ok := concreteVal[GT]; ok {
+				q.Value = comparisonValue(v)
+				q.GreaterThan = true
 			}
 		default:
 			log.Error(nil, nil, "Unexpected value: %#v", val)
@@ -320,6 +371,19 @@ func parseMap(queryMap map[string]interface{}, q *Query) {
 	}
 }
 
+// comparisonValue returns the value of a "$LT" or "$GT" operator, which is
+// either a string or a JSON number, as a string
+func comparisonValue(v interface{}) *string {
+	switch v := v.(type) {
+	case string:
+		return &v
+	case float64:
+		s := strconv.FormatFloat(v, 'f', -1, 64)
+		return &s
+	}
+	return nil
+}
+
 func parseOptions(queryMap map[string]interface{}) *QueryOptions {
 	for key, val := range queryMap {
 		if ifArr, ok := val.(map[string]interface{}); key == OPTS && ok {
@@ -373,6 +437,10 @@ type Query struct {
 	// If Substring is true, instead of exact match, anything that matches partially
 	// will be considered.
 	Substring bool
+	// If LessThan or GreaterThan is true, anything that is less or greater
+	// than the Value will be considered instead of an exact match.
+	LessThan    bool
+	GreaterThan bool
 	// A Query is expected to have child queries only if the Name field contains
 	// an operator like "$AND", or "$OR". If the Name is not an operator, the
 	// Children slice MUST be empty.
@@ -396,6 +464,8 @@ var searchKeyMap = map[string]string{
 	"type":         "Type",
 	"workitemtype": "Type", // same as 'type' - added for compatibility. (Ref. #1564)
 	"space":        "SpaceID",
+	"due":          workitem.SystemDueDate,
+	"blocked":      workitem.SystemBlocked,
 }
 
 func (q Query) determineLiteralType(key string, val string) criteria.Expression {
@@ -407,6 +477,41 @@ func (q Query) determineLiteralType(key string, val string) criteria.Expression
 	}
 }
 
+// generateComparison returns the expression for the "$LT" and "$GT"
+// operators, which are only supported on the numeric, duration and instant
+// fields. Due dates are compared by their stored value in nanoseconds, the
+// other fields by the given number.
+func (q Query) generateComparison(key string) (criteria.Expression, error) {
+	op := GT
+	if q.LessThan {
+		op = LT
+	}
+	if !workitem.IsComparableField(key) {
+		return nil, errors.NewBadParameterError(op, q.Name).Expected("a numeric, duration or instant field")
+	}
+	if q.Value == nil {
+		return nil, errors.NewBadParameterError(q.Name, nil).Expected("a string or a number to compare with")
+	}
+	var right criteria.Expression
+	if key == workitem.SystemDueDate {
+		dueDate, err := workitem.ParseDueDate(*q.Value)
+		if err != nil {
+			return nil, err
+		}
+		right = criteria.Literal(dueDate.UnixNano())
+	} else {
+		number, err := strconv.ParseFloat(strings.TrimSpace(*q.Value), 64)
+		if err != nil {
+			return nil, errors.NewBadParameterError(q.Name, *q.Value).Expected("a number")
+		}
+		right = criteria.Literal(number)
+	}
+	if q.LessThan {
+		return criteria.LessThan(criteria.Field(key), right), nil
+	}
+	return criteria.GreaterThan(criteria.Field(key), right), nil
+}
+
 // handleWitGroup Here we handle the "$WITGROUP" and "typegroup.name" query
 // parameter which we translate from a simple
 //
@@ -414,10 +519,12 @@ func (q Query) determineLiteralType(key string, val string) criteria.Expression
 //
 // expression into an
 //
-// "Type in (y1, y2, y3, ... ,yn)"
+// "system.typegroup = y"
 //
-// expression where yi represents the i-th work item type associated with the
-// work item type group y.
+// expression which matches the work items whose type belongs to the type group
+// y of their space. The type groups are stored with the space when it is
+// created from a space template; the spaces created without a template use the
+// type groups of the default template.
 func handleWitGroup(q Query, expArr *[]criteria.Expression) error {
 	if q.Name != WITGROUP && q.Name != TypeGroupName {
 		return nil
@@ -429,38 +536,20 @@ func handleWitGroup(q Query, expArr *[]criteria.Expression) error {
 	paramName := q.Name
 
 	typeGroupName := q.Value
-	if typeGroupName == nil {
-		return errors.NewBadParameterError(paramName, typeGroupName).Expected("not nil")
+	if typeGroupName == nil || *typeGroupName == "" {
+		return errors.NewBadParameterError(paramName, typeGroupName).Expected("not empty")
 	}
-	typeGroup := workitem.TypeGroupByName(*typeGroupName)
-	if typeGroup == nil {
-		return errors.NewBadParameterError(paramName, *typeGroupName).Expected("existing " + paramName)
+	membership := workitem.TypeGroupMembership{Name: *typeGroupName}
+	for _, typeGroup := range spacetemplate.TypeGroups() {
+		if typeGroup.Name == *typeGroupName {
+			membership.DefaultTypes = typeGroup.TypeList
+		}
 	}
 	var e criteria.Expression
 	if !q.Negate {
-		for _, witID := range typeGroup.TypeList {
-			eq := criteria.Equals(
-				criteria.Field("Type"),
-				criteria.Literal(witID.String()),
-			)
-			if e != nil {
-				e = criteria.Or(e, eq)
-			} else {
-				e = eq
-			}
-		}
+		e = criteria.Equals(criteria.Field(workitem.SystemTypeGroup), criteria.Literal(membership))
 	} else {
-		for _, witID := range typeGroup.TypeList {
-			eq := criteria.Not(
-				criteria.Field("Type"),
-				criteria.Literal(witID.String()),
-			)
-			if e != nil {
-				e = criteria.And(e, eq)
-			} else {
-				e = eq
-			}
-		}
+		e = criteria.Not(criteria.Field(workitem.SystemTypeGroup), criteria.Literal(membership))
 	}
 	*expArr = append(*expArr, e)
 	return nil
@@ -487,11 +576,21 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 				break
 			}
 		}
+		// custom fields of the work item types are searched by their name
+		if !ok && workitem.IsCustomField(q.Name) {
+			key, ok = q.Name, true
+		}
 		if !ok && !handledByJoin {
 			return nil, errors.NewBadParameterError("key not found", q.Name)
 		}
 		left := criteria.Field(key)
-		if q.Value != nil {
+		if q.LessThan || q.GreaterThan {
+			exp, err := q.generateComparison(key)
+			if err != nil {
+				return nil, err
+			}
+			myexpr = append(myexpr, exp)
+		} else if q.Value != nil {
 			right := q.determineLiteralType(key, *q.Value)
 			if q.Negate {
 				myexpr = append(myexpr, criteria.Not(left, right))
@@ -533,11 +632,21 @@ func (q Query) generateExpression() (criteria.Expression, error) {
 					break
 				}
 			}
+			// custom fields of the work item types are searched by their name
+			if !ok && workitem.IsCustomField(child.Name) {
+				key, ok = child.Name, true
+			}
 			if !ok && !handledByJoin {
 				return nil, errors.NewBadParameterError("key not found", child.Name)
 			}
 			left := criteria.Field(key)
-			if child.Value != nil {
+			if child.LessThan || child.GreaterThan {
+				exp, err := child.generateComparison(key)
+				if err != nil {
+					return nil, err
+				}
+				myexpr = append(myexpr, exp)
+			} else if child.Value != nil {
 				right := q.determineLiteralType(key, *child.Value)
 				if child.Negate {
 					myexpr = append(myexpr, criteria.Not(left, right))
@@ -623,7 +732,7 @@ func generateSQLSearchInfo(keywords searchKeyword) (sqlParameter string) {
 
 // extracted this function from List() in order to close the rows object with "defer" for more readability
 // workaround for https://github.com/lib/pq/issues/81
-func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, int, error) {
+func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParameter string, workItemTypes []uuid.UUID, keys []string, start *int, limit *int, spaceID *string) ([]workitem.WorkItemStorage, int, error) {
 	db := r.db.Model(workitem.WorkItemStorage{}).Where("tsv @@ query")
 	if start != nil {
 		if *start < 0 {
@@ -645,6 +754,10 @@ func (r *GormSearchRepository) search(ctx context.Context, sqlSearchQueryParamet
 			"where supertype.id in (?))", workitem.WorkItemStorage{}.TableName(), workitem.WorkItemType{}.TableName())
 		db = db.Where(query, workItemTypes)
 	}
+	if len(keys) > 0 {
+		// restrict to the work items with the given keys
+		db = db.Where(fmt.Sprintf("upper(%s.key) in (?)", workitem.WorkItemStorage{}.TableName()), keys)
+	}
 
 	db = db.Select("count(*) over () as cnt2 , *").Order("execution_order desc")
 	db = db.Joins(", to_tsquery('english', ?) as query, ts_rank(tsv, query) as rank", sqlSearchQueryParameter)
@@ -716,7 +829,7 @@ func (r *GormSearchRepository) SearchFullText(ctx context.Context, rawSearchStri
 	sqlSearchQueryParameter := generateSQLSearchInfo(parsedSearchDict)
 	var rows []workitem.WorkItemStorage
 	log.Debug(ctx, map[string]interface{}{"search query": sqlSearchQueryParameter}, "searching for work items")
-	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, start, limit, spaceID)
+	rows, count, err := r.search(ctx, sqlSearchQueryParameter, parsedSearchDict.workItemTypes, parsedSearchDict.keys, start, limit, spaceID)
 	if err != nil {
 		return nil, 0, errs.WithStack(err)
 	}
@@ -19,6 +19,7 @@ func init() {
 	// Please do not include trailing slashes because it will be removed before scanning starts
 	RegisterAsKnownURL("test-work-item-list-details", `(?P<domain>demo.openshift.io)(?P<path>/work-item/list/detail/)(?P<id>\d*)`)
 	RegisterAsKnownURL("test-work-item-board-details", `(?P<domain>demo.openshift.io)(?P<path>/work-item/board/detail/)(?P<id>\d*)`)
+	RegisterAsKnownURL("test-work-item-key", `(?P<domain>demo.openshift.io)(?P<path>/api/workitemkeys/)(?P<key>[A-Za-z][A-Za-z0-9]{1,9}-\d+)`)
 }
 
 func TestGenerateSQLSearchStringText(t *testing.T) {
@@ -60,6 +61,34 @@ func TestParseSearchString(t *testing.T) {
 	assert.True(t, assert.ObjectsAreEqualValues(expectedSearchRes, op))
 }
 
+func TestParseSearchStringKey(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	t.Run("key keyword", func(t *testing.T) {
+		op, err := parseSearchString(context.Background(), "crash key:PLAT-99 key:plat2-400")
+		require.NoError(t, err)
+		expectedSearchRes := searchKeyword{
+			number: []string{"99:A", "400:A"},
+			keys:   []string{"PLAT-99", "PLAT2-400"},
+			words:  []string{"crash:*"},
+		}
+		assert.Equal(t, expectedSearchRes, op)
+	})
+	t.Run("key URL", func(t *testing.T) {
+		op, err := parseSearchString(context.Background(), "http://demo.openshift.io/api/workitemkeys/PLAT-99")
+		require.NoError(t, err)
+		expectedSearchRes := searchKeyword{
+			number: []string{"99:A"},
+			keys:   []string{"PLAT-99"},
+		}
+		assert.Equal(t, expectedSearchRes, op)
+	})
+	t.Run("invalid key", func(t *testing.T) {
+		_, err := parseSearchString(context.Background(), "key:99")
+		require.Error(t, err)
+	})
+}
+
 type searchTestData struct {
 	query    string
 	expected searchKeyword
@@ -250,131 +279,35 @@ func TestHandleWitGroup(t *testing.T) {
 			ExpectError         bool
 			ExpectedExrpessions []criteria.Expression
 		}
+		membership := func(name string, defaultTypes ...uuid.UUID) criteria.Expression {
+			return criteria.Literal(workitem.TypeGroupMembership{Name: name, DefaultTypes: defaultTypes})
+		}
 		td := []testData{
 			{"foo", "bar", false, false, []criteria.Expression{}},
-			{paramName, "unknown", false, true, []criteria.Expression{}},
+			{paramName, "", false, true, []criteria.Expression{}},
+			{paramName, "unknown", false, false, []criteria.Expression{
+				criteria.Equals(criteria.Field(workitem.SystemTypeGroup), membership("unknown")),
+			}},
 			{paramName, "Scenarios", false, false, []criteria.Expression{
-				criteria.Or(
-					criteria.Or(
-						criteria.Equals(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemScenario.String()),
-						),
-						criteria.Equals(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemFundamental.String()),
-						),
-					),
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemPapercuts.String()),
-					),
-				)},
-			},
+				criteria.Equals(criteria.Field(workitem.SystemTypeGroup), membership("Scenarios", workitem.SystemScenario, workitem.SystemFundamental, workitem.SystemPapercuts)),
+			}},
 			{paramName, "Experiences", false, false, []criteria.Expression{
-				criteria.Or(
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemExperience.String()),
-					),
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemValueProposition.String()),
-					),
-				)},
-			},
+				criteria.Equals(criteria.Field(workitem.SystemTypeGroup), membership("Experiences", workitem.SystemExperience, workitem.SystemValueProposition)),
+			}},
 			{paramName, "Requirements", false, false, []criteria.Expression{
-				criteria.Or(
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemFeature.String()),
-					),
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemBug.String()),
-					),
-				)},
-			},
+				criteria.Equals(criteria.Field(workitem.SystemTypeGroup), membership("Requirements", workitem.SystemFeature, workitem.SystemBug)),
+			}},
 			{paramName, "Execution", false, false, []criteria.Expression{
-				criteria.Or(
-					criteria.Or(
-						criteria.Equals(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemTask.String()),
-						),
-						criteria.Equals(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemBug.String()),
-						),
-					),
-					criteria.Equals(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemFeature.String()),
-					),
-				),
+				criteria.Equals(criteria.Field(workitem.SystemTypeGroup), membership("Execution", workitem.SystemTask, workitem.SystemBug, workitem.SystemFeature)),
 			}},
-			// // same with negation
+			// same with negation
 			{"foo", "bar", true, false, []criteria.Expression{}},
-			{paramName, "unknown", true, true, []criteria.Expression{}},
+			{paramName, "", true, true, []criteria.Expression{}},
 			{paramName, "Scenarios", true, false, []criteria.Expression{
-				criteria.And(
-					criteria.And(
-						criteria.Not(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemScenario.String()),
-						),
-						criteria.Not(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemFundamental.String()),
-						),
-					),
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemPapercuts.String()),
-					),
-				)},
-			},
-			{paramName, "Experiences", true, false, []criteria.Expression{
-				criteria.And(
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemExperience.String()),
-					),
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemValueProposition.String()),
-					),
-				)},
-			},
-			{paramName, "Requirements", true, false, []criteria.Expression{
-				criteria.And(
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemFeature.String()),
-					),
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemBug.String()),
-					),
-				)},
-			},
+				criteria.Not(criteria.Field(workitem.SystemTypeGroup), membership("Scenarios", workitem.SystemScenario, workitem.SystemFundamental, workitem.SystemPapercuts)),
+			}},
 			{paramName, "Execution", true, false, []criteria.Expression{
-				criteria.And(
-					criteria.And(
-						criteria.Not(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemTask.String()),
-						),
-						criteria.Not(
-							criteria.Field("Type"),
-							criteria.Literal(workitem.SystemBug.String()),
-						),
-					),
-					criteria.Not(
-						criteria.Field("Type"),
-						criteria.Literal(workitem.SystemFeature.String()),
-					),
-				),
+				criteria.Not(criteria.Field(workitem.SystemTypeGroup), membership("Execution", workitem.SystemTask, workitem.SystemBug, workitem.SystemFeature)),
 			}},
 		}
 		for _, d := range td {
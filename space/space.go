@@ -34,6 +34,9 @@ type Space struct {
 	Name        string
 	Description string
 	OwnerID     uuid.UUID `sql:"type:uuid"` // Belongs To Identity
+	// optional prefix of the human-readable keys of the work items in this
+	// space (e.g. "PLAT" for "PLAT-123")
+	KeyPrefix *string `sql:"column:key_prefix"`
 }
 
 // Ensure Fields implements the Equaler interface
@@ -62,6 +65,12 @@ func (p Space) Equal(u convert.Equaler) bool {
 	if !uuid.Equal(p.OwnerID, other.OwnerID) {
 		return false
 	}
+	if (p.KeyPrefix == nil) != (other.KeyPrefix == nil) {
+		return false
+	}
+	if p.KeyPrefix != nil && *p.KeyPrefix != *other.KeyPrefix {
+		return false
+	}
 	return true
 }
 
@@ -235,6 +244,12 @@ func (r *GormRepository) Save(ctx context.Context, p *Space) (*Space, error) {
 		if gormsupport.IsUniqueViolation(tx.Error, "spaces_name_idx") {
 			return nil, errors.NewBadParameterError("Name", p.Name).Expected("unique")
 		}
+		if gormsupport.IsCheckViolation(tx.Error, "spaces_key_prefix_check") {
+			return nil, errors.NewBadParameterError("KeyPrefix", *p.KeyPrefix).Expected("2 to 10 upper case letters or digits, starting with a letter")
+		}
+		if gormsupport.IsUniqueViolation(tx.Error, "spaces_key_prefix_idx") {
+			return nil, errors.NewBadParameterError("KeyPrefix", *p.KeyPrefix).Expected("unique")
+		}
 		log.Error(ctx, map[string]interface{}{
 			"err":      err,
 			"version":  oldVersion,
@@ -273,6 +288,12 @@ func (r *GormRepository) Create(ctx context.Context, space *Space) (*Space, erro
 			}, "unable to create space because a space with the same name already exists for this user")
 			return nil, errors.NewDataConflictError(fmt.Sprintf("space already exists ( for this user ) : %s ", space.Name))
 		}
+		if gormsupport.IsCheckViolation(tx.Error, "spaces_key_prefix_check") {
+			return nil, errors.NewBadParameterError("KeyPrefix", *space.KeyPrefix).Expected("2 to 10 upper case letters or digits, starting with a letter")
+		}
+		if gormsupport.IsUniqueViolation(tx.Error, "spaces_key_prefix_idx") {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("space key prefix already in use: %s", *space.KeyPrefix))
+		}
 		return nil, errors.NewInternalError(ctx, err)
 	}
 	log.Debug(ctx, map[string]interface{}{
@@ -0,0 +1,204 @@
+package spacetemplate
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Never ever change these UUIDs!!!
+var (
+	// ScrumTemplateID is the ID of the built-in Scrum template
+	ScrumTemplateID = uuid.FromStringOrNil("368c8024-c347-42d3-b27b-5bd8f30ca81d")
+	// KanbanTemplateID is the ID of the built-in Kanban template
+	KanbanTemplateID = uuid.FromStringOrNil("e34fed4c-88b6-47c1-9677-3d747415e2fd")
+)
+
+// scrumTemplate groups the system work item types the way the planner shows
+// them. Its type groups are the ones of the spaces that were created without
+// a template.
+const scrumTemplate = `
+name: Scrum
+description: Scenarios and experiences broken down into features and bugs that are planned in sprints.
+work_item_types:
+- id: 71171e90-6d35-498f-a6a7-2083b5267c18 # scenario
+  reuse: true
+- id: ee7ca005-f81d-4eea-9b9b-1965df0988d0 # fundamental
+  reuse: true
+- id: 6d603ab4-7c5e-4c5f-bba8-a3ba9d370985 # papercuts
+  reuse: true
+- id: b9a71831-c803-4f66-8774-4193fffd1311 # experience
+  reuse: true
+- id: 3194ab60-855b-4155-9005-9dce4a05f1eb # valueproposition
+  reuse: true
+- id: 0a24d3c2-e0a6-4686-8051-ec0ea1915a28 # feature
+  reuse: true
+- id: 26787039-b68f-4e28-8814-c2f93be1ef4e # bug
+  reuse: true
+- id: bbf35418-04b6-426c-a60b-7f80beb0b624 # task
+  reuse: true
+type_groups:
+# There can be more than one groups in the "Portfolio" bucket
+- id: feb28a28-44a6-43f8-946a-bae987713891
+  name: Scenarios
+  bucket: portfolio
+  icon: fa fa-bullseye
+  types:
+  - 71171e90-6d35-498f-a6a7-2083b5267c18
+  - ee7ca005-f81d-4eea-9b9b-1965df0988d0
+  - 6d603ab4-7c5e-4c5f-bba8-a3ba9d370985
+- id: d4e2c859-f416-4e9a-a3e0-e7bb4e1b454b
+  name: Experiences
+  bucket: portfolio
+  icon: pficon pficon-infrastructure
+  types:
+  - b9a71831-c803-4f66-8774-4193fffd1311
+  - 3194ab60-855b-4155-9005-9dce4a05f1eb
+# There's always only one group in the "Requirement" bucket
+- id: bb1de8b6-3175-4821-abe9-50d0a64f19a2
+  name: Requirements
+  bucket: requirement
+  icon: fa fa-list-ul
+  types:
+  - 0a24d3c2-e0a6-4686-8051-ec0ea1915a28
+  - 26787039-b68f-4e28-8814-c2f93be1ef4e
+# There's always only one group in the "Iteration" bucket
+- id: 7fdfde54-9cf2-4098-b33b-30cd505dcfc3
+  name: Execution
+  bucket: iteration
+  icon: fa fa-repeat
+  types:
+  - bbf35418-04b6-426c-a60b-7f80beb0b624
+  - 26787039-b68f-4e28-8814-c2f93be1ef4e
+  - 0a24d3c2-e0a6-4686-8051-ec0ea1915a28
+iterations:
+- Sprint 1
+`
+
+// kanbanTemplate organizes features, bugs and tasks on a single board
+// without sprints.
+const kanbanTemplate = `
+name: Kanban
+description: A continuous flow of features, bugs and tasks without sprints.
+work_item_types:
+- id: 0a24d3c2-e0a6-4686-8051-ec0ea1915a28 # feature
+  reuse: true
+- id: 26787039-b68f-4e28-8814-c2f93be1ef4e # bug
+  reuse: true
+- id: bbf35418-04b6-426c-a60b-7f80beb0b624 # task
+  reuse: true
+type_groups:
+- id: b4f4a244-3acd-4dae-b020-389e2897ff0b
+  name: Backlog
+  bucket: requirement
+  icon: fa fa-list-ul
+  types:
+  - 0a24d3c2-e0a6-4686-8051-ec0ea1915a28
+  - 26787039-b68f-4e28-8814-c2f93be1ef4e
+- id: feb33cf0-eaf2-47dd-812f-59aabb4a448f
+  name: Board
+  bucket: iteration
+  icon: fa fa-columns
+  types:
+  - bbf35418-04b6-426c-a60b-7f80beb0b624
+  - 26787039-b68f-4e28-8814-c2f93be1ef4e
+  - 0a24d3c2-e0a6-4686-8051-ec0ea1915a28
+labels:
+- name: expedite
+  text_color: "#FFFFFF"
+  background_color: "#CC0000"
+  border_color: "#CC0000"
+- name: blocked
+  text_color: "#000000"
+  background_color: "#F0AB00"
+  border_color: "#F0AB00"
+`
+
+var (
+	builtInTemplates     []SpaceTemplate
+	builtInTemplatesOnce sync.Once
+)
+
+// BuiltInTemplates returns the templates that ship with the service
+func BuiltInTemplates() []SpaceTemplate {
+	builtInTemplatesOnce.Do(func() {
+		for _, t := range []struct {
+			id       uuid.UUID
+			document string
+		}{
+			{ScrumTemplateID, scrumTemplate},
+			{KanbanTemplateID, kanbanTemplate},
+		} {
+			template, err := Parse([]byte(t.document))
+			if err != nil {
+				panic(fmt.Sprintf("invalid built-in space template %s: %+v", t.id, err))
+			}
+			builtInTemplates = append(builtInTemplates, SpaceTemplate{
+				ID:          t.id,
+				Name:        template.Name,
+				Version:     1,
+				Description: template.Description,
+				Template:    *template,
+				BuiltIn:     true,
+			})
+		}
+	})
+	result := make([]SpaceTemplate, len(builtInTemplates))
+	copy(result, builtInTemplates)
+	return result
+}
+
+// TypeGroups returns the list of work item type groups of the spaces that
+// were created without a template, which are the groups of the built-in
+// Scrum template
+func TypeGroups() []workitem.WorkItemTypeGroup {
+	for _, t := range BuiltInTemplates() {
+		if uuid.Equal(t.ID, ScrumTemplateID) {
+			return t.Template.BuiltInTypeGroups()
+		}
+	}
+	return nil
+}
+
+// TypeGroupByName returns a type group of the built-in templates based on its
+// name if such a group exists; otherwise nil is returned.
+func TypeGroupByName(name string) *workitem.WorkItemTypeGroup {
+	for _, t := range BuiltInTemplates() {
+		for _, g := range t.Template.BuiltInTypeGroups() {
+			if g.Name == name {
+				return &g
+			}
+		}
+	}
+	return nil
+}
+
+// TypeGroupsByBucket returns all type groups which fall into the given bucket
+func TypeGroupsByBucket(bucket workitem.TypeBucket) []workitem.WorkItemTypeGroup {
+	res := []workitem.WorkItemTypeGroup{}
+	for _, t := range TypeGroups() {
+		if t.Bucket == bucket {
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// BuiltInTypeGroups converts the type groups of a template that only reuses
+// existing work item types into work item type groups
+func (t Template) BuiltInTypeGroups() []workitem.WorkItemTypeGroup {
+	groups := make([]workitem.WorkItemTypeGroup, len(t.TypeGroups))
+	for i, g := range t.TypeGroups {
+		groups[i] = workitem.WorkItemTypeGroup{
+			ID:       g.ID,
+			Bucket:   g.Bucket,
+			Name:     g.Name,
+			Icon:     g.Icon,
+			TypeList: append(workitem.TypeList{}, g.Types...),
+			Position: i,
+		}
+	}
+	return groups
+}
@@ -0,0 +1,131 @@
+package spacetemplate
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// SpaceTemplate is a version of a template as it is stored in the db.
+// Importing a template with the name of an existing template creates a new
+// version of it.
+type SpaceTemplate struct {
+	gormsupport.Lifecycle
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	Name        string
+	Version     int
+	Description string
+	Template    Template `sql:"type:jsonb"`
+	// BuiltIn is true for the templates that ship with the service
+	BuiltIn bool `gorm:"-"`
+}
+
+// TableName implements gorm.tabler
+func (t SpaceTemplate) TableName() string {
+	return "space_templates"
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (t SpaceTemplate) GetETagData() []interface{} {
+	return []interface{}{t.ID, t.Version}
+}
+
+// GetLastModified returns the last modification time
+func (t SpaceTemplate) GetLastModified() time.Time {
+	return t.UpdatedAt.Truncate(time.Second)
+}
+
+// Repository encapsulates storage & retrieval of space templates
+type Repository interface {
+	Import(ctx context.Context, template Template) (*SpaceTemplate, error)
+	Load(ctx context.Context, id uuid.UUID) (*SpaceTemplate, error)
+	List(ctx context.Context) ([]SpaceTemplate, error)
+}
+
+// NewRepository creates a space template repository based on gorm
+func NewRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository implements Repository using gorm
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// Import stores the given template as the next version of the template with
+// the same name
+// returns BadParameterError, DataConflictError or InternalError
+func (r *GormRepository) Import(ctx context.Context, template Template) (*SpaceTemplate, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "spacetemplate", "import"}, time.Now())
+	if err := template.Validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	for _, builtIn := range BuiltInTemplates() {
+		if builtIn.Name == template.Name {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("the built-in template %s cannot be replaced", template.Name))
+		}
+	}
+	var latest struct {
+		Version int
+	}
+	if err := r.db.Table(SpaceTemplate{}.TableName()).Select("COALESCE(MAX(version), 0) AS version").Where("name = ?", template.Name).Scan(&latest).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	st := SpaceTemplate{
+		ID:          uuid.NewV4(),
+		Name:        template.Name,
+		Version:     latest.Version + 1,
+		Description: template.Description,
+		Template:    template,
+	}
+	if err := r.db.Create(&st).Error; err != nil {
+		if gormsupport.IsUniqueViolation(err, "space_templates_name_version_idx") {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("version %d of template %s was imported concurrently", st.Version, st.Name))
+		}
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	log.Debug(ctx, map[string]interface{}{"space_template_id": st.ID, "name": st.Name, "version": st.Version}, "space template imported")
+	return &st, nil
+}
+
+// Load returns the space template with the given ID, which can be one of the
+// built-in templates
+// returns NotFoundError or InternalError
+func (r *GormRepository) Load(ctx context.Context, id uuid.UUID) (*SpaceTemplate, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "spacetemplate", "load"}, time.Now())
+	for _, builtIn := range BuiltInTemplates() {
+		if uuid.Equal(builtIn.ID, id) {
+			return &builtIn, nil
+		}
+	}
+	st := SpaceTemplate{}
+	db := r.db.Where("id = ?", id).First(&st)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError("space template", id.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &st, nil
+}
+
+// List returns the built-in templates followed by all versions of the
+// imported templates
+// returns InternalError
+func (r *GormRepository) List(ctx context.Context) ([]SpaceTemplate, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "spacetemplate", "list"}, time.Now())
+	var imported []SpaceTemplate
+	if err := r.db.Order("name, version").Find(&imported).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return append(BuiltInTemplates(), imported...), nil
+}
@@ -0,0 +1,96 @@
+package spacetemplate_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestSpaceTemplateRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunSpaceTemplateRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestSpaceTemplateRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestSpaceTemplateRepository) TestImport() {
+	repo := spacetemplate.NewRepository(s.DB)
+	name := "TestImport-" + uuid.NewV4().String()
+
+	s.T().Run("new versions", func(t *testing.T) {
+		// when
+		first, err := repo.Import(context.Background(), spacetemplate.Template{Name: name, Description: "first"})
+		require.NoError(t, err)
+		second, err := repo.Import(context.Background(), spacetemplate.Template{Name: name, Description: "second", Areas: []string{"ui"}})
+		require.NoError(t, err)
+		// then
+		assert.Equal(t, 1, first.Version)
+		assert.Equal(t, 2, second.Version)
+		loaded, err := repo.Load(context.Background(), second.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "second", loaded.Description)
+		assert.Equal(t, []string{"ui"}, loaded.Template.Areas)
+		assert.False(t, loaded.BuiltIn)
+	})
+
+	s.T().Run("built-in name", func(t *testing.T) {
+		_, err := repo.Import(context.Background(), spacetemplate.Template{Name: "Scrum"})
+		require.Error(t, err)
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+
+	s.T().Run("invalid template", func(t *testing.T) {
+		_, err := repo.Import(context.Background(), spacetemplate.Template{})
+		require.Error(t, err)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestSpaceTemplateRepository) TestLoad() {
+	repo := spacetemplate.NewRepository(s.DB)
+
+	s.T().Run("built-in", func(t *testing.T) {
+		st, err := repo.Load(context.Background(), spacetemplate.KanbanTemplateID)
+		require.NoError(t, err)
+		assert.True(t, st.BuiltIn)
+		assert.Equal(t, "Kanban", st.Name)
+	})
+
+	s.T().Run("not found", func(t *testing.T) {
+		_, err := repo.Load(context.Background(), uuid.NewV4())
+		require.Error(t, err)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *TestSpaceTemplateRepository) TestList() {
+	// given
+	repo := spacetemplate.NewRepository(s.DB)
+	imported, err := repo.Import(context.Background(), spacetemplate.Template{Name: "TestList-" + uuid.NewV4().String()})
+	require.NoError(s.T(), err)
+	// when
+	templates, err := repo.List(context.Background())
+	// then
+	require.NoError(s.T(), err)
+	require.True(s.T(), len(templates) >= 3)
+	assert.Equal(s.T(), spacetemplate.ScrumTemplateID, templates[0].ID)
+	assert.Equal(s.T(), spacetemplate.KanbanTemplateID, templates[1].ID)
+	found := false
+	for _, st := range templates[2:] {
+		if uuid.Equal(st.ID, imported.ID) {
+			found = true
+		}
+	}
+	assert.True(s.T(), found)
+}
@@ -0,0 +1,206 @@
+package spacetemplate
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+
+	"github.com/ghodss/yaml"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Template describes everything that is set up in a space when the template
+// is applied to it. Templates are written as YAML or JSON documents.
+type Template struct {
+	Name          string         `json:"name"`
+	Description   string         `json:"description,omitempty"`
+	WorkItemTypes []WorkItemType `json:"work_item_types,omitempty"`
+	TypeGroups    []TypeGroup    `json:"type_groups,omitempty"`
+	LinkTypes     []LinkType     `json:"link_types,omitempty"`
+	// Areas are the names of the areas created below the root area
+	Areas []string `json:"areas,omitempty"`
+	// Iterations are the names of the iterations created below the root
+	// iteration
+	Iterations []string `json:"iterations,omitempty"`
+	Labels     []Label  `json:"labels,omitempty"`
+}
+
+// WorkItemType describes a work item type of a template. The ID identifies
+// the type within the template, the type created in a space gets a new ID.
+type WorkItemType struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Icon        string    `json:"icon,omitempty"`
+	// Reuse references the existing work item type with the given ID (e.g.
+	// one of the system types) instead of creating a new type in the space
+	Reuse bool `json:"reuse,omitempty"`
+	// Extends is the ID of another type of the template or of an existing
+	// work item type whose fields (workflow and roll-ups) are inherited
+	Extends  *uuid.UUID                          `json:"extends,omitempty"`
+	Fields   map[string]workitem.FieldDefinition `json:"fields,omitempty"`
+	Workflow *workitem.Workflow                  `json:"workflow,omitempty"`
+	RollUps  workitem.RollUps                    `json:"rollups,omitempty"`
+}
+
+// TypeGroup describes a group of work item types of a template
+type TypeGroup struct {
+	// ID is only used for the type groups of the built-in templates
+	ID     uuid.UUID           `json:"id,omitempty"`
+	Name   string              `json:"name"`
+	Bucket workitem.TypeBucket `json:"bucket"`
+	Icon   string              `json:"icon,omitempty"`
+	// Types are the IDs of the work item types of the template in the group
+	Types []uuid.UUID `json:"types"`
+}
+
+// LinkType describes a work item link type of a template
+type LinkType struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Topology    link.Topology `json:"topology"`
+	ForwardName string        `json:"forward_name"`
+	ReverseName string        `json:"reverse_name"`
+	// CategoryID is the ID of an existing link category, the user category is
+	// used if none is given
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+}
+
+// Label describes a label of a template
+type Label struct {
+	Name            string `json:"name"`
+	TextColor       string `json:"text_color,omitempty"`
+	BackgroundColor string `json:"background_color,omitempty"`
+	BorderColor     string `json:"border_color,omitempty"`
+}
+
+// Parse reads a template from the given YAML or JSON document and validates
+// it.
+// returns BadParameterError
+func Parse(document []byte) (*Template, error) {
+	t := Template{}
+	if err := yaml.Unmarshal(document, &t); err != nil {
+		return nil, errors.NewBadParameterError("template", err.Error()).Expected("a YAML or JSON space template")
+	}
+	if err := t.Validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	return &t, nil
+}
+
+// Validate checks that the template is consistent. Whether the referenced
+// existing work item types and link categories exist is only checked when the
+// template is applied.
+// returns BadParameterError
+func (t Template) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.NewBadParameterError("name", t.Name).Expected("a non-empty template name")
+	}
+	types := map[uuid.UUID]WorkItemType{}
+	for _, wit := range t.WorkItemTypes {
+		if uuid.Equal(wit.ID, uuid.Nil) {
+			return errors.NewBadParameterError("work_item_types.id", wit.ID).Expected("a work item type ID")
+		}
+		if _, exists := types[wit.ID]; exists {
+			return errors.NewBadParameterError("work_item_types.id", wit.ID).Expected("unique work item type IDs")
+		}
+		if !wit.Reuse && strings.TrimSpace(wit.Name) == "" {
+			return errors.NewBadParameterError("work_item_types.name", wit.Name).Expected("a non-empty work item type name")
+		}
+		types[wit.ID] = wit
+	}
+	// extended types of the template must not form a cycle
+	for _, wit := range t.WorkItemTypes {
+		visited := map[uuid.UUID]bool{wit.ID: true}
+		for current := wit; current.Extends != nil; {
+			next, ok := types[*current.Extends]
+			if !ok {
+				break
+			}
+			if visited[next.ID] {
+				return errors.NewBadParameterError("work_item_types.extends", wit.ID).Expected("no cycle of extended types")
+			}
+			visited[next.ID] = true
+			current = next
+		}
+	}
+	for _, g := range t.TypeGroups {
+		if strings.TrimSpace(g.Name) == "" {
+			return errors.NewBadParameterError("type_groups.name", g.Name).Expected("a non-empty type group name")
+		}
+		switch g.Bucket {
+		case workitem.BucketPortfolio, workitem.BucketRequirement, workitem.BucketIteration:
+		default:
+			return errors.NewBadParameterError("type_groups.bucket", g.Bucket).Expected(strings.Join([]string{workitem.BucketPortfolio.String(), workitem.BucketRequirement.String(), workitem.BucketIteration.String()}, ", "))
+		}
+		for _, id := range g.Types {
+			if _, ok := types[id]; !ok {
+				return errors.NewBadParameterError("type_groups.types", id).Expected("a work item type of the template")
+			}
+		}
+	}
+	for _, lt := range t.LinkTypes {
+		if strings.TrimSpace(lt.Name) == "" {
+			return errors.NewBadParameterError("link_types.name", lt.Name).Expected("a non-empty link type name")
+		}
+		if err := lt.Topology.CheckValid(); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	for _, l := range t.Labels {
+		if strings.TrimSpace(l.Name) == "" {
+			return errors.NewBadParameterError("labels.name", l.Name).Expected("a non-empty label name")
+		}
+	}
+	return nil
+}
+
+// SortedWorkItemTypes returns the work item types of the template so that
+// every type comes after the type of the template it extends
+func (t Template) SortedWorkItemTypes() []WorkItemType {
+	result := make([]WorkItemType, 0, len(t.WorkItemTypes))
+	added := map[uuid.UUID]bool{}
+	types := map[uuid.UUID]WorkItemType{}
+	for _, wit := range t.WorkItemTypes {
+		types[wit.ID] = wit
+	}
+	var add func(wit WorkItemType)
+	add = func(wit WorkItemType) {
+		if added[wit.ID] {
+			return
+		}
+		added[wit.ID] = true
+		if wit.Extends != nil {
+			if extended, ok := types[*wit.Extends]; ok {
+				add(extended)
+			}
+		}
+		result = append(result, wit)
+	}
+	for _, wit := range t.WorkItemTypes {
+		add(wit)
+	}
+	return result
+}
+
+// Value implements the driver.Valuer interface
+func (t Template) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// Scan implements the sql.Scanner interface
+func (t *Template) Scan(src interface{}) error {
+	if src == nil {
+		return nil
+	}
+	b, ok := src.([]byte)
+	if !ok {
+		return errs.Errorf("scan source was not []byte but %T", src)
+	}
+	return json.Unmarshal(b, t)
+}
@@ -0,0 +1,138 @@
+package spacetemplate_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/spacetemplate"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("ok", func(t *testing.T) {
+		t.Parallel()
+		// given
+		document := `
+name: Support
+work_item_types:
+- id: 8fd7b7d2-4bf7-4d0f-8a4b-6f3ad4c7a1a1
+  name: Incident
+  extends: 00000000-0000-0000-0000-000000000001
+- id: 26787039-b68f-4e28-8814-c2f93be1ef4e
+  reuse: true
+type_groups:
+- name: Queue
+  bucket: requirement
+  types:
+  - 8fd7b7d2-4bf7-4d0f-8a4b-6f3ad4c7a1a1
+  - 26787039-b68f-4e28-8814-c2f93be1ef4e
+link_types:
+- name: duplicates
+  topology: network
+  forward_name: duplicates
+  reverse_name: is duplicated by
+areas:
+- Frontend
+labels:
+- name: urgent
+`
+		// when
+		tmpl, err := spacetemplate.Parse([]byte(document))
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Support", tmpl.Name)
+		require.Len(t, tmpl.WorkItemTypes, 2)
+		assert.Equal(t, "Incident", tmpl.WorkItemTypes[0].Name)
+		assert.True(t, tmpl.WorkItemTypes[1].Reuse)
+		require.Len(t, tmpl.TypeGroups, 1)
+		assert.Equal(t, workitem.BucketRequirement, tmpl.TypeGroups[0].Bucket)
+		assert.Equal(t, []string{"Frontend"}, tmpl.Areas)
+		assert.Equal(t, "urgent", tmpl.Labels[0].Name)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+		witID := "8fd7b7d2-4bf7-4d0f-8a4b-6f3ad4c7a1a1"
+		for name, document := range map[string]string{
+			"not yaml":      "name: [",
+			"no name":       "description: foo",
+			"no type name":  "name: x\nwork_item_types:\n- id: " + witID,
+			"duplicate id":  "name: x\nwork_item_types:\n- id: " + witID + "\n  reuse: true\n- id: " + witID + "\n  reuse: true",
+			"extends cycle": "name: x\nwork_item_types:\n- id: " + witID + "\n  name: a\n  extends: " + witID,
+			"bad bucket":    "name: x\ntype_groups:\n- name: g\n  bucket: foo\n  types: []",
+			"unknown type":  "name: x\ntype_groups:\n- name: g\n  bucket: iteration\n  types:\n  - " + witID,
+			"bad topology":  "name: x\nlink_types:\n- name: l\n  topology: foo",
+		} {
+			document := document
+			t.Run(name, func(t *testing.T) {
+				t.Parallel()
+				_, err := spacetemplate.Parse([]byte(document))
+				require.Error(t, err)
+				require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+			})
+		}
+	})
+}
+
+func TestSortedWorkItemTypes(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// given
+	baseID := uuid.NewV4()
+	childID := uuid.NewV4()
+	tmpl := spacetemplate.Template{
+		Name: "x",
+		WorkItemTypes: []spacetemplate.WorkItemType{
+			{ID: childID, Name: "child", Extends: &baseID},
+			{ID: baseID, Name: "base"},
+		},
+	}
+	// when
+	sorted := tmpl.SortedWorkItemTypes()
+	// then
+	require.Len(t, sorted, 2)
+	assert.Equal(t, baseID, sorted[0].ID)
+	assert.Equal(t, childID, sorted[1].ID)
+}
+
+func TestBuiltInTemplates(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	templates := spacetemplate.BuiltInTemplates()
+	require.Len(t, templates, 2)
+	assert.Equal(t, spacetemplate.ScrumTemplateID, templates[0].ID)
+	assert.Equal(t, "Scrum", templates[0].Name)
+	assert.Equal(t, spacetemplate.KanbanTemplateID, templates[1].ID)
+	assert.Equal(t, "Kanban", templates[1].Name)
+	for _, st := range templates {
+		assert.True(t, st.BuiltIn)
+		assert.Equal(t, 1, st.Version)
+	}
+
+	t.Run("type groups of spaces without template", func(t *testing.T) {
+		t.Parallel()
+		groups := spacetemplate.TypeGroups()
+		require.Len(t, groups, 4)
+		assert.Equal(t, "feb28a28-44a6-43f8-946a-bae987713891", groups[0].ID.String())
+		assert.Equal(t, []uuid.UUID{workitem.SystemScenario, workitem.SystemFundamental, workitem.SystemPapercuts}, []uuid.UUID(groups[0].TypeList))
+		assert.Equal(t, "bb1de8b6-3175-4821-abe9-50d0a64f19a2", groups[2].ID.String())
+		assert.Equal(t, []uuid.UUID{workitem.SystemFeature, workitem.SystemBug}, []uuid.UUID(groups[2].TypeList))
+		assert.Len(t, spacetemplate.TypeGroupsByBucket(workitem.BucketPortfolio), 2)
+	})
+
+	t.Run("type group by name", func(t *testing.T) {
+		t.Parallel()
+		require.NotNil(t, spacetemplate.TypeGroupByName("Execution"))
+		require.NotNil(t, spacetemplate.TypeGroupByName("Board"))
+		require.Nil(t, spacetemplate.TypeGroupByName("foo"))
+	})
+}
@@ -216,9 +216,9 @@ func newFixture(db *gorm.DB, isolatedCreation bool, recipeFuncs ...RecipeFunctio
 		// make the objects that DON'T have any dependency
 		makeIdentities,
 		makeTrackers,
-		makeWorkItemLinkCategories,
 		// actually make the objects that DO have dependencies
 		makeSpaces,
+		makeWorkItemLinkCategories,
 		makeLabels,
 		makeQueries,
 		makeWorkItemLinkTypes,
@@ -0,0 +1,103 @@
+package webhook
+
+import (
+	"context"
+	"net"
+	"net/url"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	errs "github.com/pkg/errors"
+)
+
+// privateNetworks are the address ranges that are only reachable from within
+// the cluster or the host, in addition to the loopback, link-local and
+// unspecified addresses
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"10.0.0.0/8",
+		"172.16.0.0/12",
+		"192.168.0.0/16",
+		"100.64.0.0/10", // carrier-grade NAT
+		"fc00::/7",      // unique local addresses
+	} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsPublicAddress returns true if the given IP address can be the target of a
+// webhook. Loopback, link-local (e.g. the 169.254.169.254 metadata endpoint of
+// cloud providers), private, multicast and unspecified addresses are refused
+// so that webhooks cannot be used to reach internal services.
+func IsPublicAddress(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePublicAddress returns the addresses of the given host and an error
+// if the host cannot be resolved or if any of its addresses is not public
+func resolvePublicAddress(ctx context.Context, host string) ([]net.IPAddr, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to resolve host %s", host)
+	}
+	if len(addrs) == 0 {
+		return nil, errs.Errorf("no address found for host %s", host)
+	}
+	for _, addr := range addrs {
+		if !IsPublicAddress(addr.IP) {
+			return nil, errs.Errorf("host %s resolves to the non-public address %s", host, addr.IP)
+		}
+	}
+	return addrs, nil
+}
+
+// ValidateHost resolves the host of the webhook URL and checks that all its
+// addresses are public (see IsPublicAddress). The addresses are checked again
+// on every delivery since the DNS records can change after the webhook was
+// saved.
+// returns BadParameterError
+func (m Webhook) ValidateHost(ctx context.Context) error {
+	u, err := url.Parse(m.URL)
+	if err != nil || u.Hostname() == "" {
+		return errors.NewBadParameterError("url", m.URL).Expected("absolute http or https URL")
+	}
+	if _, err := resolvePublicAddress(ctx, u.Hostname()); err != nil {
+		return errors.NewBadParameterError("url", m.URL).Expected("a URL whose host resolves to public addresses only")
+	}
+	return nil
+}
+
+// publicDialer connects to public addresses only. The host is resolved and
+// checked right before the connection is made, which also covers redirects
+// and DNS records that changed since the webhook was saved.
+type publicDialer struct {
+	dialer *net.Dialer
+}
+
+// DialContext resolves the host of the given address and connects to the
+// first of its addresses if all of them are public
+func (d publicDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errs.Wrapf(err, "invalid address %s", address)
+	}
+	addrs, err := resolvePublicAddress(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	return d.dialer.DialContext(ctx, network, net.JoinHostPort(addrs[0].IP.String(), port))
+}
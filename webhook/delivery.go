@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	uuid "github.com/satori/go.uuid"
+)
+
+// DeliveryStatus describes the state of a webhook delivery
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending marks deliveries that still have to be sent
+	DeliveryStatusPending DeliveryStatus = "pending"
+	// DeliveryStatusDelivered marks deliveries the webhook accepted
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusFailed marks deliveries that exceeded the maximum number of attempts
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// Delivery records the payload of an event sent to a webhook together with
+// the outcome of the delivery attempts
+type Delivery struct {
+	gormsupport.Lifecycle
+	ID             uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	WebhookID      uuid.UUID `sql:"type:uuid"`
+	EventID        uuid.UUID `sql:"type:uuid"`
+	EventType      string
+	Payload        string `sql:"type:jsonb"`
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus *int
+	LastError      *string
+	DeliveredAt    *time.Time
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// DeliveryRepository describes interactions with webhook deliveries
+type DeliveryRepository interface {
+	Create(ctx context.Context, d *Delivery) error
+	List(ctx context.Context, webhookID uuid.UUID, start int, limit int) ([]Delivery, int, error)
+	ClaimDue(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]Delivery, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID, responseStatus int) error
+	MarkFailed(ctx context.Context, id uuid.UUID, responseStatus *int, cause error, nextAttemptAt *time.Time) error
+}
+
+// NewDeliveryRepository creates a new storage type.
+func NewDeliveryRepository(db *gorm.DB) DeliveryRepository {
+	return &GormDeliveryRepository{db: db}
+}
+
+// GormDeliveryRepository is the implementation of the storage interface for
+// webhook deliveries.
+type GormDeliveryRepository struct {
+	db *gorm.DB
+}
+
+// Create stores a new pending delivery
+func (r *GormDeliveryRepository) Create(ctx context.Context, d *Delivery) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook_delivery", "create"}, time.Now())
+	d.ID = uuid.NewV4()
+	d.Status = DeliveryStatusPending
+	d.Attempts = 0
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	if err := r.db.Create(d).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": d.WebhookID,
+			"event_type": d.EventType,
+			"err":        err,
+		}, "unable to store the webhook delivery")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// List returns the deliveries of a webhook, most recent first, together with
+// their total count
+func (r *GormDeliveryRepository) List(ctx context.Context, webhookID uuid.UUID, start int, limit int) ([]Delivery, int, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook_delivery", "list"}, time.Now())
+	var count int
+	db := r.db.Model(&Delivery{}).Where("webhook_id = ?", webhookID)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, errors.NewInternalError(ctx, err)
+	}
+	var objs []Delivery
+	err := db.Order("created_at desc").Offset(start).Limit(limit).Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, 0, errors.NewInternalError(ctx, err)
+	}
+	return objs, count, nil
+}
+
+// ClaimDue returns up to limit pending deliveries whose next attempt is due
+// and leases them by moving their next attempt to leaseUntil. Rows locked by
+// other dispatchers are skipped. Once the surrounding transaction commits, the
+// claimed deliveries are not returned again before the lease expires, so they
+// can be sent outside of a transaction.
+func (r *GormDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, limit int, leaseUntil time.Time) ([]Delivery, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook_delivery", "claimdue"}, time.Now())
+	var objs []Delivery
+	err := r.db.Set("gorm:query_option", "FOR UPDATE SKIP LOCKED").
+		Where("status = ? AND next_attempt_at <= ?", DeliveryStatusPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if len(objs) == 0 {
+		return objs, nil
+	}
+	ids := make([]uuid.UUID, len(objs))
+	for i, d := range objs {
+		ids[i] = d.ID
+	}
+	err = r.db.Model(&Delivery{}).Where("id IN (?)", ids).UpdateColumn("next_attempt_at", leaseUntil).Error
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": err,
+		}, "unable to lease due webhook deliveries")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// MarkDelivered records the successful delivery
+func (r *GormDeliveryRepository) MarkDelivered(ctx context.Context, id uuid.UUID, responseStatus int) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook_delivery", "delivered"}, time.Now())
+	tx := r.db.Model(&Delivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":          DeliveryStatusDelivered,
+		"attempts":        gorm.Expr("attempts + 1"),
+		"response_status": responseStatus,
+		"delivered_at":    time.Now(),
+		"last_error":      nil,
+	})
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("webhook delivery", id.String())
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt. When nextAttemptAt is nil the
+// delivery won't be retried.
+func (r *GormDeliveryRepository) MarkFailed(ctx context.Context, id uuid.UUID, responseStatus *int, cause error, nextAttemptAt *time.Time) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook_delivery", "failed"}, time.Now())
+	updates := map[string]interface{}{
+		"attempts":        gorm.Expr("attempts + 1"),
+		"response_status": responseStatus,
+		"last_error":      cause.Error(),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["status"] = DeliveryStatusFailed
+	}
+	tx := r.db.Model(&Delivery{}).Where("id = ?", id).Updates(updates)
+	if tx.Error != nil {
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("webhook delivery", id.String())
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/models"
+	"github.com/fabric8-services/fabric8-wit/notification/outbox"
+	"github.com/fabric8-services/fabric8-wit/rest"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+)
+
+// The HTTP headers set on every request sent to a webhook
+const (
+	HeaderEvent     = "X-WIT-Event"
+	HeaderDelivery  = "X-WIT-Delivery"
+	HeaderSignature = "X-WIT-Signature"
+)
+
+// Sign returns the hex encoded HMAC-SHA256 signature of the payload computed
+// with the secret of the webhook, prefixed with "sha256=".
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// DispatcherConfiguration holds the options that control the delivery of the
+// webhook payloads
+type DispatcherConfiguration interface {
+	GetWebhookDispatchInterval() time.Duration
+	GetWebhookDispatchBatchSize() int
+	GetWebhookMaxDeliveryAttempts() int
+	GetWebhookRetryBackoff() time.Duration
+	GetWebhookMaxRetryBackoff() time.Duration
+	GetWebhookHTTPTimeout() time.Duration
+	GetWebhookAllowPrivateAddresses() bool
+}
+
+// Dispatcher periodically posts the pending deliveries to their webhooks.
+// Failed deliveries are retried with an exponential backoff until the
+// maximum number of attempts is reached. Several dispatchers can run
+// concurrently against the same database.
+type Dispatcher struct {
+	db     *gorm.DB
+	client *http.Client
+	config DispatcherConfiguration
+	stop   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a webhook dispatcher. Unless private addresses are
+// allowed, the payloads are only sent to public addresses.
+func NewDispatcher(db *gorm.DB, config DispatcherConfiguration) *Dispatcher {
+	dialer := &net.Dialer{Timeout: config.GetWebhookHTTPTimeout()}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: config.GetWebhookHTTPTimeout(),
+	}
+	if !config.GetWebhookAllowPrivateAddresses() {
+		transport.DialContext = publicDialer{dialer: dialer}.DialContext
+	}
+	return &Dispatcher{
+		db:     db,
+		client: &http.Client{Timeout: config.GetWebhookHTTPTimeout(), Transport: transport},
+		config: config,
+	}
+}
+
+// Start runs the dispatcher in the background until Stop is called
+func (d *Dispatcher) Start() {
+	d.stop = make(chan struct{})
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.config.GetWebhookDispatchInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				if _, err := d.DispatchDue(context.Background()); err != nil {
+					log.Error(nil, map[string]interface{}{
+						"err": err,
+					}, "failed to dispatch webhook deliveries")
+				}
+			}
+		}
+	}()
+}
+
+// Stop ends the background dispatching and waits for a running batch to
+// complete
+func (d *Dispatcher) Stop() {
+	if d.stop == nil {
+		return
+	}
+	close(d.stop)
+	d.wg.Wait()
+	d.stop = nil
+}
+
+// dispatchedDelivery is a claimed delivery together with its webhook and the
+// outcome of sending it
+type dispatchedDelivery struct {
+	delivery Delivery
+	webhook  Webhook
+	status   *int
+	err      error
+}
+
+// DispatchDue sends one batch of due deliveries and returns the number of
+// successful ones. The batch is claimed in a short transaction, the payloads
+// are sent outside of any transaction and the outcome is recorded in a second
+// transaction, so that no database connection and no row lock is held while
+// waiting for the webhooks.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	batchSize := d.config.GetWebhookDispatchBatchSize()
+	// the lease must outlast the delivery of the whole batch
+	leaseUntil := now.Add(time.Duration(batchSize+1) * d.config.GetWebhookHTTPTimeout())
+	var dispatched []dispatchedDelivery
+	err := models.Transactional(d.db, func(tx *gorm.DB) error {
+		repo := NewDeliveryRepository(tx)
+		deliveries, err := repo.ClaimDue(ctx, now, batchSize, leaseUntil)
+		if err != nil {
+			return errs.Wrap(err, "failed to claim due webhook deliveries")
+		}
+		for _, delivery := range deliveries {
+			w := Webhook{}
+			if err := tx.Unscoped().Where("id = ?", delivery.WebhookID).First(&w).Error; err != nil {
+				return errs.Wrapf(err, "failed to load webhook %s", delivery.WebhookID)
+			}
+			if w.DeletedAt != nil || !w.Active {
+				if err := repo.MarkFailed(ctx, delivery.ID, nil, errs.New("webhook is no longer active"), nil); err != nil {
+					return err
+				}
+				continue
+			}
+			dispatched = append(dispatched, dispatchedDelivery{delivery: delivery, webhook: w})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(dispatched) == 0 {
+		return 0, nil
+	}
+
+	for i := range dispatched {
+		dispatched[i].status, dispatched[i].err = d.send(ctx, dispatched[i].webhook, dispatched[i].delivery)
+	}
+
+	delivered := 0
+	err = models.Transactional(d.db, func(tx *gorm.DB) error {
+		repo := NewDeliveryRepository(tx)
+		failedAt := time.Now()
+		for _, dd := range dispatched {
+			if dd.err == nil {
+				if err := repo.MarkDelivered(ctx, dd.delivery.ID, *dd.status); err != nil {
+					return err
+				}
+				delivered++
+				continue
+			}
+			attempt := dd.delivery.Attempts + 1
+			var nextAttemptAt *time.Time
+			if attempt < d.config.GetWebhookMaxDeliveryAttempts() {
+				next := failedAt.Add(outbox.RetryBackoff(attempt, d.config.GetWebhookRetryBackoff(), d.config.GetWebhookMaxRetryBackoff()))
+				nextAttemptAt = &next
+			}
+			log.Warn(ctx, map[string]interface{}{
+				"webhook_id":  dd.webhook.ID,
+				"delivery_id": dd.delivery.ID,
+				"attempt":     attempt,
+				"err":         dd.err,
+			}, "failed to deliver webhook payload")
+			if err := repo.MarkFailed(ctx, dd.delivery.ID, dd.status, dd.err, nextAttemptAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// the claimed deliveries are retried once their lease expires
+		return 0, errs.Wrap(err, "failed to record the outcome of webhook deliveries")
+	}
+	return delivered, nil
+}
+
+// send posts the payload of the delivery to the webhook and returns the
+// response status, if any
+func (d *Dispatcher) send(ctx context.Context, w Webhook, delivery Delivery) (*int, error) {
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to create request for webhook %s", w.ID)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+	req.Header.Set(HeaderSignature, Sign(w.Secret, payload))
+	resp, err := d.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to post to webhook %s", w.ID)
+	}
+	defer rest.CloseResponse(resp)
+	status := resp.StatusCode
+	if status < 200 || status >= 300 {
+		return &status, errs.Errorf("webhook %s responded with status %d", w.ID, status)
+	}
+	return &status, nil
+}
@@ -0,0 +1,274 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeWebhooks helps to avoid string literal
+const APIStringTypeWebhooks = "webhooks"
+
+// The events a webhook can subscribe to
+const (
+	EventWorkItemCreate = "workitem.create"
+	EventWorkItemUpdate = "workitem.update"
+	EventCommentCreate  = "comment.create"
+	EventLinkCreate     = "link.create"
+	EventLinkDelete     = "link.delete"
+)
+
+// KnownEvents lists all events a webhook can subscribe to
+var KnownEvents = []string{
+	EventWorkItemCreate,
+	EventWorkItemUpdate,
+	EventCommentCreate,
+	EventLinkCreate,
+	EventLinkDelete,
+}
+
+// Events is the list of events a webhook subscribed to
+type Events []string
+
+// Contains returns true if the given event is part of the list
+func (e Events) Contains(event string) bool {
+	for _, ev := range e {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements the driver.Valuer interface
+func (e Events) Value() (driver.Value, error) {
+	if e == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements the sql.Scanner interface
+func (e *Events) Scan(src interface{}) error {
+	if src == nil {
+		*e = nil
+		return nil
+	}
+	s, ok := src.([]byte)
+	if !ok {
+		return errs.New("Scan source was not []byte")
+	}
+	return json.Unmarshal(s, e)
+}
+
+// Webhook describes an HTTP endpoint that gets notified about events in a
+// space
+type Webhook struct {
+	gormsupport.Lifecycle
+	ID      uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"` // This is the ID PK field
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	URL     string
+	Secret  string
+	Events  Events `sql:"type:jsonb"`
+	Active  bool
+	Creator uuid.UUID `sql:"type:uuid"`
+	Version int
+}
+
+// GetETagData returns the field values to use to generate the ETag
+func (m Webhook) GetETagData() []interface{} {
+	return []interface{}{m.ID, m.Version}
+}
+
+// GetLastModified returns the last modification time
+func (m Webhook) GetLastModified() time.Time {
+	return m.UpdatedAt.Truncate(time.Second)
+}
+
+// TableName overrides the table name settings in Gorm to force a specific table name
+// in the database.
+func (m Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Validate checks the URL and the events of the webhook
+func (m Webhook) Validate() error {
+	u, err := url.Parse(m.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NewBadParameterError("url", m.URL).Expected("absolute http or https URL")
+	}
+	if len(m.Events) == 0 {
+		return errors.NewBadParameterError("events", m.Events).Expected("at least one of " + strings.Join(KnownEvents, ", "))
+	}
+	for _, ev := range m.Events {
+		if !Events(KnownEvents).Contains(ev) {
+			return errors.NewBadParameterError("events", ev).Expected("one of " + strings.Join(KnownEvents, ", "))
+		}
+	}
+	return nil
+}
+
+// NewSecret returns a random secret used to sign the payloads sent to a
+// webhook
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errs.Wrap(err, "failed to generate webhook secret")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Repository describes interactions with webhooks
+type Repository interface {
+	Create(ctx context.Context, w *Webhook) error
+	Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Webhook, error)
+	List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error)
+	ListForEvent(ctx context.Context, spaceID uuid.UUID, event string) ([]Webhook, error)
+	Save(ctx context.Context, w Webhook) (*Webhook, error)
+	Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error
+}
+
+// NewRepository creates a new storage type.
+func NewRepository(db *gorm.DB) Repository {
+	return &GormRepository{db: db}
+}
+
+// GormRepository is the implementation of the storage interface for webhooks.
+type GormRepository struct {
+	db *gorm.DB
+}
+
+// Create a new webhook. A secret is generated if none was given.
+func (r *GormRepository) Create(ctx context.Context, w *Webhook) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "create"}, time.Now())
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if w.Secret == "" {
+		secret, err := NewSecret()
+		if err != nil {
+			return errors.NewInternalError(ctx, err)
+		}
+		w.Secret = secret
+	}
+	w.ID = uuid.NewV4()
+	if err := r.db.Create(w).Error; err != nil {
+		if gormsupport.IsForeignKeyViolation(err, "webhooks_space_id_fkey") {
+			return errors.NewNotFoundError("space", w.SpaceID.String())
+		}
+		log.Error(ctx, map[string]interface{}{
+			"space_id": w.SpaceID,
+			"err":      err,
+		}, "unable to create the webhook")
+		return errors.NewInternalError(ctx, err)
+	}
+	return nil
+}
+
+// Load a webhook of a space
+func (r *GormRepository) Load(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "load"}, time.Now())
+	w := Webhook{}
+	tx := r.db.Where("id = ? AND space_id = ?", id, spaceID).First(&w)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("webhook", id.String())
+	}
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": id,
+			"err":        tx.Error,
+		}, "unable to load the webhook")
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	return &w, nil
+}
+
+// List all webhooks of a space
+func (r *GormRepository) List(ctx context.Context, spaceID uuid.UUID) ([]Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "list"}, time.Now())
+	var objs []Webhook
+	err := r.db.Where("space_id = ?", spaceID).Order("created_at").Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return objs, nil
+}
+
+// ListForEvent returns the active webhooks of a space that subscribed to the
+// given event
+func (r *GormRepository) ListForEvent(ctx context.Context, spaceID uuid.UUID, event string) ([]Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "listforevent"}, time.Now())
+	var objs []Webhook
+	err := r.db.Where("space_id = ? AND active = ?", spaceID, true).Find(&objs).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	res := []Webhook{}
+	for _, w := range objs {
+		if w.Events.Contains(event) {
+			res = append(res, w)
+		}
+	}
+	return res, nil
+}
+
+// Save updates the given webhook
+func (r *GormRepository) Save(ctx context.Context, w Webhook) (*Webhook, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "save"}, time.Now())
+	if err := w.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := r.Load(ctx, w.SpaceID, w.ID)
+	if err != nil {
+		return nil, err
+	}
+	if w.Secret == "" {
+		w.Secret = existing.Secret
+	}
+	w.Creator = existing.Creator
+	w.CreatedAt = existing.CreatedAt
+	oldVersion := w.Version
+	w.Version = existing.Version + 1
+	tx := r.db.Where("Version = ?", oldVersion).Save(&w)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": w.ID,
+			"err":        err,
+		}, "unable to save the webhook")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	return &w, nil
+}
+
+// Delete removes the webhook of a space
+func (r *GormRepository) Delete(ctx context.Context, spaceID uuid.UUID, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "webhook", "delete"}, time.Now())
+	tx := r.db.Where("id = ? AND space_id = ?", id, spaceID).Delete(&Webhook{})
+	if tx.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"webhook_id": id,
+			"err":        tx.Error,
+		}, "unable to delete the webhook")
+		return errors.NewInternalError(ctx, tx.Error)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("webhook", id.String())
+	}
+	return nil
+}
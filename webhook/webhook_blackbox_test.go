@@ -0,0 +1,247 @@
+package webhook_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/webhook"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type TestWebhookRepository struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunWebhookRepository(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &TestWebhookRepository{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *TestWebhookRepository) createWebhook(spaceID uuid.UUID, url string, events ...string) webhook.Webhook {
+	w := webhook.Webhook{
+		SpaceID: spaceID,
+		URL:     url,
+		Events:  events,
+		Active:  true,
+		Creator: uuid.NewV4(),
+	}
+	require.NoError(s.T(), webhook.NewRepository(s.DB).Create(context.Background(), &w))
+	return w
+}
+
+func (s *TestWebhookRepository) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := s.createWebhook(fxt.Spaces[0].ID, "https://example.com/hook", webhook.EventWorkItemCreate)
+		assert.NotEqual(t, uuid.Nil, w.ID)
+		assert.NotEmpty(t, w.Secret)
+		loaded, err := webhook.NewRepository(s.DB).Load(context.Background(), fxt.Spaces[0].ID, w.ID)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.Events{webhook.EventWorkItemCreate}, loaded.Events)
+	})
+	s.T().Run("invalid url", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := webhook.Webhook{SpaceID: fxt.Spaces[0].ID, URL: "ftp://example.com", Events: webhook.Events{webhook.EventWorkItemCreate}}
+		err := webhook.NewRepository(s.DB).Create(context.Background(), &w)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("unknown event", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := webhook.Webhook{SpaceID: fxt.Spaces[0].ID, URL: "https://example.com", Events: webhook.Events{"workitem.explode"}}
+		err := webhook.NewRepository(s.DB).Create(context.Background(), &w)
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+}
+
+func (s *TestWebhookRepository) TestListForEvent() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	spaceID := fxt.Spaces[0].ID
+	subscribed := s.createWebhook(spaceID, "https://example.com/a", webhook.EventWorkItemUpdate, webhook.EventLinkCreate)
+	s.createWebhook(spaceID, "https://example.com/b", webhook.EventCommentCreate)
+	inactive := s.createWebhook(spaceID, "https://example.com/c", webhook.EventWorkItemUpdate)
+	inactive.Active = false
+	_, err := webhook.NewRepository(s.DB).Save(context.Background(), inactive)
+	require.NoError(s.T(), err)
+
+	res, err := webhook.NewRepository(s.DB).ListForEvent(context.Background(), spaceID, webhook.EventWorkItemUpdate)
+	require.NoError(s.T(), err)
+	require.Len(s.T(), res, 1)
+	assert.Equal(s.T(), subscribed.ID, res[0].ID)
+}
+
+func (s *TestWebhookRepository) TestDelete() {
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(2))
+	w := s.createWebhook(fxt.Spaces[0].ID, "https://example.com/hook", webhook.EventWorkItemCreate)
+	repo := webhook.NewRepository(s.DB)
+	// deleting through another space is not possible
+	err := repo.Delete(context.Background(), fxt.Spaces[1].ID, w.ID)
+	require.IsType(s.T(), errors.NotFoundError{}, err)
+	require.NoError(s.T(), repo.Delete(context.Background(), fxt.Spaces[0].ID, w.ID))
+	_, err = repo.Load(context.Background(), fxt.Spaces[0].ID, w.ID)
+	require.IsType(s.T(), errors.NotFoundError{}, err)
+}
+
+// dispatcherConfig allows private addresses unless publicOnly is set, since
+// the test servers listen on the loopback interface
+type dispatcherConfig struct {
+	publicOnly bool
+}
+
+func (dispatcherConfig) GetWebhookDispatchInterval() time.Duration { return time.Second }
+func (dispatcherConfig) GetWebhookDispatchBatchSize() int          { return 100 }
+func (dispatcherConfig) GetWebhookMaxDeliveryAttempts() int        { return 2 }
+func (dispatcherConfig) GetWebhookRetryBackoff() time.Duration     { return 0 }
+func (dispatcherConfig) GetWebhookMaxRetryBackoff() time.Duration  { return 0 }
+func (dispatcherConfig) GetWebhookHTTPTimeout() time.Duration      { return 5 * time.Second }
+func (c dispatcherConfig) GetWebhookAllowPrivateAddresses() bool   { return !c.publicOnly }
+
+func (s *TestWebhookRepository) TestDispatch() {
+	payload := `{"event":"workitem.create"}`
+	s.T().Run("signed delivery", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := s.createWebhook(fxt.Spaces[0].ID, server.URL, webhook.EventWorkItemCreate)
+		d := webhook.Delivery{WebhookID: w.ID, EventID: uuid.NewV4(), EventType: webhook.EventWorkItemCreate, Payload: payload}
+		require.NoError(t, webhook.NewDeliveryRepository(s.DB).Create(context.Background(), &d))
+		// when
+		_, err := webhook.NewDispatcher(s.DB, dispatcherConfig{}).DispatchDue(context.Background())
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, received)
+		assert.Equal(t, webhook.EventWorkItemCreate, received.Header.Get(webhook.HeaderEvent))
+		assert.Equal(t, webhook.Sign(w.Secret, body), received.Header.Get(webhook.HeaderSignature))
+		deliveries, count, err := webhook.NewDeliveryRepository(s.DB).List(context.Background(), w.ID, 0, 10)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		assert.Equal(t, webhook.DeliveryStatusDelivered, deliveries[0].Status)
+		require.NotNil(t, deliveries[0].ResponseStatus)
+		assert.Equal(t, http.StatusNoContent, *deliveries[0].ResponseStatus)
+	})
+	s.T().Run("retried and given up", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(1))
+		w := s.createWebhook(fxt.Spaces[0].ID, server.URL, webhook.EventWorkItemCreate)
+		d := webhook.Delivery{WebhookID: w.ID, EventID: uuid.NewV4(), EventType: webhook.EventWorkItemCreate, Payload: payload}
+		require.NoError(t, webhook.NewDeliveryRepository(s.DB).Create(context.Background(), &d))
+		dispatcher := webhook.NewDispatcher(s.DB, dispatcherConfig{})
+		// when
+		_, err := dispatcher.DispatchDue(context.Background())
+		require.NoError(t, err)
+		deliveries, _, err := webhook.NewDeliveryRepository(s.DB).List(context.Background(), w.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.DeliveryStatusPending, deliveries[0].Status)
+		_, err = dispatcher.DispatchDue(context.Background())
+		require.NoError(t, err)
+		// then
+		deliveries, _, err = webhook.NewDeliveryRepository(s.DB).List(context.Background(), w.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Equal(t, webhook.DeliveryStatusFailed, deliveries[0].Status)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		require.NotNil(t, deliveries[0].ResponseStatus)
+		assert.Equal(t, http.StatusInternalServerError, *deliveries[0].ResponseStatus)
+	})
+}
+
+func (s *TestWebhookRepository) TestDispatchToPrivateAddress() {
+	// given
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	w := s.createWebhook(fxt.Spaces[0].ID, server.URL, webhook.EventWorkItemCreate)
+	d := webhook.Delivery{WebhookID: w.ID, EventID: uuid.NewV4(), EventType: webhook.EventWorkItemCreate, Payload: `{}`}
+	require.NoError(s.T(), webhook.NewDeliveryRepository(s.DB).Create(context.Background(), &d))
+	// when
+	_, err := webhook.NewDispatcher(s.DB, dispatcherConfig{publicOnly: true}).DispatchDue(context.Background())
+	// then the loopback address of the test server is refused
+	require.NoError(s.T(), err)
+	assert.False(s.T(), requested)
+	deliveries, _, err := webhook.NewDeliveryRepository(s.DB).List(context.Background(), w.ID, 0, 10)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, deliveries[0].Attempts)
+	require.NotNil(s.T(), deliveries[0].LastError)
+	assert.Contains(s.T(), *deliveries[0].LastError, "non-public address")
+}
+
+func (s *TestWebhookRepository) TestClaimDueLeasesDeliveries() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.Spaces(1))
+	w := s.createWebhook(fxt.Spaces[0].ID, "https://example.com/hook", webhook.EventWorkItemCreate)
+	d := webhook.Delivery{WebhookID: w.ID, EventID: uuid.NewV4(), EventType: webhook.EventWorkItemCreate, Payload: `{}`}
+	repo := webhook.NewDeliveryRepository(s.DB)
+	require.NoError(s.T(), repo.Create(context.Background(), &d))
+	now := time.Now()
+	contains := func(deliveries []webhook.Delivery) bool {
+		for _, delivery := range deliveries {
+			if delivery.ID == d.ID {
+				return true
+			}
+		}
+		return false
+	}
+	// when
+	claimed, err := repo.ClaimDue(context.Background(), now, 100, now.Add(time.Minute))
+	// then
+	require.NoError(s.T(), err)
+	assert.True(s.T(), contains(claimed))
+	// the delivery is not claimed again before the lease expires
+	claimed, err = repo.ClaimDue(context.Background(), now, 100, now.Add(time.Minute))
+	require.NoError(s.T(), err)
+	assert.False(s.T(), contains(claimed))
+	claimed, err = repo.ClaimDue(context.Background(), now.Add(2*time.Minute), 100, now.Add(3*time.Minute))
+	require.NoError(s.T(), err)
+	assert.True(s.T(), contains(claimed))
+}
+
+func TestIsPublicAddress(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	for _, ip := range []string{"127.0.0.1", "::1", "169.254.169.254", "fe80::1", "10.1.2.3", "172.16.0.1", "192.168.1.1", "100.64.0.1", "fd00::1", "0.0.0.0", "224.0.0.1"} {
+		assert.False(t, webhook.IsPublicAddress(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"8.8.8.8", "172.32.0.1", "2001:4860:4860::8888"} {
+		assert.True(t, webhook.IsPublicAddress(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestValidateHost(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	for _, u := range []string{"http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data", "https://[::1]/hook", "http://10.0.0.1/hook"} {
+		err := webhook.Webhook{URL: u}.ValidateHost(context.Background())
+		assert.IsType(t, errors.BadParameterError{}, err, u)
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// see RFC 4231 test case 2
+	assert.Equal(t, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		webhook.Sign("Jefe", []byte("what do ya want for nothing?")))
+}
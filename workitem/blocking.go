@@ -0,0 +1,141 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/closeable"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// IsClosedState returns true if the given state is one of the states in which
+// a work item is done and no longer blocks other work items.
+func IsClosedState(state string) bool {
+	return state == SystemStateResolved || state == SystemStateClosed
+}
+
+// ClosedStateCondition returns an SQL condition that is true if the work item
+// with the given table alias is in one of the states in which IsClosedState
+// considers it done.
+func ClosedStateCondition(alias string) string {
+	return fmt.Sprintf(`COALESCE(%[1]s.fields->>'%[2]s', '') IN ('%[3]s', '%[4]s')`, alias, SystemState, SystemStateResolved, SystemStateClosed)
+}
+
+// openBlockerLinks returns a query for the given columns of the links through
+// which open work items block the work items matching the given target
+// condition. Only the links of a blocking dependency link type are considered
+// (the source of a link, aliased "b", blocks its target).
+// NOTE: The work item package cannot use the link package, hence the link
+// tables are referred to by their names.
+func openBlockerLinks(columns string, targetCondition string) string {
+	return fmt.Sprintf(`SELECT %[1]s FROM work_item_links l
+		JOIN work_item_link_types t ON t.id = l.link_type_id
+		JOIN %[3]s b ON b.id = l.source_id
+		WHERE %[2]s
+			AND t.topology = 'dependency'
+			AND t.blocking
+			AND l.deleted_at IS NULL
+			AND b.deleted_at IS NULL
+			AND NOT %[4]s`,
+		columns, targetCondition, workitemTableName, ClosedStateCondition("b"))
+}
+
+// openBlockersSelect returns a query for the IDs of the open work items that
+// block the work item with the given ID expression.
+func openBlockersSelect(targetID string) string {
+	return openBlockerLinks("l.source_id", "l.target_id = "+targetID)
+}
+
+// ListOpenBlockers returns the IDs of the open work items that block the given
+// work item, ordered by their number.
+// returns InternalError
+func (r *GormWorkItemRepository) ListOpenBlockers(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "blockers"}, time.Now())
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE id IN (%s) ORDER BY number`, workitemTableName, openBlockersSelect("?"))
+	var blockers []uuid.UUID
+	rows, err := r.db.Raw(query, id).Rows()
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": id,
+			"err":   err,
+		}, "unable to list the open blockers of the work item")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the open blockers of work item %s", id))
+	}
+	defer closeable.Close(ctx, rows)
+	for rows.Next() {
+		var blocker uuid.UUID
+		if err := rows.Scan(&blocker); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to scan the open blockers of work item %s", id))
+		}
+		blockers = append(blockers, blocker)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to list the open blockers of work item %s", id))
+	}
+	return blockers, nil
+}
+
+// ListOpenBlockersBatch returns the IDs of the open work items that block each
+// of the given work items, ordered by their number. Work items without open
+// blockers have no entry in the returned map.
+// returns InternalError
+func (r *GormWorkItemRepository) ListOpenBlockersBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "blockers", "batch"}, time.Now())
+	blockers := map[uuid.UUID][]uuid.UUID{}
+	if len(ids) == 0 {
+		return blockers, nil
+	}
+	query := openBlockerLinks("DISTINCT l.target_id, l.source_id, b.number", "l.target_id IN (?)") + " ORDER BY b.number"
+	rows, err := r.db.Raw(query, ids).Rows()
+	if err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_ids": ids,
+			"err":    err,
+		}, "unable to list the open blockers of the work items")
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list the open blockers of the work items"))
+	}
+	defer closeable.Close(ctx, rows)
+	for rows.Next() {
+		var target, blocker uuid.UUID
+		var number int
+		if err := rows.Scan(&target, &blocker, &number); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the open blockers of the work items"))
+		}
+		blockers[target] = append(blockers[target], blocker)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to list the open blockers of the work items"))
+	}
+	return blockers, nil
+}
+
+// checkNotBlocked verifies that a work item which is moved into a resolved or
+// closed state has no open blockers. The given fields are in their storage
+// representation.
+// returns DataConflictError or InternalError
+func (r *GormWorkItemRepository) checkNotBlocked(ctx context.Context, id uuid.UUID, previousFields Fields, fields Fields) error {
+	previousState, _ := previousFields[SystemState].(string)
+	state, _ := fields[SystemState].(string)
+	if !IsClosedState(state) || IsClosedState(previousState) {
+		return nil
+	}
+	blockers, err := r.ListOpenBlockers(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(blockers) == 0 {
+		return nil
+	}
+	ids := make([]string, len(blockers))
+	for i, blocker := range blockers {
+		ids[i] = blocker.String()
+	}
+	return errors.NewDataConflictError(fmt.Sprintf("work item %s cannot be %s while it is blocked by the open work items: %s", id, state, strings.Join(ids, ", ")))
+}
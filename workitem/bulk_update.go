@@ -0,0 +1,118 @@
+package workitem
+
+import (
+	"reflect"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+)
+
+// FieldOperationKind defines how a field operation modifies the value of a
+// field
+type FieldOperationKind string
+
+const (
+	// FieldOperationSet replaces the value of the field
+	FieldOperationSet FieldOperationKind = "set"
+	// FieldOperationAdd adds values to a list field, values that are in the
+	// list already are ignored
+	FieldOperationAdd FieldOperationKind = "add"
+	// FieldOperationRemove removes values from a list field
+	FieldOperationRemove FieldOperationKind = "remove"
+)
+
+// FieldOperation describes the modification of a single field of a work item
+// as done in a bulk update. The value is given in the same representation as
+// the fields of a WorkItem. For list operations the value can either be a
+// single value or a list of values.
+type FieldOperation struct {
+	Kind  FieldOperationKind
+	Field string
+	Value interface{}
+}
+
+// Apply applies the operation to the fields of the given work item of the
+// given type. The modified work item still needs to be stored with Save,
+// which converts and validates the new field values.
+// returns BadParameterError or ConversionError
+func (op FieldOperation) Apply(wiType WorkItemType, wi *WorkItem) error {
+	fieldDef, ok := wiType.Fields[op.Field]
+	if !ok || isMaintainedByRepository(op.Field) {
+		return errors.NewBadParameterError("field", op.Field).Expected("a modifiable field of work item type " + wiType.Name)
+	}
+	switch op.Kind {
+	case FieldOperationSet:
+		wi.Fields[op.Field] = op.Value
+		return nil
+	case FieldOperationAdd, FieldOperationRemove:
+		if _, isList := fieldDef.Type.(ListType); !isList {
+			return errors.NewBadParameterError("field", op.Field).Expected("a list field")
+		}
+		current, err := asList(wi.Fields[op.Field])
+		if err != nil {
+			return errors.NewConversionError(err.Error())
+		}
+		values := []interface{}{op.Value}
+		if op.Value != nil {
+			if kind := reflect.TypeOf(op.Value).Kind(); kind == reflect.Slice || kind == reflect.Array {
+				values, err = asList(op.Value)
+				if err != nil {
+					return errors.NewConversionError(err.Error())
+				}
+			}
+		}
+		if op.Kind == FieldOperationAdd {
+			wi.Fields[op.Field] = addToList(current, values)
+		} else {
+			wi.Fields[op.Field] = removeFromList(current, values)
+		}
+		return nil
+	default:
+		return errors.NewBadParameterError("op", op.Kind).Expected("set, add or remove")
+	}
+}
+
+// ApplyAll applies the given operations in order to the work item
+func ApplyAll(wiType WorkItemType, wi *WorkItem, ops []FieldOperation) error {
+	for _, op := range ops {
+		if err := op.Apply(wiType, wi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// asList returns the elements of the given array or slice, as is
+func asList(value interface{}) ([]interface{}, error) {
+	return ConvertList(func(fieldType FieldType, value interface{}) (interface{}, error) {
+		return value, nil
+	}, SimpleType{}, value)
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, v := range list {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func addToList(list []interface{}, values []interface{}) []interface{} {
+	result := append([]interface{}{}, list...)
+	for _, v := range values {
+		if !containsValue(result, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func removeFromList(list []interface{}, values []interface{}) []interface{} {
+	result := []interface{}{}
+	for _, v := range list {
+		if !containsValue(values, v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
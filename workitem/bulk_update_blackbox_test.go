@@ -0,0 +1,71 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	. "github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldOperation_Apply(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	wiType := WorkItemType{
+		Name: "foo",
+		Fields: FieldDefinitions{
+			SystemTitle: {Type: SimpleType{Kind: KindString}},
+			SystemLabels: {Type: ListType{
+				SimpleType:    SimpleType{Kind: KindList},
+				ComponentType: SimpleType{Kind: KindString},
+			}},
+			SystemCreator: {Type: SimpleType{Kind: KindUser}},
+		},
+	}
+	newWorkItem := func() *WorkItem {
+		return &WorkItem{Fields: map[string]interface{}{
+			SystemTitle:  "title",
+			SystemLabels: []interface{}{"a", "b"},
+		}}
+	}
+	t.Run("set", func(t *testing.T) {
+		wi := newWorkItem()
+		require.NoError(t, FieldOperation{Kind: FieldOperationSet, Field: SystemTitle, Value: "new"}.Apply(wiType, wi))
+		assert.Equal(t, "new", wi.Fields[SystemTitle])
+	})
+	t.Run("add", func(t *testing.T) {
+		wi := newWorkItem()
+		require.NoError(t, FieldOperation{Kind: FieldOperationAdd, Field: SystemLabels, Value: []interface{}{"b", "c"}}.Apply(wiType, wi))
+		assert.Equal(t, []interface{}{"a", "b", "c"}, wi.Fields[SystemLabels])
+	})
+	t.Run("add single value to empty list", func(t *testing.T) {
+		wi := newWorkItem()
+		delete(wi.Fields, SystemLabels)
+		require.NoError(t, FieldOperation{Kind: FieldOperationAdd, Field: SystemLabels, Value: "a"}.Apply(wiType, wi))
+		assert.Equal(t, []interface{}{"a"}, wi.Fields[SystemLabels])
+	})
+	t.Run("remove", func(t *testing.T) {
+		wi := newWorkItem()
+		require.NoError(t, FieldOperation{Kind: FieldOperationRemove, Field: SystemLabels, Value: "a"}.Apply(wiType, wi))
+		assert.Equal(t, []interface{}{"b"}, wi.Fields[SystemLabels])
+	})
+	t.Run("fail - add to a non-list field", func(t *testing.T) {
+		err := FieldOperation{Kind: FieldOperationAdd, Field: SystemTitle, Value: "a"}.Apply(wiType, newWorkItem())
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+	t.Run("fail - unknown field", func(t *testing.T) {
+		err := FieldOperation{Kind: FieldOperationSet, Field: "bar", Value: "a"}.Apply(wiType, newWorkItem())
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+	t.Run("fail - field maintained by the repository", func(t *testing.T) {
+		err := FieldOperation{Kind: FieldOperationSet, Field: SystemCreator, Value: "a"}.Apply(wiType, newWorkItem())
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+	t.Run("fail - unknown operation", func(t *testing.T) {
+		err := FieldOperation{Kind: "replace", Field: SystemTitle, Value: "a"}.Apply(wiType, newWorkItem())
+		assert.IsType(t, errors.BadParameterError{}, err)
+	})
+}
@@ -0,0 +1,191 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TypeChange tells what happens to the field values of a work item when its
+// type is changed
+type TypeChange struct {
+	// Kept are the names of the fields whose values are carried over
+	Kept []string
+	// Dropped are the names of the fields whose values are lost because the
+	// new type has no compatible field of the same name
+	Dropped []string
+}
+
+// compatibleValue returns true if the given value (in its storage
+// representation) of a field with the definition from is also a valid value
+// of a field with the definition to. This is the case if both fields are
+// compatible or if they have the same kind and the value is accepted by the
+// new field (e.g. a state that exists in both enums).
+func compatibleValue(name string, from, to FieldDefinition, value interface{}) bool {
+	// whether a field is required only matters for missing values
+	if compatibleFields(FieldDefinition{Type: from.Type}, FieldDefinition{Type: to.Type}) {
+		return true
+	}
+	if from.Type.GetKind() != to.Type.GetKind() {
+		return false
+	}
+	converted, err := from.ConvertFromModel(name, value)
+	if err != nil {
+		return false
+	}
+	_, err = to.ConvertToModel(name, converted)
+	return err == nil
+}
+
+// mapFieldsToType maps the given field values (in their storage
+// representation) of a work item of the type from onto the type to. Values
+// are kept for the fields of the same name and a compatible kind, all other
+// values are dropped. A state that is dropped is replaced with the initial
+// state of the new type, other fields that are required by the new type get
+// their default value.
+// returns BadParameterError if a required field of the new type has no value
+func mapFieldsToType(from, to WorkItemType, fields Fields) (Fields, *TypeChange, error) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := Fields{}
+	change := TypeChange{Kept: []string{}, Dropped: []string{}}
+	for _, name := range names {
+		value := fields[name]
+		if value == nil {
+			continue
+		}
+		fromDef, known := from.Fields[name]
+		toDef, ok := to.Fields[name]
+		if known && ok && compatibleValue(name, fromDef, toDef, value) {
+			result[name] = value
+			if !isMaintainedByRepository(name) {
+				change.Kept = append(change.Kept, name)
+			}
+			continue
+		}
+		change.Dropped = append(change.Dropped, name)
+	}
+	for name, def := range to.Fields {
+		if _, ok := result[name]; ok || isMaintainedByRepository(name) {
+			continue
+		}
+		switch {
+		case name == SystemState:
+			result[name] = SystemStateNew
+			if to.Workflow != nil && to.Workflow.InitialState() != "" {
+				result[name] = to.Workflow.InitialState()
+			}
+		case def.DefaultValue != nil:
+			result[name] = def.DefaultValue
+		case def.Required:
+			return nil, &change, errors.NewBadParameterError("type", to.ID).Expected(fmt.Sprintf("a type that does not require the field %s", name))
+		}
+	}
+	return result, &change, nil
+}
+
+// ChangeType changes the type of the work item with the given ID. The new
+// type must belong to the space of the work item (or be a system type). The
+// values of the fields that have the same name and a compatible kind in the
+// new type are kept, the other values are dropped; dropping values must be
+// confirmed, otherwise the work item is left unchanged and the returned
+// DataConflictError lists the fields. The change is recorded as a revision.
+// returns NotFoundError, BadParameterError, VersionConflictError,
+// DataConflictError or InternalError
+func (r *GormWorkItemRepository) ChangeType(ctx context.Context, workitemID uuid.UUID, typeID uuid.UUID, version int, confirmDrop bool, modifierID uuid.UUID) (*WorkItem, *TypeChange, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "changeType"}, time.Now())
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, nil, errors.NewNotFoundError("work item", workitemID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	if wiStorage.Version != version {
+		return nil, nil, errors.NewVersionConflictError("version conflict")
+	}
+	if uuid.Equal(wiStorage.Type, typeID) {
+		return nil, nil, errors.NewBadParameterError("type", typeID).Expected("a type other than the current type of the work item")
+	}
+	fromType, err := r.witr.LoadTypeFromDB(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, nil, errors.NewInternalError(ctx, err)
+	}
+	toType, err := r.witr.LoadTypeFromDB(ctx, typeID)
+	if err != nil {
+		if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+			return nil, nil, errors.NewBadParameterError("type", typeID).Expected("an existing work item type")
+		}
+		return nil, nil, err
+	}
+	if !uuid.Equal(toType.SpaceID, space.SystemSpace) && !uuid.Equal(toType.SpaceID, wiStorage.SpaceID) {
+		return nil, nil, errors.NewBadParameterError("type", typeID).Expected("a work item type of the space of the work item")
+	}
+	fields, change, err := mapFieldsToType(*fromType, *toType, wiStorage.Fields)
+	if err != nil {
+		return nil, change, err
+	}
+	if len(change.Dropped) > 0 && !confirmDrop {
+		return nil, change, errors.NewDataConflictError(fmt.Sprintf("changing the type drops the values of the fields %s, the change must be confirmed", strings.Join(change.Dropped, ", ")))
+	}
+	if toType.Workflow != nil {
+		if state, ok := fields[SystemState].(string); ok {
+			if err := toType.Workflow.CheckState(state, fields); err != nil {
+				return nil, change, err
+			}
+		}
+	}
+	wiStorage.Type = toType.ID
+	wiStorage.Fields = fields
+	if wiStorage.Mentions, err = r.resolveMentions(ctx, wiStorage.Fields); err != nil {
+		return nil, change, err
+	}
+	wiStorage.Version = version + 1
+	tx = r.db.Where("version = ?", version).Save(&wiStorage)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":   workitemID,
+			"wit_id":  typeID,
+			"version": version,
+			"err":     err,
+		}, "unable to change the type of the work item")
+		return nil, change, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, change, errors.NewVersionConflictError("version conflict")
+	}
+	if err := r.wirr.Create(context.Background(), modifierID, RevisionTypeChangeType, wiStorage); err != nil {
+		return nil, change, errs.Wrapf(err, "error while changing the type of work item")
+	}
+	// the roll-ups of the work item depend on its type and the roll-ups of
+	// the parents are computed from its fields
+	cache := NewRollUpCache(r.db)
+	if err := cache.Invalidate(ctx, workitemID); err != nil {
+		return nil, change, errs.Wrapf(err, "error while changing the type of work item")
+	}
+	if err := cache.InvalidateParents(ctx, workitemID); err != nil {
+		return nil, change, errs.Wrapf(err, "error while changing the type of work item")
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"wi_id":       workitemID,
+		"from_wit_id": fromType.ID,
+		"to_wit_id":   toType.ID,
+		"dropped":     change.Dropped,
+	}, "Work item type changed successfully!")
+	wi, err := ConvertWorkItemStorageToModel(toType, &wiStorage)
+	return wi, change, err
+}
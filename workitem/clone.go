@@ -0,0 +1,54 @@
+package workitem
+
+import (
+	uuid "github.com/satori/go.uuid"
+)
+
+// CloneOptions defines how the field values of a work item are modified
+// when the work item is cloned
+type CloneOptions struct {
+	// ResetState puts the clone into the initial state of its type
+	ResetState bool
+	// ClearAssignees leaves the clone unassigned
+	ClearAssignees bool
+	// IterationID is the iteration of the clone, the iteration of the cloned
+	// work item is kept if nil
+	IterationID *uuid.UUID
+	// AreaID is the area of the clone, the area of the cloned work item is
+	// kept if nil
+	AreaID *uuid.UUID
+}
+
+// CloneFields returns the field values for a clone of the given work item of
+// the given type, in the same representation as the fields of a WorkItem.
+// The fields that are set by the repository when the clone is created (e.g.
+// the creator or the number) are left out.
+func (o CloneOptions) CloneFields(wiType WorkItemType, wi WorkItem) map[string]interface{} {
+	fields := map[string]interface{}{}
+	for name := range wiType.Fields {
+		if isMaintainedByRepository(name) {
+			continue
+		}
+		if value, ok := wi.Fields[name]; ok {
+			fields[name] = value
+		}
+	}
+	if o.ResetState {
+		if _, ok := wiType.Fields[SystemState]; ok {
+			fields[SystemState] = SystemStateNew
+			if wiType.Workflow != nil && wiType.Workflow.InitialState() != "" {
+				fields[SystemState] = wiType.Workflow.InitialState()
+			}
+		}
+	}
+	if o.ClearAssignees {
+		delete(fields, SystemAssignees)
+	}
+	if o.IterationID != nil {
+		fields[SystemIteration] = o.IterationID.String()
+	}
+	if o.AreaID != nil {
+		fields[SystemArea] = o.AreaID.String()
+	}
+	return fields
+}
@@ -0,0 +1,74 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	. "github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneOptions_CloneFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	wiType := WorkItemType{
+		Name: "foo",
+		Fields: FieldDefinitions{
+			SystemTitle:     {Type: SimpleType{Kind: KindString}},
+			SystemState:     {Type: SimpleType{Kind: KindString}},
+			SystemCreator:   {Type: SimpleType{Kind: KindUser}},
+			SystemNumber:    {Type: SimpleType{Kind: KindInteger}},
+			SystemIteration: {Type: SimpleType{Kind: KindIteration}},
+			SystemArea:      {Type: SimpleType{Kind: KindArea}},
+			SystemAssignees: {Type: ListType{
+				SimpleType:    SimpleType{Kind: KindList},
+				ComponentType: SimpleType{Kind: KindUser},
+			}},
+		},
+	}
+	iterationID := uuid.NewV4()
+	areaID := uuid.NewV4()
+	wi := WorkItem{Fields: map[string]interface{}{
+		SystemTitle:     "title",
+		SystemState:     SystemStateInProgress,
+		SystemCreator:   uuid.NewV4().String(),
+		SystemNumber:    42,
+		SystemIteration: iterationID.String(),
+		SystemArea:      areaID.String(),
+		SystemAssignees: []interface{}{uuid.NewV4().String()},
+		"unknown":       "value",
+	}}
+	t.Run("copy", func(t *testing.T) {
+		fields := CloneOptions{}.CloneFields(wiType, wi)
+		assert.Equal(t, map[string]interface{}{
+			SystemTitle:     "title",
+			SystemState:     SystemStateInProgress,
+			SystemIteration: iterationID.String(),
+			SystemArea:      areaID.String(),
+			SystemAssignees: wi.Fields[SystemAssignees],
+		}, fields)
+	})
+	t.Run("reset state", func(t *testing.T) {
+		fields := CloneOptions{ResetState: true}.CloneFields(wiType, wi)
+		assert.Equal(t, SystemStateNew, fields[SystemState])
+	})
+	t.Run("reset state with workflow", func(t *testing.T) {
+		withWorkflow := wiType
+		withWorkflow.Workflow = &Workflow{States: []WorkflowState{{Name: "todo"}, {Name: "done"}}}
+		fields := CloneOptions{ResetState: true}.CloneFields(withWorkflow, wi)
+		assert.Equal(t, "todo", fields[SystemState])
+	})
+	t.Run("clear assignees", func(t *testing.T) {
+		fields := CloneOptions{ClearAssignees: true}.CloneFields(wiType, wi)
+		assert.NotContains(t, fields, SystemAssignees)
+	})
+	t.Run("retarget iteration and area", func(t *testing.T) {
+		otherIterationID := uuid.NewV4()
+		otherAreaID := uuid.NewV4()
+		fields := CloneOptions{IterationID: &otherIterationID, AreaID: &otherAreaID}.CloneFields(wiType, wi)
+		assert.Equal(t, otherIterationID.String(), fields[SystemIteration])
+		assert.Equal(t, otherAreaID.String(), fields[SystemArea])
+	})
+}
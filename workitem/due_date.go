@@ -0,0 +1,24 @@
+package workitem
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+)
+
+// dueDateLayouts are the accepted formats of due dates. A date without a time
+// refers to the beginning of that day in UTC.
+var dueDateLayouts = []string{time.RFC3339, "2006-01-02"}
+
+// ParseDueDate parses the given due date, which is either a RFC3339 timestamp
+// (e.g. "2018-01-31T17:00:00Z") or a plain date (e.g. "2018-01-31")
+// returns BadParameterError
+func ParseDueDate(value string) (time.Time, error) {
+	for _, layout := range dueDateLayouts {
+		if t, err := time.Parse(layout, strings.TrimSpace(value)); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.NewBadParameterError(SystemDueDate, value).Expected("a RFC3339 timestamp or a date like 2018-01-31")
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
@@ -71,6 +72,14 @@ func bubbleUpJSONContext(c *expressionCompiler) func(exp criteria.Expression) bo
 			if t.Left().Annotation(jsonAnnotation) == true || t.Right().Annotation(jsonAnnotation) == true {
 				t.SetAnnotation(jsonAnnotation, true)
 			}
+		case *criteria.LessThanExpression:
+			if t.Left().Annotation(jsonAnnotation) == true || t.Right().Annotation(jsonAnnotation) == true {
+				t.SetAnnotation(jsonAnnotation, true)
+			}
+		case *criteria.GreaterThanExpression:
+			if t.Left().Annotation(jsonAnnotation) == true || t.Right().Annotation(jsonAnnotation) == true {
+				t.SetAnnotation(jsonAnnotation, true)
+			}
 		}
 		return true
 	}
@@ -277,6 +286,15 @@ func (c *expressionCompiler) Or(a *criteria.OrExpression) interface{} {
 }
 
 func (c *expressionCompiler) Equals(e *criteria.EqualsExpression) interface{} {
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && left.FieldName == SystemBlocked {
+		return c.blockedEquals(e.Right(), false)
+	}
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && left.FieldName == SystemTypeGroup {
+		return c.typeGroupEquals(e.Right(), false)
+	}
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && IsCustomField(left.FieldName) {
+		return c.customFieldEquals(left.FieldName, e.Right())
+	}
 	op := "="
 	if isInJSONContext(e.Left()) {
 		op = ":"
@@ -331,6 +349,143 @@ func (c *expressionCompiler) Substring(e *criteria.SubstringExpression) interfac
 	return c.binary(e, "ILIKE")
 }
 
+func (c *expressionCompiler) LessThan(e *criteria.LessThanExpression) interface{} {
+	return c.compare(e, "<")
+}
+
+func (c *expressionCompiler) GreaterThan(e *criteria.GreaterThanExpression) interface{} {
+	return c.compare(e, ">")
+}
+
+// comparableColumns are the columns of the work items that hold numbers
+var comparableColumns = map[string]bool{
+	"Number":  true,
+	"Version": true,
+}
+
+// compare compiles an ordering comparison with the given operator. Only the
+// numeric columns and the fields that IsComparableField accepts are ordered.
+// Fields stored in the jsonb column are compared as numbers, which covers
+// instants (stored in nanoseconds), durations and integers; custom fields only
+// match if their value is a number.
+func (c *expressionCompiler) compare(e criteria.BinaryExpression, op string) interface{} {
+	left, ok := e.Left().(*criteria.FieldExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("invalid left expression (not a field expression): %+v", e.Left()))
+		return nil
+	}
+	if !isInJSONContext(e.Left()) {
+		if !comparableColumns[left.FieldName] {
+			c.err = append(c.err, errors.NewBadParameterError("field", left.FieldName).Expected("a numeric, duration or instant field"))
+			return nil
+		}
+		return c.binary(e, op)
+	}
+	if strings.Contains(left.FieldName, "'") {
+		// beware of injection, it's a reasonable restriction for field names,
+		// make sure it's not allowed when creating wi types
+		c.err = append(c.err, errs.Errorf("single quote not allowed in field name: %s", left.FieldName))
+		return nil
+	}
+	if !IsComparableField(left.FieldName) {
+		c.err = append(c.err, errors.NewBadParameterError("field", left.FieldName).Expected("a numeric, duration or instant field"))
+		return nil
+	}
+	litExp, ok := e.Right().(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("failed to convert right expression to literal expression: %+v", e.Right()))
+		return nil
+	}
+	c.parameters = append(c.parameters, litExp.Value)
+	fields := Column(WorkItemStorage{}.TableName(), "fields")
+	if IsCustomField(left.FieldName) {
+		// the cast must not be applied to the values of other kinds
+		return "(CASE WHEN jsonb_typeof(" + fields + "->'" + left.FieldName + "') = 'number' THEN (" + fields + "->>'" + left.FieldName + "')::numeric " + op + " ? END)"
+	}
+	return "((" + fields + "->>'" + left.FieldName + "')::numeric " + op + " ?)"
+}
+
+// customFieldEquals compares the value of a custom field with a literal. The
+// kind of a custom field differs between spaces, which is why the value is
+// compared in its text form; list fields match if they contain the value.
+func (c *expressionCompiler) customFieldEquals(fieldName string, right criteria.Expression) interface{} {
+	litExp, ok := right.(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("failed to convert right expression to literal expression: %+v", right))
+		return nil
+	}
+	value := fmt.Sprint(litExp.Value)
+	if values, ok := litExp.Value.([]string); ok && len(values) == 1 {
+		value = values[0]
+	}
+	c.parameters = append(c.parameters, value, value)
+	fields := Column(WorkItemStorage{}.TableName(), "fields")
+	return "(" + fields + "->>'" + fieldName + "' = ? OR jsonb_exists(" + fields + "->'" + fieldName + "', ?))"
+}
+
+// blockedEquals compares the computed "system.blocked" flag of a work item,
+// which is true if the work item has open blockers, with a boolean literal.
+func (c *expressionCompiler) blockedEquals(right criteria.Expression, negate bool) interface{} {
+	litExp, ok := right.(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("failed to convert right expression to literal expression: %+v", right))
+		return nil
+	}
+	var blocked bool
+	switch v := litExp.Value.(type) {
+	case bool:
+		blocked = v
+	case string:
+		var err error
+		blocked, err = strconv.ParseBool(v)
+		if err != nil {
+			c.err = append(c.err, errs.Wrapf(err, "invalid value for %s: %s", SystemBlocked, v))
+			return nil
+		}
+	default:
+		c.err = append(c.err, errs.Errorf("invalid value for %s: %+v", SystemBlocked, litExp.Value))
+		return nil
+	}
+	condition := "EXISTS (" + openBlockersSelect(Column(WorkItemStorage{}.TableName(), "id")) + ")"
+	if blocked == negate {
+		condition = "NOT " + condition
+	}
+	return "(" + condition + ")"
+}
+
+// typeGroupEquals checks whether the type of a work item belongs to a type
+// group of its space. Spaces without stored type groups use the default types
+// of the group.
+func (c *expressionCompiler) typeGroupEquals(right criteria.Expression, negate bool) interface{} {
+	litExp, ok := right.(*criteria.LiteralExpression)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("failed to convert right expression to literal expression: %+v", right))
+		return nil
+	}
+	membership, ok := litExp.Value.(TypeGroupMembership)
+	if !ok {
+		c.err = append(c.err, errs.Errorf("invalid value for %s: %+v", SystemTypeGroup, litExp.Value))
+		return nil
+	}
+	typeColumn := Column(WorkItemStorage{}.TableName(), "type")
+	spaceGroups := "SELECT 1 FROM " + WorkItemTypeGroup{}.TableName() + " g WHERE g.space_id = " + Column(WorkItemStorage{}.TableName(), "space_id") + " AND g.deleted_at IS NULL"
+	c.parameters = append(c.parameters, membership.Name)
+	defaultTypes := "false"
+	if len(membership.DefaultTypes) > 0 {
+		placeholders := make([]string, len(membership.DefaultTypes))
+		for i, id := range membership.DefaultTypes {
+			placeholders[i] = "?"
+			c.parameters = append(c.parameters, id.String())
+		}
+		defaultTypes = typeColumn + " IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	condition := "(EXISTS (" + spaceGroups + " AND g.name = ? AND jsonb_exists(g.type_list, " + typeColumn + "::text)) OR (NOT EXISTS (" + spaceGroups + ") AND " + defaultTypes + "))"
+	if negate {
+		condition = "NOT " + condition
+	}
+	return "(" + condition + ")"
+}
+
 func (c *expressionCompiler) IsNull(e *criteria.IsNullExpression) interface{} {
 	mappedFieldName, isJSONField := c.getFieldName(e.FieldName)
 	if isJSONField {
@@ -340,6 +495,19 @@ func (c *expressionCompiler) IsNull(e *criteria.IsNullExpression) interface{} {
 }
 
 func (c *expressionCompiler) Not(e *criteria.NotExpression) interface{} {
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && left.FieldName == SystemBlocked {
+		return c.blockedEquals(e.Right(), true)
+	}
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && left.FieldName == SystemTypeGroup {
+		return c.typeGroupEquals(e.Right(), true)
+	}
+	if left, ok := e.Left().(*criteria.FieldExpression); ok && IsCustomField(left.FieldName) {
+		condition := c.customFieldEquals(left.FieldName, e.Right())
+		if condition == nil {
+			return nil
+		}
+		return "NOT COALESCE(" + condition.(string) + ", false)"
+	}
 	if isInJSONContext(e.Left()) {
 		condition := c.binary(e, ":")
 		if condition != nil {
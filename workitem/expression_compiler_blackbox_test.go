@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	c "github.com/fabric8-services/fabric8-wit/criteria"
+	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +25,9 @@ func TestField(t *testing.T) {
 	expect(t, c.Not(c.Field("Version"), c.Literal("abcd")), `(`+workitem.Column(wiTbl, "version")+` != ?)`, []interface{}{"abcd"}, nil)
 	expect(t, c.Not(c.Field("Number"), c.Literal("abcd")), `(`+workitem.Column(wiTbl, "number")+` != ?)`, []interface{}{"abcd"}, nil)
 	expect(t, c.Not(c.Field("SpaceID"), c.Literal("abcd")), `(`+workitem.Column(wiTbl, "space_id")+` != ?)`, []interface{}{"abcd"}, nil)
+	customField := `(` + workitem.Column(wiTbl, "fields") + `->>'custom.points' = ? OR jsonb_exists(` + workitem.Column(wiTbl, "fields") + `->'custom.points', ?))`
+	expect(t, c.Equals(c.Field("custom.points"), c.Literal("5")), customField, []interface{}{"5", "5"}, nil)
+	expect(t, c.Not(c.Field("custom.points"), c.Literal(5)), `NOT COALESCE(`+customField+`, false)`, []interface{}{"5", "5"}, nil)
 
 	t.Run("test join", func(t *testing.T) {
 		t.Run("iteration", func(t *testing.T) {
@@ -204,3 +209,83 @@ func TestSubstring(t *testing.T) {
 		assert.Equal(t, "", where)
 	})
 }
+
+func TestComparison(t *testing.T) {
+	wiTbl := workitem.WorkItemStorage{}.TableName()
+	t.Run("json field", func(t *testing.T) {
+		expect(t, c.LessThan(c.Field("system.due_date"), c.Literal(int64(42))), `((`+workitem.Column(wiTbl, "fields")+`->>'system.due_date')::numeric < ?)`, []interface{}{int64(42)}, nil)
+		expect(t, c.GreaterThan(c.Field("system.due_date"), c.Literal(int64(42))), `((`+workitem.Column(wiTbl, "fields")+`->>'system.due_date')::numeric > ?)`, []interface{}{int64(42)}, nil)
+	})
+	t.Run("column", func(t *testing.T) {
+		expect(t, c.GreaterThan(c.Field("Number"), c.Literal(5)), `(`+workitem.Column(wiTbl, "number")+` > ?)`, []interface{}{5}, nil)
+	})
+	t.Run("json field with single quote", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.LessThan(c.Field("system.due_date'DELETE FROM work_items"), c.Literal(int64(42))))
+		require.NotEmpty(t, compileErrors)
+	})
+	t.Run("custom field", func(t *testing.T) {
+		fields := workitem.Column(wiTbl, "fields")
+		expect(t, c.LessThan(c.Field("custom.points"), c.Literal(float64(8))), `(CASE WHEN jsonb_typeof(`+fields+`->'custom.points') = 'number' THEN (`+fields+`->>'custom.points')::numeric < ? END)`, []interface{}{float64(8)}, nil)
+	})
+	t.Run("json field that is not comparable", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.LessThan(c.Field(workitem.SystemTitle), c.Literal("foo")))
+		require.Len(t, compileErrors, 1)
+		require.IsType(t, errors.BadParameterError{}, compileErrors[0])
+	})
+	t.Run("column that is not comparable", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.GreaterThan(c.Field("SpaceID"), c.Literal("foo")))
+		require.Len(t, compileErrors, 1)
+		require.IsType(t, errors.BadParameterError{}, compileErrors[0])
+	})
+}
+
+func TestTypeGroup(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	membership := workitem.TypeGroupMembership{Name: "Requirements", DefaultTypes: []uuid.UUID{workitem.SystemFeature, workitem.SystemBug}}
+	t.Run("in type group", func(t *testing.T) {
+		where, params, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemTypeGroup), c.Literal(membership)))
+		require.Empty(t, compileErrors)
+		assert.Regexp(t, `^\(\(EXISTS \(SELECT 1 FROM work_item_type_groups g WHERE g.space_id = "work_items"."space_id"`, where)
+		assert.Contains(t, where, `"work_items"."type" IN (?, ?)`)
+		assert.Equal(t, []interface{}{"Requirements", workitem.SystemFeature.String(), workitem.SystemBug.String()}, params)
+	})
+	t.Run("not in type group", func(t *testing.T) {
+		where, _, _, compileErrors := workitem.Compile(c.Not(c.Field(workitem.SystemTypeGroup), c.Literal(membership)))
+		require.Empty(t, compileErrors)
+		assert.Regexp(t, `^\(NOT \(EXISTS`, where)
+	})
+	t.Run("no default types", func(t *testing.T) {
+		where, params, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemTypeGroup), c.Literal(workitem.TypeGroupMembership{Name: "Board"})))
+		require.Empty(t, compileErrors)
+		assert.Contains(t, where, "AND false))")
+		assert.Equal(t, []interface{}{"Board"}, params)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemTypeGroup), c.Literal("Requirements")))
+		require.NotEmpty(t, compileErrors)
+	})
+}
+
+func TestBlocked(t *testing.T) {
+	resource.Require(t, resource.UnitTest)
+	t.Run("blocked", func(t *testing.T) {
+		where, params, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemBlocked), c.Literal("true")))
+		require.Empty(t, compileErrors)
+		assert.Regexp(t, `^\(EXISTS \(SELECT l.source_id FROM work_item_links l`, where)
+		assert.Empty(t, params)
+	})
+	t.Run("not blocked", func(t *testing.T) {
+		where, _, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemBlocked), c.Literal(false)))
+		require.Empty(t, compileErrors)
+		assert.Regexp(t, `^\(NOT EXISTS \(`, where)
+	})
+	t.Run("negated", func(t *testing.T) {
+		where, _, _, compileErrors := workitem.Compile(c.Not(c.Field(workitem.SystemBlocked), c.Literal("true")))
+		require.Empty(t, compileErrors)
+		assert.Regexp(t, `^\(NOT EXISTS \(`, where)
+	})
+	t.Run("invalid value", func(t *testing.T) {
+		_, _, _, compileErrors := workitem.Compile(c.Equals(c.Field(workitem.SystemBlocked), c.Literal("maybe")))
+		require.NotEmpty(t, compileErrors)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"regexp"
 
 	"strings"
 
@@ -63,6 +64,14 @@ type FieldDefinition struct {
 	Label       string
 	Description string
 	Type        FieldType
+	// Deprecated fields keep their values but should no longer be offered
+	// for input
+	Deprecated bool `json:",omitempty"`
+	// Order is the position of the field when presenting the fields of a type
+	Order int `json:",omitempty"`
+	// DefaultValue is the value (in the storage representation) of the field
+	// for work items that were created before the field was added to the type
+	DefaultValue interface{} `json:",omitempty"`
 }
 
 // Ensure FieldDefinition implements the Equaler interface
@@ -84,6 +93,15 @@ func (f FieldDefinition) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if f.Deprecated != other.Deprecated {
+		return false
+	}
+	if f.Order != other.Order {
+		return false
+	}
+	if !reflect.DeepEqual(f.DefaultValue, other.DefaultValue) {
+		return false
+	}
 	return f.Type.Equal(other.Type)
 }
 
@@ -115,10 +133,13 @@ func (f FieldDefinition) ConvertFromModel(name string, value interface{}) (inter
 }
 
 type rawFieldDef struct {
-	Required    bool
-	Label       string
-	Description string
-	Type        *json.RawMessage
+	Required     bool
+	Label        string
+	Description  string
+	Type         *json.RawMessage
+	Deprecated   bool
+	Order        int
+	DefaultValue interface{}
 }
 
 // Ensure rawFieldDef implements the Equaler interface
@@ -140,6 +161,9 @@ func (f rawFieldDef) Equal(u convert.Equaler) bool {
 	if f.Description != other.Description {
 		return false
 	}
+	if f.Deprecated != other.Deprecated || f.Order != other.Order || !reflect.DeepEqual(f.DefaultValue, other.DefaultValue) {
+		return false
+	}
 	if f.Type == nil && other.Type == nil {
 		return true
 	}
@@ -171,21 +195,21 @@ func (f *FieldDefinition) UnmarshalJSON(bytes []byte) error {
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Deprecated: temp.Deprecated, Order: temp.Order, DefaultValue: temp.DefaultValue}
 	case KindEnum:
 		theType := EnumType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Deprecated: temp.Deprecated, Order: temp.Order, DefaultValue: temp.DefaultValue}
 	default:
 		theType := SimpleType{}
 		err = json.Unmarshal(*temp.Type, &theType)
 		if err != nil {
 			return errs.WithStack(err)
 		}
-		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description}
+		*f = FieldDefinition{Type: theType, Required: temp.Required, Label: temp.Label, Description: temp.Description, Deprecated: temp.Deprecated, Order: temp.Order, DefaultValue: temp.DefaultValue}
 	}
 	return nil
 }
@@ -208,6 +232,36 @@ func ConvertStringToKind(k string) (*Kind, error) {
 	return nil, fmt.Errorf("kind '%s' is not a simple type", k)
 }
 
+// CustomFieldPrefix is the prefix of the names of the fields that are added to
+// work item types at runtime. As the name contains a dot the fields are
+// treated as JSON fields when compiling search expressions.
+const CustomFieldPrefix = "custom."
+
+var customFieldNamePattern = regexp.MustCompile(`^custom\.[a-zA-Z0-9_\-]+$`)
+
+// IsCustomField returns true if the given field name is the name of a custom
+// field; otherwise false is returned.
+func IsCustomField(name string) bool {
+	return customFieldNamePattern.MatchString(name)
+}
+
+// comparableSystemFields are the system fields stored in the jsonb column that
+// hold numbers: the instants (in nanoseconds) and the durations
+var comparableSystemFields = map[string]bool{
+	SystemDueDate:           true,
+	SystemOriginalEstimate:  true,
+	SystemRemainingEstimate: true,
+}
+
+// IsComparableField returns true if the values of the field with the given
+// name can be ordered, i.e. if it is a system field of the kind instant or
+// duration or a custom field. The kind of a custom field differs between
+// spaces, only its numeric values (integers, floats, instants and durations)
+// are ordered.
+func IsComparableField(name string) bool {
+	return comparableSystemFields[name] || IsCustomField(name)
+}
+
 // compatibleFields returns true if the existing and new field are compatible;
 // otherwise false is returned. It does so by comparing all members of the field
 // definition except for the label and description.
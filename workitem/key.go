@@ -0,0 +1,103 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// keyRegexp matches the human-readable keys of work items, i.e., the key
+// prefix of a space followed by a dash and the number of a work item (e.g.
+// "PLAT-123"). Keys are case-insensitive.
+var keyRegexp = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9]{1,9})-([0-9]+)$`)
+
+// ParseKey splits the given human-readable work item key into the (upper
+// case) key prefix of the space and the number of the work item
+// returns BadParameterError
+func ParseKey(key string) (string, int, error) {
+	matches := keyRegexp.FindStringSubmatch(strings.TrimSpace(key))
+	if matches == nil {
+		return "", 0, errors.NewBadParameterError("key", key).Expected("<PREFIX>-<number>")
+	}
+	number, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return "", 0, errors.NewBadParameterError("key", key).Expected("<PREFIX>-<number>")
+	}
+	return strings.ToUpper(matches[1]), number, nil
+}
+
+// LookupIDByKey returns the work item's ID and space ID for the given
+// human-readable key (e.g. "PLAT-123"). Keys that a work item had before it
+// was moved to another space are resolved as well.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemRepository) LookupIDByKey(ctx context.Context, key string) (*uuid.UUID, *uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "lookupIDByKey"}, time.Now())
+	prefix, number, err := ParseKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	query := fmt.Sprintf(`select wi.id, wi.space_id from %[1]s wi
+		where upper(wi.key) = ? and
+		wi.deleted_at IS NULL`,
+		WorkItemStorage{}.TableName())
+	// 'scan' destination must be slice or struct
+	type Result struct {
+		WiID    uuid.UUID `gorm:"column:id"`
+		SpaceID uuid.UUID
+	}
+	var result Result
+	db := r.db.Raw(query, fmt.Sprintf("%s-%d", prefix, number)).Scan(&result)
+	if db.RecordNotFound() {
+		// the work item may have been moved to another space since
+		query = fmt.Sprintf(`select wi.id, wi.space_id from %[1]s m
+			join %[2]s wi on m.work_item_id = wi.id
+			join %[3]s s on m.from_space_id = s.id
+			where s.key_prefix = ? and
+			m.from_number = ? and
+			wi.deleted_at IS NULL and
+			s.deleted_at IS NULL`,
+			Move{}.TableName(), WorkItemStorage{}.TableName(), space.Space{}.TableName())
+		db = r.db.Raw(query, prefix, number).Scan(&result)
+	}
+	if db.RecordNotFound() {
+		return nil, nil, errors.NewNotFoundError("work item", key)
+	}
+	if db.Error != nil {
+		return nil, nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "error while looking up a work item ID"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"wi_key": key,
+	}, "Matching work item with ID='%s' in space with ID='%s'", result.WiID.String(), result.SpaceID.String())
+	return &result.WiID, &result.SpaceID, nil
+}
+
+// lookupKey returns the key of the work item with the given number in the
+// given space, or nil if the space has no key prefix. The key is maintained
+// by a database trigger, this function only computes the same value for the
+// work items in memory.
+// returns InternalError
+func (r *GormWorkItemRepository) lookupKey(ctx context.Context, spaceID uuid.UUID, number int) (*string, error) {
+	var result struct {
+		KeyPrefix *string
+	}
+	db := r.db.Raw(fmt.Sprintf(`SELECT key_prefix FROM %s WHERE id = ?`, space.Space{}.TableName()), spaceID).Scan(&result)
+	if db.Error != nil && !db.RecordNotFound() {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to load the key prefix of space %s", spaceID))
+	}
+	if result.KeyPrefix == nil {
+		return nil, nil
+	}
+	key := fmt.Sprintf("%s-%d", *result.KeyPrefix, number)
+	return &key, nil
+}
@@ -23,6 +23,9 @@ type WorkItemLinkCategory struct {
 	Description *string
 	// Version for optimistic concurrency control
 	Version int
+	// Reference to the space that owns the category, the categories of the
+	// system space can be used in all spaces
+	SpaceID uuid.UUID `sql:"type:uuid"`
 }
 
 // Ensure Fields implements the Equaler interface
@@ -50,6 +53,9 @@ func (c WorkItemLinkCategory) Equal(u convert.Equaler) bool {
 	if !strPtrIsNilOrContentIsEqual(c.Description, other.Description) {
 		return false
 	}
+	if !uuid.Equal(c.SpaceID, other.SpaceID) {
+		return false
+	}
 	return true
 }
 
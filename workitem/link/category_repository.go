@@ -9,9 +9,11 @@ import (
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
 
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -21,6 +23,7 @@ type WorkItemLinkCategoryRepository interface {
 	Create(ctx context.Context, linkCat *WorkItemLinkCategory) (*WorkItemLinkCategory, error)
 	Load(ctx context.Context, ID uuid.UUID) (*WorkItemLinkCategory, error)
 	List(ctx context.Context) ([]WorkItemLinkCategory, error)
+	ListBySpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemLinkCategory, error)
 	Delete(ctx context.Context, ID uuid.UUID) error
 	Save(ctx context.Context, linkCat WorkItemLinkCategory) (*WorkItemLinkCategory, error)
 }
@@ -42,6 +45,9 @@ func (r *GormWorkItemLinkCategoryRepository) Create(ctx context.Context, linkCat
 	if linkCat.Name == "" {
 		return nil, errors.NewBadParameterError("name", linkCat.Name)
 	}
+	if linkCat.SpaceID == uuid.Nil {
+		linkCat.SpaceID = space.SystemSpace
+	}
 	db := r.db.Create(linkCat)
 	if db.Error != nil {
 		if gormsupport.IsUniqueViolation(db.Error, "work_item_link_categories_name_idx") {
@@ -51,6 +57,9 @@ func (r *GormWorkItemLinkCategoryRepository) Create(ctx context.Context, linkCat
 			}, "unable to create work item link category because a category already exists with the same name")
 			return nil, errors.NewDataConflictError(fmt.Sprintf("work item link category already exists with the same name: %s ", linkCat.Name))
 		}
+		if gormsupport.IsForeignKeyViolation(db.Error, "work_item_link_categories_space_id_fkey") {
+			return nil, errors.NewBadParameterError("space", linkCat.SpaceID)
+		}
 		return nil, errors.NewInternalError(ctx, db.Error)
 	}
 	log.Info(ctx, map[string]interface{}{
@@ -98,8 +107,22 @@ func (r *GormWorkItemLinkCategoryRepository) List(ctx context.Context) ([]WorkIt
 	return rows, nil
 }
 
-// Delete deletes the work item link category with the given id
-// returns NotFoundError or InternalError
+// ListBySpace returns the work item link categories that can be used in the
+// space with the given ID, i.e. the ones of the space and of the system space
+// TODO: Handle pagination
+func (r *GormWorkItemLinkCategoryRepository) ListBySpace(ctx context.Context, spaceID uuid.UUID) ([]WorkItemLinkCategory, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlinkcategory", "listBySpace"}, time.Now())
+	var rows []WorkItemLinkCategory
+	db := r.db.Where("space_id IN (?, ?)", spaceID, space.SystemSpace).Find(&rows)
+	if db.Error != nil {
+		return nil, errs.WithStack(db.Error)
+	}
+	return rows, nil
+}
+
+// Delete deletes the work item link category with the given id. Categories
+// that are still used by link types cannot be deleted.
+// returns NotFoundError, DataConflictError or InternalError
 func (r *GormWorkItemLinkCategoryRepository) Delete(ctx context.Context, ID uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemlinkcategory", "delete"}, time.Now())
 	var cat = WorkItemLinkCategory{
@@ -108,7 +131,15 @@ func (r *GormWorkItemLinkCategoryRepository) Delete(ctx context.Context, ID uuid
 	log.Info(ctx, map[string]interface{}{
 		"wilc_id": ID,
 	}, "Work item link category to delete")
-	db := r.db.Delete(&cat)
+	var count int
+	db := r.db.Model(&WorkItemLinkType{}).Where("link_category_id=?", ID).Count(&count)
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, db.Error)
+	}
+	if count > 0 {
+		return errors.NewDataConflictError(fmt.Sprintf("work item link category %s is still used by %d link type(s)", ID, count))
+	}
+	db = r.db.Delete(&cat)
 	if db.Error != nil {
 		return errors.NewInternalError(ctx, db.Error)
 	}
@@ -146,6 +177,8 @@ func (r *GormWorkItemLinkCategoryRepository) Save(ctx context.Context, linkCat W
 		Version:     linkCat.Version + 1,
 		Name:        linkCat.Name,
 		Description: linkCat.Description,
+		// the space of a category cannot be changed
+		SpaceID: res.SpaceID,
 	}
 	db = db.Save(&newLinkCat)
 	if db.Error != nil {
@@ -0,0 +1,247 @@
+package link
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ListDescendantLinks returns the links of a tree topology that lead from the
+// given work item to its descendants, up to the given level or through all
+// levels for AncestorLevelAll. The links are ordered by their level, i.e. the
+// links to the children come first.
+func (r *GormWorkItemLinkRepository) ListDescendantLinks(ctx context.Context, workItemID uuid.UUID, upToLevel int) ([]WorkItemLink, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "get", "descendants"}, time.Now())
+	if upToLevel <= 0 && upToLevel != AncestorLevelAll {
+		return nil, nil
+	}
+	levelLimitation := ""
+	if upToLevel != AncestorLevelAll {
+		levelLimitation = fmt.Sprintf(" AND array_length(w.already_visited, 1) < %d ", upToLevel)
+	}
+	// Postgres Common Table Expression (https://www.postgresql.org/docs/current/static/queries-with.html)
+	query := fmt.Sprintf(`
+		WITH RECURSIVE working_table(id, target_id, already_visited, cycle) AS (
+
+			-- non recursive term: the links to the children of the given item
+
+			SELECT l.id, l.target_id, ARRAY[l.id], false
+			FROM %[1]s l JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE
+				l.source_id = ?
+				AND t.topology = ?
+				AND l.deleted_at IS NULL
+		UNION
+
+			-- recursive term: the links to the children of the targets of
+			-- the links in the "working table"
+
+			SELECT l.id, l.target_id, w.already_visited || l.id, l.id = ANY(w.already_visited)
+			FROM working_table w
+				JOIN %[1]s l ON l.source_id = w.target_id
+				JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE
+				t.topology = ?
+				AND l.deleted_at IS NULL
+				AND NOT w.cycle -- recursive termination criteria
+				%[3]s
+		)
+		SELECT l.* FROM %[1]s l JOIN (
+			SELECT id, min(array_length(already_visited, 1)) AS level
+			FROM working_table
+			WHERE NOT cycle
+			GROUP BY id
+		) w ON w.id = l.id
+		ORDER BY w.level, l.created_at`,
+		WorkItemLink{}.TableName(),
+		WorkItemLinkType{}.TableName(),
+		levelLimitation,
+	)
+	var links []WorkItemLink
+	db := r.db.Raw(query, workItemID, TopologyTree, TopologyTree).Scan(&links)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workItemID,
+			"err":   db.Error,
+		}, "failed to find the descendants of the work item")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find the descendants of work item %s", workItemID))
+	}
+	return links, nil
+}
+
+// ListDependencyLinks returns the transitive closure of the links of a
+// dependency topology of the given work item, i.e. the links to all the work
+// items that it depends on directly or indirectly and from all the work items
+// that depend on it directly or indirectly.
+func (r *GormWorkItemLinkRepository) ListDependencyLinks(ctx context.Context, workItemID uuid.UUID) ([]WorkItemLink, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "get", "dependencies"}, time.Now())
+	// UNION (in contrast to UNION ALL) discards the rows that were already
+	// found, which ends the recursion even if the links formed a cycle
+	query := fmt.Sprintf(`
+		WITH RECURSIVE forward(id, target_id) AS (
+			SELECT l.id, l.target_id
+			FROM %[1]s l JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE l.source_id = ? AND t.topology = ? AND l.deleted_at IS NULL
+		UNION
+			SELECT l.id, l.target_id
+			FROM forward f
+				JOIN %[1]s l ON l.source_id = f.target_id
+				JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE t.topology = ? AND l.deleted_at IS NULL
+		), backward(id, source_id) AS (
+			SELECT l.id, l.source_id
+			FROM %[1]s l JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE l.target_id = ? AND t.topology = ? AND l.deleted_at IS NULL
+		UNION
+			SELECT l.id, l.source_id
+			FROM backward b
+				JOIN %[1]s l ON l.target_id = b.source_id
+				JOIN %[2]s t ON t.id = l.link_type_id
+			WHERE t.topology = ? AND l.deleted_at IS NULL
+		)
+		SELECT l.* FROM %[1]s l
+		WHERE l.id IN (SELECT id FROM forward UNION SELECT id FROM backward)
+		ORDER BY l.created_at`,
+		WorkItemLink{}.TableName(),
+		WorkItemLinkType{}.TableName(),
+	)
+	var links []WorkItemLink
+	db := r.db.Raw(query, workItemID, TopologyDependency, TopologyDependency, workItemID, TopologyDependency, TopologyDependency).Scan(&links)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id": workItemID,
+			"err":   db.Error,
+		}, "failed to find the dependencies of the work item")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find the dependencies of work item %s", workItemID))
+	}
+	return links, nil
+}
+
+// GraphNode is a work item in a Graph
+type GraphNode struct {
+	ID    uuid.UUID
+	Label string
+}
+
+// Graph holds work items and the links between them for an export to one of
+// the formats of graph visualization tools
+type Graph struct {
+	Nodes []GraphNode
+	Links []WorkItemLink
+	// LinkTypeNames holds the forward names of the link types by their IDs,
+	// which are used as the labels of the links
+	LinkTypeNames map[uuid.UUID]string
+}
+
+// dotQuote returns the given string as a quoted DOT identifier
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "").Replace(s) + `"`
+}
+
+// WriteDOT writes the graph in the DOT language of Graphviz (see
+// https://graphviz.gitlab.io/_pages/doc/info/lang.html) to the given writer
+func (g Graph) WriteDOT(w io.Writer) error {
+	var b bytes.Buffer
+	b.WriteString("digraph workitems {\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\t%s [label=%s];\n", dotQuote(n.ID.String()), dotQuote(n.Label))
+	}
+	for _, l := range g.Links {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s];\n", dotQuote(l.SourceID.String()), dotQuote(l.TargetID.String()), dotQuote(g.LinkTypeNames[l.LinkTypeID]))
+	}
+	b.WriteString("}\n")
+	_, err := b.WriteTo(w)
+	return errs.Wrap(err, "failed to write the DOT graph")
+}
+
+// the elements of a GraphML document (see http://graphml.graphdrawing.org/)
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLNode struct {
+	ID   string      `xml:"id,attr"`
+	Data graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Data   graphMLData `xml:"data"`
+}
+
+// WriteGraphML writes the graph as a GraphML document (see
+// http://graphml.graphdrawing.org/) to the given writer
+func (g Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "type", For: "edge", AttrName: "type", AttrType: "string"},
+		},
+		Graph: graphMLGraph{
+			ID:          "workitems",
+			EdgeDefault: "directed",
+			Nodes:       make([]graphMLNode, len(g.Nodes)),
+			Edges:       make([]graphMLEdge, len(g.Links)),
+		},
+	}
+	for i, n := range g.Nodes {
+		doc.Graph.Nodes[i] = graphMLNode{
+			ID:   n.ID.String(),
+			Data: graphMLData{Key: "label", Value: n.Label},
+		}
+	}
+	for i, l := range g.Links {
+		doc.Graph.Edges[i] = graphMLEdge{
+			ID:     l.ID.String(),
+			Source: l.SourceID.String(),
+			Target: l.TargetID.String(),
+			Data:   graphMLData{Key: "type", Value: g.LinkTypeNames[l.LinkTypeID]},
+		}
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return errs.Wrap(err, "failed to write the GraphML graph")
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return errs.Wrap(err, "failed to write the GraphML graph")
+	}
+	_, err := io.WriteString(w, "\n")
+	return errs.Wrap(err, "failed to write the GraphML graph")
+}
@@ -0,0 +1,79 @@
+package link_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGraph() link.Graph {
+	a := uuid.FromStringOrNil("00000000-0000-0000-0000-00000000000a")
+	b := uuid.FromStringOrNil("00000000-0000-0000-0000-00000000000b")
+	linkTypeID := uuid.FromStringOrNil("00000000-0000-0000-0000-000000000001")
+	return link.Graph{
+		Nodes: []link.GraphNode{
+			{ID: a, Label: `say "hello"`},
+			{ID: b, Label: "B & C"},
+		},
+		Links: []link.WorkItemLink{
+			{ID: uuid.FromStringOrNil("00000000-0000-0000-0000-000000000002"), SourceID: a, TargetID: b, LinkTypeID: linkTypeID},
+		},
+		LinkTypeNames: map[uuid.UUID]string{linkTypeID: "parent of"},
+	}
+}
+
+func TestGraphWriteDOT(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// when
+	var buf bytes.Buffer
+	err := newTestGraph().WriteDOT(&buf)
+	// then
+	require.NoError(t, err)
+	expected := "digraph workitems {\n" +
+		"\t\"00000000-0000-0000-0000-00000000000a\" [label=\"say \\\"hello\\\"\"];\n" +
+		"\t\"00000000-0000-0000-0000-00000000000b\" [label=\"B & C\"];\n" +
+		"\t\"00000000-0000-0000-0000-00000000000a\" -> \"00000000-0000-0000-0000-00000000000b\" [label=\"parent of\"];\n" +
+		"}\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestGraphWriteGraphML(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	// when
+	var buf bytes.Buffer
+	err := newTestGraph().WriteGraphML(&buf)
+	// then
+	require.NoError(t, err)
+	var doc struct {
+		Graph struct {
+			EdgeDefault string `xml:"edgedefault,attr"`
+			Nodes       []struct {
+				ID   string `xml:"id,attr"`
+				Data string `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+				Data   string `xml:"data"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "directed", doc.Graph.EdgeDefault)
+	require.Len(t, doc.Graph.Nodes, 2)
+	assert.Equal(t, "00000000-0000-0000-0000-00000000000a", doc.Graph.Nodes[0].ID)
+	assert.Equal(t, `say "hello"`, doc.Graph.Nodes[0].Data)
+	assert.Equal(t, "B & C", doc.Graph.Nodes[1].Data)
+	require.Len(t, doc.Graph.Edges, 1)
+	assert.Equal(t, "00000000-0000-0000-0000-00000000000a", doc.Graph.Edges[0].Source)
+	assert.Equal(t, "00000000-0000-0000-0000-00000000000b", doc.Graph.Edges[0].Target)
+	assert.Equal(t, "parent of", doc.Graph.Edges[0].Data)
+}
@@ -39,12 +39,28 @@ type WorkItemLinkRepository interface {
 	List(ctx context.Context) ([]WorkItemLink, error)
 	ListByWorkItem(ctx context.Context, wiID uuid.UUID) ([]WorkItemLink, error)
 	DeleteRelatedLinks(ctx context.Context, wiID uuid.UUID, suppressorID uuid.UUID) error
+	RestoreRelatedLinks(ctx context.Context, wiID uuid.UUID, modifierID uuid.UUID) ([]WorkItemLink, error)
 	Delete(ctx context.Context, ID uuid.UUID, suppressorID uuid.UUID) error
+	Restore(ctx context.Context, ID uuid.UUID, modifierID uuid.UUID) (*WorkItemLink, error)
 	ListChildLinks(ctx context.Context, linkTypeID uuid.UUID, parentIDs ...uuid.UUID) (WorkItemLinkList, error)
 	ListWorkItemChildren(ctx context.Context, parentID uuid.UUID, start *int, limit *int) ([]workitem.WorkItem, int, error)
 	WorkItemHasChildren(ctx context.Context, parentID uuid.UUID) (bool, error)
 	// GetAncestors returns all ancestors for the given work items.
 	GetAncestors(ctx context.Context, linkTypeID uuid.UUID, upToLevel int, workItemIDs ...uuid.UUID) (ancestors AncestorList, err error)
+	// ListDescendantLinks returns the links of a tree topology to all
+	// descendants of the given work item up to the given level.
+	ListDescendantLinks(ctx context.Context, workItemID uuid.UUID, upToLevel int) ([]WorkItemLink, error)
+	// ListDependencyLinks returns the transitive closure of the links of a
+	// dependency topology of the given work item.
+	ListDependencyLinks(ctx context.Context, workItemID uuid.UUID) ([]WorkItemLink, error)
+	// LoadRollUps returns the values of the roll-ups of the types of the
+	// given work items by their IDs, computed from their children and cached
+	// until the children or the links to the children change.
+	LoadRollUps(ctx context.Context, wis ...workitem.WorkItem) (map[uuid.UUID]map[string]interface{}, error)
+	// ComputeSchedule computes the earliest and latest dates, the slack and
+	// the critical path of the given work items over the links of the
+	// dependency topology between them.
+	ComputeSchedule(ctx context.Context, workItemIDs []uuid.UUID, start time.Time) (*Schedule, error)
 }
 
 // NewWorkItemLinkRepository creates a work item link repository based on gorm
@@ -243,6 +259,21 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err != nil {
 		return nil, errs.Wrap(err, "failed to load link type")
 	}
+	if !inSpace(linkType.SpaceID, spaceID) {
+		return nil, errors.NewBadParameterError("link type", linkTypeID).Expected("a link type of the space of the work items")
+	}
+	var sourceTypeID, targetTypeID uuid.UUID
+	for _, item := range items {
+		if uuid.Equal(item.ID, sourceID) {
+			sourceTypeID = item.Type
+		}
+		if uuid.Equal(item.ID, targetID) {
+			targetTypeID = item.Type
+		}
+	}
+	if err := linkType.CheckValidForTypes(sourceTypeID, targetTypeID); err != nil {
+		return nil, errs.Wrap(err, "failed to create work item link due to a type constraint of the link type")
+	}
 
 	// Make sure we don't violate the topology when we add the link from source
 	// to target.
@@ -271,6 +302,9 @@ func (r *GormWorkItemLinkRepository) Create(ctx context.Context, sourceID, targe
 	if err := r.revisionRepo.Create(ctx, creatorID, RevisionTypeCreate, *link); err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	if err := r.invalidateRollUps(ctx, *link); err != nil {
+		return nil, errs.Wrapf(err, "error while creating work item link")
+	}
 	return link, nil
 }
 
@@ -353,6 +387,57 @@ func (r *GormWorkItemLinkRepository) Delete(ctx context.Context, linkID uuid.UUI
 	return nil
 }
 
+// Restore undeletes the soft-deleted work item link with the given id. The
+// source and target of the link must still exist and the link must not
+// violate the topology of its link type.
+// Returns NotFoundError, BadParameterError, DataConflictError or InternalError
+func (r *GormWorkItemLinkRepository) Restore(ctx context.Context, linkID uuid.UUID, modifierID uuid.UUID) (*WorkItemLink, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "restore"}, time.Now())
+	var lnk = WorkItemLink{}
+	tx := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", linkID).First(&lnk)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("deleted work item link", linkID.String())
+	}
+	if tx.Error != nil {
+		return nil, errors.NewInternalError(ctx, tx.Error)
+	}
+	wiRepo := workitem.NewWorkItemRepository(r.db)
+	workItemIDs := []uuid.UUID{lnk.SourceID, lnk.TargetID}
+	items, err := wiRepo.LoadBatchByID(ctx, workItemIDs)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load source and target work items: %+v", workItemIDs)
+	}
+	if len(items) != len(workItemIDs) {
+		return nil, errors.NewBadParameterError("link", linkID).Expected("a link between existing work items")
+	}
+	if err := r.acquireLock(items[0].SpaceID); err != nil {
+		return nil, errs.Wrap(err, "failed to acquire lock during link restoration")
+	}
+	linkType, err := r.workItemLinkTypeRepo.Load(ctx, lnk.LinkTypeID)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to load link type")
+	}
+	if err := r.ValidateTopology(ctx, lnk.SourceID, lnk.TargetID, *linkType); err != nil {
+		return nil, errs.Wrapf(err, "failed to restore work item link due to topology violation")
+	}
+	db := r.db.Unscoped().Model(&lnk).Update("deleted_at", nil)
+	if db.Error != nil {
+		if gormsupport.IsUniqueViolation(db.Error, "work_item_links_unique_idx") {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("work item link already exists with data.relationships.source_id: %s; data.relationships.target_id: %s; data.relationships.link_type_id: %s ", lnk.SourceID, lnk.TargetID, lnk.LinkTypeID))
+		}
+		return nil, errors.NewInternalError(ctx, db.Error)
+	}
+	lnk.DeletedAt = nil
+	// save a revision of the restored work item link
+	if err := r.revisionRepo.Create(ctx, modifierID, RevisionTypeCreate, lnk); err != nil {
+		return nil, errs.Wrapf(err, "error while restoring work item link")
+	}
+	if err := r.invalidateRollUps(ctx, lnk); err != nil {
+		return nil, errs.Wrapf(err, "error while restoring work item link")
+	}
+	return &lnk, nil
+}
+
 // DeleteRelatedLinks deletes all links in which the source or target equals the
 // given work item ID.
 func (r *GormWorkItemLinkRepository) DeleteRelatedLinks(ctx context.Context, wiID uuid.UUID, suppressorID uuid.UUID) error {
@@ -366,12 +451,18 @@ func (r *GormWorkItemLinkRepository) DeleteRelatedLinks(ctx context.Context, wiI
 	locked := false
 	for _, workitemLink := range workitemLinks {
 		if !locked {
+			// the given work item may already be deleted, so the space is
+			// taken from the other end of the link
+			otherID := workitemLink.SourceID
+			if otherID == wiID {
+				otherID = workitemLink.TargetID
+			}
 			wiRepo := workitem.NewWorkItemRepository(r.db)
-			src, err := wiRepo.LoadByID(ctx, workitemLink.SourceID)
+			other, err := wiRepo.LoadByID(ctx, otherID)
 			if err != nil {
-				return errs.Wrapf(err, "failed to load source work item for: %s", workitemLink.SourceID)
+				return errs.Wrapf(err, "failed to load linked work item for: %s", otherID)
 			}
-			if err := r.acquireLock(src.SpaceID); err != nil {
+			if err := r.acquireLock(other.SpaceID); err != nil {
 				return errs.Wrap(err, "failed to acquire lock during link deletion")
 			}
 			locked = true
@@ -381,6 +472,62 @@ func (r *GormWorkItemLinkRepository) DeleteRelatedLinks(ctx context.Context, wiI
 	return nil
 }
 
+// RestoreRelatedLinks restores the links of the given (restored) work item
+// that were deleted together with it by DeleteRelatedLinks, i.e. the links
+// deleted since the last deletion of the work item. Links that were deleted
+// before, links to work items that are still deleted and links that would
+// violate the topology of their link type by now are not restored.
+// returns the restored links
+func (r *GormWorkItemLinkRepository) RestoreRelatedLinks(ctx context.Context, wiID uuid.UUID, modifierID uuid.UUID) ([]WorkItemLink, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "restoreRelatedLinks"}, time.Now())
+	var deletedAt *time.Time
+	row := r.db.Model(&workitem.Revision{}).
+		Where("work_item_id = ? AND revision_type = ?", wiID, workitem.RevisionTypeDelete).
+		Select("max(revision_time)").Row()
+	if err := row.Scan(&deletedAt); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to find the deletion of work item %s", wiID))
+	}
+	if deletedAt == nil {
+		return nil, nil
+	}
+	// links that were recreated in the meantime are not restored
+	var deletedLinks []WorkItemLink
+	db := r.db.Unscoped().
+		Where("? IN (source_id, target_id) AND deleted_at >= ?", wiID, *deletedAt).
+		Where(fmt.Sprintf("source_id IN (SELECT id FROM %[1]s WHERE deleted_at IS NULL) AND target_id IN (SELECT id FROM %[1]s WHERE deleted_at IS NULL)", workitem.WorkItemStorage{}.TableName())).
+		Where(fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM %[1]s l WHERE l.deleted_at IS NULL AND l.source_id = %[1]s.source_id
+			AND l.target_id = %[1]s.target_id AND l.link_type_id = %[1]s.link_type_id)`, WorkItemLink{}.TableName())).
+		Order("deleted_at").
+		Find(&deletedLinks)
+	if db.Error != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find the deleted links of work item %s", wiID))
+	}
+	restored := []WorkItemLink{}
+	for _, l := range deletedLinks {
+		linkType, err := r.workItemLinkTypeRepo.Load(ctx, l.LinkTypeID)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to load link type")
+		}
+		if err := r.ValidateTopology(ctx, l.SourceID, l.TargetID, *linkType); err != nil {
+			if _, ok := errs.Cause(err).(errors.InternalError); ok {
+				return nil, errs.Wrapf(err, "failed to validate the topology of work item link %s", l.ID)
+			}
+			log.Warn(ctx, map[string]interface{}{
+				"wi_id":  wiID,
+				"wil_id": l.ID,
+				"err":    err,
+			}, "not restoring the work item link because it violates the topology of its link type")
+			continue
+		}
+		lnk, err := r.Restore(ctx, l.ID, modifierID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "failed to restore work item link %s", l.ID)
+		}
+		restored = append(restored, *lnk)
+	}
+	return restored, nil
+}
+
 // Delete deletes the work item link with the given id
 // returns NotFoundError or InternalError
 func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkItemLink, suppressorID uuid.UUID) error {
@@ -403,9 +550,21 @@ func (r *GormWorkItemLinkRepository) deleteLink(ctx context.Context, lnk WorkIte
 	if err := r.revisionRepo.Create(ctx, suppressorID, RevisionTypeDelete, lnk); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	if err := r.invalidateRollUps(ctx, lnk); err != nil {
+		return errs.Wrapf(err, "error while deleting work item link")
+	}
 	return nil
 }
 
+// invalidateRollUps invalidates the cached roll-up values of the source of
+// the given link if they are computed from the target
+func (r *GormWorkItemLinkRepository) invalidateRollUps(ctx context.Context, lnk WorkItemLink) error {
+	if !uuid.Equal(lnk.LinkTypeID, SystemWorkItemLinkTypeParentChildID) {
+		return nil
+	}
+	return workitem.NewRollUpCache(r.db).Invalidate(ctx, lnk.SourceID)
+}
+
 // ListChildLinks gets all links to children for the given parents
 func (r *GormWorkItemLinkRepository) ListChildLinks(ctx context.Context, linkTypeID uuid.UUID, parentIDs ...uuid.UUID) (WorkItemLinkList, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "list", "children", "ids"}, time.Now())
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
@@ -204,6 +205,98 @@ func (s *linkRepoBlackBoxTest) TestWorkItemHasChildren() {
 	})
 }
 
+func (s *linkRepoBlackBoxTest) TestRestore() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a deleted work item link
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItemLinks(1, tf.BuildLinks(tf.LinkChain("A", "B")...)),
+		)
+		require.NoError(t, s.workitemLinkRepo.Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID))
+		// when
+		restored, err := s.workitemLinkRepo.Restore(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemLinks[0].ID, restored.ID)
+		_, err = s.workitemLinkRepo.Load(s.Ctx, fxt.WorkItemLinks[0].ID)
+		require.NoError(t, err)
+	})
+	s.T().Run("fail", func(t *testing.T) {
+		t.Run("link not deleted", func(t *testing.T) {
+			fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinks(1))
+			_, err := s.workitemLinkRepo.Restore(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID)
+			require.IsType(t, errors.NotFoundError{}, err)
+		})
+		t.Run("single-parent violation in tree topology", func(t *testing.T) {
+			// given a deleted link whose child got another parent meanwhile
+			fxt := tf.NewTestFixture(t, s.DB,
+				tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C")),
+				tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+				tf.WorkItemLinks(1, tf.BuildLinks(tf.LinkChain("A", "B")...)),
+			)
+			require.NoError(t, s.workitemLinkRepo.Delete(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID))
+			_, err := s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("C").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID)
+			require.NoError(t, err)
+			// when
+			_, err = s.workitemLinkRepo.Restore(s.Ctx, fxt.WorkItemLinks[0].ID, fxt.Identities[0].ID)
+			// then
+			require.Error(t, err)
+		})
+	})
+}
+
+func (s *linkRepoBlackBoxTest) TestRestoreRelatedLinks() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given a work item B with a parent A, a child C and a link to D
+		// that was deleted before B was deleted
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(4, tf.SetWorkItemTitles("A", "B", "C", "D")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItemLinks(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("B", "D"))),
+		)
+		b := fxt.WorkItemByTitle("B")
+		require.NoError(t, s.workitemLinkRepo.Delete(s.Ctx, fxt.WorkItemLinks[2].ID, fxt.Identities[0].ID))
+		require.NoError(t, s.workitemRepo.Delete(s.Ctx, b.ID, fxt.Identities[0].ID))
+		require.NoError(t, s.workitemLinkRepo.DeleteRelatedLinks(s.Ctx, b.ID, fxt.Identities[0].ID))
+		_, err := s.workitemRepo.Restore(s.Ctx, b.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		restored, err := s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, b.ID, fxt.Identities[0].ID)
+		// then only the links deleted together with B are restored
+		require.NoError(t, err)
+		restoredIDs := []uuid.UUID{}
+		for _, l := range restored {
+			restoredIDs = append(restoredIDs, l.ID)
+		}
+		assert.ElementsMatch(t, []uuid.UUID{fxt.WorkItemLinks[0].ID, fxt.WorkItemLinks[1].ID}, restoredIDs)
+		links, err := s.workitemLinkRepo.ListByWorkItem(s.Ctx, b.ID)
+		require.NoError(t, err)
+		assert.Len(t, links, 2)
+	})
+	s.T().Run("skips links violating the topology", func(t *testing.T) {
+		// given a work item B whose former parent A got the child B again
+		// through a new work item
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C")),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+			tf.WorkItemLinks(1, tf.BuildLinks(tf.L("A", "B"))),
+		)
+		b := fxt.WorkItemByTitle("B")
+		require.NoError(t, s.workitemRepo.Delete(s.Ctx, b.ID, fxt.Identities[0].ID))
+		require.NoError(t, s.workitemLinkRepo.DeleteRelatedLinks(s.Ctx, b.ID, fxt.Identities[0].ID))
+		_, err := s.workitemRepo.Restore(s.Ctx, b.ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		_, err = s.workitemLinkRepo.Create(s.Ctx, fxt.WorkItemByTitle("C").ID, b.ID, fxt.WorkItemLinkTypes[0].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		restored, err := s.workitemLinkRepo.RestoreRelatedLinks(s.Ctx, b.ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, restored)
+	})
+}
+
 func (s *linkRepoBlackBoxTest) TestValidateTopology() {
 	// given 2 work items linked with one tree-topology link type
 	fxt := tf.NewTestFixture(s.T(), s.DB,
@@ -857,3 +950,235 @@ func (s *linkRepoBlackBoxTest) TestListChildLinks() {
 		require.True(t, foundAC, "failed to find link A-C")
 	})
 }
+
+func (s *linkRepoBlackBoxTest) TestListDescendantLinks() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyTree)),
+		tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "D", "E")),
+		tf.WorkItemLinksCustom(4, tf.BuildLinks(append(tf.LinkChain("A", "B", "C", "D"), tf.L("A", "E"))...)),
+	)
+	linkNames := func(links []link.WorkItemLink) []string {
+		res := make([]string, len(links))
+		for i, l := range links {
+			res[i] = fxt.WorkItemByID(l.SourceID).Fields[workitem.SystemTitle].(string) + "-" + fxt.WorkItemByID(l.TargetID).Fields[workitem.SystemTitle].(string)
+		}
+		return res
+	}
+	s.T().Run("all levels", func(t *testing.T) {
+		// when
+		links, err := s.workitemLinkRepo.ListDescendantLinks(s.Ctx, fxt.WorkItemByTitle("A").ID, link.AncestorLevelAll)
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"A-B", "A-E", "B-C", "C-D"}, linkNames(links))
+		// links to the children come first
+		assert.ElementsMatch(t, []string{"A-B", "A-E"}, linkNames(links[:2]))
+	})
+	s.T().Run("up to level 2", func(t *testing.T) {
+		// when
+		links, err := s.workitemLinkRepo.ListDescendantLinks(s.Ctx, fxt.WorkItemByTitle("A").ID, 2)
+		// then
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"A-B", "A-E", "B-C"}, linkNames(links))
+	})
+	s.T().Run("leaf", func(t *testing.T) {
+		// when
+		links, err := s.workitemLinkRepo.ListDescendantLinks(s.Ctx, fxt.WorkItemByTitle("D").ID, link.AncestorLevelAll)
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, links)
+	})
+}
+
+func (s *linkRepoBlackBoxTest) TestListDependencyLinks() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+		tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "D", "E")),
+		tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "B"), tf.L("B", "C"), tf.L("D", "E"))),
+	)
+	// when
+	links, err := s.workitemLinkRepo.ListDependencyLinks(s.Ctx, fxt.WorkItemByTitle("B").ID)
+	// then
+	require.NoError(s.T(), err)
+	ids := make([]uuid.UUID, len(links))
+	for i, l := range links {
+		ids[i] = l.ID
+	}
+	assert.ElementsMatch(s.T(), []uuid.UUID{fxt.WorkItemLinks[0].ID, fxt.WorkItemLinks[1].ID}, ids)
+}
+
+func (s *linkRepoBlackBoxTest) TestLoadRollUps() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].Fields["custom.points"] = workitem.FieldDefinition{
+				Label: "Points",
+				Type:  workitem.SimpleType{Kind: workitem.KindFloat},
+			}
+			fxt.WorkItemTypes[idx].RollUps = workitem.RollUps{
+				{Name: "points", Function: workitem.RollUpSum, Field: "custom.points"},
+				{Name: "children", Function: workitem.RollUpCount},
+				{Name: "progress", Function: workitem.RollUpProgress},
+			}
+			return nil
+		}),
+		tf.WorkItems(4, tf.SetWorkItemTitles("parent", "A", "B", "C"), func(fxt *tf.TestFixture, idx int) error {
+			if idx > 0 {
+				fxt.WorkItems[idx].Fields["custom.points"] = float64(idx)
+			}
+			if idx == 3 {
+				fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateClosed
+			}
+			return nil
+		}),
+		tf.WorkItemLinksCustom(3,
+			tf.BuildLinks(tf.L("parent", "A"), tf.L("parent", "B"), tf.L("parent", "C")),
+			func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinks[idx].LinkTypeID = link.SystemWorkItemLinkTypeParentChildID
+				return nil
+			},
+		),
+	)
+	parent := *fxt.WorkItemByTitle("parent")
+
+	s.T().Run("computed from the children", func(t *testing.T) {
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent)
+		// then
+		require.NoError(t, err)
+		require.Contains(t, values, parent.ID)
+		assert.Equal(t, float64(6), values[parent.ID]["points"])
+		assert.Equal(t, 3, values[parent.ID]["children"])
+		require.IsType(t, float64(0), values[parent.ID]["progress"])
+		assert.InDelta(t, 100.0/3, values[parent.ID]["progress"], 0.001)
+	})
+	s.T().Run("several work items at once", func(t *testing.T) {
+		// given
+		a := *fxt.WorkItemByTitle("A")
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent, a)
+		// then
+		require.NoError(t, err)
+		require.Len(t, values, 2)
+		assert.Equal(t, float64(6), values[parent.ID]["points"])
+		assert.Equal(t, 0, values[a.ID]["children"])
+	})
+	s.T().Run("no children", func(t *testing.T) {
+		// given
+		a := *fxt.WorkItemByTitle("A")
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, a)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(0), values[a.ID]["points"])
+		assert.Equal(t, 0, values[a.ID]["children"])
+		assert.Nil(t, values[a.ID]["progress"])
+	})
+	s.T().Run("no roll-ups", func(t *testing.T) {
+		// given a work item of a type without roll-ups
+		other := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, *other.WorkItems[0])
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+	s.T().Run("cached until a child is updated", func(t *testing.T) {
+		// given cached values and a child that is changed without the
+		// repository
+		_, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent)
+		require.NoError(t, err)
+		b := fxt.WorkItemByTitle("B")
+		require.NoError(t, s.DB.Exec(`UPDATE work_items SET fields = jsonb_set(fields, '{custom.points}', '7') WHERE id = ?`, b.ID).Error)
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent)
+		// then the cached values are returned
+		require.NoError(t, err)
+		assert.Equal(t, float64(6), values[parent.ID]["points"])
+		assert.Equal(t, 3, values[parent.ID]["children"])
+		// restore the child
+		require.NoError(t, s.DB.Exec(`UPDATE work_items SET fields = jsonb_set(fields, '{custom.points}', '2') WHERE id = ?`, b.ID).Error)
+	})
+	s.T().Run("recomputed after a child update", func(t *testing.T) {
+		// given
+		child := *fxt.WorkItemByTitle("A")
+		child.Fields["custom.points"] = float64(10)
+		_, err := s.workitemRepo.Save(s.Ctx, child.SpaceID, child, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(15), values[parent.ID]["points"])
+	})
+	s.T().Run("recomputed after a link deletion", func(t *testing.T) {
+		// given
+		err := s.workitemLinkRepo.Delete(s.Ctx, fxt.WorkItemLinks[2].ID, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		// when
+		values, err := s.workitemLinkRepo.LoadRollUps(s.Ctx, parent)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float64(12), values[parent.ID]["points"])
+		assert.Equal(t, 2, values[parent.ID]["children"])
+		assert.Equal(t, float64(0), values[parent.ID]["progress"])
+	})
+}
+
+func (s *linkRepoBlackBoxTest) TestComputeSchedule() {
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].Fields[workitem.SystemRemainingEstimate] = workitem.FieldDefinition{
+					Label: "Remaining estimate",
+					Type:  workitem.SimpleType{Kind: workitem.KindDuration},
+				}
+				return nil
+			}),
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)),
+			tf.WorkItems(3, tf.SetWorkItemTitles("A", "B", "C"), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemRemainingEstimate] = float64((idx + 1) * 3600)
+				return nil
+			}),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "C"))),
+		)
+		a, b, c := fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID
+		// when
+		schedule, err := s.workitemLinkRepo.ComputeSchedule(s.Ctx, []uuid.UUID{a, b, c}, start)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(4*time.Hour), schedule.Finish)
+		assert.Equal(t, []uuid.UUID{a, c}, schedule.CriticalPath)
+		require.Len(t, schedule.Items, 3)
+		assert.Equal(t, b, schedule.Items[1].ID)
+		assert.Equal(t, 2*time.Hour, schedule.Items[1].Slack)
+	})
+	s.T().Run("fail - cycle", func(t *testing.T) {
+		// given a cycle across two link types, which is not prevented when
+		// the links are created
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(2, tf.SetTopologies(link.TopologyDependency, link.TopologyDependency)),
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinksCustom(2,
+				tf.BuildLinks(tf.L("A", "B"), tf.L("B", "A")),
+				func(fxt *tf.TestFixture, idx int) error {
+					fxt.WorkItemLinks[idx].LinkTypeID = fxt.WorkItemLinkTypes[idx].ID
+					return nil
+				},
+			),
+		)
+		// when
+		_, err := s.workitemLinkRepo.ComputeSchedule(s.Ctx, []uuid.UUID{fxt.WorkItemByTitle("A").ID, fxt.WorkItemByTitle("B").ID}, start)
+		// then
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+	s.T().Run("fail - unknown work item", func(t *testing.T) {
+		// when
+		_, err := s.workitemLinkRepo.ComputeSchedule(s.Ctx, []uuid.UUID{uuid.NewV4()}, start)
+		// then
+		require.IsType(t, errors.NotFoundError{}, err)
+	})
+}
@@ -18,6 +18,8 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, l WorkItemLink) error
 	// List retrieves all revisions for a given work item link
 	List(ctx context.Context, workitemID uuid.UUID) ([]Revision, error)
+	// ListForWorkItem retrieves all revisions of the links that have the given work item as source or target
+	ListForWorkItem(ctx context.Context, workitemID uuid.UUID) ([]Revision, error)
 }
 
 // NewRevisionRepository creates a GormCommentRevisionRepository
@@ -64,3 +66,13 @@ func (r *GormWorkItemLinkRevisionRepository) List(ctx context.Context, linkID uu
 	}
 	return revisions, nil
 }
+
+// ListForWorkItem retrieves all revisions of the links that have the given work item as source or target
+func (r *GormWorkItemLinkRevisionRepository) ListForWorkItem(ctx context.Context, workitemID uuid.UUID) ([]Revision, error) {
+	log.Debug(nil, map[string]interface{}{}, "List all revisions for work item links of work item with ID=%v", workitemID.String())
+	var revisions []Revision
+	if err := r.db.Where("work_item_link_source_id = ? OR work_item_link_target_id = ?", workitemID.String(), workitemID.String()).Order("revision_time asc").Find(&revisions).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to retrieve work item link revisions"))
+	}
+	return revisions, nil
+}
@@ -0,0 +1,108 @@
+package link
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// LoadRollUps returns the values of the roll-ups of the types of the given
+// work items by the IDs of the work items and the names of the roll-ups. The
+// values are computed from the children of the work items in the
+// parent-child link tree and cached until the children or the links to the
+// children change. The children of all the given work items whose values are
+// not cached are loaded at once. Work items whose type has no roll-ups have
+// no entry in the returned map.
+// returns NotFoundError or InternalError
+func (r *GormWorkItemLinkRepository) LoadRollUps(ctx context.Context, wis ...workitem.WorkItem) (map[uuid.UUID]map[string]interface{}, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "rollups"}, time.Now())
+	result := map[uuid.UUID]map[string]interface{}{}
+	rollUpsByType := map[uuid.UUID]workitem.RollUps{}
+	var parentIDs []uuid.UUID
+	for _, wi := range wis {
+		rollUps, ok := rollUpsByType[wi.Type]
+		if !ok {
+			// the type is not taken from the cache of this instance, which
+			// may hold the former roll-ups of the type
+			wit, err := r.workItemTypeRepo.LoadTypeFromDB(ctx, wi.Type)
+			if err != nil {
+				return nil, errs.Wrapf(err, "failed to load the type of work item %s", wi.ID)
+			}
+			rollUps = wit.RollUps
+			rollUpsByType[wi.Type] = rollUps
+		}
+		if len(rollUps) > 0 {
+			parentIDs = append(parentIDs, wi.ID)
+		}
+	}
+	if len(parentIDs) == 0 {
+		return result, nil
+	}
+	rollUpsByID := make(map[uuid.UUID]workitem.RollUps, len(parentIDs))
+	for _, wi := range wis {
+		if rollUps := rollUpsByType[wi.Type]; len(rollUps) > 0 {
+			rollUpsByID[wi.ID] = rollUps
+		}
+	}
+	cache := workitem.NewRollUpCache(r.db)
+	cached, err := cache.Load(ctx, rollUpsByID)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to load the cached roll-up values of the work items")
+	}
+	var staleIDs []uuid.UUID
+	for _, id := range parentIDs {
+		if c, ok := cached[id]; ok && c.Values != nil {
+			result[id] = c.Values
+		} else {
+			staleIDs = append(staleIDs, id)
+		}
+	}
+	if len(staleIDs) == 0 {
+		return result, nil
+	}
+	childLinks, err := r.ListChildLinks(ctx, SystemWorkItemLinkTypeParentChildID, staleIDs...)
+	if err != nil {
+		return nil, errs.Wrap(err, "failed to find the children of the work items")
+	}
+	childrenByParent := map[uuid.UUID][]*workitem.WorkItem{}
+	if len(childLinks) > 0 {
+		childIDs := make([]uuid.UUID, len(childLinks))
+		for i, l := range childLinks {
+			childIDs[i] = l.TargetID
+		}
+		children, err := r.workItemRepo.LoadBatchByID(ctx, childIDs)
+		if err != nil {
+			return nil, errs.Wrap(err, "failed to load the children of the work items")
+		}
+		childByID := make(map[uuid.UUID]*workitem.WorkItem, len(children))
+		for _, child := range children {
+			childByID[child.ID] = child
+		}
+		for _, l := range childLinks {
+			if child, ok := childByID[l.TargetID]; ok {
+				childrenByParent[l.SourceID] = append(childrenByParent[l.SourceID], child)
+			}
+		}
+	}
+	for _, id := range staleIDs {
+		if _, ok := result[id]; ok {
+			// the same work item was given twice
+			continue
+		}
+		values := rollUpsByID[id].Compute(childrenByParent[id])
+		var c *workitem.CachedRollUps
+		if loaded, ok := cached[id]; ok {
+			c = &loaded
+		}
+		if err := cache.Store(ctx, id, c, values); err != nil {
+			return nil, errs.Wrapf(err, "failed to cache the roll-up values of work item %s", id)
+		}
+		result[id] = values
+	}
+	return result, nil
+}
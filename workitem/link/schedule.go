@@ -0,0 +1,250 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// ScheduledWorkItem holds the dates computed for a work item by the critical
+// path method
+type ScheduledWorkItem struct {
+	ID    uuid.UUID
+	Title string
+	// Duration is the remaining effort of the work item
+	Duration       time.Duration
+	EarliestStart  time.Time
+	EarliestFinish time.Time
+	LatestStart    time.Time
+	LatestFinish   time.Time
+	// Slack is the time by which the work item can be delayed without
+	// delaying the finish of all work items or missing its due date (or one
+	// of its dependents' due dates). It is negative if a due date cannot be
+	// met.
+	Slack time.Duration
+	// Critical is true if the work item cannot be delayed, i.e. if it has no
+	// slack
+	Critical bool
+}
+
+// Schedule is the result of the critical path method for a set of work items
+// and the dependency links between them
+type Schedule struct {
+	Start  time.Time
+	Finish time.Time
+	// Items are the scheduled work items in an order in which every work
+	// item comes after the work items it depends on
+	Items []ScheduledWorkItem
+	// CriticalPath is the chain of dependent work items that determines the
+	// finish of the schedule, from the first to the last work item
+	CriticalPath []uuid.UUID
+}
+
+// scheduleDuration returns the effort that is still needed to complete the
+// given work item, which is its remaining estimate, or its original estimate
+// if there is no remaining estimate. Resolved or closed work items need no
+// more effort.
+func scheduleDuration(wi workitem.WorkItem) time.Duration {
+	if state, _ := wi.Fields[workitem.SystemState].(string); workitem.IsClosedState(state) {
+		return 0
+	}
+	for _, field := range []string{workitem.SystemRemainingEstimate, workitem.SystemOriginalEstimate} {
+		// durations are stored in seconds
+		switch v := wi.Fields[field].(type) {
+		case int:
+			return time.Duration(v) * time.Second
+		case int64:
+			return time.Duration(v) * time.Second
+		case float64:
+			return time.Duration(v) * time.Second
+		}
+	}
+	return 0
+}
+
+// NewSchedule computes the earliest and latest start and finish dates, the
+// slack and the critical path of the given work items that start at the given
+// time. The source of a link must be finished before its target can start;
+// links to work items outside of the given ones are ignored. A work item must
+// be finished by its due date, the latest finish of all others is the
+// earliest finish of the schedule.
+// returns BadParameterError if the links form a cycle
+func NewSchedule(start time.Time, wis []workitem.WorkItem, links []WorkItemLink) (*Schedule, error) {
+	index := make(map[uuid.UUID]int, len(wis))
+	for i, wi := range wis {
+		index[wi.ID] = i
+	}
+	predecessors := make([][]int, len(wis))
+	successors := make([][]int, len(wis))
+	inDegree := make([]int, len(wis))
+	for _, l := range links {
+		source, sourceFound := index[l.SourceID]
+		target, targetFound := index[l.TargetID]
+		if !sourceFound || !targetFound {
+			continue
+		}
+		predecessors[target] = append(predecessors[target], source)
+		successors[source] = append(successors[source], target)
+		inDegree[target]++
+	}
+
+	// topological sort (Kahn's algorithm) in the order of the given work
+	// items for the work items that don't depend on each other
+	order := make([]int, 0, len(wis))
+	for i := range wis {
+		if inDegree[i] == 0 {
+			order = append(order, i)
+		}
+	}
+	for next := 0; next < len(order); next++ {
+		for _, successor := range successors[order[next]] {
+			inDegree[successor]--
+			if inDegree[successor] == 0 {
+				order = append(order, successor)
+			}
+		}
+	}
+	if len(order) < len(wis) {
+		return nil, errors.NewBadParameterError("links", fmt.Sprintf("%d work items in a cycle", len(wis)-len(order))).Expected("dependency links without cycles")
+	}
+
+	// forward pass: the offsets of the earliest start and finish from the
+	// start of the schedule
+	durations := make([]time.Duration, len(wis))
+	earliestStart := make([]time.Duration, len(wis))
+	earliestFinish := make([]time.Duration, len(wis))
+	var finish time.Duration
+	for _, i := range order {
+		durations[i] = scheduleDuration(wis[i])
+		for _, predecessor := range predecessors[i] {
+			if earliestFinish[predecessor] > earliestStart[i] {
+				earliestStart[i] = earliestFinish[predecessor]
+			}
+		}
+		earliestFinish[i] = earliestStart[i] + durations[i]
+		if earliestFinish[i] > finish {
+			finish = earliestFinish[i]
+		}
+	}
+
+	// backward pass: the offsets of the latest start and finish
+	latestStart := make([]time.Duration, len(wis))
+	latestFinish := make([]time.Duration, len(wis))
+	for k := len(order) - 1; k >= 0; k-- {
+		i := order[k]
+		latestFinish[i] = finish
+		if dueDate, ok := wis[i].Fields[workitem.SystemDueDate].(time.Time); ok && dueDate.Sub(start) < latestFinish[i] {
+			latestFinish[i] = dueDate.Sub(start)
+		}
+		for _, successor := range successors[i] {
+			if latestStart[successor] < latestFinish[i] {
+				latestFinish[i] = latestStart[successor]
+			}
+		}
+		latestStart[i] = latestFinish[i] - durations[i]
+	}
+
+	schedule := Schedule{
+		Start:  start,
+		Finish: start.Add(finish),
+		Items:  make([]ScheduledWorkItem, len(order)),
+	}
+	for k, i := range order {
+		title, _ := wis[i].Fields[workitem.SystemTitle].(string)
+		slack := latestStart[i] - earliestStart[i]
+		schedule.Items[k] = ScheduledWorkItem{
+			ID:             wis[i].ID,
+			Title:          title,
+			Duration:       durations[i],
+			EarliestStart:  start.Add(earliestStart[i]),
+			EarliestFinish: start.Add(earliestFinish[i]),
+			LatestStart:    start.Add(latestStart[i]),
+			LatestFinish:   start.Add(latestFinish[i]),
+			Slack:          slack,
+			Critical:       slack <= 0,
+		}
+	}
+
+	// the critical path leads backwards from the first work item that
+	// finishes last through the predecessors that finish right when their
+	// dependent work item starts
+	last := -1
+	for _, i := range order {
+		if earliestFinish[i] == finish {
+			last = i
+			break
+		}
+	}
+	for current := last; current >= 0; {
+		schedule.CriticalPath = append([]uuid.UUID{wis[current].ID}, schedule.CriticalPath...)
+		driving := -1
+		for _, predecessor := range predecessors[current] {
+			if earliestFinish[predecessor] == earliestStart[current] {
+				driving = predecessor
+				break
+			}
+		}
+		current = driving
+	}
+	return &schedule, nil
+}
+
+// ComputeSchedule computes the schedule of the given work items, which start
+// at the given time, over the links of the dependency topology between them
+// (see NewSchedule). The links are checked for cycles in the same way as new
+// links are.
+// returns NotFoundError, BadParameterError or InternalError
+func (r *GormWorkItemLinkRepository) ComputeSchedule(ctx context.Context, workItemIDs []uuid.UUID, start time.Time) (*Schedule, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemlink", "schedule"}, time.Now())
+	if len(workItemIDs) == 0 {
+		return &Schedule{Start: start, Finish: start}, nil
+	}
+	items, err := r.workItemRepo.LoadBatchByID(ctx, workItemIDs)
+	if err != nil {
+		return nil, errs.Wrapf(err, "failed to load the work items to schedule: %+v", workItemIDs)
+	}
+	// keep the order of the given work items
+	loaded := make(map[uuid.UUID]*workitem.WorkItem, len(items))
+	for _, item := range items {
+		loaded[item.ID] = item
+	}
+	wis := make([]workitem.WorkItem, 0, len(workItemIDs))
+	for _, id := range workItemIDs {
+		item, ok := loaded[id]
+		if !ok {
+			return nil, errors.NewNotFoundError("work item", id.String())
+		}
+		if item != nil {
+			wis = append(wis, *item)
+			// skip the duplicates
+			loaded[id] = nil
+		}
+	}
+	var links []WorkItemLink
+	db := r.db.Where(fmt.Sprintf("source_id IN (?) AND target_id IN (?) AND link_type_id IN (SELECT id FROM %s WHERE topology = ?)", WorkItemLinkType{}.TableName()),
+		workItemIDs, workItemIDs, TopologyDependency).Order("created_at").Find(&links)
+	if db.Error != nil {
+		log.Error(ctx, map[string]interface{}{
+			"err": db.Error,
+		}, "failed to find the dependency links between the work items: %+v", workItemIDs)
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to find the dependency links between the work items: %+v", workItemIDs))
+	}
+	for _, l := range links {
+		hasCycle, err := r.DetectCycle(ctx, l.SourceID, l.TargetID, l.LinkTypeID)
+		if err != nil {
+			return nil, errs.Wrapf(err, "error during cycle-detection of link %s", l.ID)
+		}
+		if hasCycle {
+			return nil, errors.NewBadParameterError("links", l.ID).Expected("dependency links without cycles")
+		}
+	}
+	return NewSchedule(start, wis, links)
+}
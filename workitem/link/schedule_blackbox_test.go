@@ -0,0 +1,116 @@
+package link_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduleWorkItem(id string, hours int) workitem.WorkItem {
+	return workitem.WorkItem{
+		ID: uuid.FromStringOrNil(id),
+		Fields: map[string]interface{}{
+			workitem.SystemTitle:             id[len(id)-1:],
+			workitem.SystemRemainingEstimate: float64(hours * 3600),
+		},
+	}
+}
+
+func TestNewSchedule(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	a := newScheduleWorkItem("00000000-0000-0000-0000-00000000000a", 8)
+	b := newScheduleWorkItem("00000000-0000-0000-0000-00000000000b", 4)
+	c := newScheduleWorkItem("00000000-0000-0000-0000-00000000000c", 2)
+	d := newScheduleWorkItem("00000000-0000-0000-0000-00000000000d", 1)
+	// C depends on A and B
+	links := []link.WorkItemLink{
+		{SourceID: a.ID, TargetID: c.ID},
+		{SourceID: b.ID, TargetID: c.ID},
+	}
+
+	t.Run("critical path", func(t *testing.T) {
+		// when
+		schedule, err := link.NewSchedule(start, []workitem.WorkItem{a, b, c, d}, links)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(10*time.Hour), schedule.Finish)
+		assert.Equal(t, []uuid.UUID{a.ID, c.ID}, schedule.CriticalPath)
+		require.Len(t, schedule.Items, 4)
+		// every work item comes after the work items it depends on
+		assert.Equal(t, []uuid.UUID{a.ID, b.ID, d.ID, c.ID}, []uuid.UUID{schedule.Items[0].ID, schedule.Items[1].ID, schedule.Items[2].ID, schedule.Items[3].ID})
+		assert.Equal(t, link.ScheduledWorkItem{
+			ID:             b.ID,
+			Title:          "b",
+			Duration:       4 * time.Hour,
+			EarliestStart:  start,
+			EarliestFinish: start.Add(4 * time.Hour),
+			LatestStart:    start.Add(4 * time.Hour),
+			LatestFinish:   start.Add(8 * time.Hour),
+			Slack:          4 * time.Hour,
+		}, schedule.Items[1])
+		assert.Equal(t, link.ScheduledWorkItem{
+			ID:             c.ID,
+			Title:          "c",
+			Duration:       2 * time.Hour,
+			EarliestStart:  start.Add(8 * time.Hour),
+			EarliestFinish: start.Add(10 * time.Hour),
+			LatestStart:    start.Add(8 * time.Hour),
+			LatestFinish:   start.Add(10 * time.Hour),
+			Critical:       true,
+		}, schedule.Items[3])
+		assert.True(t, schedule.Items[0].Critical)
+		assert.Equal(t, 9*time.Hour, schedule.Items[2].Slack)
+	})
+
+	t.Run("due date", func(t *testing.T) {
+		// given
+		due := newScheduleWorkItem("00000000-0000-0000-0000-00000000000d", 1)
+		due.Fields[workitem.SystemDueDate] = start.Add(30 * time.Minute)
+		// when
+		schedule, err := link.NewSchedule(start, []workitem.WorkItem{a, b, c, due}, links)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, start.Add(30*time.Minute), schedule.Items[2].LatestFinish)
+		assert.Equal(t, -30*time.Minute, schedule.Items[2].Slack)
+		assert.True(t, schedule.Items[2].Critical)
+		assert.Equal(t, []uuid.UUID{a.ID, c.ID}, schedule.CriticalPath)
+	})
+
+	t.Run("estimates and states", func(t *testing.T) {
+		// given
+		closed := newScheduleWorkItem("00000000-0000-0000-0000-00000000000a", 8)
+		closed.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		original := workitem.WorkItem{
+			ID: b.ID,
+			Fields: map[string]interface{}{
+				workitem.SystemOriginalEstimate: int64(3600),
+			},
+		}
+		// when
+		schedule, err := link.NewSchedule(start, []workitem.WorkItem{closed, original}, nil)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, time.Duration(0), schedule.Items[0].Duration)
+		assert.Equal(t, time.Hour, schedule.Items[1].Duration)
+		assert.Equal(t, []uuid.UUID{b.ID}, schedule.CriticalPath)
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		// given
+		cyclicLinks := append(links, link.WorkItemLink{SourceID: c.ID, TargetID: a.ID})
+		// when
+		_, err := link.NewSchedule(start, []workitem.WorkItem{a, b, c, d}, cyclicLinks)
+		// then
+		require.IsType(t, errors.BadParameterError{}, err)
+	})
+}
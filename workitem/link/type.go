@@ -41,6 +41,16 @@ func strPtrIsNilOrContentIsEqual(l, r *string) bool {
 	return *l == *r
 }
 
+// returns true if the left hand and right hand side UUID
+// pointers either both point to nil or reference the same
+// UUID; otherwise false is returned.
+func uuidPtrIsNilOrContentIsEqual(l, r *uuid.UUID) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	return uuid.Equal(*l, *r)
+}
+
 // WorkItemLinkType represents the type of a work item link as it is stored in the db
 type WorkItemLinkType struct {
 	gormsupport.Lifecycle
@@ -61,6 +71,18 @@ type WorkItemLinkType struct {
 
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
+
+	// SourceTypeID optionally restricts the source of the links of this type
+	// to work items of the given type
+	SourceTypeID *uuid.UUID `sql:"type:uuid"`
+	// TargetTypeID optionally restricts the target of the links of this type
+	// to work items of the given type
+	TargetTypeID *uuid.UUID `sql:"type:uuid"`
+
+	// Blocking can only be set for link types of the dependency topology and
+	// prevents the target of a link from being resolved or closed as long as
+	// the source of the link (the blocker) is open.
+	Blocking bool
 }
 
 // Ensure Fields implements the Equaler interface
@@ -103,9 +125,40 @@ func (t WorkItemLinkType) Equal(u convert.Equaler) bool {
 	if !uuid.Equal(t.SpaceID, other.SpaceID) {
 		return false
 	}
+	if !uuidPtrIsNilOrContentIsEqual(t.SourceTypeID, other.SourceTypeID) {
+		return false
+	}
+	if !uuidPtrIsNilOrContentIsEqual(t.TargetTypeID, other.TargetTypeID) {
+		return false
+	}
+	if t.Blocking != other.Blocking {
+		return false
+	}
 	return true
 }
 
+// checkValidBlocking returns a BadParameterError if the link type is blocking
+// but not of the dependency topology.
+func (t WorkItemLinkType) checkValidBlocking() error {
+	if t.Blocking && t.Topology != TopologyDependency {
+		return errors.NewBadParameterError("blocking", t.Blocking).Expected("only for the " + TopologyDependency.String() + " topology")
+	}
+	return nil
+}
+
+// CheckValidForTypes returns nil if a link of this type can connect a work
+// item of the given source type with a work item of the given target type;
+// otherwise a BadParameterError is returned.
+func (t WorkItemLinkType) CheckValidForTypes(sourceTypeID, targetTypeID uuid.UUID) error {
+	if t.SourceTypeID != nil && !uuid.Equal(*t.SourceTypeID, sourceTypeID) {
+		return errors.NewBadParameterError("source type", sourceTypeID).Expected(t.SourceTypeID.String())
+	}
+	if t.TargetTypeID != nil && !uuid.Equal(*t.TargetTypeID, targetTypeID) {
+		return errors.NewBadParameterError("target type", targetTypeID).Expected(t.TargetTypeID.String())
+	}
+	return nil
+}
+
 // CheckValidForCreation returns an error if the work item link type
 // cannot be used for the creation of a new work item link type.
 func (t *WorkItemLinkType) CheckValidForCreation() error {
@@ -121,6 +174,9 @@ func (t *WorkItemLinkType) CheckValidForCreation() error {
 	if err := t.Topology.CheckValid(); err != nil {
 		return errs.WithStack(err)
 	}
+	if err := t.checkValidBlocking(); err != nil {
+		return err
+	}
 	if t.LinkCategoryID == uuid.Nil {
 		return errors.NewBadParameterError("link_category_id", t.LinkCategoryID)
 	}
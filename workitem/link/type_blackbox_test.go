@@ -89,6 +89,11 @@ func TestWorkItemLinkType_Equal(t *testing.T) {
 	b = a
 	b.SpaceID = uuid.FromStringOrNil("aaa71e36-871b-43a6-9166-0v5ce684dBBB")
 	require.False(t, a.Equal(b))
+
+	// Test Blocking
+	b = a
+	b.Blocking = true
+	require.False(t, a.Equal(b))
 }
 
 func TestWorkItemLinkTypeCheckValidForCreation(t *testing.T) {
@@ -141,4 +146,39 @@ func TestWorkItemLinkTypeCheckValidForCreation(t *testing.T) {
 	b = a
 	b.SpaceID = uuid.Nil
 	require.NotNil(t, b.CheckValidForCreation())
+
+	// Check Blocking of a network topology
+	b = a
+	b.Blocking = true
+	require.NotNil(t, b.CheckValidForCreation())
+
+	// Check Blocking of a dependency topology
+	b = a
+	b.Topology = link.TopologyDependency
+	b.Blocking = true
+	require.Nil(t, b.CheckValidForCreation())
+}
+
+func TestWorkItemLinkTypeCheckValidForTypes(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	testCase := uuid.FromStringOrNil("0e671e36-871b-43a6-9166-0c4bd573e231")
+	bug := uuid.FromStringOrNil("26787039-b68f-4e28-8814-c2f93be1ef4e")
+
+	// Check without constraints
+	a := link.WorkItemLinkType{}
+	require.Nil(t, a.CheckValidForTypes(testCase, bug))
+
+	// Check matching constraints
+	b := a
+	b.SourceTypeID = &testCase
+	b.TargetTypeID = &bug
+	require.Nil(t, b.CheckValidForTypes(testCase, bug))
+
+	// Check wrong source type
+	require.NotNil(t, b.CheckValidForTypes(bug, bug))
+
+	// Check wrong target type
+	require.NotNil(t, b.CheckValidForTypes(testCase, testCase))
 }
@@ -11,6 +11,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/fabric8-services/fabric8-wit/space"
+	"github.com/fabric8-services/fabric8-wit/workitem"
 
 	"github.com/goadesign/goa"
 	"github.com/jinzhu/gorm"
@@ -45,24 +46,18 @@ func (r *GormWorkItemLinkTypeRepository) Create(ctx context.Context, linkType *W
 	if err := linkType.CheckValidForCreation(); err != nil {
 		return nil, errs.WithStack(err)
 	}
-	// Check link category exists
-	linkCategory := WorkItemLinkCategory{}
-	db := r.db.Where("id=?", linkType.LinkCategoryID).Find(&linkCategory)
-	if db.RecordNotFound() {
-		return nil, errors.NewBadParameterError("work item link category", linkType.LinkCategoryID)
-	}
-	if db.Error != nil {
-		return nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to find work item link category"))
-	}
 	// Check space exists
 	space := space.Space{}
-	db = r.db.Where("id=?", linkType.SpaceID).Find(&space)
+	db := r.db.Where("id=?", linkType.SpaceID).Find(&space)
 	if db.RecordNotFound() {
 		return nil, errors.NewBadParameterError("work item link space", linkType.SpaceID)
 	}
 	if db.Error != nil {
 		return nil, errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to find work item link space"))
 	}
+	if err := r.checkReferences(ctx, *linkType); err != nil {
+		return nil, err
+	}
 
 	db = r.db.Create(linkType)
 	if db.Error != nil {
@@ -80,6 +75,59 @@ func (r *GormWorkItemLinkTypeRepository) Create(ctx context.Context, linkType *W
 	return linkType, nil
 }
 
+// inSpace returns true if something that belongs to the space with the given
+// ID can be used in the given space, which is the case for the things of the
+// space itself and of the system space.
+func inSpace(ownerSpaceID, spaceID uuid.UUID) bool {
+	return uuid.Equal(ownerSpaceID, spaceID) || uuid.Equal(ownerSpaceID, space.SystemSpace)
+}
+
+// checkReferences makes sure that the link category and the work item types
+// that the given link type references exist and can be used in the space of
+// the link type.
+// Returns BadParameterError or InternalError
+func (r *GormWorkItemLinkTypeRepository) checkReferences(ctx context.Context, linkType WorkItemLinkType) error {
+	linkCategory := WorkItemLinkCategory{}
+	db := r.db.Where("id=?", linkType.LinkCategoryID).Find(&linkCategory)
+	if db.RecordNotFound() {
+		return errors.NewBadParameterError("work item link category", linkType.LinkCategoryID)
+	}
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to find work item link category"))
+	}
+	if !inSpace(linkCategory.SpaceID, linkType.SpaceID) {
+		return errors.NewBadParameterError("work item link category", linkType.LinkCategoryID).Expected("a link category of the space of the link type")
+	}
+	witRepo := workitem.NewWorkItemTypeRepository(r.db)
+	for name, typeID := range map[string]*uuid.UUID{"source type": linkType.SourceTypeID, "target type": linkType.TargetTypeID} {
+		if typeID == nil {
+			continue
+		}
+		wit, err := witRepo.LoadTypeFromDB(ctx, *typeID)
+		if err != nil {
+			if _, ok := errs.Cause(err).(errors.NotFoundError); ok {
+				return errors.NewBadParameterError(name, *typeID).Expected("an existing work item type")
+			}
+			return errors.NewInternalError(ctx, err)
+		}
+		if !inSpace(wit.SpaceID, linkType.SpaceID) {
+			return errors.NewBadParameterError(name, *typeID).Expected("a work item type of the space of the link type")
+		}
+	}
+	return nil
+}
+
+// countLinks returns the number of (not deleted) work item links of the link
+// type with the given ID.
+func (r *GormWorkItemLinkTypeRepository) countLinks(ctx context.Context, ID uuid.UUID) (int, error) {
+	var count int
+	db := r.db.Model(&WorkItemLink{}).Where("link_type_id=?", ID).Count(&count)
+	if db.Error != nil {
+		return 0, errors.NewInternalError(ctx, errs.Wrap(db.Error, "failed to count the links of the work item link type"))
+	}
+	return count, nil
+}
+
 // Load returns the work item link type for the given ID.
 // Returns NotFoundError, ConversionError or InternalError
 func (r *GormWorkItemLinkTypeRepository) Load(ctx context.Context, ID uuid.UUID) (*WorkItemLinkType, error) {
@@ -125,8 +173,9 @@ func (r *GormWorkItemLinkTypeRepository) List(ctx context.Context, spaceID uuid.
 	return modelLinkTypes, nil
 }
 
-// Delete deletes the work item link type with the given id
-// returns NotFoundError or InternalError
+// Delete deletes the work item link type with the given id. Link types that
+// are still used by links cannot be deleted.
+// returns NotFoundError, DataConflictError or InternalError
 func (r *GormWorkItemLinkTypeRepository) Delete(ctx context.Context, spaceID uuid.UUID, ID uuid.UUID) error {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemlinktype", "delete"}, time.Now())
 	var cat = WorkItemLinkType{
@@ -138,7 +187,14 @@ func (r *GormWorkItemLinkTypeRepository) Delete(ctx context.Context, spaceID uui
 		"space_id": spaceID,
 	}, "Work item link type to delete %v", cat)
 
-	db := r.db.Delete(&cat)
+	count, err := r.countLinks(ctx, ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.NewDataConflictError(fmt.Sprintf("work item link type %s is still used by %d link(s)", ID, count))
+	}
+	db := r.db.Where("space_id=?", spaceID).Delete(&cat)
 	if db.Error != nil {
 		return errors.NewInternalError(ctx, db.Error)
 	}
@@ -170,6 +226,28 @@ func (r *GormWorkItemLinkTypeRepository) Save(ctx context.Context, modelToSave W
 	if existingModel.Version != modelToSave.Version {
 		return nil, errors.NewVersionConflictError("version conflict")
 	}
+	if err := modelToSave.Topology.CheckValid(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := modelToSave.checkValidBlocking(); err != nil {
+		return nil, err
+	}
+	if err := r.checkReferences(ctx, modelToSave); err != nil {
+		return nil, err
+	}
+	// the existing links might not fit another topology or other type
+	// constraints
+	if modelToSave.Topology != existingModel.Topology ||
+		!uuidPtrIsNilOrContentIsEqual(modelToSave.SourceTypeID, existingModel.SourceTypeID) ||
+		!uuidPtrIsNilOrContentIsEqual(modelToSave.TargetTypeID, existingModel.TargetTypeID) {
+		count, err := r.countLinks(ctx, modelToSave.ID)
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			return nil, errors.NewDataConflictError(fmt.Sprintf("the topology and the type constraints of work item link type %s cannot be changed because it is used by %d link(s)", modelToSave.ID, count))
+		}
+	}
 	modelToSave.Version = modelToSave.Version + 1
 	db = db.Save(&modelToSave)
 	if db.Error != nil {
@@ -0,0 +1,216 @@
+package link_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
+	errs "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type typeRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	linkTypeRepo *link.GormWorkItemLinkTypeRepository
+	linkCatRepo  *link.GormWorkItemLinkCategoryRepository
+}
+
+func TestRunTypeRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &typeRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../../config.yaml")})
+}
+
+func (s *typeRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.linkTypeRepo = link.NewWorkItemLinkTypeRepository(s.DB)
+	s.linkCatRepo = link.NewWorkItemLinkCategoryRepository(s.DB)
+}
+
+// newLinkType returns a valid link type of the first space of the given
+// fixture that is not yet created
+func newLinkType(fxt *tf.TestFixture) link.WorkItemLinkType {
+	return link.WorkItemLinkType{
+		Name:           "tested by",
+		Topology:       link.TopologyNetwork,
+		ForwardName:    "tests",
+		ReverseName:    "tested by",
+		LinkCategoryID: fxt.WorkItemLinkCategories[0].ID,
+		SpaceID:        fxt.Spaces[0].ID,
+	}
+}
+
+func (s *typeRepoBlackBoxTest) TestCreate() {
+	s.T().Run("ok - with type constraints", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkCategories(1), tf.WorkItemTypes(2))
+		linkType := newLinkType(fxt)
+		linkType.SourceTypeID = &fxt.WorkItemTypes[0].ID
+		linkType.TargetTypeID = &fxt.WorkItemTypes[1].ID
+		// when
+		_, err := s.linkTypeRepo.Create(s.Ctx, &linkType)
+		// then
+		require.NoError(t, err)
+		loaded, err := s.linkTypeRepo.Load(s.Ctx, linkType.ID)
+		require.NoError(t, err)
+		require.NotNil(t, loaded.SourceTypeID)
+		require.NotNil(t, loaded.TargetTypeID)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, *loaded.SourceTypeID)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, *loaded.TargetTypeID)
+	})
+	s.T().Run("fail - category of another space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkCategories(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkCategories[idx].SpaceID = fxt.Spaces[1].ID
+			return nil
+		}))
+		linkType := newLinkType(fxt)
+		// when
+		_, err := s.linkTypeRepo.Create(s.Ctx, &linkType)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - work item type of another space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkCategories(1), tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemTypes[idx].SpaceID = fxt.Spaces[1].ID
+			return nil
+		}))
+		linkType := newLinkType(fxt)
+		linkType.TargetTypeID = &fxt.WorkItemTypes[0].ID
+		// when
+		_, err := s.linkTypeRepo.Create(s.Ctx, &linkType)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestSave() {
+	s.T().Run("ok - topology of an unused link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyNetwork)))
+		linkType := *fxt.WorkItemLinkTypes[0]
+		linkType.Topology = link.TopologyTree
+		// when
+		saved, err := s.linkTypeRepo.Save(s.Ctx, linkType)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, link.TopologyTree, saved.Topology)
+	})
+	s.T().Run("fail - topology of a used link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyNetwork)),
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "B"))),
+		)
+		linkType := *fxt.WorkItemLinkTypes[0]
+		linkType.Topology = link.TopologyTree
+		// when
+		_, err := s.linkTypeRepo.Save(s.Ctx, linkType)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("ok - blocking of a dependency link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency)))
+		linkType := *fxt.WorkItemLinkTypes[0]
+		linkType.Blocking = true
+		// when
+		saved, err := s.linkTypeRepo.Save(s.Ctx, linkType)
+		// then
+		require.NoError(t, err)
+		assert.True(t, saved.Blocking)
+	})
+	s.T().Run("fail - blocking of a network link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyNetwork)))
+		linkType := *fxt.WorkItemLinkTypes[0]
+		linkType.Blocking = true
+		// when
+		_, err := s.linkTypeRepo.Save(s.Ctx, linkType)
+		// then
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("ok - name of a used link type", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1),
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "B"))),
+		)
+		linkType := *fxt.WorkItemLinkTypes[0]
+		linkType.Name = "renamed"
+		// when
+		saved, err := s.linkTypeRepo.Save(s.Ctx, linkType)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "renamed", saved.Name)
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestDelete() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		// when
+		err := s.linkTypeRepo.Delete(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+		// then
+		require.NoError(t, err)
+		_, err = s.linkTypeRepo.Load(s.Ctx, fxt.WorkItemLinkTypes[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - used by links", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1),
+			tf.WorkItems(2, tf.SetWorkItemTitles("A", "B")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("A", "B"))),
+		)
+		// when
+		err := s.linkTypeRepo.Delete(s.Ctx, fxt.Spaces[0].ID, fxt.WorkItemLinkTypes[0].ID)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - other space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkTypes(1))
+		// when
+		err := s.linkTypeRepo.Delete(s.Ctx, fxt.Spaces[1].ID, fxt.WorkItemLinkTypes[0].ID)
+		// then
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *typeRepoBlackBoxTest) TestCategories() {
+	s.T().Run("list by space", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItemLinkCategories(2, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItemLinkCategories[idx].SpaceID = fxt.Spaces[idx].ID
+			return nil
+		}))
+		// when
+		cats, err := s.linkCatRepo.ListBySpace(s.Ctx, fxt.Spaces[0].ID)
+		// then
+		require.NoError(t, err)
+		ids := map[string]bool{}
+		for _, cat := range cats {
+			ids[cat.ID.String()] = true
+		}
+		assert.True(t, ids[fxt.WorkItemLinkCategories[0].ID.String()])
+		assert.False(t, ids[fxt.WorkItemLinkCategories[1].ID.String()])
+		assert.True(t, ids[link.SystemWorkItemLinkCategoryUserID.String()])
+	})
+	s.T().Run("fail - delete category used by link types", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemLinkTypes(1))
+		// when
+		err := s.linkCatRepo.Delete(s.Ctx, fxt.WorkItemLinkCategories[0].ID)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+	})
+}
@@ -0,0 +1,231 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/area"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/iteration"
+	"github.com/fabric8-services/fabric8-wit/label"
+	"github.com/fabric8-services/fabric8-wit/log"
+	"github.com/fabric8-services/fabric8-wit/space"
+
+	"github.com/goadesign/goa"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Move records the move of a work item from one space to another. The
+// previous space and number of the work item are kept so that its former URL
+// still leads to it.
+type Move struct {
+	ID          uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	CreatedAt   time.Time
+	WorkItemID  uuid.UUID `sql:"type:uuid"`
+	ModifierID  uuid.UUID `sql:"type:uuid"`
+	FromSpaceID uuid.UUID `sql:"type:uuid"`
+	FromNumber  int
+	ToSpaceID   uuid.UUID `sql:"type:uuid"`
+	ToNumber    int
+}
+
+// TableName implements gorm.tabler
+func (m Move) TableName() string {
+	return "work_item_moves"
+}
+
+// Move transfers the work item with the given ID to the given space. The work
+// item gets a new number in the target space and is placed at the bottom of
+// its backlog. The iteration and area are replaced with the ones of the same
+// name in the target space (or with its root iteration and area), labels are
+// replaced with the ones of the same name in the target space or dropped and
+// the codebase is cleared. A work item type of the previous space is replaced
+// with the type of the same name in the target space.
+// returns NotFoundError, BadParameterError, ConversionError or InternalError
+func (r *GormWorkItemRepository) Move(ctx context.Context, workitemID uuid.UUID, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*WorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "move"}, time.Now())
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Set("gorm:query_option", "FOR UPDATE").Where("id = ?", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item", workitemID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if uuid.Equal(wiStorage.SpaceID, targetSpaceID) {
+		return nil, errors.NewBadParameterError("space", targetSpaceID).Expected("a space other than the one of the work item")
+	}
+	s := space.Space{}
+	tx = r.db.Where("id = ?", targetSpaceID).First(&s)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("space", targetSpaceID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if !uuid.Equal(wiType.SpaceID, space.SystemSpace) && !uuid.Equal(wiType.SpaceID, targetSpaceID) {
+		targetType := WorkItemType{}
+		tx = r.db.Where("space_id = ? AND name = ?", targetSpaceID, wiType.Name).First(&targetType)
+		if tx.RecordNotFound() {
+			return nil, errors.NewBadParameterError("space", targetSpaceID).Expected("a space with the work item type " + wiType.Name)
+		}
+		if err := tx.Error; err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		if wiType, err = r.witr.LoadTypeFromDB(ctx, targetType.ID); err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		wiStorage.Type = wiType.ID
+	}
+	move := Move{
+		WorkItemID:  wiStorage.ID,
+		ModifierID:  modifierID,
+		FromSpaceID: wiStorage.SpaceID,
+		FromNumber:  wiStorage.Number,
+		ToSpaceID:   targetSpaceID,
+	}
+	if err := r.remapFields(ctx, wiStorage.Fields, targetSpaceID); err != nil {
+		return nil, err
+	}
+	pos, err := r.LoadHighestOrder(ctx, targetSpaceID)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	number, err := r.winr.NextVal(ctx, targetSpaceID)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	key, err := r.lookupKey(ctx, targetSpaceID, *number)
+	if err != nil {
+		return nil, err
+	}
+	version := wiStorage.Version
+	wiStorage.SpaceID = targetSpaceID
+	wiStorage.Number = *number
+	wiStorage.Key = key
+	wiStorage.ExecutionOrder = pos + orderValue
+	wiStorage.Version = version + 1
+	tx = r.db.Where("version = ?", version).Save(&wiStorage)
+	if err := tx.Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":    workitemID,
+			"space_id": targetSpaceID,
+			"err":      err,
+		}, "unable to move the work item")
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	move.ToNumber = wiStorage.Number
+	if err := r.db.Create(&move).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to record the move of the work item"))
+	}
+	if err := r.wirr.Create(context.Background(), modifierID, RevisionTypeMove, wiStorage); err != nil {
+		return nil, errs.Wrapf(err, "error while moving work item")
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"wi_id":         workitemID,
+		"from_space_id": move.FromSpaceID,
+		"to_space_id":   move.ToSpaceID,
+		"number":        move.ToNumber,
+	}, "Work item moved successfully!")
+	return ConvertWorkItemStorageToModel(wiType, &wiStorage)
+}
+
+// remapFields replaces the references to the iteration, area and labels of
+// the previous space in the given field values (in their storage
+// representation) with the ones of the same name in the given space, and
+// clears the codebase.
+// returns InternalError
+func (r *GormWorkItemRepository) remapFields(ctx context.Context, fields Fields, spaceID uuid.UUID) error {
+	for _, ref := range []struct {
+		field string
+		table string
+	}{
+		{SystemIteration, iteration.Iteration{}.TableName()},
+		{SystemArea, area.Area{}.TableName()},
+	} {
+		id, ok := fields[ref.field].(string)
+		if !ok {
+			continue
+		}
+		mapped, err := r.lookupByName(ctx, ref.table, id, spaceID)
+		if err != nil {
+			return err
+		}
+		if mapped == nil {
+			if mapped, err = r.lookupRoot(ctx, ref.table, spaceID); err != nil {
+				return err
+			}
+		}
+		if mapped == nil {
+			delete(fields, ref.field)
+			continue
+		}
+		fields[ref.field] = mapped.String()
+	}
+	if labels, err := asList(fields[SystemLabels]); err == nil && len(labels) > 0 {
+		mappedLabels := []interface{}{}
+		for _, l := range labels {
+			id, ok := l.(string)
+			if !ok {
+				continue
+			}
+			mapped, err := r.lookupByName(ctx, label.Label{}.TableName(), id, spaceID)
+			if err != nil {
+				return err
+			}
+			if mapped != nil {
+				mappedLabels = append(mappedLabels, mapped.String())
+			}
+		}
+		if len(mappedLabels) > 0 {
+			fields[SystemLabels] = mappedLabels
+		} else {
+			delete(fields, SystemLabels)
+		}
+	}
+	delete(fields, SystemCodebase)
+	return nil
+}
+
+// lookupByName returns the ID of the entry of the given table in the given
+// space that has the same name as the entry with the given ID, or nil if
+// there is none
+// returns InternalError
+func (r *GormWorkItemRepository) lookupByName(ctx context.Context, tableName string, id string, spaceID uuid.UUID) (*uuid.UUID, error) {
+	query := fmt.Sprintf(`SELECT t.id FROM %[1]s t
+		JOIN %[1]s o ON o.name = t.name
+		WHERE o.id = ? AND t.space_id = ? AND t.deleted_at IS NULL
+		ORDER BY t.created_at LIMIT 1`, tableName)
+	return r.lookupID(ctx, query, id, spaceID)
+}
+
+// lookupRoot returns the ID of the root entry of the given table (areas or
+// iterations) in the given space, or nil if there is none
+// returns InternalError
+func (r *GormWorkItemRepository) lookupRoot(ctx context.Context, tableName string, spaceID uuid.UUID) (*uuid.UUID, error) {
+	query := fmt.Sprintf(`SELECT id FROM %s WHERE space_id = ? AND path = '' AND deleted_at IS NULL LIMIT 1`, tableName)
+	return r.lookupID(ctx, query, spaceID)
+}
+
+func (r *GormWorkItemRepository) lookupID(ctx context.Context, query string, values ...interface{}) (*uuid.UUID, error) {
+	var result struct {
+		ID uuid.UUID
+	}
+	db := r.db.Raw(query, values...).Scan(&result)
+	if db.RecordNotFound() {
+		return nil, nil
+	}
+	if db.Error != nil {
+		return nil, errors.NewInternalError(ctx, db.Error)
+	}
+	return &result.ID, nil
+}
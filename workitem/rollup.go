@@ -0,0 +1,338 @@
+package workitem
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/closeable"
+	"github.com/fabric8-services/fabric8-wit/convert"
+	"github.com/fabric8-services/fabric8-wit/errors"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// RollUpFunction computes a value from the children of a work item
+type RollUpFunction string
+
+// The functions of a roll-up
+const (
+	// RollUpSum adds up the numeric values of a field of the children
+	RollUpSum RollUpFunction = "sum"
+	// RollUpMin is the smallest numeric value of a field of the children
+	RollUpMin RollUpFunction = "min"
+	// RollUpMax is the largest numeric value of a field of the children
+	RollUpMax RollUpFunction = "max"
+	// RollUpCount is the number of children that have a value in a field or
+	// the number of children if no field is given
+	RollUpCount RollUpFunction = "count"
+	// RollUpProgress is the percentage of children in a resolved or closed
+	// state
+	RollUpProgress RollUpFunction = "progress"
+)
+
+// RollUp defines a read-only value of the work items of a type that is
+// computed from their children
+type RollUp struct {
+	Name     string         `json:"name"`
+	Function RollUpFunction `json:"function"`
+	// Field is the field of the children the function is applied to
+	Field string `json:"field,omitempty"`
+}
+
+// RollUps are the roll-ups of a work item type
+type RollUps []RollUp
+
+// Ensure RollUps implements the Equaler interface
+var _ convert.Equaler = RollUps{}
+var _ convert.Equaler = (*RollUps)(nil)
+
+// Equal returns true if two RollUps objects are equal; otherwise false is returned.
+func (r RollUps) Equal(u convert.Equaler) bool {
+	other, ok := u.(RollUps)
+	if !ok {
+		return false
+	}
+	if len(r) == 0 && len(other) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(r, other)
+}
+
+// Value implements the driver.Valuer interface
+func (r RollUps) Value() (driver.Value, error) {
+	if len(r) == 0 {
+		return nil, nil
+	}
+	return toBytes(r)
+}
+
+// Scan implements the sql.Scanner interface
+func (r *RollUps) Scan(src interface{}) error {
+	if src == nil {
+		*r = nil
+		return nil
+	}
+	return fromBytes(src, r)
+}
+
+// Validate checks that the roll-ups are well defined. The children of a work
+// item can be of any type, so the fields are not checked against a type.
+// returns BadParameterError
+func (r RollUps) Validate() error {
+	names := map[string]struct{}{}
+	for _, rollUp := range r {
+		if rollUp.Name == "" {
+			return errors.NewBadParameterError("rollups.name", rollUp.Name).Expected("a non-empty roll-up name")
+		}
+		if _, exists := names[rollUp.Name]; exists {
+			return errors.NewBadParameterError("rollups.name", rollUp.Name).Expected("unique roll-up names")
+		}
+		names[rollUp.Name] = struct{}{}
+		switch rollUp.Function {
+		case RollUpSum, RollUpMin, RollUpMax:
+			if rollUp.Field == "" {
+				return errors.NewBadParameterError("rollups.field", rollUp.Field).Expected(fmt.Sprintf("the field the %s of roll-up %s is computed from", rollUp.Function, rollUp.Name))
+			}
+		case RollUpCount:
+		case RollUpProgress:
+			if rollUp.Field != "" {
+				return errors.NewBadParameterError("rollups.field", rollUp.Field).Expected(fmt.Sprintf("no field for roll-up %s, the progress is computed from the states of the children", rollUp.Name))
+			}
+		default:
+			return errors.NewBadParameterError("rollups.function", rollUp.Function).Expected(fmt.Sprintf("one of %s, %s, %s, %s, %s", RollUpSum, RollUpMin, RollUpMax, RollUpCount, RollUpProgress))
+		}
+	}
+	return nil
+}
+
+// numericValue returns the given field value as a float64 and true if it is a
+// number; otherwise false is returned
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// Compute returns the values of the roll-ups for the given children by the
+// names of the roll-ups. The minimum and maximum are nil if no child has a
+// numeric value in the field and the progress is nil if there are no children.
+func (r RollUps) Compute(children []*WorkItem) map[string]interface{} {
+	values := make(map[string]interface{}, len(r))
+	for _, rollUp := range r {
+		switch rollUp.Function {
+		case RollUpCount:
+			count := 0
+			for _, child := range children {
+				if rollUp.Field == "" || !isEmptyValue(child.Fields[rollUp.Field]) {
+					count++
+				}
+			}
+			values[rollUp.Name] = count
+		case RollUpProgress:
+			if len(children) == 0 {
+				values[rollUp.Name] = nil
+				continue
+			}
+			closed := 0
+			for _, child := range children {
+				if state, _ := child.Fields[SystemState].(string); IsClosedState(state) {
+					closed++
+				}
+			}
+			values[rollUp.Name] = float64(closed) * 100 / float64(len(children))
+		default:
+			var result *float64
+			for _, child := range children {
+				n, ok := numericValue(child.Fields[rollUp.Field])
+				if !ok {
+					continue
+				}
+				switch {
+				case result == nil:
+					result = &n
+				case rollUp.Function == RollUpSum:
+					*result += n
+				case rollUp.Function == RollUpMin && n < *result,
+					rollUp.Function == RollUpMax && n > *result:
+					*result = n
+				}
+			}
+			if result == nil {
+				if rollUp.Function == RollUpSum {
+					values[rollUp.Name] = float64(0)
+				} else {
+					values[rollUp.Name] = nil
+				}
+				continue
+			}
+			values[rollUp.Name] = *result
+		}
+	}
+	return values
+}
+
+// rollUpValuesTableName is the table that holds the cached roll-up values of
+// the work items
+const rollUpValuesTableName = "work_item_roll_up_values"
+
+// CachedRollUps are the cached roll-up values of a work item
+type CachedRollUps struct {
+	// Generation is incremented whenever the values are invalidated, values
+	// computed before an invalidation are not stored
+	Generation int
+	// Values are nil if they are stale
+	Values map[string]interface{}
+}
+
+// RollUpCache caches the roll-up values of the work items in the database, so
+// that they are shared by all instances of the service. The values of a work
+// item are invalidated in the same transaction that changes its children or
+// the links to its children.
+type RollUpCache struct {
+	db *gorm.DB
+}
+
+// NewRollUpCache creates a roll-up cache based on gorm
+func NewRollUpCache(db *gorm.DB) *RollUpCache {
+	return &RollUpCache{db: db}
+}
+
+// Load returns the cached values of the given roll-ups by the IDs of their
+// work items. Work items whose values were never stored nor invalidated have
+// no entry in the returned map.
+// returns InternalError
+func (c *RollUpCache) Load(ctx context.Context, rollUpsByID map[uuid.UUID]RollUps) (map[uuid.UUID]CachedRollUps, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemrollups", "load"}, time.Now())
+	result := map[uuid.UUID]CachedRollUps{}
+	if len(rollUpsByID) == 0 {
+		return result, nil
+	}
+	ids := make([]uuid.UUID, 0, len(rollUpsByID))
+	for id := range rollUpsByID {
+		ids = append(ids, id)
+	}
+	query := fmt.Sprintf(`SELECT work_item_id, generation, roll_ups FROM %s WHERE work_item_id IN (?)`, rollUpValuesTableName)
+	rows, err := c.db.Raw(query, ids).Rows()
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to load the cached roll-up values"))
+	}
+	defer closeable.Close(ctx, rows)
+	for rows.Next() {
+		var id uuid.UUID
+		var cached CachedRollUps
+		var data []byte
+		if err := rows.Scan(&id, &cached.Generation, &data); err != nil {
+			return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to scan the cached roll-up values"))
+		}
+		if data != nil {
+			if err := json.Unmarshal(data, &cached.Values); err != nil {
+				return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to decode the cached roll-up values of work item %s", id))
+			}
+			rollUpsByID[id].restoreCounts(cached.Values)
+		}
+		result[id] = cached
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to load the cached roll-up values"))
+	}
+	return result, nil
+}
+
+// restoreCounts converts the counts of the given values, which are decoded
+// from JSON as float64, back to int
+func (r RollUps) restoreCounts(values map[string]interface{}) {
+	for _, rollUp := range r {
+		if n, ok := values[rollUp.Name].(float64); ok && rollUp.Function == RollUpCount {
+			values[rollUp.Name] = int(n)
+		}
+	}
+}
+
+// Store stores the values computed for the given work item unless its cached
+// values were invalidated since the given cached values were loaded. The
+// cached values are nil if there were none.
+// returns InternalError
+func (c *RollUpCache) Store(ctx context.Context, id uuid.UUID, cached *CachedRollUps, values map[string]interface{}) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemrollups", "store"}, time.Now())
+	data, err := json.Marshal(values)
+	if err != nil {
+		return errs.Wrapf(err, "failed to encode the roll-up values of work item %s", id)
+	}
+	var db *gorm.DB
+	if cached == nil {
+		// an invalidation that happened meanwhile has inserted the row
+		db = c.db.Exec(fmt.Sprintf(`INSERT INTO %s (work_item_id, generation, roll_ups) VALUES (?, 0, ?)
+			ON CONFLICT (work_item_id) DO NOTHING`, rollUpValuesTableName), id, data)
+	} else {
+		db = c.db.Exec(fmt.Sprintf(`UPDATE %s SET roll_ups = ? WHERE work_item_id = ? AND generation = ?`, rollUpValuesTableName), data, id, cached.Generation)
+	}
+	if db.Error != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(db.Error, "failed to store the roll-up values of work item %s", id))
+	}
+	return nil
+}
+
+// invalidateRollUpsSQL marks the cached values of the work items selected by
+// the given query as stale
+func invalidateRollUpsSQL(selectIDs string) string {
+	return fmt.Sprintf(`INSERT INTO %[1]s (work_item_id, generation) %[2]s
+		ON CONFLICT (work_item_id) DO UPDATE SET roll_ups = NULL, generation = %[1]s.generation + 1`, rollUpValuesTableName, selectIDs)
+}
+
+// Invalidate marks the cached roll-up values of the given work items as stale
+// returns InternalError
+func (c *RollUpCache) Invalidate(ctx context.Context, ids ...uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemrollups", "invalidate"}, time.Now())
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := c.db.Exec(invalidateRollUpsSQL(`SELECT DISTINCT id FROM work_items WHERE id IN (?)`), ids).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to invalidate the roll-up values of the work items %v", ids))
+	}
+	return nil
+}
+
+// InvalidateParents marks the cached roll-up values of the parents of the
+// given work item as stale, which are computed from the work item.
+// NOTE: The work item package cannot use the link package, hence the link
+// tables are referred to by their names.
+// returns InternalError
+func (c *RollUpCache) InvalidateParents(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemrollups", "invalidateparents"}, time.Now())
+	parents := `SELECT DISTINCT l.source_id FROM work_item_links l
+		JOIN work_item_link_types t ON t.id = l.link_type_id
+		WHERE l.target_id = ? AND t.topology = 'tree' AND l.deleted_at IS NULL`
+	if err := c.db.Exec(invalidateRollUpsSQL(parents), id).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to invalidate the roll-up values of the parents of work item %s", id))
+	}
+	return nil
+}
+
+// InvalidateType marks the cached roll-up values of the work items of the
+// given type as stale
+// returns InternalError
+func (c *RollUpCache) InvalidateType(ctx context.Context, typeID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemrollups", "invalidatetype"}, time.Now())
+	stmt := invalidateRollUpsSQL(`SELECT id FROM work_items WHERE type = ?`)
+	if err := c.db.Exec(stmt, typeID).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to invalidate the roll-up values of the work items of type %s", typeID))
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	. "github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRollUps_Validate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	t.Run("valid", func(t *testing.T) {
+		rollUps := RollUps{
+			{Name: "points", Function: RollUpSum, Field: "custom.points"},
+			{Name: "children", Function: RollUpCount},
+			{Name: "estimated", Function: RollUpCount, Field: SystemRemainingEstimate},
+			{Name: "progress", Function: RollUpProgress},
+		}
+		require.NoError(t, rollUps.Validate())
+	})
+	t.Run("no name", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, RollUps{{Function: RollUpCount}}.Validate())
+	})
+	t.Run("duplicate name", func(t *testing.T) {
+		rollUps := RollUps{{Name: "foo", Function: RollUpCount}, {Name: "foo", Function: RollUpProgress}}
+		assert.IsType(t, errors.BadParameterError{}, rollUps.Validate())
+	})
+	t.Run("unknown function", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, RollUps{{Name: "foo", Function: "avg", Field: "custom.points"}}.Validate())
+	})
+	t.Run("sum without field", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, RollUps{{Name: "foo", Function: RollUpSum}}.Validate())
+	})
+	t.Run("progress with field", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, RollUps{{Name: "foo", Function: RollUpProgress, Field: SystemState}}.Validate())
+	})
+}
+
+func TestRollUps_Compute(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	rollUps := RollUps{
+		{Name: "sum", Function: RollUpSum, Field: "custom.points"},
+		{Name: "min", Function: RollUpMin, Field: "custom.points"},
+		{Name: "max", Function: RollUpMax, Field: "custom.points"},
+		{Name: "count", Function: RollUpCount, Field: "custom.points"},
+		{Name: "children", Function: RollUpCount},
+		{Name: "progress", Function: RollUpProgress},
+	}
+	t.Run("children", func(t *testing.T) {
+		children := []*WorkItem{
+			{Fields: map[string]interface{}{"custom.points": 3, SystemState: SystemStateClosed}},
+			{Fields: map[string]interface{}{"custom.points": 1.5, SystemState: SystemStateInProgress}},
+			{Fields: map[string]interface{}{"custom.points": float64(8), SystemState: SystemStateResolved}},
+			{Fields: map[string]interface{}{SystemState: SystemStateNew}},
+		}
+		values := rollUps.Compute(children)
+		assert.Equal(t, map[string]interface{}{
+			"sum":      12.5,
+			"min":      1.5,
+			"max":      float64(8),
+			"count":    3,
+			"children": 4,
+			"progress": float64(50),
+		}, values)
+	})
+	t.Run("no children", func(t *testing.T) {
+		values := rollUps.Compute(nil)
+		assert.Equal(t, map[string]interface{}{
+			"sum":      float64(0),
+			"min":      nil,
+			"max":      nil,
+			"count":    0,
+			"children": 0,
+			"progress": nil,
+		}, values)
+	})
+}
+
+type rollUpCacheBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+}
+
+func TestRunRollUpCacheBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &rollUpCacheBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *rollUpCacheBlackBoxTest) TestStore() {
+	rollUps := RollUps{{Name: "children", Function: RollUpCount}, {Name: "points", Function: RollUpSum, Field: "custom.points"}}
+	values := map[string]interface{}{"children": 2, "points": 3.5}
+
+	s.T().Run("stored and loaded", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		id := fxt.WorkItems[0].ID
+		cache := NewRollUpCache(s.DB)
+		// when
+		err := cache.Store(s.Ctx, id, nil, values)
+		// then
+		require.NoError(t, err)
+		cached, err := cache.Load(s.Ctx, map[uuid.UUID]RollUps{id: rollUps})
+		require.NoError(t, err)
+		require.Contains(t, cached, id)
+		assert.Equal(t, values, cached[id].Values)
+	})
+
+	s.T().Run("stale after an invalidation", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		id := fxt.WorkItems[0].ID
+		cache := NewRollUpCache(s.DB)
+		require.NoError(t, cache.Store(s.Ctx, id, nil, values))
+		// when
+		err := cache.Invalidate(s.Ctx, id)
+		// then
+		require.NoError(t, err)
+		cached, err := cache.Load(s.Ctx, map[uuid.UUID]RollUps{id: rollUps})
+		require.NoError(t, err)
+		require.Contains(t, cached, id)
+		assert.Nil(t, cached[id].Values)
+	})
+
+	s.T().Run("values computed before an invalidation are not stored", func(t *testing.T) {
+		// given values that are computed while the cache is invalidated
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		id := fxt.WorkItems[0].ID
+		cache := NewRollUpCache(s.DB)
+		require.NoError(t, cache.Invalidate(s.Ctx, id))
+		before, err := cache.Load(s.Ctx, map[uuid.UUID]RollUps{id: rollUps})
+		require.NoError(t, err)
+		loaded := before[id]
+		require.NoError(t, cache.Invalidate(s.Ctx, id))
+		// when
+		err = cache.Store(s.Ctx, id, &loaded, values)
+		// then
+		require.NoError(t, err)
+		cached, err := cache.Load(s.Ctx, map[uuid.UUID]RollUps{id: rollUps})
+		require.NoError(t, err)
+		assert.Nil(t, cached[id].Values)
+		assert.Equal(t, loaded.Generation+1, cached[id].Generation)
+	})
+
+	s.T().Run("values computed without cached values are not stored after an invalidation", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		id := fxt.WorkItems[0].ID
+		cache := NewRollUpCache(s.DB)
+		require.NoError(t, cache.Invalidate(s.Ctx, id))
+		// when
+		err := cache.Store(s.Ctx, id, nil, values)
+		// then
+		require.NoError(t, err)
+		cached, err := cache.Load(s.Ctx, map[uuid.UUID]RollUps{id: rollUps})
+		require.NoError(t, err)
+		assert.Nil(t, cached[id].Values)
+	})
+}
@@ -59,11 +59,26 @@ func (t SimpleType) ConvertToModel(value interface{}) (interface{}, error) {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, "float64", valueType.Name())
 		}
 		return value, nil
-	case KindInteger, KindDuration: // NOTE: Duration is a typedef of int64
+	case KindInteger:
 		if valueType.Kind() != reflect.Int && valueType.Kind() != reflect.Int64 {
 			return nil, errs.Errorf("value %v should be %s, but is %s", value, "int", valueType.Name())
 		}
 		return value, nil
+	case KindDuration: // NOTE: Duration is a typedef of int64
+		switch valueType.Kind() {
+		case reflect.Int, reflect.Int64:
+			return value, nil
+		case reflect.Float64:
+			// durations sent in JSON payloads or loaded from the stored
+			// fields are decoded as float64
+			v := value.(float64)
+			if v != math.Trunc(v) {
+				return nil, errs.Errorf("value %v is not a whole number", value)
+			}
+			return int64(v), nil
+		default:
+			return nil, errs.Errorf("value %v should be %s, but is %s", value, "int", valueType.Name())
+		}
 	case KindInstant:
 		// instant == milliseconds
 		// if !valueType.Implements(timeType) {
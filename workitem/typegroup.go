@@ -31,90 +31,41 @@ const (
 	BucketIteration   TypeBucket = "iteration"
 )
 
+// TypeList is a list of work item type IDs that is stored as JSON
+type TypeList []uuid.UUID
+
+// Scan implements the https://golang.org/pkg/database/sql/#Scanner interface
+func (l *TypeList) Scan(src interface{}) error { return fromBytes(src, l) }
+
+// Value implements the https://golang.org/pkg/database/sql/driver/#Valuer interface
+func (l TypeList) Value() (driver.Value, error) { return toBytes(l) }
+
+// TypeGroupMembership is the value to compare the computed "system.typegroup"
+// field with in order to find the work items whose type belongs to the type
+// group with the given name. The type groups stored for the space of a work
+// item are used; the default types apply to the spaces without stored type
+// groups, which were created without a space template.
+type TypeGroupMembership struct {
+	Name         string
+	DefaultTypes []uuid.UUID
+}
+
 // WorkItemTypeGroup represents the node in the group of work item types
 type WorkItemTypeGroup struct {
 	gormsupport.Lifecycle
 	ID       uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
 	Bucket   TypeBucket
-	Name     string      // the name to be displayed to user (is unique)
-	TypeList []uuid.UUID // TODO(kwk): We need to store this outside of this structure in the DB
+	Name     string   // the name to be displayed to user (is unique)
+	TypeList TypeList `sql:"type:jsonb"`
 	Icon     string
+	// SpaceID is the space the group was created in when a space template was
+	// applied to it, the groups of the built-in templates have no space
+	SpaceID uuid.UUID `sql:"type:uuid"`
+	// Position defines the order of the groups of a space
+	Position int
 }
 
-// TypeGroups returns the list of work item type groups
-func TypeGroups() []WorkItemTypeGroup {
-	scenariosID := uuid.FromStringOrNil("feb28a28-44a6-43f8-946a-bae987713891")
-	experiencesID := uuid.FromStringOrNil("d4e2c859-f416-4e9a-a3e0-e7bb4e1b454b")
-	requirementsID := uuid.FromStringOrNil("bb1de8b6-3175-4821-abe9-50d0a64f19a2")
-	executionID := uuid.FromStringOrNil("7fdfde54-9cf2-4098-b33b-30cd505dcfc3")
-
-	return []WorkItemTypeGroup{
-		// There can be more than one groups in the "Portfolio" bucket
-		{
-			ID:     scenariosID,
-			Bucket: BucketPortfolio,
-			Name:   "Scenarios",
-			Icon:   "fa fa-bullseye",
-			TypeList: []uuid.UUID{
-				SystemScenario,
-				SystemFundamental,
-				SystemPapercuts,
-			},
-		},
-		{
-			ID:     experiencesID,
-			Bucket: BucketPortfolio,
-			Name:   "Experiences",
-			Icon:   "pficon pficon-infrastructure",
-			TypeList: []uuid.UUID{
-				SystemExperience,
-				SystemValueProposition,
-			},
-		},
-		// There's always only one group in the "Requirement" bucket
-		{
-			ID:     requirementsID,
-			Bucket: BucketRequirement,
-			Name:   "Requirements",
-			Icon:   "fa fa-list-ul",
-			TypeList: []uuid.UUID{
-				SystemFeature,
-				SystemBug,
-			},
-		},
-		// There's always only one group in the "Iteration" bucket
-		{
-			ID:     executionID,
-			Bucket: BucketIteration,
-			Name:   "Execution",
-			Icon:   "fa fa-repeat",
-			TypeList: []uuid.UUID{
-				SystemTask,
-				SystemBug,
-				SystemFeature,
-			},
-		},
-	}
-}
-
-// TypeGroupByName returns a type group based on its name if such a group
-// exists; otherwise nil is returned.
-func TypeGroupByName(name string) *WorkItemTypeGroup {
-	for _, t := range TypeGroups() {
-		if t.Name == name {
-			return &t
-		}
-	}
-	return nil
-}
-
-// TypeGroupsByBucket returns all type groups which fall into the given bucket
-func TypeGroupsByBucket(bucket TypeBucket) []WorkItemTypeGroup {
-	res := []WorkItemTypeGroup{}
-	for _, t := range TypeGroups() {
-		if t.Bucket == bucket {
-			res = append(res, t)
-		}
-	}
-	return res
+// TableName implements gorm.tabler
+func (g WorkItemTypeGroup) TableName() string {
+	return "work_item_type_groups"
 }
@@ -0,0 +1,77 @@
+package workitem
+
+import (
+	"context"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// WorkItemTypeGroupRepository encapsulates storage & retrieval of the work
+// item type groups of the spaces
+type WorkItemTypeGroupRepository interface {
+	Create(ctx context.Context, group *WorkItemTypeGroup) error
+	Load(ctx context.Context, id uuid.UUID) (*WorkItemTypeGroup, error)
+	List(ctx context.Context, spaceID uuid.UUID) ([]WorkItemTypeGroup, error)
+}
+
+// NewWorkItemTypeGroupRepository creates a work item type group repository
+// based on gorm
+func NewWorkItemTypeGroupRepository(db *gorm.DB) *GormWorkItemTypeGroupRepository {
+	return &GormWorkItemTypeGroupRepository{db: db}
+}
+
+// GormWorkItemTypeGroupRepository implements WorkItemTypeGroupRepository
+// using gorm
+type GormWorkItemTypeGroupRepository struct {
+	db *gorm.DB
+}
+
+// Create creates a new work item type group
+// returns BadParameterError or InternalError
+func (r *GormWorkItemTypeGroupRepository) Create(ctx context.Context, group *WorkItemTypeGroup) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtypegroup", "create"}, time.Now())
+	if group.ID == uuid.Nil {
+		group.ID = uuid.NewV4()
+	}
+	if group.Name == "" {
+		return errors.NewBadParameterError("name", group.Name).Expected("a non-empty type group name")
+	}
+	if err := r.db.Create(group).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create work item type group"))
+	}
+	log.Debug(ctx, map[string]interface{}{"type_group_id": group.ID, "space_id": group.SpaceID}, "work item type group created")
+	return nil
+}
+
+// Load returns the work item type group with the given ID
+// returns NotFoundError or InternalError
+func (r *GormWorkItemTypeGroupRepository) Load(ctx context.Context, id uuid.UUID) (*WorkItemTypeGroup, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtypegroup", "load"}, time.Now())
+	group := WorkItemTypeGroup{}
+	db := r.db.Where("id = ?", id).First(&group)
+	if db.RecordNotFound() {
+		return nil, errors.NewNotFoundError("type group", id.String())
+	}
+	if err := db.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &group, nil
+}
+
+// List returns the work item type groups of the given space in their order
+// returns InternalError
+func (r *GormWorkItemTypeGroupRepository) List(ctx context.Context, spaceID uuid.UUID) ([]WorkItemTypeGroup, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtypegroup", "list"}, time.Now())
+	var groups []WorkItemTypeGroup
+	if err := r.db.Where("space_id = ?", spaceID).Order("position").Find(&groups).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return groups, nil
+}
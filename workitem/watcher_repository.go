@@ -0,0 +1,100 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Watcher is an identity that follows a work item and gets notified about
+// its changes
+type Watcher struct {
+	CreatedAt  time.Time
+	WorkItemID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+	IdentityID uuid.UUID `sql:"type:uuid" gorm:"primary_key"`
+}
+
+// TableName implements gorm.tabler
+func (w Watcher) TableName() string {
+	return "work_item_watchers"
+}
+
+// WatcherRepository encapsulates storage & retrieval of the watchers of work
+// items
+type WatcherRepository interface {
+	Watch(ctx context.Context, workItemID, identityID uuid.UUID) error
+	Unwatch(ctx context.Context, workItemID, identityID uuid.UUID) error
+	List(ctx context.Context, workItemID uuid.UUID) ([]uuid.UUID, error)
+	IsWatching(ctx context.Context, workItemID, identityID uuid.UUID) (bool, error)
+}
+
+// NewWatcherRepository creates a work item watcher repository based on gorm
+func NewWatcherRepository(db *gorm.DB) *GormWatcherRepository {
+	return &GormWatcherRepository{db: db}
+}
+
+// GormWatcherRepository implements WatcherRepository using gorm
+type GormWatcherRepository struct {
+	db *gorm.DB
+}
+
+// Watch makes the given identity a watcher of the given work item. Watching
+// a work item twice has no effect.
+// returns InternalError
+func (r *GormWatcherRepository) Watch(ctx context.Context, workItemID, identityID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemwatcher", "watch"}, time.Now())
+	stmt := fmt.Sprintf(`INSERT INTO %s (created_at, work_item_id, identity_id) VALUES (?, ?, ?)
+		ON CONFLICT (work_item_id, identity_id) DO NOTHING`, Watcher{}.TableName())
+	if err := r.db.Exec(stmt, time.Now(), workItemID, identityID).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to add watcher %s to work item %s", identityID, workItemID))
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": workItemID, "identity_id": identityID}, "work item watched")
+	return nil
+}
+
+// Unwatch removes the given identity from the watchers of the given work
+// item. Unwatching a work item that is not watched has no effect.
+// returns InternalError
+func (r *GormWatcherRepository) Unwatch(ctx context.Context, workItemID, identityID uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemwatcher", "unwatch"}, time.Now())
+	if err := r.db.Where("work_item_id = ? AND identity_id = ?", workItemID, identityID).Delete(&Watcher{}).Error; err != nil {
+		return errors.NewInternalError(ctx, errs.Wrapf(err, "failed to remove watcher %s from work item %s", identityID, workItemID))
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": workItemID, "identity_id": identityID}, "work item unwatched")
+	return nil
+}
+
+// List returns the identity IDs of the watchers of the given work item in the
+// order they started to watch it
+// returns InternalError
+func (r *GormWatcherRepository) List(ctx context.Context, workItemID uuid.UUID) ([]uuid.UUID, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemwatcher", "list"}, time.Now())
+	var watchers []Watcher
+	if err := r.db.Where("work_item_id = ?", workItemID).Order("created_at, identity_id").Find(&watchers).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	result := make([]uuid.UUID, len(watchers))
+	for i, w := range watchers {
+		result[i] = w.IdentityID
+	}
+	return result, nil
+}
+
+// IsWatching returns true if the given identity watches the given work item
+// returns InternalError
+func (r *GormWatcherRepository) IsWatching(ctx context.Context, workItemID, identityID uuid.UUID) (bool, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemwatcher", "iswatching"}, time.Now())
+	var count int
+	if err := r.db.Model(&Watcher{}).Where("work_item_id = ? AND identity_id = ?", workItemID, identityID).Count(&count).Error; err != nil {
+		return false, errors.NewInternalError(ctx, err)
+	}
+	return count > 0, nil
+}
@@ -0,0 +1,97 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type watcherRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo workitem.WatcherRepository
+}
+
+func TestRunWatcherRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &watcherRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *watcherRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = workitem.NewWatcherRepository(s.DB)
+}
+
+func (s *watcherRepoBlackBoxTest) TestWatch() {
+	s.T().Run("creator watches the new work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		// when
+		watchers, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[0].ID}, watchers)
+	})
+
+	s.T().Run("assignees watch the work item", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItems(1))
+		wi := fxt.WorkItems[0]
+		wi.Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+		// when
+		_, err := workitem.NewWorkItemRepository(s.DB).Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		watching, err := s.repo.IsWatching(s.Ctx, wi.ID, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		assert.True(t, watching)
+	})
+
+	s.T().Run("assignees who stopped watching are not watchers again", func(t *testing.T) {
+		// given an assignee who stopped watching the work item
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItems(1))
+		wi := fxt.WorkItems[0]
+		repo := workitem.NewWorkItemRepository(s.DB)
+		wi.Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+		wi, err := repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		require.NoError(t, s.repo.Unwatch(s.Ctx, wi.ID, fxt.Identities[1].ID))
+		// when the work item is updated again
+		wi.Fields[workitem.SystemTitle] = "updated"
+		_, err = repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		watching, err := s.repo.IsWatching(s.Ctx, wi.ID, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		assert.False(t, watching)
+	})
+
+	s.T().Run("watch twice and unwatch", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItems(1))
+		wiID := fxt.WorkItems[0].ID
+		watcher := fxt.Identities[1].ID
+		// when
+		require.NoError(t, s.repo.Watch(s.Ctx, wiID, watcher))
+		require.NoError(t, s.repo.Watch(s.Ctx, wiID, watcher))
+		// then
+		watchers, err := s.repo.List(s.Ctx, wiID)
+		require.NoError(t, err)
+		assert.Len(t, watchers, 2)
+		// when
+		require.NoError(t, s.repo.Unwatch(s.Ctx, wiID, watcher))
+		// then
+		watching, err := s.repo.IsWatching(s.Ctx, wiID, watcher)
+		require.NoError(t, err)
+		assert.False(t, watching)
+		watchers, err = s.repo.List(s.Ctx, wiID)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.Identities[0].ID}, watchers)
+	})
+}
@@ -0,0 +1,258 @@
+package workitem
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fabric8-services/fabric8-wit/convert"
+	"github.com/fabric8-services/fabric8-wit/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// The roles that can be required to perform a workflow transition
+const (
+	// WorkflowRoleSpaceOwner is the owner of the space of the work item
+	WorkflowRoleSpaceOwner = "space-owner"
+	// WorkflowRoleCreator is the creator of the work item
+	WorkflowRoleCreator = "creator"
+	// WorkflowRoleAssignee is any of the assignees of the work item
+	WorkflowRoleAssignee = "assignee"
+)
+
+// WorkflowState is a state of a workflow
+type WorkflowState struct {
+	Name string `json:"name"`
+	// RequiredFields must have a value when a work item enters the state
+	RequiredFields []string `json:"required_fields,omitempty"`
+}
+
+// WorkflowTransition allows work items to move from one state to another
+type WorkflowTransition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Roles restricts who can perform the transition, everybody who can
+	// modify the work item can perform it if no roles are given
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Workflow defines the states a work item of a type can be in (as stored in
+// the system.state field) and the transitions between these states
+type Workflow struct {
+	States      []WorkflowState      `json:"states"`
+	Transitions []WorkflowTransition `json:"transitions"`
+}
+
+// Ensure Workflow implements the Equaler interface
+var _ convert.Equaler = Workflow{}
+var _ convert.Equaler = (*Workflow)(nil)
+
+// Equal returns true if two Workflow objects are equal; otherwise false is returned.
+func (w Workflow) Equal(u convert.Equaler) bool {
+	other, ok := u.(Workflow)
+	if !ok {
+		return false
+	}
+	return reflect.DeepEqual(w, other)
+}
+
+// Value implements the driver.Valuer interface
+func (w *Workflow) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return toBytes(w)
+}
+
+// Scan implements the sql.Scanner interface
+func (w *Workflow) Scan(src interface{}) error {
+	return fromBytes(src, w)
+}
+
+// State returns the state with the given name or nil if the workflow has no
+// such state
+func (w Workflow) State(name string) *WorkflowState {
+	for i := range w.States {
+		if w.States[i].Name == name {
+			return &w.States[i]
+		}
+	}
+	return nil
+}
+
+// FinalStateCondition returns an SQL condition that is true if the work item
+// with the given table alias is in a state of the workflow of its type from
+// which no transition leads to another state. It is false for the work items
+// of types without a workflow.
+func FinalStateCondition(alias string) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM %[2]s wit
+		WHERE wit.id = %[1]s.type
+			AND wit.workflow->'states' @> jsonb_build_array(jsonb_build_object('name', %[1]s.fields->>'%[3]s'))
+			AND NOT EXISTS (
+				SELECT 1 FROM jsonb_array_elements(wit.workflow->'transitions') t
+				WHERE t->>'from' = %[1]s.fields->>'%[3]s' AND t->>'to' <> t->>'from'
+			)
+	)`, alias, WorkItemType{}.TableName(), SystemState)
+}
+
+// InitialState returns the name of the first state of the workflow, which is
+// the state work items start in, or an empty string if there are no states
+func (w Workflow) InitialState() string {
+	if len(w.States) == 0 {
+		return ""
+	}
+	return w.States[0].Name
+}
+
+// Transition returns the transition between the given states or nil if the
+// workflow has no such transition
+func (w Workflow) Transition(from, to string) *WorkflowTransition {
+	for i := range w.Transitions {
+		if w.Transitions[i].From == from && w.Transitions[i].To == to {
+			return &w.Transitions[i]
+		}
+	}
+	return nil
+}
+
+// targetStates returns the names of the states that can be reached from the
+// given state
+func (w Workflow) targetStates(from string) []string {
+	names := []string{}
+	for _, t := range w.Transitions {
+		if t.From == from {
+			names = append(names, t.To)
+		}
+	}
+	return names
+}
+
+// Validate checks that the workflow is consistent with itself and with the
+// fields of the given work item type
+// returns BadParameterError
+func (w Workflow) Validate(wit WorkItemType) error {
+	if len(w.States) == 0 {
+		return errors.NewBadParameterError("workflow.states", nil).Expected("at least one state")
+	}
+	var allowedStates []interface{}
+	if stateField, ok := wit.Fields[SystemState]; ok {
+		switch enumType := stateField.Type.(type) {
+		case EnumType:
+			allowedStates = enumType.Values
+		case *EnumType:
+			allowedStates = enumType.Values
+		}
+	}
+	names := map[string]struct{}{}
+	for _, s := range w.States {
+		if s.Name == "" {
+			return errors.NewBadParameterError("workflow.states.name", s.Name).Expected("a non-empty state name")
+		}
+		if _, exists := names[s.Name]; exists {
+			return errors.NewBadParameterError("workflow.states.name", s.Name).Expected("unique state names")
+		}
+		if allowedStates != nil && !containsValue(allowedStates, s.Name) {
+			return errors.NewBadParameterError("workflow.states.name", s.Name).Expected(fmt.Sprintf("one of the values of %s: %v", SystemState, allowedStates))
+		}
+		names[s.Name] = struct{}{}
+		for _, field := range s.RequiredFields {
+			if _, ok := wit.Fields[field]; !ok {
+				return errors.NewBadParameterError("workflow.states.required_fields", field).Expected("a field of work item type " + wit.Name)
+			}
+		}
+	}
+	for _, t := range w.Transitions {
+		if w.State(t.From) == nil {
+			return errors.NewBadParameterError("workflow.transitions.from", t.From).Expected("a state of the workflow")
+		}
+		if w.State(t.To) == nil {
+			return errors.NewBadParameterError("workflow.transitions.to", t.To).Expected("a state of the workflow")
+		}
+		for _, role := range t.Roles {
+			switch role {
+			case WorkflowRoleSpaceOwner, WorkflowRoleCreator, WorkflowRoleAssignee:
+			default:
+				return errors.NewBadParameterError("workflow.transitions.roles", role).Expected(strings.Join([]string{WorkflowRoleSpaceOwner, WorkflowRoleCreator, WorkflowRoleAssignee}, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// CheckState verifies that a work item with the given fields (as stored in
+// the database) can be in the given state: the state must be part of the
+// workflow and the fields required by the state must have a value.
+// returns BadParameterError
+func (w Workflow) CheckState(state string, fields Fields) error {
+	s := w.State(state)
+	if s == nil {
+		names := make([]string, len(w.States))
+		for i, s := range w.States {
+			names[i] = s.Name
+		}
+		return errors.NewBadParameterError(SystemState, state).Expected("one of the states of the workflow: " + strings.Join(names, ", "))
+	}
+	for _, field := range s.RequiredFields {
+		if isEmptyValue(fields[field]) {
+			return errors.NewBadParameterError(field, nil).Expected(fmt.Sprintf("a value when entering state %q", state))
+		}
+	}
+	return nil
+}
+
+// CheckTransition verifies that a work item can move from one state to the
+// other. The given roles are the roles of the identity that performs the
+// transition.
+// returns BadParameterError or ForbiddenError
+func (w Workflow) CheckTransition(from, to string, roles []string) error {
+	t := w.Transition(from, to)
+	if t == nil {
+		return errors.NewBadParameterError(SystemState, to).Expected(fmt.Sprintf("a state that can be reached from %q: %s", from, strings.Join(w.targetStates(from), ", ")))
+	}
+	if len(t.Roles) == 0 {
+		return nil
+	}
+	for _, required := range t.Roles {
+		for _, role := range roles {
+			if role == required {
+				return nil
+			}
+		}
+	}
+	return errors.NewForbiddenError(fmt.Sprintf("the transition from %q to %q can only be performed by: %s", from, to, strings.Join(t.Roles, ", ")))
+}
+
+// workflowRoles returns the workflow roles of the given identity for a work
+// item with the given fields (as stored in the database) in a space owned by
+// the given space owner
+func workflowRoles(identityID uuid.UUID, spaceOwnerID uuid.UUID, fields Fields) []string {
+	roles := []string{}
+	if identityID == spaceOwnerID {
+		roles = append(roles, WorkflowRoleSpaceOwner)
+	}
+	if fields[SystemCreator] == identityID.String() {
+		roles = append(roles, WorkflowRoleCreator)
+	}
+	if assignees, err := asList(fields[SystemAssignees]); err == nil && containsValue(assignees, identityID.String()) {
+		roles = append(roles, WorkflowRoleAssignee)
+	}
+	return roles
+}
+
+// isEmptyValue returns true if the given field value is nil, an empty string
+// or an empty list
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if s, ok := value.(string); ok {
+		return s == ""
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		return v.Len() == 0
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package workitem_test
+
+import (
+	"testing"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	. "github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testWorkflow() Workflow {
+	return Workflow{
+		States: []WorkflowState{
+			{Name: SystemStateNew},
+			{Name: SystemStateInProgress},
+			{Name: SystemStateResolved, RequiredFields: []string{SystemAssignees}},
+			{Name: SystemStateClosed},
+		},
+		Transitions: []WorkflowTransition{
+			{From: SystemStateNew, To: SystemStateInProgress},
+			{From: SystemStateInProgress, To: SystemStateResolved},
+			{From: SystemStateResolved, To: SystemStateClosed, Roles: []string{WorkflowRoleSpaceOwner}},
+		},
+	}
+}
+
+func TestWorkflow_Validate(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	wit := WorkItemType{
+		Name: "foo",
+		Fields: FieldDefinitions{
+			SystemAssignees: {Type: ListType{
+				SimpleType:    SimpleType{Kind: KindList},
+				ComponentType: SimpleType{Kind: KindUser},
+			}},
+			SystemState: {Type: EnumType{
+				SimpleType: SimpleType{Kind: KindEnum},
+				BaseType:   SimpleType{Kind: KindString},
+				Values:     []interface{}{SystemStateNew, SystemStateInProgress, SystemStateResolved, SystemStateClosed},
+			}},
+		},
+	}
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, testWorkflow().Validate(wit))
+	})
+	t.Run("no states", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, Workflow{}.Validate(wit))
+	})
+	t.Run("state not in enum", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.States = append(wf.States, WorkflowState{Name: "foo"})
+		assert.IsType(t, errors.BadParameterError{}, wf.Validate(wit))
+	})
+	t.Run("duplicate state", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.States = append(wf.States, WorkflowState{Name: SystemStateNew})
+		assert.IsType(t, errors.BadParameterError{}, wf.Validate(wit))
+	})
+	t.Run("unknown required field", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.States[0].RequiredFields = []string{"foo"}
+		assert.IsType(t, errors.BadParameterError{}, wf.Validate(wit))
+	})
+	t.Run("transition to unknown state", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.States = wf.States[:3]
+		assert.IsType(t, errors.BadParameterError{}, wf.Validate(wit))
+	})
+	t.Run("unknown role", func(t *testing.T) {
+		wf := testWorkflow()
+		wf.Transitions[0].Roles = []string{"foo"}
+		assert.IsType(t, errors.BadParameterError{}, wf.Validate(wit))
+	})
+}
+
+func TestWorkflow_CheckTransition(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	wf := testWorkflow()
+	t.Run("allowed", func(t *testing.T) {
+		require.NoError(t, wf.CheckTransition(SystemStateNew, SystemStateInProgress, nil))
+	})
+	t.Run("allowed with role", func(t *testing.T) {
+		require.NoError(t, wf.CheckTransition(SystemStateResolved, SystemStateClosed, []string{WorkflowRoleCreator, WorkflowRoleSpaceOwner}))
+	})
+	t.Run("missing transition", func(t *testing.T) {
+		err := wf.CheckTransition(SystemStateNew, SystemStateClosed, nil)
+		require.IsType(t, errors.BadParameterError{}, err)
+		assert.Contains(t, err.Error(), SystemStateInProgress)
+	})
+	t.Run("missing role", func(t *testing.T) {
+		err := wf.CheckTransition(SystemStateResolved, SystemStateClosed, []string{WorkflowRoleAssignee})
+		assert.IsType(t, errors.ForbiddenError{}, err)
+	})
+}
+
+func TestWorkflow_CheckState(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+
+	wf := testWorkflow()
+	t.Run("ok", func(t *testing.T) {
+		require.NoError(t, wf.CheckState(SystemStateResolved, Fields{SystemAssignees: []interface{}{"foo"}}))
+	})
+	t.Run("unknown state", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, wf.CheckState(SystemStateOpen, Fields{}))
+	})
+	t.Run("missing required field", func(t *testing.T) {
+		assert.IsType(t, errors.BadParameterError{}, wf.CheckState(SystemStateResolved, Fields{SystemAssignees: []interface{}{}}))
+	})
+}
@@ -3,6 +3,7 @@ package workitem
 import (
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/log"
 
 	uuid "github.com/satori/go.uuid"
@@ -14,6 +15,9 @@ type WorkItem struct {
 	ID uuid.UUID
 	// unique number per _space_
 	Number int
+	// optional human-readable key (e.g. "PLAT-123"), only set when the space
+	// has a key prefix
+	Key *string
 	// ID of the type of this work item
 	Type uuid.UUID
 	// Version for optimistic concurrency control
@@ -22,6 +26,8 @@ type WorkItem struct {
 	SpaceID uuid.UUID
 	// The field values, according to the field type
 	Fields map[string]interface{}
+	// The users mentioned in the description, maintained by the repository
+	Mentions account.Mentions
 	// optional, private timestamp of the latest addition/removal of a relationship with this workitem
 	// this field is used to generate the `ETag` and `Last-Modified` values in the HTTP responses and conditional requests processing
 	relationShipsChangedAt *time.Time
@@ -32,6 +38,8 @@ type WICountsPerIteration struct {
 	IterationID string `gorm:"column:iterationid"`
 	Total       int
 	Closed      int
+	// the estimates and the time spent of the work items in the iteration
+	TimeTotals
 }
 
 // GetETagData returns the field values to use to generate the ETag
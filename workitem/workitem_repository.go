@@ -44,15 +44,22 @@ type WorkItemRepository interface {
 	LoadBatchByID(ctx context.Context, ids []uuid.UUID) ([]*WorkItem, error)
 	LoadByIteration(ctx context.Context, id uuid.UUID) ([]*WorkItem, error)
 	LookupIDByNamedSpaceAndNumber(ctx context.Context, ownerName, spaceName string, wiNumber int) (*uuid.UUID, *uuid.UUID, error)
+	LookupIDByKey(ctx context.Context, key string) (*uuid.UUID, *uuid.UUID, error)
 	Save(ctx context.Context, spaceID uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Reorder(ctx context.Context, spaceID uuid.UUID, direction DirectionType, targetID *uuid.UUID, wi WorkItem, modifierID uuid.UUID) (*WorkItem, error)
 	Delete(ctx context.Context, id uuid.UUID, suppressorID uuid.UUID) error
+	Restore(ctx context.Context, id uuid.UUID, modifierID uuid.UUID) (*WorkItem, error)
+	Move(ctx context.Context, id uuid.UUID, targetSpaceID uuid.UUID, modifierID uuid.UUID) (*WorkItem, error)
+	Revert(ctx context.Context, id uuid.UUID, revisionID uuid.UUID, version int, fieldNames []string, modifierID uuid.UUID) (*WorkItem, error)
+	ChangeType(ctx context.Context, id uuid.UUID, typeID uuid.UUID, version int, confirmDrop bool, modifierID uuid.UUID) (*WorkItem, *TypeChange, error)
 	Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error)
 	List(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression, parentExists *bool, start *int, length *int) ([]WorkItem, int, error)
 	Fetch(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (*WorkItem, error)
 	GetCountsPerIteration(ctx context.Context, spaceID uuid.UUID) (map[string]WICountsPerIteration, error)
 	GetCountsForIteration(ctx context.Context, itr *iteration.Iteration) (map[string]WICountsPerIteration, error)
 	Count(ctx context.Context, spaceID uuid.UUID, criteria criteria.Expression) (int, error)
+	ListOpenBlockers(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	ListOpenBlockersBatch(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
 }
 
 // NewWorkItemRepository creates a GormWorkItemRepository
@@ -62,6 +69,7 @@ func NewWorkItemRepository(db *gorm.DB) *GormWorkItemRepository {
 		winr: numbersequence.NewWorkItemNumberSequenceRepository(db),
 		witr: &GormWorkItemTypeRepository{db},
 		wirr: &GormRevisionRepository{db},
+		wiwr: NewWatcherRepository(db),
 	}
 	return repository
 }
@@ -72,6 +80,7 @@ type GormWorkItemRepository struct {
 	winr *numbersequence.GormWorkItemNumberSequenceRepository
 	witr *GormWorkItemTypeRepository
 	wirr *GormRevisionRepository
+	wiwr *GormWatcherRepository
 }
 
 // ************************************************
@@ -135,14 +144,20 @@ func (r *GormWorkItemRepository) LoadBatchByID(ctx context.Context, ids []uuid.U
 		return nil, errs.WithStack(err)
 	}
 	workitems := []*WorkItem{}
+	// the work items of a batch usually share a few types only
+	wiTypes := map[uuid.UUID]*WorkItemType{}
 	for _, ele := range res {
-		wiType, err := r.witr.LoadTypeFromDB(ctx, ele.Type)
-		if err != nil {
-			log.Error(nil, map[string]interface{}{
-				"wit_id": ele.Type,
-				"err":    err,
-			}, "error in loading type from DB")
-			return nil, errors.NewInternalError(ctx, err)
+		wiType, ok := wiTypes[ele.Type]
+		if !ok {
+			wiType, err = r.witr.LoadTypeFromDB(ctx, ele.Type)
+			if err != nil {
+				log.Error(nil, map[string]interface{}{
+					"wit_id": ele.Type,
+					"err":    err,
+				}, "error in loading type from DB")
+				return nil, errors.NewInternalError(ctx, err)
+			}
+			wiTypes[ele.Type] = wiType
 		}
 		convertedWI, err := ConvertWorkItemStorageToModel(wiType, &ele)
 		if err != nil {
@@ -193,6 +208,21 @@ func (r *GormWorkItemRepository) LookupIDByNamedSpaceAndNumber(ctx context.Conte
 	}
 	var result Result
 	db := r.db.Raw(query, ownerName, spaceName, wiNumber).Scan(&result)
+	if db.RecordNotFound() {
+		// the work item may have been moved to another space since
+		query = fmt.Sprintf(`select wi.id, wi.space_id from %[1]s m
+			join %[2]s wi on m.work_item_id = wi.id
+			join %[3]s s on m.from_space_id = s.id
+			join %[4]s i on s.owner_id = i.id
+			where lower(i.username) = lower(?) and
+			lower(s.name) = lower(?) and
+			m.from_number = ? and
+			wi.deleted_at IS NULL and
+			s.deleted_at IS NULL
+			and i.deleted_at IS NULL`,
+			Move{}.TableName(), WorkItemStorage{}.TableName(), space.Space{}.TableName(), account.Identity{}.TableName())
+		db = r.db.Raw(query, ownerName, spaceName, wiNumber).Scan(&result)
+	}
 	if db.RecordNotFound() {
 		log.Error(nil, map[string]interface{}{
 			"wi_number":  wiNumber,
@@ -323,10 +353,113 @@ func (r *GormWorkItemRepository) Delete(ctx context.Context, workitemID uuid.UUI
 	if err := r.wirr.Create(context.Background(), suppressorID, RevisionTypeDelete, workItem); err != nil {
 		return errs.Wrapf(err, "error while deleting work item")
 	}
+	// the roll-ups of the parents are computed from the work item
+	if err := NewRollUpCache(r.db).InvalidateParents(ctx, workitemID); err != nil {
+		return errs.Wrapf(err, "error while deleting work item")
+	}
 	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID}, "Work item deleted successfully!")
 	return nil
 }
 
+// Restore undeletes the soft-deleted work item with the given id
+// returns NotFoundError or InternalError
+func (r *GormWorkItemRepository) Restore(ctx context.Context, workitemID uuid.UUID, modifierID uuid.UUID) (*WorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "restore"}, time.Now())
+	wiStorage := WorkItemStorage{}
+	tx := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", workitemID).First(&wiStorage)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("deleted work item", workitemID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, wiStorage.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	version := wiStorage.Version
+	tx = r.db.Unscoped().Model(&wiStorage).Where("version = ?", version).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"version":    version + 1,
+	})
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return nil, errors.NewVersionConflictError("version conflict")
+	}
+	wiStorage.Version = version + 1
+	wiStorage.DeletedAt = nil
+	// store a revision of the restored work item, which holds the field
+	// values again
+	if err := r.wirr.Create(context.Background(), modifierID, RevisionTypeUpdate, wiStorage); err != nil {
+		return nil, errs.Wrapf(err, "error while restoring work item")
+	}
+	if err := NewRollUpCache(r.db).InvalidateParents(ctx, workitemID); err != nil {
+		return nil, errs.Wrapf(err, "error while restoring work item")
+	}
+	log.Debug(ctx, map[string]interface{}{"wi_id": workitemID}, "Work item restored successfully!")
+	return ConvertWorkItemStorageToModel(wiType, &wiStorage)
+}
+
+// isMaintainedByRepository returns true if the value of the given field is
+// set by the repository itself and can't be modified field by field, e.g.
+// when reverting to a revision or in a bulk update.
+func isMaintainedByRepository(fieldName string) bool {
+	switch fieldName {
+	case SystemCreatedAt, SystemUpdatedAt, SystemOrder, SystemNumber, SystemCreator:
+		return true
+	}
+	return false
+}
+
+// Revert sets the given fields of the work item with the given id back to
+// their values in the given revision, or all fields if no field names are
+// given. The work item is stored with Save, so the given version must match
+// the current version of the work item and a new revision is created.
+// returns NotFoundError, BadParameterError, VersionConflictError, ConversionError or InternalError
+func (r *GormWorkItemRepository) Revert(ctx context.Context, workitemID uuid.UUID, revisionID uuid.UUID, version int, fieldNames []string, modifierID uuid.UUID) (*WorkItem, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitem", "revert"}, time.Now())
+	wi, err := r.LoadByID(ctx, workitemID)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := r.wirr.Load(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if revision.WorkItemID != workitemID {
+		return nil, errors.NewNotFoundError("work item revision", revisionID.String())
+	}
+	if revision.Type == RevisionTypeDelete {
+		return nil, errors.NewBadParameterError("revision", revisionID).Expected("a revision that holds field values")
+	}
+	wiType, err := r.witr.LoadTypeFromDB(ctx, wi.Type)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	if len(fieldNames) == 0 {
+		for fieldName := range wiType.Fields {
+			if !isMaintainedByRepository(fieldName) {
+				fieldNames = append(fieldNames, fieldName)
+			}
+		}
+	}
+	for _, fieldName := range fieldNames {
+		fieldDef, ok := wiType.Fields[fieldName]
+		if !ok || isMaintainedByRepository(fieldName) {
+			return nil, errors.NewBadParameterError("fields", fieldName)
+		}
+		value, err := fieldDef.ConvertFromModel(fieldName, revision.WorkItemFields[fieldName])
+		if err != nil {
+			return nil, errors.NewConversionError(fmt.Sprintf("failed to convert field %s of revision %s: %s", fieldName, revisionID, err.Error()))
+		}
+		wi.Fields[fieldName] = value
+	}
+	wi.Version = version
+	return r.Save(ctx, wi.SpaceID, *wi, modifierID)
+}
+
 // CalculateOrder calculates the order of the reorder workitem
 func (r *GormWorkItemRepository) CalculateOrder(above, below *float64) float64 {
 	return (*above + *below) / 2
@@ -535,6 +668,7 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	}
 	wiStorage.Version = wiStorage.Version + 1
 	wiStorage.Type = updatedWorkItem.Type
+	previousFields := wiStorage.Fields
 	wiStorage.Fields = Fields{}
 
 	var convOk bool
@@ -567,6 +701,15 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 			return nil, errors.NewBadParameterError(fieldName, fieldValue)
 		}
 	}
+	if err := r.checkWorkflowTransition(ctx, spaceID, *wiType, previousFields, wiStorage.Fields, modifierID); err != nil {
+		return nil, err
+	}
+	if err := r.checkNotBlocked(ctx, wiStorage.ID, previousFields, wiStorage.Fields); err != nil {
+		return nil, err
+	}
+	if wiStorage.Mentions, err = r.resolveMentions(ctx, wiStorage.Fields); err != nil {
+		return nil, err
+	}
 	tx := r.db.Where("Version = ?", updatedWorkItem.Version).Save(&wiStorage)
 	if err := tx.Error; err != nil {
 		log.Error(ctx, map[string]interface{}{
@@ -585,6 +728,15 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	if err != nil {
 		return nil, errs.Wrapf(err, "error while saving work item")
 	}
+	// new assignees follow the work item, the others may have stopped
+	// following it on purpose
+	if err := r.watch(ctx, wiStorage.ID, wiStorage.Fields, previousFields); err != nil {
+		return nil, err
+	}
+	// the roll-ups of the parents are computed from the work item
+	if err := NewRollUpCache(r.db).InvalidateParents(ctx, wiStorage.ID); err != nil {
+		return nil, errs.Wrapf(err, "error while saving work item")
+	}
 	log.Info(ctx, map[string]interface{}{
 		"wi_id":    updatedWorkItem.ID,
 		"space_id": spaceID,
@@ -592,6 +744,39 @@ func (r *GormWorkItemRepository) Save(ctx context.Context, spaceID uuid.UUID, up
 	return ConvertWorkItemStorageToModel(wiType, wiStorage)
 }
 
+// checkWorkflowTransition verifies that a change of the state of a work item
+// is allowed by the workflow of its type (if any): the transition must exist,
+// the modifier must have one of the roles of the transition and the fields
+// required by the new state must have a value. The given fields are in their
+// storage representation.
+// returns BadParameterError, ForbiddenError or InternalError
+func (r *GormWorkItemRepository) checkWorkflowTransition(ctx context.Context, spaceID uuid.UUID, wiType WorkItemType, previousFields Fields, fields Fields, modifierID uuid.UUID) error {
+	if wiType.Workflow == nil {
+		return nil
+	}
+	previousState, _ := previousFields[SystemState].(string)
+	state, _ := fields[SystemState].(string)
+	if previousState == state {
+		return nil
+	}
+	if previousState != "" {
+		transition := wiType.Workflow.Transition(previousState, state)
+		var roles []string
+		if transition != nil && len(transition.Roles) > 0 {
+			s := space.Space{}
+			db := r.db.Select("owner_id").Where("id = ?", spaceID).First(&s)
+			if err := db.Error; err != nil && !db.RecordNotFound() {
+				return errors.NewInternalError(ctx, err)
+			}
+			roles = workflowRoles(modifierID, s.OwnerID, previousFields)
+		}
+		if err := wiType.Workflow.CheckTransition(previousState, state, roles); err != nil {
+			return err
+		}
+	}
+	return wiType.Workflow.CheckState(state, fields)
+}
+
 // Create creates a new work item in the repository
 // returns BadParameterError, ConversionError or InternalError
 func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID, typeID uuid.UUID, fields map[string]interface{}, creatorID uuid.UUID) (*WorkItem, error) {
@@ -612,12 +797,17 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return nil, errors.NewInternalError(ctx, err)
 	}
+	key, err := r.lookupKey(ctx, spaceID, *number)
+	if err != nil {
+		return nil, err
+	}
 	wi := WorkItemStorage{
 		Type:           typeID,
 		Fields:         Fields{},
 		ExecutionOrder: pos,
 		SpaceID:        spaceID,
 		Number:         *number,
+		Key:            key,
 	}
 	fields[SystemCreator] = creatorID.String()
 	for fieldName, fieldDef := range wiType.Fields {
@@ -640,6 +830,16 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 			}
 		}
 	}
+	if wiType.Workflow != nil {
+		if state, ok := wi.Fields[SystemState].(string); ok {
+			if err := wiType.Workflow.CheckState(state, wi.Fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if wi.Mentions, err = r.resolveMentions(ctx, wi.Fields); err != nil {
+		return nil, err
+	}
 	if err = r.db.Create(&wi).Error; err != nil {
 		return nil, errs.Wrapf(err, "failed to create work item")
 	}
@@ -653,10 +853,75 @@ func (r *GormWorkItemRepository) Create(ctx context.Context, spaceID uuid.UUID,
 	if err != nil {
 		return nil, errs.Wrapf(err, "error while creating work item")
 	}
+	// the creator and the assignees follow the work item
+	if err := r.watch(ctx, wi.ID, wi.Fields, nil, creatorID); err != nil {
+		return nil, err
+	}
 	log.Debug(ctx, map[string]interface{}{"pkg": "workitem", "wi_id": wi.ID, "number": wi.Number}, "Work item created successfully!")
 	return witem, nil
 }
 
+// watch makes the given identities and the assignees found in the given
+// field values (in their storage representation) watchers of the work item.
+// Assignees that are already found in the given previous field values are
+// left out.
+// returns InternalError
+func (r *GormWorkItemRepository) watch(ctx context.Context, workItemID uuid.UUID, fields Fields, previousFields Fields, identityIDs ...uuid.UUID) error {
+	previousAssignees := map[uuid.UUID]bool{}
+	for _, id := range assigneeIDs(previousFields) {
+		previousAssignees[id] = true
+	}
+	for _, id := range assigneeIDs(fields) {
+		if !previousAssignees[id] {
+			identityIDs = append(identityIDs, id)
+		}
+	}
+	for _, id := range identityIDs {
+		if err := r.wiwr.Watch(ctx, workItemID, id); err != nil {
+			return errs.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// assigneeIDs returns the IDs of the assignees found in the given field
+// values (in their storage representation)
+func assigneeIDs(fields Fields) []uuid.UUID {
+	var assignees []string
+	switch values := fields[SystemAssignees].(type) {
+	case []string:
+		assignees = values
+	case []interface{}:
+		for _, v := range values {
+			if s, ok := v.(string); ok {
+				assignees = append(assignees, s)
+			}
+		}
+	}
+	var ids []uuid.UUID
+	for _, assignee := range assignees {
+		if id, err := uuid.FromString(assignee); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// resolveMentions returns the users mentioned in the description of a work
+// item with the given field values in their storage representation
+// returns InternalError
+func (r *GormWorkItemRepository) resolveMentions(ctx context.Context, fields Fields) (account.Mentions, error) {
+	description := rendering.NewMarkupContentFromValue(fields[SystemDescription])
+	if description == nil {
+		return account.Mentions{}, nil
+	}
+	mentions, err := account.ResolveMentions(ctx, account.NewIdentityRepository(r.db), description.Content, description.Markup)
+	if err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return mentions, nil
+}
+
 // ConvertWorkItemStorageToModel convert work item model to app WI
 func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*WorkItem, error) {
 	result, err := wiType.ConvertWorkItemStorageToModel(*wi)
@@ -675,6 +940,7 @@ func ConvertWorkItemStorageToModel(wiType *WorkItemType, wi *WorkItemStorage) (*
 	if _, ok := wiType.Fields[SystemNumber]; ok {
 		result.Fields[SystemNumber] = wi.Number
 	}
+	result.Mentions = wi.Mentions
 	return result, nil
 
 }
@@ -848,7 +1114,7 @@ func (r *GormWorkItemRepository) getAllIterationWithCounts(ctx context.Context,
 	db.Pluck("id", &allIterations)
 	var res []WICountsPerIteration
 	db = r.db.Table(workitemTableName).Select(`iterations.id as IterationId, count(*) as Total,
-			count( case fields->>'system.state' when 'closed' then '1' else null end ) as Closed`).Joins(`left join iterations
+			count( case fields->>'system.state' when 'closed' then '1' else null end ) as Closed, `+timeTotalsSelect(workitemTableName)).Joins(`left join iterations
 			on fields@> concat('{"system.iteration": "', iterations.id, '"}')::jsonb`).Where(`iterations.space_id = ?
 			and work_items.deleted_at IS NULL`, spaceID).Group(`IterationId`).Scan(&res)
 	db.Scan(&res)
@@ -865,6 +1131,7 @@ func (r *GormWorkItemRepository) getAllIterationWithCounts(ctx context.Context,
 			IterationID: r.IterationID,
 			Total:       r.Total,
 			Closed:      r.Closed,
+			TimeTotals:  r.TimeTotals,
 		}
 	}
 	// put 0 count for iterations which are not in wiMap
@@ -929,11 +1196,13 @@ func (r *GormWorkItemRepository) getFinalCountAddingChild(ctx context.Context, d
 	for _, i := range wiMap {
 		t := i.Total
 		c := i.Closed
+		tt := i.TimeTotals
 		if children, exists := childMap[i.IterationID]; exists {
 			for _, child := range children {
 				if _, exists := wiMap[child]; exists {
 					t += wiMap[child].Total
 					c += wiMap[child].Closed
+					tt = tt.Add(wiMap[child].TimeTotals)
 				}
 			}
 		}
@@ -941,6 +1210,7 @@ func (r *GormWorkItemRepository) getFinalCountAddingChild(ctx context.Context, d
 			IterationID: i.IterationID,
 			Total:       t,
 			Closed:      c,
+			TimeTotals:  tt,
 		}
 	}
 	return countsMap, nil
@@ -1007,11 +1277,12 @@ func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, itr
 						count(CASE fields->>'system.state'
 									WHEN 'closed' THEN '1'
 									ELSE NULL
-								END) AS Closed
+								END) AS Closed,
+						%s
 					FROM %s wi
 					WHERE %s
 					AND wi.deleted_at IS NULL`,
-		workitemTableName, whereClause)
+		timeTotalsSelect("wi"), workitemTableName, whereClause)
 	db = r.db.Raw(query)
 	db.Scan(&res)
 	if db.Error != nil {
@@ -1026,6 +1297,7 @@ func (r *GormWorkItemRepository) GetCountsForIteration(ctx context.Context, itr
 		IterationID: itr.ID.String(),
 		Closed:      res.Closed,
 		Total:       res.Total,
+		TimeTotals:  res.TimeTotals,
 	}
 	return countsMap, nil
 }
@@ -1044,14 +1316,21 @@ func (r *GormWorkItemRepository) LoadByIteration(ctx context.Context, iterationI
 		return nil, errors.NewInternalError(ctx, tx.Error)
 	}
 	workitems := []*WorkItem{}
+	// the work items of a batch usually share a few types only
+	wiTypes := map[uuid.UUID]*WorkItemType{}
 	for _, ele := range res {
-		wiType, err := r.witr.LoadTypeFromDB(ctx, ele.Type)
-		if err != nil {
-			log.Error(nil, map[string]interface{}{
-				"wit_id": ele.Type,
-				"err":    err,
-			}, "error in loading type from DB")
-			return nil, errors.NewInternalError(ctx, err)
+		wiType, ok := wiTypes[ele.Type]
+		if !ok {
+			var err error
+			wiType, err = r.witr.LoadTypeFromDB(ctx, ele.Type)
+			if err != nil {
+				log.Error(nil, map[string]interface{}{
+					"wit_id": ele.Type,
+					"err":    err,
+				}, "error in loading type from DB")
+				return nil, errors.NewInternalError(ctx, err)
+			}
+			wiTypes[ele.Type] = wiType
 		}
 		convertedWI, err := ConvertWorkItemStorageToModel(wiType, &ele)
 		if err != nil {
@@ -3,6 +3,7 @@ package workitem_test
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -11,11 +12,13 @@ import (
 	"github.com/fabric8-services/fabric8-wit/codebase"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/ptr"
 	"github.com/fabric8-services/fabric8-wit/rendering"
 	"github.com/fabric8-services/fabric8-wit/resource"
 	"github.com/fabric8-services/fabric8-wit/space"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	"github.com/fabric8-services/fabric8-wit/workitem/link"
 	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -268,6 +271,463 @@ func (s *workItemRepoBlackBoxTest) TestCreate() {
 	})
 }
 
+func (s *workItemRepoBlackBoxTest) TestRevert() {
+	setup := func(t *testing.T) (*tf.TestFixture, workitem.Revision, *workitem.WorkItem) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemTitle] = "old title"
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			return nil
+		}))
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, revisions, 1)
+		wi := fxt.WorkItems[0]
+		wi.Fields[workitem.SystemTitle] = "new title"
+		wi.Fields[workitem.SystemState] = workitem.SystemStateOpen
+		wi, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		return fxt, revisions[0], wi
+	}
+	s.T().Run("all fields", func(t *testing.T) {
+		// given
+		fxt, revision, wi := setup(t)
+		// when
+		reverted, err := s.repo.Revert(s.Ctx, wi.ID, revision.ID, wi.Version, nil, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "old title", reverted.Fields[workitem.SystemTitle])
+		assert.Equal(t, workitem.SystemStateNew, reverted.Fields[workitem.SystemState])
+		assert.Equal(t, wi.Version+1, reverted.Version)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Len(t, revisions, 3)
+	})
+	s.T().Run("selected fields", func(t *testing.T) {
+		// given
+		fxt, revision, wi := setup(t)
+		// when
+		reverted, err := s.repo.Revert(s.Ctx, wi.ID, revision.ID, wi.Version, []string{workitem.SystemTitle}, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "old title", reverted.Fields[workitem.SystemTitle])
+		assert.Equal(t, workitem.SystemStateOpen, reverted.Fields[workitem.SystemState])
+	})
+	s.T().Run("fail - unknown field", func(t *testing.T) {
+		fxt, revision, wi := setup(t)
+		_, err := s.repo.Revert(s.Ctx, wi.ID, revision.ID, wi.Version, []string{"foo"}, fxt.Identities[0].ID)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - field maintained by the repository", func(t *testing.T) {
+		fxt, revision, wi := setup(t)
+		_, err := s.repo.Revert(s.Ctx, wi.ID, revision.ID, wi.Version, []string{workitem.SystemCreator}, fxt.Identities[0].ID)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - version conflict", func(t *testing.T) {
+		fxt, revision, wi := setup(t)
+		_, err := s.repo.Revert(s.Ctx, wi.ID, revision.ID, wi.Version-1, nil, fxt.Identities[0].ID)
+		assert.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - revision of another work item", func(t *testing.T) {
+		fxt, revision, _ := setup(t)
+		_, _, other := setup(t)
+		_, err := s.repo.Revert(s.Ctx, other.ID, revision.ID, other.Version, nil, fxt.Identities[0].ID)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestSaveWithWorkflow() {
+	setup := func(t *testing.T) (*tf.TestFixture, *workitem.WorkItem) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(2), tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemState] = workitem.SystemStateNew
+			return nil
+		}))
+		_, err := workitem.NewWorkItemTypeRepository(s.DB).SetWorkflow(s.Ctx, fxt.WorkItemTypes[0].ID, &workitem.Workflow{
+			States: []workitem.WorkflowState{
+				{Name: workitem.SystemStateNew},
+				{Name: workitem.SystemStateInProgress},
+				{Name: workitem.SystemStateResolved, RequiredFields: []string{workitem.SystemAssignees}},
+				{Name: workitem.SystemStateClosed},
+			},
+			Transitions: []workitem.WorkflowTransition{
+				{From: workitem.SystemStateNew, To: workitem.SystemStateInProgress},
+				{From: workitem.SystemStateInProgress, To: workitem.SystemStateResolved},
+				{From: workitem.SystemStateResolved, To: workitem.SystemStateClosed, Roles: []string{workitem.WorkflowRoleSpaceOwner}},
+			},
+		})
+		require.NoError(t, err)
+		wi, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		return fxt, wi
+	}
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt, wi := setup(t)
+		// when
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		wi, err := s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		wi.Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+		wi, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		wi, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Spaces[0].OwnerID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, wi.Fields[workitem.SystemState])
+	})
+	s.T().Run("fail - illegal transition", func(t *testing.T) {
+		fxt, wi := setup(t)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, err := s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - missing required field", func(t *testing.T) {
+		fxt, wi := setup(t)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		wi, err := s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		require.NoError(t, err)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		_, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - missing role", func(t *testing.T) {
+		fxt, wi := setup(t)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateInProgress
+		wi, err := s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateResolved
+		wi.Fields[workitem.SystemAssignees] = []string{fxt.Identities[1].ID.String()}
+		wi, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[1].ID)
+		require.NoError(t, err)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		_, err = s.repo.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[1].ID)
+		assert.IsType(t, errors.ForbiddenError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestRestore() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1, tf.SetWorkItemTitles("foo")))
+		require.NoError(t, s.repo.Delete(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID))
+		_, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+		// when
+		restored, err := s.repo.Restore(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "foo", restored.Fields[workitem.SystemTitle])
+		assert.Equal(t, fxt.WorkItems[0].Version+1, restored.Version)
+		loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, restored.Version, loaded.Version)
+	})
+	s.T().Run("fail - work item not deleted", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		_, err := s.repo.Restore(s.Ctx, fxt.WorkItems[0].ID, fxt.Identities[0].ID)
+		assert.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestMove() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given two spaces with a work item type, an iteration and a label
+		// of the same name
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2),
+			tf.WorkItemTypes(2, tf.SetWorkItemTypeNames("story", "story"), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].SpaceID = fxt.Spaces[idx].ID
+				return nil
+			}),
+			tf.Iterations(2, tf.SetIterationNames("sprint", "sprint"), func(fxt *tf.TestFixture, idx int) error {
+				fxt.Iterations[idx].SpaceID = fxt.Spaces[idx].ID
+				return nil
+			}),
+			tf.Labels(3, tf.SetLabelNames("bug", "ui", "bug"), func(fxt *tf.TestFixture, idx int) error {
+				if idx == 2 {
+					fxt.Labels[idx].SpaceID = fxt.Spaces[1].ID
+				}
+				return nil
+			}),
+			tf.WorkItems(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields[workitem.SystemIteration] = fxt.Iterations[0].ID.String()
+				fxt.WorkItems[idx].Fields[workitem.SystemLabels] = []string{fxt.Labels[0].ID.String(), fxt.Labels[1].ID.String()}
+				return nil
+			}),
+		)
+		// when
+		moved, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.Spaces[1].ID, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.Spaces[1].ID, moved.SpaceID)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, moved.Type)
+		assert.Equal(t, fxt.WorkItems[0].Version+1, moved.Version)
+		assert.Equal(t, fxt.Iterations[1].ID.String(), moved.Fields[workitem.SystemIteration])
+		assert.Equal(t, []interface{}{fxt.Labels[2].ID.String()}, moved.Fields[workitem.SystemLabels])
+		loaded, err := s.repo.Load(s.Ctx, fxt.Spaces[1].ID, moved.Number)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItems[0].ID, loaded.ID)
+		t.Run("former number redirects", func(t *testing.T) {
+			wiID, spaceID, err := s.repo.LookupIDByNamedSpaceAndNumber(s.Ctx, fxt.Identities[0].Username, fxt.Spaces[0].Name, fxt.WorkItems[0].Number)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+			assert.Equal(t, fxt.Spaces[1].ID, *spaceID)
+		})
+		t.Run("revision", func(t *testing.T) {
+			revisions, err := workitem.NewRevisionRepository(s.DB).ListLatest(s.Ctx, fxt.WorkItems[0].ID, 1)
+			require.NoError(t, err)
+			require.Len(t, revisions, 1)
+			assert.Equal(t, workitem.RevisionTypeMove, revisions[0].Type)
+			assert.Equal(t, moved.Version, revisions[0].WorkItemVersion)
+		})
+	})
+	s.T().Run("fail - same space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		_, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.Spaces[0].ID, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - unknown space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		_, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, uuid.NewV4(), fxt.Identities[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - work item type not available in target space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Spaces(2), tf.WorkItems(1))
+		_, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.Spaces[1].ID, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestChangeType() {
+	// given a bug type with a severity and a feature type without one
+	newFixture := func(t *testing.T) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2),
+			tf.WorkItemTypes(3, tf.SetWorkItemTypeNames("bug", "feature", "other"), func(fxt *tf.TestFixture, idx int) error {
+				switch idx {
+				case 0:
+					fxt.WorkItemTypes[idx].Fields["severity"] = workitem.FieldDefinition{
+						Label: "Severity",
+						Type:  workitem.SimpleType{Kind: workitem.KindString},
+					}
+				case 2:
+					fxt.WorkItemTypes[idx].SpaceID = fxt.Spaces[1].ID
+				}
+				return nil
+			}),
+			tf.WorkItems(1, tf.SetWorkItemTitles("bug"), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].Fields["severity"] = "high"
+				return nil
+			}),
+		)
+	}
+	s.T().Run("ok", func(t *testing.T) {
+		fxt := newFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		// when
+		changed, change, err := s.repo.ChangeType(s.Ctx, wi.ID, fxt.WorkItemTypes[1].ID, wi.Version, true, fxt.Identities[0].ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, changed.Type)
+		assert.Equal(t, wi.Version+1, changed.Version)
+		assert.Equal(t, "bug", changed.Fields[workitem.SystemTitle])
+		assert.NotContains(t, changed.Fields, "severity")
+		assert.Equal(t, []string{"severity"}, change.Dropped)
+		assert.Contains(t, change.Kept, workitem.SystemTitle)
+		revisions, err := workitem.NewRevisionRepository(s.DB).List(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		require.NotEmpty(t, revisions)
+		last := revisions[len(revisions)-1]
+		assert.Equal(t, workitem.RevisionTypeChangeType, last.Type)
+		assert.Equal(t, fxt.WorkItemTypes[1].ID, last.WorkItemTypeID)
+	})
+	s.T().Run("fail - dropped fields not confirmed", func(t *testing.T) {
+		fxt := newFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		// when
+		_, change, err := s.repo.ChangeType(s.Ctx, wi.ID, fxt.WorkItemTypes[1].ID, wi.Version, false, fxt.Identities[0].ID)
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		require.NotNil(t, change)
+		assert.Equal(t, []string{"severity"}, change.Dropped)
+		loaded, err := s.repo.LoadByID(s.Ctx, wi.ID)
+		require.NoError(t, err)
+		assert.Equal(t, fxt.WorkItemTypes[0].ID, loaded.Type)
+		assert.Equal(t, "high", loaded.Fields["severity"])
+	})
+	s.T().Run("fail - type of another space", func(t *testing.T) {
+		fxt := newFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		_, _, err := s.repo.ChangeType(s.Ctx, wi.ID, fxt.WorkItemTypes[2].ID, wi.Version, true, fxt.Identities[0].ID)
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - version conflict", func(t *testing.T) {
+		fxt := newFixture(t)
+		wi := fxt.WorkItemByTitle("bug")
+		_, _, err := s.repo.ChangeType(s.Ctx, wi.ID, fxt.WorkItemTypes[1].ID, wi.Version+1, true, fxt.Identities[0].ID)
+		require.IsType(t, errors.VersionConflictError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - unknown work item", func(t *testing.T) {
+		fxt := newFixture(t)
+		_, _, err := s.repo.ChangeType(s.Ctx, uuid.NewV4(), fxt.WorkItemTypes[1].ID, 0, true, fxt.Identities[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestBlockingDependencies() {
+	// given "blocker" blocks "blocked" through a blocking dependency link
+	newFixture := func(t *testing.T, blocking bool) *tf.TestFixture {
+		return tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinkTypes[idx].Blocking = blocking
+				return nil
+			}),
+			tf.WorkItems(2, tf.SetWorkItemTitles("blocker", "blocked")),
+			tf.WorkItemLinksCustom(1, tf.BuildLinks(tf.L("blocker", "blocked"))),
+		)
+	}
+	closeWorkItem := func(fxt *tf.TestFixture, title string) (*workitem.WorkItem, error) {
+		wi := *fxt.WorkItemByTitle(title)
+		wi.Fields[workitem.SystemState] = workitem.SystemStateClosed
+		return s.repo.Save(s.Ctx, wi.SpaceID, wi, fxt.Identities[0].ID)
+	}
+	s.T().Run("open blockers", func(t *testing.T) {
+		fxt := newFixture(t, true)
+		// when
+		blockers, err := s.repo.ListOpenBlockers(s.Ctx, fxt.WorkItemByTitle("blocked").ID)
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("blocker").ID}, blockers)
+	})
+	s.T().Run("open blockers of several work items", func(t *testing.T) {
+		// given a second work item blocked by two work items
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.WorkItemLinkTypes(1, tf.SetTopologies(link.TopologyDependency), func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemLinkTypes[idx].Blocking = true
+				return nil
+			}),
+			tf.WorkItems(5, tf.SetWorkItemTitles("A", "B", "C", "blocked by A", "blocked by B and C")),
+			tf.WorkItemLinksCustom(3, tf.BuildLinks(tf.L("A", "blocked by A"), tf.L("B", "blocked by B and C"), tf.L("C", "blocked by B and C"))),
+		)
+		// when
+		blockers, err := s.repo.ListOpenBlockersBatch(s.Ctx, []uuid.UUID{
+			fxt.WorkItemByTitle("blocked by A").ID,
+			fxt.WorkItemByTitle("blocked by B and C").ID,
+			fxt.WorkItemByTitle("A").ID,
+		})
+		// then
+		require.NoError(t, err)
+		require.Len(t, blockers, 2)
+		assert.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("A").ID}, blockers[fxt.WorkItemByTitle("blocked by A").ID])
+		assert.Equal(t, []uuid.UUID{fxt.WorkItemByTitle("B").ID, fxt.WorkItemByTitle("C").ID}, blockers[fxt.WorkItemByTitle("blocked by B and C").ID])
+	})
+	s.T().Run("fail - close blocked work item", func(t *testing.T) {
+		fxt := newFixture(t, true)
+		// when
+		_, err := closeWorkItem(fxt, "blocked")
+		// then
+		require.IsType(t, errors.DataConflictError{}, errs.Cause(err))
+		assert.Contains(t, err.Error(), fxt.WorkItemByTitle("blocker").ID.String())
+	})
+	s.T().Run("ok - close blocked work item after its blocker", func(t *testing.T) {
+		fxt := newFixture(t, true)
+		// when
+		_, err := closeWorkItem(fxt, "blocker")
+		require.NoError(t, err)
+		closed, err := closeWorkItem(fxt, "blocked")
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, workitem.SystemStateClosed, closed.Fields[workitem.SystemState])
+		blockers, err := s.repo.ListOpenBlockers(s.Ctx, fxt.WorkItemByTitle("blocked").ID)
+		require.NoError(t, err)
+		assert.Empty(t, blockers)
+	})
+	s.T().Run("ok - close work item of a non-blocking dependency", func(t *testing.T) {
+		fxt := newFixture(t, false)
+		// when
+		_, err := closeWorkItem(fxt, "blocked")
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func (s *workItemRepoBlackBoxTest) TestLookupIDByKey() {
+	// newKeyPrefix returns a key prefix that is not used by another space yet
+	newKeyPrefix := func() string {
+		return "K" + strings.ToUpper(uuid.NewV4().String()[:6])
+	}
+	s.T().Run("ok", func(t *testing.T) {
+		// given a space with a key prefix and another one without
+		prefix := newKeyPrefix()
+		fxt := tf.NewTestFixture(t, s.DB,
+			tf.Spaces(2, func(fxt *tf.TestFixture, idx int) error {
+				if idx == 0 {
+					fxt.Spaces[idx].KeyPrefix = ptr.String(prefix)
+				}
+				return nil
+			}),
+			tf.WorkItemTypes(1, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItemTypes[idx].SpaceID = space.SystemSpace
+				return nil
+			}),
+			tf.WorkItems(2, func(fxt *tf.TestFixture, idx int) error {
+				fxt.WorkItems[idx].SpaceID = fxt.Spaces[idx].ID
+				return nil
+			}),
+		)
+		key := fmt.Sprintf("%s-%d", prefix, fxt.WorkItems[0].Number)
+		require.NotNil(t, fxt.WorkItems[0].Key)
+		assert.Equal(t, key, *fxt.WorkItems[0].Key)
+		assert.Nil(t, fxt.WorkItems[1].Key)
+		t.Run("exact key", func(t *testing.T) {
+			wiID, spaceID, err := s.repo.LookupIDByKey(s.Ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+			assert.Equal(t, fxt.Spaces[0].ID, *spaceID)
+		})
+		t.Run("case-insensitive key", func(t *testing.T) {
+			wiID, _, err := s.repo.LookupIDByKey(s.Ctx, strings.ToLower(key))
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+		})
+		t.Run("loaded key", func(t *testing.T) {
+			loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+			require.NoError(t, err)
+			require.NotNil(t, loaded.Key)
+			assert.Equal(t, key, *loaded.Key)
+		})
+		t.Run("former key redirects after move", func(t *testing.T) {
+			moved, err := s.repo.Move(s.Ctx, fxt.WorkItems[0].ID, fxt.Spaces[1].ID, fxt.Identities[0].ID)
+			require.NoError(t, err)
+			assert.Nil(t, moved.Key)
+			wiID, spaceID, err := s.repo.LookupIDByKey(s.Ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, fxt.WorkItems[0].ID, *wiID)
+			assert.Equal(t, fxt.Spaces[1].ID, *spaceID)
+		})
+		t.Run("key prefix change", func(t *testing.T) {
+			sp := *fxt.Spaces[1]
+			newPrefix := newKeyPrefix()
+			sp.KeyPrefix = ptr.String(newPrefix)
+			_, err := space.NewRepository(s.DB).Save(s.Ctx, &sp)
+			require.NoError(t, err)
+			loaded, err := s.repo.LoadByID(s.Ctx, fxt.WorkItems[1].ID)
+			require.NoError(t, err)
+			require.NotNil(t, loaded.Key)
+			assert.Equal(t, fmt.Sprintf("%s-%d", newPrefix, fxt.WorkItems[1].Number), *loaded.Key)
+		})
+	})
+	s.T().Run("fail - invalid key", func(t *testing.T) {
+		_, _, err := s.repo.LookupIDByKey(s.Ctx, "PLAT123")
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - unknown key", func(t *testing.T) {
+		_, _, err := s.repo.LookupIDByKey(s.Ctx, newKeyPrefix()+"-1")
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
 func (s *workItemRepoBlackBoxTest) TestCheckExists() {
 	s.T().Run("work item exists", func(t *testing.T) {
 		// given
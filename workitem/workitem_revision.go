@@ -1,8 +1,12 @@
 package workitem
 
 import (
+	"reflect"
+	"sort"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
+
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -18,6 +22,10 @@ const (
 	_                  // ignore 3rd value
 	// RevisionTypeUpdate a work item update
 	RevisionTypeUpdate // 4
+	// RevisionTypeMove a work item was moved to another space
+	RevisionTypeMove // 5
+	// RevisionTypeChangeType the type of a work item was changed
+	RevisionTypeChangeType // 6
 )
 
 // Revision represents a version of a work item
@@ -37,6 +45,9 @@ type Revision struct {
 	WorkItemVersion int `gorm:"column:work_item_version"`
 	// the field values (or empty when the work item was deleted)
 	WorkItemFields Fields `gorm:"column:work_item_fields" sql:"type:jsonb"`
+	// the users mentioned in the description (or empty when the work item
+	// was deleted)
+	WorkItemMentions account.Mentions `gorm:"column:work_item_mentions" sql:"type:jsonb"`
 }
 
 const (
@@ -47,3 +58,54 @@ const (
 func (w Revision) TableName() string {
 	return revisionTableName
 }
+
+// FieldChange describes how the value of a single field changed between two
+// revisions of a work item
+type FieldChange struct {
+	Name string      `json:"name"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// DiffFields returns the changes that lead from the old to the new field
+// values, sorted by field name. Fields that are missing on one side are
+// reported with a nil value.
+func DiffFields(old, new Fields) []FieldChange {
+	names := []string{}
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	changes := []FieldChange{}
+	for _, name := range names {
+		if reflect.DeepEqual(old[name], new[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Name: name, Old: old[name], New: new[name]})
+	}
+	return changes
+}
+
+// Diff returns the field changes of this revision compared to the given
+// previous revision. When there is no previous revision all fields of this
+// revision are reported as new.
+func (w Revision) Diff(previous *Revision) []FieldChange {
+	if previous == nil {
+		return DiffFields(nil, w.WorkItemFields)
+	}
+	return DiffFields(previous.WorkItemFields, w.WorkItemFields)
+}
+
+// AddedMentions returns the IDs of the users mentioned in this revision that
+// were not mentioned in the given previous revision.
+func (w Revision) AddedMentions(previous *Revision) []uuid.UUID {
+	if previous == nil {
+		return w.WorkItemMentions.Added(nil)
+	}
+	return w.WorkItemMentions.Added(previous.WorkItemMentions)
+}
@@ -5,6 +5,7 @@ import (
 
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/log"
 	"github.com/jinzhu/gorm"
@@ -18,6 +19,10 @@ type RevisionRepository interface {
 	Create(ctx context.Context, modifierID uuid.UUID, revisionType RevisionType, workitem WorkItemStorage) error
 	// List retrieves all revisions for a given work item
 	List(ctx context.Context, workitemID uuid.UUID) ([]Revision, error)
+	// ListLatest retrieves the given number of most recent revisions for a given work item, newest first
+	ListLatest(ctx context.Context, workitemID uuid.UUID, limit int) ([]Revision, error)
+	// Load retrieves the revision with the given ID
+	Load(ctx context.Context, revisionID uuid.UUID) (*Revision, error)
 }
 
 // NewRevisionRepository creates a GormRevisionRepository
@@ -46,10 +51,12 @@ func (r *GormRevisionRepository) Create(ctx context.Context, modifierID uuid.UUI
 		WorkItemTypeID:   workitem.Type,
 		WorkItemVersion:  workitem.Version,
 		WorkItemFields:   workitem.Fields,
+		WorkItemMentions: workitem.Mentions,
 	}
 	// do not store fields when the work item is deleted
 	if workitemRevision.Type == RevisionTypeDelete {
 		workitemRevision.WorkItemFields = Fields{}
+		workitemRevision.WorkItemMentions = account.Mentions{}
 	}
 	if err := tx.Create(&workitemRevision).Error; err != nil {
 		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create new work item revision"))
@@ -67,3 +74,27 @@ func (r *GormRevisionRepository) List(ctx context.Context, workitemID uuid.UUID)
 	}
 	return revisions, nil
 }
+
+// ListLatest retrieves the given number of most recent revisions for a given work item, newest first
+func (r *GormRevisionRepository) ListLatest(ctx context.Context, workitemID uuid.UUID, limit int) ([]Revision, error) {
+	log.Debug(nil, map[string]interface{}{}, "List the %d latest revisions for work item with ID=%v", limit, workitemID)
+	var revisions []Revision
+	if err := r.db.Where("work_item_id = ?", workitemID).Order("revision_time desc").Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to retrieve work item revisions"))
+	}
+	return revisions, nil
+}
+
+// Load retrieves the revision with the given ID
+func (r *GormRevisionRepository) Load(ctx context.Context, revisionID uuid.UUID) (*Revision, error) {
+	log.Debug(nil, map[string]interface{}{}, "Load work item revision with ID=%v", revisionID)
+	revision := Revision{}
+	tx := r.db.Where("id = ?", revisionID).First(&revision)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("work item revision", revisionID.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, errs.Wrap(err, "failed to retrieve work item revision"))
+	}
+	return &revision, nil
+}
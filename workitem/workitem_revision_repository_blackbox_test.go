@@ -94,3 +94,48 @@ func (s *workItemRevisionRepositoryBlackBoxTest) TestStoreRevisions() {
 		require.Empty(t, revision4.WorkItemFields)
 	})
 }
+
+func (s *workItemRevisionRepositoryBlackBoxTest) TestListLatest() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1, tf.SetWorkItemTitles("Title")), tf.Identities(1))
+	wi := fxt.WorkItems[0]
+	wi.Fields[workitem.SystemTitle] = "Updated Title"
+	wi, err := s.repository.Save(s.Ctx, wi.SpaceID, *wi, fxt.Identities[0].ID)
+	require.NoError(s.T(), err)
+	// when
+	revisions, err := s.revisionRepository.ListLatest(s.Ctx, wi.ID, 2)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), revisions, 2)
+	assert.Equal(s.T(), workitem.RevisionTypeUpdate, revisions[0].Type)
+	assert.Equal(s.T(), workitem.RevisionTypeCreate, revisions[1].Type)
+	changes := revisions[0].Diff(&revisions[1])
+	require.Len(s.T(), changes, 1)
+	assert.Equal(s.T(), workitem.FieldChange{Name: workitem.SystemTitle, Old: "Title", New: "Updated Title"}, changes[0])
+}
+
+func TestDiffFields(t *testing.T) {
+	t.Parallel()
+	resource.Require(t, resource.UnitTest)
+	t.Run("no changes", func(t *testing.T) {
+		fields := workitem.Fields{workitem.SystemTitle: "foo"}
+		assert.Empty(t, workitem.DiffFields(fields, fields))
+	})
+	t.Run("changed, added and removed fields", func(t *testing.T) {
+		old := workitem.Fields{
+			workitem.SystemTitle:       "foo",
+			workitem.SystemDescription: "bar",
+			workitem.SystemLabels:      []interface{}{"a"},
+		}
+		new := workitem.Fields{
+			workitem.SystemTitle:  "foo",
+			workitem.SystemLabels: []interface{}{"a", "b"},
+			workitem.SystemState:  workitem.SystemStateOpen,
+		}
+		assert.Equal(t, []workitem.FieldChange{
+			{Name: workitem.SystemDescription, Old: "bar", New: nil},
+			{Name: workitem.SystemLabels, Old: []interface{}{"a"}, New: []interface{}{"a", "b"}},
+			{Name: workitem.SystemState, Old: nil, New: workitem.SystemStateOpen},
+		}, workitem.DiffFields(old, new))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/fabric8-services/fabric8-wit/account"
 	"github.com/fabric8-services/fabric8-wit/convert"
 	"github.com/fabric8-services/fabric8-wit/errors"
 	"github.com/fabric8-services/fabric8-wit/gormsupport"
@@ -18,6 +19,9 @@ type WorkItemStorage struct {
 	ID uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
 	// unique number per _space_
 	Number int
+	// optional human-readable key made of the key prefix of the space and the
+	// number (e.g. "PLAT-123"), maintained by a database trigger
+	Key *string
 	// Id of the type of this work item
 	Type uuid.UUID `sql:"type:uuid"`
 	// Version for optimistic concurrency control
@@ -30,6 +34,8 @@ type WorkItemStorage struct {
 	SpaceID uuid.UUID `sql:"type:uuid"`
 	// optional timestamp of the latest addition/removal of a relationship with this workitem
 	RelationShipsChangedAt *time.Time `sql:"column:relationships_changed_at"`
+	// the users mentioned in the description
+	Mentions account.Mentions `sql:"type:jsonb"`
 }
 
 const (
@@ -20,6 +20,7 @@ const (
 
 	SystemRemoteItemID        = "system.remote_item_id"
 	SystemNumber              = "system.number"
+	SystemKey                 = "system.key"
 	SystemTitle               = "system.title"
 	SystemDescription         = "system.description"
 	SystemDescriptionMarkup   = "system.description.markup"
@@ -34,6 +35,12 @@ const (
 	SystemArea                = "system.area"
 	SystemCodebase            = "system.codebase"
 	SystemLabels              = "system.labels"
+	SystemOriginalEstimate    = "system.original_estimate"
+	SystemRemainingEstimate   = "system.remaining_estimate"
+	SystemDueDate             = "system.due_date"
+	SystemBlocked             = "system.blocked"   // computed, not stored in the fields
+	SystemRollUps             = "system.rollups"   // computed, not stored in the fields
+	SystemTypeGroup           = "system.typegroup" // computed, not stored in the fields
 
 	SystemStateOpen       = "open"
 	SystemStateNew        = "new"
@@ -75,6 +82,12 @@ type WorkItemType struct {
 	Fields FieldDefinitions `sql:"type:jsonb"`
 	// Reference to one Space
 	SpaceID uuid.UUID `sql:"type:uuid"`
+	// Workflow optionally restricts the states and state transitions of the
+	// work items of this type
+	Workflow *Workflow `sql:"type:jsonb"`
+	// RollUps are the values of the work items of this type that are computed
+	// from their children
+	RollUps RollUps `sql:"type:jsonb"`
 }
 
 // GetTypePathSeparator returns the work item type's path separator "."
@@ -158,6 +171,15 @@ func (wit WorkItemType) Equal(u convert.Equaler) bool {
 			return false
 		}
 	}
+	if (wit.Workflow == nil) != (other.Workflow == nil) {
+		return false
+	}
+	if wit.Workflow != nil && !wit.Workflow.Equal(*other.Workflow) {
+		return false
+	}
+	if !wit.RollUps.Equal(other.RollUps) {
+		return false
+	}
 	return wit.SpaceID == other.SpaceID
 }
 
@@ -166,6 +188,7 @@ func (wit WorkItemType) ConvertWorkItemStorageToModel(workItem WorkItemStorage)
 	result := WorkItem{
 		ID:                     workItem.ID,
 		Number:                 workItem.Number,
+		Key:                    workItem.Key,
 		Type:                   workItem.Type,
 		Version:                workItem.Version,
 		Fields:                 map[string]interface{}{},
@@ -178,7 +201,13 @@ func (wit WorkItemType) ConvertWorkItemStorageToModel(workItem WorkItemStorage)
 		if name == SystemCreatedAt {
 			continue
 		}
-		result.Fields[name], err = field.ConvertFromModel(name, workItem.Fields[name])
+		value, exists := workItem.Fields[name]
+		if !exists && field.DefaultValue != nil {
+			// the field was added to the type after the work item was stored,
+			// the default value is persisted with the next save of the item
+			value = field.DefaultValue
+		}
+		result.Fields[name], err = field.ConvertFromModel(name, value)
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
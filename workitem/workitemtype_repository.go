@@ -1,6 +1,9 @@
 package workitem
 
 import (
+	"encoding/json"
+	"math"
+	"sort"
 	"time"
 
 	"context"
@@ -26,6 +29,11 @@ type WorkItemTypeRepository interface {
 	CreateFromModel(ctx context.Context, model *WorkItemType) (*WorkItemType, error)
 	List(ctx context.Context, spaceID uuid.UUID, start *int, length *int) ([]WorkItemType, error)
 	ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error)
+	SetWorkflow(ctx context.Context, id uuid.UUID, workflow *Workflow) (*WorkItemType, error)
+	SetRollUps(ctx context.Context, id uuid.UUID, rollUps RollUps) (*WorkItemType, error)
+	AddField(ctx context.Context, id uuid.UUID, name string, definition FieldDefinition) (*WorkItemType, error)
+	DeprecateField(ctx context.Context, id uuid.UUID, name string) (*WorkItemType, error)
+	ReorderFields(ctx context.Context, id uuid.UUID, names []string) (*WorkItemType, error)
 }
 
 // NewWorkItemTypeRepository creates a wi type repository based on gorm
@@ -144,6 +152,8 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 
 	allFields := map[string]FieldDefinition{}
 	path := LtreeSafeID(*id)
+	var workflow *Workflow
+	var rollUps RollUps
 	if extendedTypeID != nil {
 		extendedType := WorkItemType{}
 		db := r.db.Model(&extendedType).Where("id=?", extendedTypeID).First(&extendedType)
@@ -158,6 +168,9 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 			allFields[key] = value
 		}
 		path = extendedType.Path + pathSep + path
+		// the workflow and the roll-ups are inherited from the extended type
+		workflow = extendedType.Workflow
+		rollUps = extendedType.RollUps
 	}
 	// now process new fields, checking whether they are already there.
 	for field, definition := range fields {
@@ -177,11 +190,61 @@ func (r *GormWorkItemTypeRepository) Create(ctx context.Context, spaceID uuid.UU
 		Path:        path,
 		Fields:      allFields,
 		SpaceID:     spaceID,
+		Workflow:    workflow,
+		RollUps:     rollUps,
 	}
 
 	return r.CreateFromModel(ctx, &model)
 }
 
+// SetWorkflow replaces the workflow of the work item type with the given ID.
+// A nil workflow removes all restrictions on the states of the work items of
+// the type.
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) SetWorkflow(ctx context.Context, id uuid.UUID, workflow *Workflow) (*WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "setworkflow"}, time.Now())
+	wit, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if workflow != nil {
+		if err := workflow.Validate(*wit); err != nil {
+			return nil, errs.WithStack(err)
+		}
+	}
+	if err := r.update(ctx, wit, map[string]interface{}{"workflow": workflow}); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wit.Workflow = workflow
+	log.Debug(ctx, map[string]interface{}{"wit_id": id}, "work item type workflow updated")
+	return wit, nil
+}
+
+// SetRollUps replaces the roll-ups of the work item type with the given ID.
+// No roll-ups removes the computed values from the work items of the type.
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) SetRollUps(ctx context.Context, id uuid.UUID, rollUps RollUps) (*WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "setrollups"}, time.Now())
+	wit, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := rollUps.Validate(); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if err := r.update(ctx, wit, map[string]interface{}{"roll_ups": rollUps}); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wit.RollUps = rollUps
+	// the cached values of the work items of the type are computed with the
+	// former roll-ups
+	if err := NewRollUpCache(r.db).InvalidateType(ctx, id); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	log.Debug(ctx, map[string]interface{}{"wit_id": id}, "work item type roll-ups updated")
+	return wit, nil
+}
+
 // List returns work item types that derives from PlannerItem type
 func (r *GormWorkItemTypeRepository) ListPlannerItems(ctx context.Context, spaceID uuid.UUID) ([]WorkItemType, error) {
 	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "listPlannerItems"}, time.Now())
@@ -218,3 +281,187 @@ func (r *GormWorkItemTypeRepository) List(ctx context.Context, spaceID uuid.UUID
 	}
 	return rows, nil
 }
+
+// update stores the given columns of the work item type and increments its
+// version. The cached copy of the type is dropped so that the next load
+// returns the updated type.
+// returns VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) update(ctx context.Context, wit *WorkItemType, columns map[string]interface{}) error {
+	columns["version"] = wit.Version + 1
+	db := r.db.Model(&WorkItemType{}).Where("id = ? AND version = ?", wit.ID, wit.Version).Updates(columns)
+	if err := db.Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	if db.RowsAffected == 0 {
+		return errors.NewVersionConflictError("version conflict")
+	}
+	cache.Remove(wit.ID)
+	wit.Version = wit.Version + 1
+	return nil
+}
+
+// AddField adds a custom field to the work item type with the given ID, or
+// re-enables a deprecated custom field. The definition must be compatible with
+// the definitions of the fields with the same name of the other work item
+// types in the space so that the field can be searched across types. The
+// default value of the field is stored in the existing work items of the type
+// that have no value for the field, so that they match search filters on it.
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) AddField(ctx context.Context, id uuid.UUID, name string, definition FieldDefinition) (*WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "addfield"}, time.Now())
+	if !IsCustomField(name) {
+		return nil, errors.NewBadParameterError("name", name).Expected("a field name matching " + customFieldNamePattern.String())
+	}
+	if definition.Type == nil {
+		return nil, errors.NewBadParameterError("type", nil).Expected("a field type")
+	}
+	wit, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	if existing, exists := wit.Fields[name]; exists {
+		if !existing.Deprecated {
+			return nil, errors.NewBadParameterError("name", name).Expected("a field that does not exist in work item type " + wit.Name)
+		}
+		// deprecated fields are never required
+		existing.Required = definition.Required
+		if !compatibleFields(existing, definition) {
+			return nil, errors.NewBadParameterError("type", name).Expected("a definition compatible with the deprecated field")
+		}
+	}
+	var others []WorkItemType
+	if err := r.db.Where("space_id = ? AND id != ? AND jsonb_exists(fields, ?)", wit.SpaceID, wit.ID, name).Find(&others).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	for _, other := range others {
+		otherDef := other.Fields[name]
+		otherDef.Required = definition.Required
+		if !compatibleFields(otherDef, definition) {
+			return nil, errors.NewBadParameterError("type", name).Expected("a definition compatible with the field of work item type " + other.Name)
+		}
+	}
+	if definition.DefaultValue != nil {
+		if f, ok := definition.DefaultValue.(float64); ok && f == math.Trunc(f) {
+			// JSON numbers are decoded as floats
+			switch definition.Type.GetKind() {
+			case KindInteger, KindDuration:
+				definition.DefaultValue = int(f)
+			}
+		}
+		definition.DefaultValue, err = definition.ConvertToModel(name, definition.DefaultValue)
+		if err != nil {
+			return nil, errors.NewBadParameterError("defaultValue", definition.DefaultValue).Expected(err.Error())
+		}
+	} else if definition.Required {
+		return nil, errors.NewBadParameterError("defaultValue", nil).Expected("a default value for the existing work items")
+	}
+	definition.Deprecated = false
+	if definition.Order == 0 {
+		for _, f := range wit.Fields {
+			if f.Order > definition.Order {
+				definition.Order = f.Order
+			}
+		}
+		definition.Order++
+	}
+	fields := FieldDefinitions{}
+	for k, v := range wit.Fields {
+		fields[k] = v
+	}
+	fields[name] = definition
+	if err := r.update(ctx, wit, map[string]interface{}{"fields": fields}); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wit.Fields = fields
+	if definition.DefaultValue != nil {
+		value, err := json.Marshal(definition.DefaultValue)
+		if err != nil {
+			return nil, errors.NewInternalError(ctx, err)
+		}
+		db := r.db.Exec("UPDATE "+WorkItemStorage{}.TableName()+" SET fields = fields || jsonb_build_object(?::text, ?::jsonb) WHERE type = ? AND NOT jsonb_exists(fields, ?)", name, string(value), wit.ID, name)
+		if db.Error != nil {
+			return nil, errors.NewInternalError(ctx, db.Error)
+		}
+		log.Debug(ctx, map[string]interface{}{"wit_id": id, "field": name, "work_items": db.RowsAffected}, "default value stored in the existing work items")
+	}
+	log.Debug(ctx, map[string]interface{}{"wit_id": id, "field": name}, "custom field added to work item type")
+	return wit, nil
+}
+
+// DeprecateField marks the custom field with the given name as deprecated.
+// The values of the field are kept but the field is no longer required.
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) DeprecateField(ctx context.Context, id uuid.UUID, name string) (*WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "deprecatefield"}, time.Now())
+	wit, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	definition, exists := wit.Fields[name]
+	if !exists || !IsCustomField(name) {
+		return nil, errors.NewBadParameterError("name", name).Expected("a custom field of work item type " + wit.Name)
+	}
+	if definition.Deprecated {
+		return wit, nil
+	}
+	definition.Deprecated = true
+	definition.Required = false
+	fields := FieldDefinitions{}
+	for k, v := range wit.Fields {
+		fields[k] = v
+	}
+	fields[name] = definition
+	if err := r.update(ctx, wit, map[string]interface{}{"fields": fields}); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wit.Fields = fields
+	return wit, nil
+}
+
+// ReorderFields puts the fields with the given names first, in the given
+// order. The other fields keep their relative order behind them.
+// returns NotFoundError, BadParameterError, VersionConflictError or InternalError
+func (r *GormWorkItemTypeRepository) ReorderFields(ctx context.Context, id uuid.UUID, names []string) (*WorkItemType, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemtype", "reorderfields"}, time.Now())
+	wit, err := r.LoadTypeFromDB(ctx, id)
+	if err != nil {
+		return nil, errs.WithStack(err)
+	}
+	fields := FieldDefinitions{}
+	for k, v := range wit.Fields {
+		fields[k] = v
+	}
+	listed := map[string]bool{}
+	for i, name := range names {
+		definition, exists := fields[name]
+		if !exists || listed[name] {
+			return nil, errors.NewBadParameterError("fields", name).Expected("distinct fields of work item type " + wit.Name)
+		}
+		listed[name] = true
+		definition.Order = i + 1
+		fields[name] = definition
+	}
+	var others []string
+	for name := range wit.Fields {
+		if !listed[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Slice(others, func(i, j int) bool {
+		oi, oj := wit.Fields[others[i]].Order, wit.Fields[others[j]].Order
+		if oi != oj {
+			return oi < oj
+		}
+		return others[i] < others[j]
+	})
+	for i, name := range others {
+		definition := fields[name]
+		definition.Order = len(names) + i + 1
+		fields[name] = definition
+	}
+	if err := r.update(ctx, wit, map[string]interface{}{"fields": fields}); err != nil {
+		return nil, errs.WithStack(err)
+	}
+	wit.Fields = fields
+	return wit, nil
+}
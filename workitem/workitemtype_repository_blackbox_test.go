@@ -7,6 +7,7 @@ import (
 	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
 	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
 	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -145,3 +146,96 @@ func (s *workItemTypeRepoBlackBoxTest) TestCreate() {
 		assert.Equal(t, true, field.Required)
 	})
 }
+
+func (s *workItemTypeRepoBlackBoxTest) TestCustomFields() {
+	storyPoints := workitem.FieldDefinition{
+		Label:    "Story Points",
+		Required: true,
+		Type:     workitem.SimpleType{Kind: workitem.KindInteger},
+	}
+	s.T().Run("add field", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		def := storyPoints
+		def.DefaultValue = 3
+		// when
+		wit, err := s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points", def)
+		// then
+		require.NoError(t, err)
+		require.Contains(t, wit.Fields, "custom.story_points")
+		assert.Equal(t, fxt.WorkItemTypes[0].Version+1, wit.Version)
+		loaded, err := s.repo.Load(s.Ctx, wit.ID)
+		require.NoError(t, err)
+		require.Contains(t, loaded.Fields, "custom.story_points")
+		t.Run("existing work items get the default value", func(t *testing.T) {
+			wi, err := workitem.NewWorkItemRepository(s.DB).LoadByID(s.Ctx, fxt.WorkItems[0].ID)
+			require.NoError(t, err)
+			assert.EqualValues(t, 3, wi.Fields["custom.story_points"])
+		})
+		t.Run("default value is stored in the existing work items", func(t *testing.T) {
+			var count int
+			err := s.DB.Table(workitem.WorkItemStorage{}.TableName()).Where("id = ? AND (fields->>'custom.story_points')::numeric = 3", fxt.WorkItems[0].ID).Count(&count).Error
+			require.NoError(t, err)
+			assert.Equal(t, 1, count)
+		})
+	})
+	s.T().Run("fail - no custom field name", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		_, err := s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "story_points", storyPoints)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - required field without default value", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		_, err := s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points", storyPoints)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - incompatible with other type of the space", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(2))
+		def := storyPoints
+		def.Required = false
+		_, err := s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points", def)
+		require.NoError(t, err)
+		def.Type = workitem.SimpleType{Kind: workitem.KindString}
+		_, err = s.repo.AddField(s.Ctx, fxt.WorkItemTypes[1].ID, "custom.story_points", def)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("deprecate and re-add field", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		def := storyPoints
+		def.DefaultValue = 1
+		_, err := s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points", def)
+		require.NoError(t, err)
+		// when
+		wit, err := s.repo.DeprecateField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points")
+		// then
+		require.NoError(t, err)
+		assert.True(t, wit.Fields["custom.story_points"].Deprecated)
+		assert.False(t, wit.Fields["custom.story_points"].Required)
+		wit, err = s.repo.AddField(s.Ctx, fxt.WorkItemTypes[0].ID, "custom.story_points", def)
+		require.NoError(t, err)
+		assert.False(t, wit.Fields["custom.story_points"].Deprecated)
+	})
+	s.T().Run("fail - deprecate system field", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		_, err := s.repo.DeprecateField(s.Ctx, fxt.WorkItemTypes[0].ID, workitem.SystemTitle)
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("reorder fields", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItemTypes(1))
+		// when
+		wit, err := s.repo.ReorderFields(s.Ctx, fxt.WorkItemTypes[0].ID, []string{workitem.SystemState, workitem.SystemTitle})
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, wit.Fields[workitem.SystemState].Order)
+		assert.Equal(t, 2, wit.Fields[workitem.SystemTitle].Order)
+		for name, def := range wit.Fields {
+			if name != workitem.SystemState && name != workitem.SystemTitle {
+				assert.True(t, def.Order > 2, "field %s", name)
+			}
+		}
+		_, err = s.repo.ReorderFields(s.Ctx, fxt.WorkItemTypes[0].ID, []string{"foo"})
+		assert.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
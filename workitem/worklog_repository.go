@@ -0,0 +1,184 @@
+package workitem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormsupport"
+	"github.com/fabric8-services/fabric8-wit/log"
+
+	"github.com/goadesign/goa"
+	"github.com/jinzhu/gorm"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIStringTypeWorklogs is the JSON-API type of worklogs
+const APIStringTypeWorklogs = "worklogs"
+
+// Worklog records the time that someone spent on a work item
+type Worklog struct {
+	gormsupport.Lifecycle
+	ID         uuid.UUID `sql:"type:uuid default uuid_generate_v4()" gorm:"primary_key"`
+	WorkItemID uuid.UUID `sql:"type:uuid"`
+	// the identity who spent the time
+	Creator uuid.UUID `sql:"type:uuid"`
+	// when the work started
+	StartedAt time.Time
+	// the time spent, in seconds
+	Duration int64
+	Note     string
+}
+
+// TableName implements gorm.tabler
+func (w Worklog) TableName() string {
+	return "work_item_worklogs"
+}
+
+// TimeTotals holds the estimated and the spent effort (in seconds) of one or
+// more work items
+type TimeTotals struct {
+	OriginalEstimate  int64 `gorm:"column:original_estimate"`
+	RemainingEstimate int64 `gorm:"column:remaining_estimate"`
+	TimeSpent         int64 `gorm:"column:time_spent"`
+}
+
+// Add returns the sum of both totals
+func (t TimeTotals) Add(other TimeTotals) TimeTotals {
+	return TimeTotals{
+		OriginalEstimate:  t.OriginalEstimate + other.OriginalEstimate,
+		RemainingEstimate: t.RemainingEstimate + other.RemainingEstimate,
+		TimeSpent:         t.TimeSpent + other.TimeSpent,
+	}
+}
+
+// timeTotalsSelect returns the SQL expressions that aggregate the estimates
+// and the time spent of the rows of the given work items table (or alias)
+func timeTotalsSelect(table string) string {
+	return fmt.Sprintf(`coalesce(sum((%[1]s.fields->>'%[2]s')::numeric), 0)::bigint AS original_estimate,
+		coalesce(sum((%[1]s.fields->>'%[3]s')::numeric), 0)::bigint AS remaining_estimate,
+		coalesce(sum((SELECT sum(wl.duration) FROM %[4]s wl WHERE wl.work_item_id = %[1]s.id AND wl.deleted_at IS NULL)), 0)::bigint AS time_spent`,
+		table, SystemOriginalEstimate, SystemRemainingEstimate, Worklog{}.TableName())
+}
+
+// WorklogRepository encapsulates storage & retrieval of the time spent on
+// work items
+type WorklogRepository interface {
+	Create(ctx context.Context, worklog *Worklog) error
+	Load(ctx context.Context, id uuid.UUID) (*Worklog, error)
+	List(ctx context.Context, workItemID uuid.UUID) ([]Worklog, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	Totals(ctx context.Context, workItemID uuid.UUID, rollUp bool) (*TimeTotals, error)
+}
+
+// NewWorklogRepository creates a work item worklog repository based on gorm
+func NewWorklogRepository(db *gorm.DB) *GormWorklogRepository {
+	return &GormWorklogRepository{db: db}
+}
+
+// GormWorklogRepository implements WorklogRepository using gorm
+type GormWorklogRepository struct {
+	db *gorm.DB
+}
+
+// Create records the given time spent on a work item
+// returns BadParameterError or InternalError
+func (r *GormWorklogRepository) Create(ctx context.Context, worklog *Worklog) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemworklog", "create"}, time.Now())
+	if worklog.Duration <= 0 {
+		return errors.NewBadParameterError("duration", worklog.Duration).Expected("a positive number of seconds")
+	}
+	if worklog.ID == uuid.Nil {
+		worklog.ID = uuid.NewV4()
+	}
+	if worklog.StartedAt.IsZero() {
+		worklog.StartedAt = time.Now()
+	}
+	if err := r.db.Create(worklog).Error; err != nil {
+		if gormsupport.IsForeignKeyViolation(err, "work_item_worklogs_work_item_id_fkey") {
+			return errors.NewBadParameterError("work_item_id", worklog.WorkItemID).Expected("an existing work item")
+		}
+		return errors.NewInternalError(ctx, errs.Wrap(err, "failed to create worklog"))
+	}
+	log.Debug(ctx, map[string]interface{}{
+		"worklog_id": worklog.ID,
+		"wi_id":      worklog.WorkItemID,
+	}, "worklog created")
+	return nil
+}
+
+// Load returns the worklog with the given ID
+// returns NotFoundError or InternalError
+func (r *GormWorklogRepository) Load(ctx context.Context, id uuid.UUID) (*Worklog, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemworklog", "load"}, time.Now())
+	w := Worklog{}
+	tx := r.db.Where("id = ?", id).First(&w)
+	if tx.RecordNotFound() {
+		return nil, errors.NewNotFoundError("worklog", id.String())
+	}
+	if err := tx.Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return &w, nil
+}
+
+// List returns the worklogs of the given work item in the order in which the
+// work was done
+// returns InternalError
+func (r *GormWorklogRepository) List(ctx context.Context, workItemID uuid.UUID) ([]Worklog, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemworklog", "list"}, time.Now())
+	var worklogs []Worklog
+	if err := r.db.Where("work_item_id = ?", workItemID).Order("started_at, created_at").Find(&worklogs).Error; err != nil {
+		return nil, errors.NewInternalError(ctx, err)
+	}
+	return worklogs, nil
+}
+
+// Delete deletes the worklog with the given ID
+// returns NotFoundError or InternalError
+func (r *GormWorklogRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemworklog", "delete"}, time.Now())
+	tx := r.db.Delete(&Worklog{ID: id})
+	if err := tx.Error; err != nil {
+		return errors.NewInternalError(ctx, err)
+	}
+	if tx.RowsAffected == 0 {
+		return errors.NewNotFoundError("worklog", id.String())
+	}
+	return nil
+}
+
+// Totals returns the estimates and the time spent of the given work item. If
+// rollUp is true, the totals include the descendants of the work item
+// through the links of a tree topology (e.g. parent/child), each descendant
+// being counted once.
+// returns InternalError
+func (r *GormWorklogRepository) Totals(ctx context.Context, workItemID uuid.UUID, rollUp bool) (*TimeTotals, error) {
+	defer goa.MeasureSince([]string{"goa", "db", "workitemworklog", "totals"}, time.Now())
+	query := fmt.Sprintf(`SELECT %[1]s FROM %[2]s wi WHERE wi.id = ? AND wi.deleted_at IS NULL`,
+		timeTotalsSelect("wi"), workitemTableName)
+	if rollUp {
+		query = fmt.Sprintf(`WITH RECURSIVE tree(id) AS (
+				SELECT ?::uuid
+				UNION
+				SELECT l.target_id FROM work_item_links l
+				JOIN work_item_link_types t ON t.id = l.link_type_id
+				JOIN tree ON l.source_id = tree.id
+				WHERE t.topology = 'tree' AND l.deleted_at IS NULL
+			)
+			SELECT %[1]s FROM %[2]s wi JOIN tree ON tree.id = wi.id WHERE wi.deleted_at IS NULL`,
+			timeTotalsSelect("wi"), workitemTableName)
+	}
+	var totals TimeTotals
+	if err := r.db.Raw(query, workItemID).Scan(&totals).Error; err != nil {
+		log.Error(ctx, map[string]interface{}{
+			"wi_id":   workItemID,
+			"roll_up": rollUp,
+			"err":     err,
+		}, "unable to compute the time totals of the work item")
+		return nil, errors.NewInternalError(ctx, errs.Wrapf(err, "failed to compute the time totals of work item %s", workItemID))
+	}
+	return &totals, nil
+}
@@ -0,0 +1,161 @@
+package workitem_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fabric8-services/fabric8-wit/errors"
+	"github.com/fabric8-services/fabric8-wit/gormtestsupport"
+	"github.com/fabric8-services/fabric8-wit/resource"
+	tf "github.com/fabric8-services/fabric8-wit/test/testfixture"
+	"github.com/fabric8-services/fabric8-wit/workitem"
+	errs "github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type worklogRepoBlackBoxTest struct {
+	gormtestsupport.DBTestSuite
+	repo workitem.WorklogRepository
+}
+
+func TestRunWorklogRepoBlackBoxTest(t *testing.T) {
+	resource.Require(t, resource.Database)
+	suite.Run(t, &worklogRepoBlackBoxTest{DBTestSuite: gormtestsupport.NewDBTestSuite("../config.yaml")})
+}
+
+func (s *worklogRepoBlackBoxTest) SetupTest() {
+	s.DBTestSuite.SetupTest()
+	s.repo = workitem.NewWorklogRepository(s.DB)
+}
+
+// estimatedWorkItemTypes adds the estimate fields to the work item types of
+// the test fixture
+func estimatedWorkItemTypes(fxt *tf.TestFixture, idx int) error {
+	fxt.WorkItemTypes[idx].Fields[workitem.SystemOriginalEstimate] = workitem.FieldDefinition{
+		Label: "Original estimate",
+		Type:  workitem.SimpleType{Kind: workitem.KindDuration},
+	}
+	fxt.WorkItemTypes[idx].Fields[workitem.SystemRemainingEstimate] = workitem.FieldDefinition{
+		Label: "Remaining estimate",
+		Type:  workitem.SimpleType{Kind: workitem.KindDuration},
+	}
+	return nil
+}
+
+func (s *worklogRepoBlackBoxTest) TestCreate() {
+	s.T().Run("ok", func(t *testing.T) {
+		// given
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		w := workitem.Worklog{
+			WorkItemID: fxt.WorkItems[0].ID,
+			Creator:    fxt.Identities[0].ID,
+			Duration:   3600,
+			Note:       "investigation",
+		}
+		// when
+		err := s.repo.Create(s.Ctx, &w)
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, w.ID)
+		assert.False(t, w.StartedAt.IsZero())
+		loaded, err := s.repo.Load(s.Ctx, w.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3600), loaded.Duration)
+		assert.Equal(t, "investigation", loaded.Note)
+	})
+	s.T().Run("fail - no duration", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.WorkItems(1))
+		err := s.repo.Create(s.Ctx, &workitem.Worklog{WorkItemID: fxt.WorkItems[0].ID, Creator: fxt.Identities[0].ID})
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+	s.T().Run("fail - unknown work item", func(t *testing.T) {
+		fxt := tf.NewTestFixture(t, s.DB, tf.Identities(1))
+		err := s.repo.Create(s.Ctx, &workitem.Worklog{WorkItemID: uuid.NewV4(), Creator: fxt.Identities[0].ID, Duration: 60})
+		require.IsType(t, errors.BadParameterError{}, errs.Cause(err))
+	})
+}
+
+func (s *worklogRepoBlackBoxTest) TestListAndDelete() {
+	// given
+	fxt := tf.NewTestFixture(s.T(), s.DB, tf.WorkItems(1))
+	now := time.Now()
+	for _, d := range []time.Duration{2 * time.Hour, time.Hour} {
+		w := workitem.Worklog{
+			WorkItemID: fxt.WorkItems[0].ID,
+			Creator:    fxt.Identities[0].ID,
+			StartedAt:  now.Add(-d),
+			Duration:   int64(d.Seconds()),
+		}
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &w))
+	}
+	// when
+	worklogs, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+	// then
+	require.NoError(s.T(), err)
+	require.Len(s.T(), worklogs, 2)
+	assert.Equal(s.T(), int64(7200), worklogs[0].Duration)
+	assert.Equal(s.T(), int64(3600), worklogs[1].Duration)
+
+	s.T().Run("delete", func(t *testing.T) {
+		require.NoError(t, s.repo.Delete(s.Ctx, worklogs[0].ID))
+		remaining, err := s.repo.List(s.Ctx, fxt.WorkItems[0].ID)
+		require.NoError(t, err)
+		require.Len(t, remaining, 1)
+		_, err = s.repo.Load(s.Ctx, worklogs[0].ID)
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+	s.T().Run("delete unknown", func(t *testing.T) {
+		err := s.repo.Delete(s.Ctx, uuid.NewV4())
+		require.IsType(t, errors.NotFoundError{}, errs.Cause(err))
+	})
+}
+
+func (s *worklogRepoBlackBoxTest) TestTotals() {
+	// given a parent with a child and a grand child that all have estimates
+	// and time spent
+	fxt := tf.NewTestFixture(s.T(), s.DB,
+		tf.WorkItemTypes(1, estimatedWorkItemTypes),
+		tf.WorkItems(3, tf.SetWorkItemTitles("parent", "child", "grandchild"), func(fxt *tf.TestFixture, idx int) error {
+			fxt.WorkItems[idx].Fields[workitem.SystemOriginalEstimate] = int64(1000 * (idx + 1))
+			fxt.WorkItems[idx].Fields[workitem.SystemRemainingEstimate] = int64(100 * (idx + 1))
+			return nil
+		}),
+		tf.WorkItemLinksCustom(2, tf.BuildLinks(tf.LinkChain("parent", "child", "grandchild")...)),
+	)
+	for idx, wi := range fxt.WorkItems {
+		w := workitem.Worklog{
+			WorkItemID: wi.ID,
+			Creator:    fxt.Identities[0].ID,
+			Duration:   int64(10 * (idx + 1)),
+		}
+		require.NoError(s.T(), s.repo.Create(s.Ctx, &w))
+	}
+	s.T().Run("own", func(t *testing.T) {
+		totals, err := s.repo.Totals(s.Ctx, fxt.WorkItemByTitle("parent").ID, false)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.TimeTotals{OriginalEstimate: 1000, RemainingEstimate: 100, TimeSpent: 10}, *totals)
+	})
+	s.T().Run("rolled up", func(t *testing.T) {
+		totals, err := s.repo.Totals(s.Ctx, fxt.WorkItemByTitle("parent").ID, true)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.TimeTotals{OriginalEstimate: 6000, RemainingEstimate: 600, TimeSpent: 60}, *totals)
+	})
+	s.T().Run("rolled up from the middle", func(t *testing.T) {
+		totals, err := s.repo.Totals(s.Ctx, fxt.WorkItemByTitle("child").ID, true)
+		require.NoError(t, err)
+		assert.Equal(t, workitem.TimeTotals{OriginalEstimate: 5000, RemainingEstimate: 500, TimeSpent: 50}, *totals)
+	})
+	s.T().Run("iteration counts", func(t *testing.T) {
+		counts, err := workitem.NewWorkItemRepository(s.DB).GetCountsPerIteration(s.Ctx, fxt.Spaces[0].ID)
+		require.NoError(t, err)
+		var total workitem.TimeTotals
+		for _, c := range counts {
+			total = total.Add(c.TimeTotals)
+		}
+		// work items without an iteration are not counted
+		assert.True(t, total.TimeSpent <= 60)
+	})
+}